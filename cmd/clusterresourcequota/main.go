@@ -17,8 +17,11 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"path/filepath"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -26,6 +29,7 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -44,6 +48,14 @@ func main() {
 	var probeAddr string
 	var webhookPort int
 	var webhookCertDir string
+	var reservationTTL time.Duration
+	var debouncePeriod time.Duration
+	var webhookSelfManaged bool
+	var webhookNamespace string
+	var webhookServiceName string
+	var webhookSecretName string
+	var webhookConfigurationName string
+	var webhookCertValidity time.Duration
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -55,6 +67,23 @@ func main() {
 			"If not set, webhook server would look up the server key and certificate in {TempDir}/k8s-webhook-server/serving-certs."+
 			"The server key and certificate must be named tls.key and tls.crt, respectively.",
 	)
+	flag.DurationVar(&reservationTTL, "reservation-ttl", controllers.DefaultReservationTTL,
+		"The duration the usage reserved by the webhook is counted in the status of the cluster resource quotas, "+
+			"before it is expected to be observed in the namespaces.")
+	flag.DurationVar(&debouncePeriod, "reconcile-debounce", controllers.DefaultDebouncePeriod,
+		"The duration the changes of the resource quotas and the namespaces are coalesced "+
+			"before the cluster resource quotas are reconciled.")
+	flag.BoolVar(&webhookSelfManaged, "webhook-self-managed", false,
+		"Generate and rotate the serving certificate of the webhook, and register the webhook in the validating webhook configuration "+
+			"with the ca bundle, instead of the ones crafted by hand.")
+	flag.StringVar(&webhookNamespace, "webhook-service-namespace", "default",
+		"The namespace of the service of the webhook, where the secret of the webhook certificates is kept.")
+	flag.StringVar(&webhookServiceName, "webhook-service-name", "kubezoo-cluster-resource-quota", "The name of the service of the webhook.")
+	flag.StringVar(&webhookSecretName, "webhook-secret-name", "quota-webhook-pki", "The name of the secret of the webhook certificates.")
+	flag.StringVar(&webhookConfigurationName, "webhook-configuration-name", controllers.DefaultWebhookConfigurationName,
+		"The name of the validating webhook configuration registered by the webhook.")
+	flag.DurationVar(&webhookCertValidity, "webhook-cert-validity", controllers.DefaultWebhookCertValidity,
+		"The validity of the serving certificate of the webhook, which is rotated in the last fifth of the validity.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -97,16 +126,50 @@ func main() {
 	}
 
 	if err = (&controllers.ClusterResourceQuotaReconciler{
-		Client:    mgr.GetClient(),
-		Scheme:    mgr.GetScheme(),
-		Cache:     mgr.GetCache(),
-		APIReader: mgr.GetAPIReader(),
-		Logger:    mgr.GetLogger(),
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Cache:          mgr.GetCache(),
+		APIReader:      mgr.GetAPIReader(),
+		Logger:         mgr.GetLogger(),
+		ReservationTTL: reservationTTL,
+		DebouncePeriod: debouncePeriod,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClusterResourceQuota")
 		os.Exit(1)
 	}
 
+	if webhookSelfManaged {
+		if webhookCertDir == "" {
+			webhookCertDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+		}
+		// the cache of the manager is not started yet, while the serving
+		// certificate is required by the webhook server on start
+		c, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+		if err != nil {
+			setupLog.Error(err, "unable to create client")
+			os.Exit(1)
+		}
+		registration := &controllers.WebhookRegistration{
+			Client:            c,
+			Logger:            mgr.GetLogger().WithName("webhook-registration"),
+			Namespace:         webhookNamespace,
+			ServiceName:       webhookServiceName,
+			Port:              int32(webhookPort),
+			SecretName:        webhookSecretName,
+			CertDir:           webhookCertDir,
+			ConfigurationName: webhookConfigurationName,
+			CertValidity:      webhookCertValidity,
+		}
+		if err := registration.Sync(context.Background()); err != nil {
+			setupLog.Error(err, "unable to register webhook")
+			os.Exit(1)
+		}
+		if err := mgr.Add(registration); err != nil {
+			setupLog.Error(err, "unable to set up webhook registration")
+			os.Exit(1)
+		}
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
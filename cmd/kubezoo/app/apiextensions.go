@@ -254,6 +254,10 @@ func createKubeAPIExtensionsServer(apiextensionsConfig *apiextensionsapiserver.C
 		s.Informers.Start(context.StopCh)
 		return nil
 	})
+	s.GenericAPIServer.AddPostStartHookOrDie("start-crd-storage-evictor", func(context genericapiserver.PostStartHookContext) error {
+		go crdHandler.runStorageEvictor(context.StopCh)
+		return nil
+	})
 
 	// we don't want to report healthy until we can handle all CRDs that have already been registered.  Waiting for the informer
 	// to sync makes sure that the lister will be valid before we begin.  There may still be races for CRDs added after startup,
@@ -16,6 +16,7 @@ import (
 	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	batchapiv1 "k8s.io/api/batch/v1"
 	batchapiv1beta1 "k8s.io/api/batch/v1beta1"
+	certificatesv1 "k8s.io/api/certificates/v1"
 	certificatesv1beta1 "k8s.io/api/certificates/v1beta1"
 	coordinationv1 "k8s.io/api/coordination/v1"
 	coordinationv1beta1 "k8s.io/api/coordination/v1beta1"
@@ -1019,6 +1020,29 @@ var nonLegacyGroups = []common.APIGroupConfig{
 	{
 		certificatesv1beta1.GroupName,
 		map[string]map[string]*common.StorageConfig{
+			"v1": {
+				"certificatesigningrequests": {
+					Kind:            certificatesv1.SchemeGroupVersion.WithKind("CertificateSigningRequest"),
+					Resource:        "certificatesigningrequests",
+					NamespaceScoped: false,
+					NewFunc:         func() runtime.Object { return &certificates.CertificateSigningRequest{} },
+					NewListFunc:     func() runtime.Object { return &certificates.CertificateSigningRequestList{} },
+				},
+				"certificatesigningrequests/status": {
+					Kind:            certificatesv1.SchemeGroupVersion.WithKind("CertificateSigningRequest"),
+					Resource:        "certificatesigningrequests",
+					Subresource:     "status",
+					NamespaceScoped: false,
+					NewFunc:         func() runtime.Object { return &certificates.CertificateSigningRequest{} },
+				},
+				"certificatesigningrequests/approval": {
+					Kind:            certificatesv1.SchemeGroupVersion.WithKind("CertificateSigningRequest"),
+					Resource:        "certificatesigningrequests",
+					Subresource:     "approval",
+					NamespaceScoped: false,
+					NewFunc:         func() runtime.Object { return &certificates.CertificateSigningRequest{} },
+				},
+			},
 			"v1beta1": {
 				"certificatesigningrequests": {
 					Kind:            certificatesv1beta1.SchemeGroupVersion.WithKind("CertificateSigningRequest"),
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog"
+)
+
+const (
+	evictionReasonLRU  = "lru"
+	evictionReasonIdle = "idle"
+
+	// minStorageEvictionInterval bounds how often the storage map is
+	// scanned for idle storages.
+	minStorageEvictionInterval = time.Minute
+)
+
+var (
+	crdStorageCacheSize = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Namespace:      "kubezoo",
+			Subsystem:      "crd_storage",
+			Name:           "cache_size",
+			Help:           "Number of custom resource definitions whose serving storage is cached.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	crdStorageEvictions = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      "kubezoo",
+			Subsystem:      "crd_storage",
+			Name:           "evictions_total",
+			Help:           "Number of serving storages evicted from the cache, partitioned by the reason.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"reason"},
+	)
+
+	registerCRDStorageMetricsOnce sync.Once
+)
+
+// registerCRDStorageMetrics registers the metrics of the crd storage cache.
+func registerCRDStorageMetrics() {
+	registerCRDStorageMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(crdStorageCacheSize)
+		legacyregistry.MustRegister(crdStorageEvictions)
+	})
+}
+
+// touch records the storage is used at the given time.
+func (in *crdInfo) touch(now time.Time) {
+	atomic.StoreInt64(&in.lastUsed, now.UnixNano())
+}
+
+// lastUsedTime returns the time the storage was last used.
+func (in *crdInfo) lastUsedTime() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&in.lastUsed))
+}
+
+// leastRecentlyUsed returns the uid of the least recently used storage.
+func (in crdStorageMap) leastRecentlyUsed() (types.UID, bool) {
+	var (
+		lru     types.UID
+		lruTime int64
+		found   bool
+	)
+	for uid, info := range in {
+		lastUsed := atomic.LoadInt64(&info.lastUsed)
+		if !found || lastUsed < lruTime {
+			lru, lruTime, found = uid, lastUsed, true
+		}
+	}
+	return lru, found
+}
+
+// idleSince returns the uids of the storages not used since the deadline.
+func (in crdStorageMap) idleSince(deadline time.Time) []types.UID {
+	var idle []types.UID
+	for uid, info := range in {
+		if info.lastUsedTime().Before(deadline) {
+			idle = append(idle, uid)
+		}
+	}
+	return idle
+}
+
+// storeStorageMap_locked stores the storage map and updates the cache size metric.
+// NOTE: Caller MUST hold r.customStorageLock to write r.customStorage thread-safely.
+func (r *crdHandler) storeStorageMap_locked(storageMap crdStorageMap) {
+	r.customStorage.Store(storageMap)
+	crdStorageCacheSize.Set(float64(len(storageMap)))
+}
+
+// evictIdleStorage removes the storages which have not been used within
+// the idle timeout, they will be re-created on demand.
+func (r *crdHandler) evictIdleStorage() {
+	r.customStorageLock.Lock()
+	defer r.customStorageLock.Unlock()
+
+	storageMap := r.customStorage.Load().(crdStorageMap)
+	idle := storageMap.idleSince(time.Now().Add(-r.storageIdleTimeout))
+	if len(idle) == 0 {
+		return
+	}
+
+	// Copy because we cannot write to storageMap without a race
+	storageMap2 := storageMap.clone()
+	for _, uid := range idle {
+		info := storageMap2[uid]
+		klog.V(4).Infof("Evicting idle CRD storage for %s/%s", info.spec.Group, info.spec.Names.Kind)
+		delete(storageMap2, uid)
+		go r.tearDown(info)
+	}
+	r.storeStorageMap_locked(storageMap2)
+	crdStorageEvictions.WithLabelValues(evictionReasonIdle).Add(float64(len(idle)))
+}
+
+// runStorageEvictor evicts idle storages periodically until stopCh is closed.
+// Note that long-running requests, e.g. watches, don't count as usage, so
+// the watches of an idle storage are terminated and expected to be
+// re-established by the clients.
+func (r *crdHandler) runStorageEvictor(stopCh <-chan struct{}) {
+	if r.storageIdleTimeout <= 0 {
+		return
+	}
+	interval := r.storageIdleTimeout / 2
+	if interval < minStorageEvictionInterval {
+		interval = minStorageEvictionInterval
+	}
+	wait.Until(r.evictIdleStorage, interval, stopCh)
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	goruntime "runtime"
+	"testing"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	extensionsapiserver "k8s.io/apiextensions-apiserver/pkg/apiserver"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/conversion"
+	listers "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/registry/customresource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilwaitgroup "k8s.io/apimachinery/pkg/util/waitgroup"
+	"k8s.io/apiserver/pkg/authentication/user"
+	openapinamer "k8s.io/apiserver/pkg/endpoints/openapi"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/generic"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/client-go/tools/cache"
+	openapibuilder "k8s.io/kube-openapi/pkg/builder"
+	"k8s.io/utils/pointer"
+
+	generatedopenapi "github.com/kubewharf/kubezoo/pkg/apis/openapi"
+)
+
+// newFakeStorageMap returns a storage map with n entries, the i-th entry
+// is last used at base+i seconds.
+func newFakeStorageMap(n int, base time.Time) crdStorageMap {
+	storageMap := make(crdStorageMap, n)
+	for i := 0; i < n; i++ {
+		info := &crdInfo{
+			spec: &apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: fmt.Sprintf("111111-group%d.com", i),
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Foo"},
+			},
+			storages:  map[string]customresource.CustomResourceStorage{},
+			waitGroup: &utilwaitgroup.SafeWaitGroup{},
+		}
+		info.touch(base.Add(time.Duration(i) * time.Second))
+		storageMap[types.UID(fmt.Sprintf("uid-%d", i))] = info
+	}
+	return storageMap
+}
+
+// TestCRDStorageMapLeastRecentlyUsed tests the least recently used storage
+// is picked for eviction.
+func TestCRDStorageMapLeastRecentlyUsed(t *testing.T) {
+	if _, ok := (crdStorageMap{}).leastRecentlyUsed(); ok {
+		t.Errorf("expect no storage in empty map")
+	}
+
+	storageMap := newFakeStorageMap(3, time.Now())
+	uid, ok := storageMap.leastRecentlyUsed()
+	if !ok || uid != "uid-0" {
+		t.Errorf("expect uid-0 to be least recently used, got %q", uid)
+	}
+
+	storageMap["uid-0"].touch(time.Now().Add(time.Hour))
+	uid, ok = storageMap.leastRecentlyUsed()
+	if !ok || uid != "uid-1" {
+		t.Errorf("expect uid-1 to be least recently used, got %q", uid)
+	}
+}
+
+// TestCRDHandlerEvictIdleStorage tests the storages not used within the
+// idle timeout are evicted.
+func TestCRDHandlerEvictIdleStorage(t *testing.T) {
+	now := time.Now()
+	r := &crdHandler{
+		storageIdleTimeout: time.Minute,
+		requestTimeout:     time.Second,
+	}
+	// uid-0 and uid-1 are idle for more than a minute
+	r.customStorage.Store(newFakeStorageMap(4, now.Add(-61500*time.Millisecond)))
+
+	r.evictIdleStorage()
+
+	storageMap := r.customStorage.Load().(crdStorageMap)
+	if len(storageMap) != 2 {
+		t.Fatalf("expect 2 storages left, got %d", len(storageMap))
+	}
+	for _, uid := range []types.UID{"uid-2", "uid-3"} {
+		if _, ok := storageMap[uid]; !ok {
+			t.Errorf("expect storage %s to be kept", uid)
+		}
+	}
+}
+
+// BenchmarkCRDStorageMapHit measures the cost of serving a request from a
+// cached storage, which only records the usage time.
+func BenchmarkCRDStorageMapHit(b *testing.B) {
+	storageMap := newFakeStorageMap(1000, time.Now())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if info, ok := storageMap["uid-500"]; ok {
+			info.touch(time.Now())
+		}
+	}
+}
+
+// BenchmarkCRDStorageMapEvictLRU measures the cost of evicting the least
+// recently used storage from a full cache of the default size, which is
+// paid once for every storage created after the cache is full.
+func BenchmarkCRDStorageMapEvictLRU(b *testing.B) {
+	storageMap := newFakeStorageMap(1000, time.Now())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		storageMap2 := storageMap.clone()
+		if uid, ok := storageMap2.leastRecentlyUsed(); ok {
+			delete(storageMap2, uid)
+		}
+	}
+}
+
+// fakeRESTOptionsGetter returns the options of a storage which never
+// reaches etcd, so that only the serving state of kubezoo is measured.
+type fakeRESTOptionsGetter struct{}
+
+func (fakeRESTOptionsGetter) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	return generic.RESTOptions{
+		StorageConfig: &storagebackend.ConfigForResource{
+			Config:        storagebackend.Config{Codec: unstructured.UnstructuredJSONScheme},
+			GroupResource: resource,
+		},
+		Decorator: func(*storagebackend.ConfigForResource, string, func(runtime.Object) (string, error),
+			func() runtime.Object, func() runtime.Object, storage.AttrFunc, storage.IndexerFuncs,
+			*cache.Indexers) (storage.Interface, factory.DestroyFunc, error) {
+			return nil, func() {}, nil
+		},
+		ResourcePrefix: resource.Group + "/" + resource.Resource,
+	}, nil
+}
+
+// newBenchmarkCRD returns the i-th custom resource definition of the
+// tenant, with a schema, the status and scale subresources and a printer
+// column, as the upstream one.
+func newBenchmarkCRD(tenantID string, i int) *apiextensionsv1.CustomResourceDefinition {
+	group := fmt.Sprintf("%s-group%d.com", tenantID, i)
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foos." + group,
+			UID:  types.UID(fmt.Sprintf("uid-%d", i)),
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group:      group,
+			Names:      apiextensionsv1.CustomResourceDefinitionNames{Plural: "foos", Singular: "foo", Kind: "Foo", ListKind: "FooList"},
+			Scope:      apiextensionsv1.NamespaceScoped,
+			Conversion: &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    "v1",
+				Served:  true,
+				Storage: true,
+				Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"spec": {
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"replicas": {Type: "integer"},
+									"image":    {Type: "string"},
+									"args":     {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}}},
+								},
+							},
+							"status": {
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"replicas": {Type: "integer"},
+									"selector": {Type: "string"},
+								},
+							},
+						},
+					},
+				},
+				Subresources: &apiextensionsv1.CustomResourceSubresources{
+					Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+					Scale: &apiextensionsv1.CustomResourceSubresourceScale{
+						SpecReplicasPath:   ".spec.replicas",
+						StatusReplicasPath: ".status.replicas",
+						LabelSelectorPath:  pointer.String(".status.selector"),
+					},
+				},
+				AdditionalPrinterColumns: []apiextensionsv1.CustomResourceColumnDefinition{
+					{Name: "Image", Type: "string", JSONPath: ".spec.image"},
+				},
+			}},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{
+			AcceptedNames: apiextensionsv1.CustomResourceDefinitionNames{Plural: "foos", Singular: "foo", Kind: "Foo", ListKind: "FooList"},
+		},
+	}
+}
+
+// BenchmarkCRDStorageMemory measures the heap retained by the serving
+// storage of a custom resource definition, built against the static
+// openapi spec of the apiextensions group as the server does. It is where
+// the default of --max-cached-crd-storages comes from: each storage keeps
+// about 135KiB alive after about 7MB of allocations to build it, so that
+// 1000 of them bound the cache to about 130MiB, while rebuilding an
+// evicted storage stays rare for the tenants of a proxy.
+func BenchmarkCRDStorageMemory(b *testing.B) {
+	tenantID := "111111"
+	openAPIConfig := genericapiserver.DefaultOpenAPIConfig(generatedopenapi.GetOpenAPIDefinitions,
+		openapinamer.NewDefinitionNamer(extensionsapiserver.Scheme))
+	openAPIConfig.Info.Title = "Kubernetes"
+	openAPIConfig.Info.Version = "v1.24.0"
+	staticOpenAPISpec, err := openapibuilder.BuildOpenAPIDefinitionsForResources(openAPIConfig,
+		"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1.CustomResourceDefinition")
+	if err != nil {
+		b.Fatal(err)
+	}
+	converterFactory, err := conversion.NewCRConverterFactory(nil, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for i := 0; i < b.N; i++ {
+		if err := indexer.Add(newBenchmarkCRD(tenantID, i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+	r := &crdHandler{
+		crdLister:         listers.NewCustomResourceDefinitionLister(indexer),
+		restOptionsGetter: fakeRESTOptionsGetter{},
+		converterFactory:  converterFactory,
+		staticOpenAPISpec: staticOpenAPISpec,
+	}
+	r.customStorage.Store(crdStorageMap{})
+	ctx := request.WithUser(context.Background(), &user.DefaultInfo{
+		Name:  "admin",
+		Extra: map[string][]string{"tenant": {tenantID}},
+	})
+
+	var before, after goruntime.MemStats
+	goruntime.GC()
+	goruntime.ReadMemStats(&before)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.getOrCreateServingInfoFor(ctx, types.UID(fmt.Sprintf("uid-%d", i)), fmt.Sprintf("foos.group%d.com", i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	goruntime.GC()
+	goruntime.ReadMemStats(&after)
+	b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/float64(b.N), "B/storage")
+	goruntime.KeepAlive(r)
+}
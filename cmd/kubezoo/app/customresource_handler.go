@@ -133,11 +133,24 @@ type crdHandler struct {
 	// 0 means no limit.
 	maxRequestBodyBytes int64
 
+	// maxCachedStorages is the max number of crdInfo entries kept in the
+	// storage map, the least recently used one is evicted when exceeded.
+	// 0 means no limit.
+	maxCachedStorages int
+
+	// storageIdleTimeout is the duration after which an unused crdInfo
+	// entry is evicted from the storage map. 0 means never.
+	storageIdleTimeout time.Duration
+
 	upstreamConfig *ProxyConfig
 }
 
 // crdInfo stores enough information to serve the storage for the custom resource
 type crdInfo struct {
+	// lastUsed is the unix nano time the storage was last used, it is
+	// accessed atomically and kept first for 64-bit alignment.
+	lastUsed int64
+
 	// spec and acceptedNames are used to compare against if a change is made on a CRD. We only update
 	// the storage if one of these changes.
 	spec          *apiextensionsv1.CustomResourceDefinitionSpec
@@ -197,6 +210,8 @@ func NewCustomResourceDefinitionHandler(
 		minRequestTimeout:   minRequestTimeout,
 		staticOpenAPISpec:   staticOpenAPISpec,
 		maxRequestBodyBytes: maxRequestBodyBytes,
+		maxCachedStorages:   upstreamConfig.maxCachedCRDStorages,
+		storageIdleTimeout:  upstreamConfig.crdStorageIdleTimeout,
 		upstreamConfig:      upstreamConfig,
 	}
 	crdInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -213,6 +228,7 @@ func NewCustomResourceDefinitionHandler(
 	ret.converterFactory = crConverterFactory
 
 	ret.customStorage.Store(crdStorageMap{})
+	registerCRDStorageMetrics()
 
 	return ret, nil
 }
@@ -528,7 +544,7 @@ func (r *crdHandler) removeStorage_locked(uid types.UID) {
 
 		// Remove from the CRD info map and store the map
 		delete(storageMap2, uid)
-		r.customStorage.Store(storageMap2)
+		r.storeStorageMap_locked(storageMap2)
 
 		// Tear down the old storage
 		go r.tearDown(oldInfo)
@@ -554,7 +570,7 @@ func (r *crdHandler) removeDeadStorage() {
 			storageMap2[crd.UID] = storageMap[crd.UID]
 		}
 	}
-	r.customStorage.Store(storageMap2)
+	r.storeStorageMap_locked(storageMap2)
 
 	for uid, crdInfo := range storageMap {
 		if _, ok := storageMap2[uid]; !ok {
@@ -602,6 +618,7 @@ func (r *crdHandler) GetCustomResourceListerCollectionDeleter(crd *apiextensions
 func (r *crdHandler) getOrCreateServingInfoFor(ctx context.Context, uid types.UID, name string) (*crdInfo, error) {
 	storageMap := r.customStorage.Load().(crdStorageMap)
 	if ret, ok := storageMap[uid]; ok {
+		ret.touch(time.Now())
 		return ret, nil
 	}
 
@@ -618,6 +635,7 @@ func (r *crdHandler) getOrCreateServingInfoFor(ctx context.Context, uid types.UI
 	}
 	storageMap = r.customStorage.Load().(crdStorageMap)
 	if ret, ok := storageMap[crd.UID]; ok {
+		ret.touch(time.Now())
 		return ret, nil
 	}
 
@@ -961,13 +979,26 @@ func (r *crdHandler) getOrCreateServingInfoFor(ctx context.Context, uid types.UI
 		storageVersion:      storageVersion,
 		waitGroup:           &utilwaitgroup.SafeWaitGroup{},
 	}
+	ret.touch(time.Now())
 
 	// Copy because we cannot write to storageMap without a race
 	// as it is used without locking elsewhere.
 	storageMap2 := storageMap.clone()
 
+	// make room for the new storage by evicting the least recently used ones
+	for r.maxCachedStorages > 0 && len(storageMap2) >= r.maxCachedStorages {
+		uid, ok := storageMap2.leastRecentlyUsed()
+		if !ok {
+			break
+		}
+		klog.V(4).Infof("Evicting least recently used CRD storage for %s/%s", storageMap2[uid].spec.Group, storageMap2[uid].spec.Names.Kind)
+		go r.tearDown(storageMap2[uid])
+		delete(storageMap2, uid)
+		crdStorageEvictions.WithLabelValues(evictionReasonLRU).Inc()
+	}
+
 	storageMap2[crd.UID] = ret
-	r.customStorage.Store(storageMap2)
+	r.storeStorageMap_locked(storageMap2)
 
 	return ret, nil
 }
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// TenantAuditOptions configures the audit backends of the tenants, which
+// receive the audit events of the tenants as seen inside the tenants. The
+// events are audited by the audit policy declared on the tenant, or by the
+// --audit-policy-file if the tenant declares none.
+type TenantAuditOptions struct {
+	LogDir            string
+	LogMaxSize        int
+	LogMaxBackups     int
+	WebhookConfigFile string
+}
+
+// NewTenantAuditOptions creates a new TenantAuditOptions object
+func NewTenantAuditOptions() *TenantAuditOptions {
+	return &TenantAuditOptions{
+		LogMaxSize:    100,
+		LogMaxBackups: 10,
+	}
+}
+
+func (o *TenantAuditOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+	fs.StringVar(&o.LogDir, "tenant-audit-log-dir", o.LogDir, "If set, the audit events of each tenant are written into "+
+		"<dir>/<tenant>.log in JSON, which can be downloaded by the tenant admin from /kubezoo/audit/events.")
+	fs.IntVar(&o.LogMaxSize, "tenant-audit-log-maxsize", o.LogMaxSize, "The maximum size in megabytes of the audit log "+
+		"file of a tenant before it gets rotated.")
+	fs.IntVar(&o.LogMaxBackups, "tenant-audit-log-maxbackup", o.LogMaxBackups, "The maximum number of rotated audit log "+
+		"files to retain for a tenant.")
+	fs.StringVar(&o.WebhookConfigFile, "tenant-audit-webhook-config-file", o.WebhookConfigFile, "Path to a kubeconfig "+
+		"formatted file that defines the audit webhook receiving the audit events of the tenants, as seen inside the "+
+		"tenants and annotated with kubezoo.io/tenant.")
+}
+
+func (o *TenantAuditOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	errors := []error{}
+
+	if o.LogMaxSize < 0 {
+		errors = append(errors, fmt.Errorf("--tenant-audit-log-maxsize %v cannot be negative", o.LogMaxSize))
+	}
+	if o.LogMaxBackups < 0 {
+		errors = append(errors, fmt.Errorf("--tenant-audit-log-maxbackup %v cannot be negative", o.LogMaxBackups))
+	}
+	return errors
+}
+
+// Enabled returns whether any audit backend of the tenants is configured.
+func (o *TenantAuditOptions) Enabled() bool {
+	return o != nil && (len(o.LogDir) != 0 || len(o.WebhookConfigFile) != 0)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/pflag"
+	cliflag "k8s.io/component-base/cli/flag"
+
+	"github.com/kubewharf/kubezoo/pkg/authentication"
+)
+
+// TenantOIDCOptions configures the OIDC token authentication of the tenant
+// users, whose tenant is taken from a claim of the ID token. It is separated
+// from the built-in --oidc-* flags, whose users belong to no tenant.
+type TenantOIDCOptions struct {
+	IssuerURL      string
+	ClientID       string
+	CAFile         string
+	UsernameClaim  string
+	UsernamePrefix string
+	TenantClaim    string
+	TenantPrefix   string
+	SigningAlgs    []string
+	RequiredClaims map[string]string
+}
+
+// NewTenantOIDCOptions creates a new TenantOIDCOptions object
+func NewTenantOIDCOptions() *TenantOIDCOptions {
+	return &TenantOIDCOptions{
+		UsernameClaim:  "sub",
+		UsernamePrefix: "oidc:",
+		TenantClaim:    "groups",
+		SigningAlgs:    []string{"RS256"},
+	}
+}
+
+func (o *TenantOIDCOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+	fs.StringVar(&o.IssuerURL, "tenant-oidc-issuer-url", o.IssuerURL, "The URL of the OpenID issuer of the tenant users, only HTTPS "+
+		"scheme will be accepted. If set, it will be used to verify the OIDC JSON Web Token (JWT) and map the user to a tenant.")
+	fs.StringVar(&o.ClientID, "tenant-oidc-client-id", o.ClientID, "The client ID for the OpenID Connect client of the tenant users, "+
+		"must be set if tenant-oidc-issuer-url is set.")
+	fs.StringVar(&o.CAFile, "tenant-oidc-ca-file", o.CAFile, "If set, the OpenID server's certificate will be verified by one of "+
+		"the authorities in the tenant-oidc-ca-file, otherwise the host's root CA set will be used.")
+	fs.StringVar(&o.UsernameClaim, "tenant-oidc-username-claim", o.UsernameClaim, "The OpenID claim to use as the user name of the tenant user.")
+	fs.StringVar(&o.UsernamePrefix, "tenant-oidc-username-prefix", o.UsernamePrefix, "The prefix prepended to the user name claim, "+
+		"which keeps the OIDC users apart from the users whose certificates are issued by kubezoo.")
+	fs.StringVar(&o.TenantClaim, "tenant-oidc-tenant-claim", o.TenantClaim, "The OpenID claim, a string or an array of strings, "+
+		"holding the tenant ID of the user. The token is rejected unless exactly one existing tenant is found in the claim.")
+	fs.StringVar(&o.TenantPrefix, "tenant-oidc-tenant-prefix", o.TenantPrefix, "If set, only the values of the tenant claim "+
+		"with the prefix are taken as tenant IDs after the prefix is trimmed, e.g. 'kubezoo:' for the groups claim.")
+	fs.StringSliceVar(&o.SigningAlgs, "tenant-oidc-signing-algs", o.SigningAlgs, "Comma-separated list of allowed JOSE "+
+		"asymmetric signing algorithms of the tenant OIDC tokens.")
+	fs.Var(cliflag.NewMapStringStringNoSplit(&o.RequiredClaims), "tenant-oidc-required-claim", "A key=value pair that describes a "+
+		"required claim in the ID Token. Repeat this flag to specify multiple claims.")
+}
+
+func (o *TenantOIDCOptions) Validate() []error {
+	if o == nil || len(o.IssuerURL) == 0 {
+		return nil
+	}
+
+	errors := []error{}
+
+	if u, err := url.Parse(o.IssuerURL); err != nil || u.Scheme != "https" {
+		errors = append(errors, fmt.Errorf("--tenant-oidc-issuer-url %q must be a valid https URL", o.IssuerURL))
+	}
+	if len(o.ClientID) == 0 {
+		errors = append(errors, fmt.Errorf("--tenant-oidc-client-id must be set if --tenant-oidc-issuer-url is set"))
+	}
+	if len(o.UsernameClaim) == 0 {
+		errors = append(errors, fmt.Errorf("--tenant-oidc-username-claim cannot be empty"))
+	}
+	if len(o.TenantClaim) == 0 {
+		errors = append(errors, fmt.Errorf("--tenant-oidc-tenant-claim cannot be empty"))
+	}
+	return errors
+}
+
+// ToOIDCConfig returns the config of the tenant OIDC authenticator, nil if
+// the authentication is not enabled.
+func (o *TenantOIDCOptions) ToOIDCConfig() *authentication.OIDCConfig {
+	if o == nil || len(o.IssuerURL) == 0 {
+		return nil
+	}
+	return &authentication.OIDCConfig{
+		IssuerURL:      o.IssuerURL,
+		ClientID:       o.ClientID,
+		CAFile:         o.CAFile,
+		UsernameClaim:  o.UsernameClaim,
+		UsernamePrefix: o.UsernamePrefix,
+		TenantClaim:    o.TenantClaim,
+		TenantPrefix:   o.TenantPrefix,
+		SigningAlgs:    o.SigningAlgs,
+		RequiredClaims: o.RequiredClaims,
+	}
+}
@@ -49,6 +49,10 @@ type ServerRunOptions struct {
 	APIEnablement             *genericoptions.APIEnablementOptions
 	EgressSelector            *genericoptions.EgressSelectorOptions
 	Proxy                     *ProxyOptions
+	TenantOIDC                *TenantOIDCOptions
+	TenantWebhook             *TenantWebhookOptions
+	TenantServiceAccount      *TenantServiceAccountOptions
+	TenantAudit               *TenantAuditOptions
 	AllowPrivileged           bool
 	EnableLogsHandler         bool
 	EventTTL                  time.Duration
@@ -79,6 +83,10 @@ type ServerRunOptions struct {
 	ServiceAccountTokenMaxExpiration time.Duration
 
 	ShowHiddenMetricsForVersion string
+
+	// AllowUnboundTenantAdminCertificates accepts the client certificates of
+	// the tenant admins not bound to the tenant UID, for the migration only.
+	AllowUnboundTenantAdminCertificates bool
 }
 
 // NewServerRunOptions creates a new ServerRunOptions object with default parameters
@@ -96,6 +104,10 @@ func NewServerRunOptions() *ServerRunOptions {
 		APIEnablement:           genericoptions.NewAPIEnablementOptions(),
 		EgressSelector:          genericoptions.NewEgressSelectorOptions(),
 		Proxy:                   NewProxyOptions(),
+		TenantOIDC:              NewTenantOIDCOptions(),
+		TenantWebhook:           NewTenantWebhookOptions(),
+		TenantServiceAccount:    NewTenantServiceAccountOptions(),
+		TenantAudit:             NewTenantAuditOptions(),
 		EnableLogsHandler:       true,
 		EventTTL:                1 * time.Hour,
 		MasterCount:             1,
@@ -133,6 +145,7 @@ func (s *ServerRunOptions) Flags() (fss cliflag.NamedFlagSets) {
 	s.Etcd.AddFlags(fss.FlagSet("etcd"))
 	s.SecureServing.AddFlags(fss.FlagSet("secure serving"))
 	s.Audit.AddFlags(fss.FlagSet("auditing"))
+	s.TenantAudit.AddFlags(fss.FlagSet("auditing"))
 	s.Features.AddFlags(fss.FlagSet("features"))
 	s.Authentication.AddFlags(fss.FlagSet("authentication"))
 	s.Authorization.AddFlags(fss.FlagSet("authorization"))
@@ -143,6 +156,9 @@ func (s *ServerRunOptions) Flags() (fss cliflag.NamedFlagSets) {
 
 	// Add kubezoo proxy flags
 	s.Proxy.AddFlags(fss.FlagSet("proxy"))
+	s.TenantOIDC.AddFlags(fss.FlagSet("tenant authentication"))
+	s.TenantWebhook.AddFlags(fss.FlagSet("tenant authentication"))
+	s.TenantServiceAccount.AddFlags(fss.FlagSet("tenant authentication"))
 
 	mfs := fss.FlagSet("metrics")
 	mfs.StringVar(&s.ShowHiddenMetricsForVersion, "show-hidden-metrics-for-version", s.ShowHiddenMetricsForVersion,
@@ -161,6 +177,11 @@ func (s *ServerRunOptions) Flags() (fss cliflag.NamedFlagSets) {
 	fs.BoolVar(&s.AllowPrivileged, "allow-privileged", s.AllowPrivileged,
 		"If true, allow privileged containers. [default=false]")
 
+	fs.BoolVar(&s.AllowUnboundTenantAdminCertificates, "allow-unbound-tenant-admin-certificates", s.AllowUnboundTenantAdminCertificates, ""+
+		"If true, accept the client certificates of the tenant admins which are not bound to the tenant UID, "+
+		"i.e. issued before the binding is introduced. The tenant controller reissues them in the tenant "+
+		"kubeconfig annotation, so this is meant for the migration only. [default=false]")
+
 	fs.BoolVar(&s.EnableLogsHandler, "enable-logs-handler", s.EnableLogsHandler,
 		"If true, install a /logs handler for the apiserver logs.")
 	fs.MarkDeprecated("enable-logs-handler", "This flag will be removed in v1.19")
@@ -1,9 +1,27 @@
 package options
 
 import (
+	"crypto/tls"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/spf13/pflag"
+	certutil "k8s.io/client-go/util/cert"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+const (
+	// ClientCASignerFile signs the tenant certificates with the key in
+	// --client-ca-key-file.
+	ClientCASignerFile = "file"
+	// ClientCASignerExec signs the tenant certificates by running the
+	// --client-ca-signer-command.
+	ClientCASignerExec = "exec"
+	// ClientCASignerHTTP signs the tenant certificates by calling the
+	// --client-ca-signer-url.
+	ClientCASignerHTTP = "http"
 )
 
 // ProxyOptions runs a kubezoo proxy server
@@ -22,6 +40,36 @@ type ProxyOptions struct {
 	ServiceAccountKeyFile string
 	BindAddress           string
 	SecurePort            int
+
+	// MaxCachedCRDStorages is the max number of custom resource definitions
+	// whose serving storage is cached, 0 means no limit. A storage retains
+	// about 135KiB of heap, see BenchmarkCRDStorageMemory, so the default
+	// of 1000 bounds the cache to about 130MiB.
+	MaxCachedCRDStorages int
+	// CRDStorageIdleTimeout is the duration after which the serving storage
+	// of an unused custom resource definition is evicted, 0 means never.
+	CRDStorageIdleTimeout time.Duration
+
+	// TenantCSRSigningDuration is the max validity of the certificates
+	// issued for the certificate signing requests of the tenants.
+	TenantCSRSigningDuration time.Duration
+
+	// ClientCASigner is the backend holding the key of the client CA, i.e.
+	// file, exec or http.
+	ClientCASigner               string
+	ClientCASignerCommand        string
+	ClientCASignerArgs           []string
+	ClientCASignerURL            string
+	ClientCASignerCAFile         string
+	ClientCASignerClientCertFile string
+	ClientCASignerClientKeyFile  string
+	ClientCASignerTimeout        time.Duration
+	// TenantCertKeyAlgorithm is the algorithm of the keys generated for the
+	// certificates of the tenant admins and named users.
+	TenantCertKeyAlgorithm string
+	// TenantCertValidity is the validity of the certificates of the tenant
+	// admins and named users, which are renewed before expiry.
+	TenantCertValidity time.Duration
 }
 
 // NewProxyOptions creates a new ProxyOptions object
@@ -30,6 +78,16 @@ func NewProxyOptions() *ProxyOptions {
 		ProxyClientQPS:   1000,
 		ProxyClientBurst: 2000,
 		SecurePort:       6443,
+
+		MaxCachedCRDStorages:  1000,
+		CRDStorageIdleTimeout: 30 * time.Minute,
+
+		TenantCSRSigningDuration: 30 * 24 * time.Hour,
+
+		ClientCASigner:         ClientCASignerFile,
+		ClientCASignerTimeout:  10 * time.Second,
+		TenantCertKeyAlgorithm: string(util.KeyAlgorithmRSA),
+		TenantCertValidity:     util.CertificateValidity,
 	}
 }
 
@@ -50,6 +108,34 @@ func (o *ProxyOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.BindAddress, "proxy-bind-address", o.BindAddress, "The server address of the tenants' kubeconfig file, N.B. this address should be a valid server address of the client-ca-file.")
 	fs.IntVar(&o.SecurePort, "proxy-secure-port", o.SecurePort, "The port on which the kubezoo used to serve HTTPS with authentication and authorization.")
 	fs.StringVar(&o.ClientCAKeyFile, "client-ca-key-file", o.ClientCAKeyFile, "Filename containing a PEM-encoded RSA or ECDSA private key used to sign tenant certificates.")
+	fs.IntVar(&o.MaxCachedCRDStorages, "max-cached-crd-storages", o.MaxCachedCRDStorages, "The max number of custom resource definitions "+
+		"whose serving storage is cached, the least recently used one is evicted when exceeded. Each storage retains about 135KiB "+
+		"of memory. 0 means no limit.")
+	fs.DurationVar(&o.CRDStorageIdleTimeout, "crd-storage-idle-timeout", o.CRDStorageIdleTimeout, "The duration after which the serving storage "+
+		"of an unused custom resource definition is evicted. 0 means never.")
+	fs.DurationVar(&o.TenantCSRSigningDuration, "tenant-csr-signing-duration", o.TenantCSRSigningDuration, "The max validity of "+
+		"the certificates issued for the approved certificate signing requests of the kubezoo.io/tenant-client signer, "+
+		"which can only be shortened by the expirationSeconds of the requests.")
+	fs.StringVar(&o.ClientCASigner, "client-ca-signer", o.ClientCASigner, "The backend signing the tenant certificates with "+
+		"the key of the client CA, one of file, exec and http. The key never leaves the external signer with exec and http, "+
+		"which only sign the digests of the certificates.")
+	fs.StringVar(&o.ClientCASignerCommand, "client-ca-signer-command", o.ClientCASignerCommand, "The command run by the exec "+
+		"signer, e.g. a PKCS #11 helper, which reads a JSON request {\"hash\", \"digest\"} from stdin and writes a JSON response "+
+		"{\"signature\"} to stdout, with the bytes encoded in base64.")
+	fs.StringSliceVar(&o.ClientCASignerArgs, "client-ca-signer-args", o.ClientCASignerArgs, "The arguments of --client-ca-signer-command.")
+	fs.StringVar(&o.ClientCASignerURL, "client-ca-signer-url", o.ClientCASignerURL, "The URL of the http signer, to which the "+
+		"same JSON request as the exec signer is posted.")
+	fs.StringVar(&o.ClientCASignerCAFile, "client-ca-signer-ca-file", o.ClientCASignerCAFile, "The CA file to verify the http signer.")
+	fs.StringVar(&o.ClientCASignerClientCertFile, "client-ca-signer-client-cert-file", o.ClientCASignerClientCertFile,
+		"The client certificate file to authenticate to the http signer.")
+	fs.StringVar(&o.ClientCASignerClientKeyFile, "client-ca-signer-client-key-file", o.ClientCASignerClientKeyFile,
+		"The client key file to authenticate to the http signer.")
+	fs.DurationVar(&o.ClientCASignerTimeout, "client-ca-signer-timeout", o.ClientCASignerTimeout, "The timeout of each signing "+
+		"by the exec or http signer.")
+	fs.StringVar(&o.TenantCertKeyAlgorithm, "tenant-cert-key-algorithm", o.TenantCertKeyAlgorithm, "The algorithm of the keys "+
+		"generated for the certificates of the tenant admins and named users, RSA or ECDSA.")
+	fs.DurationVar(&o.TenantCertValidity, "tenant-cert-validity", o.TenantCertValidity, "The validity of the certificates of the "+
+		"tenant admins and named users. The certificates are renewed in the last fifth of the validity.")
 	return
 }
 
@@ -69,8 +155,34 @@ func (o *ProxyOptions) Validate() []error {
 	if len(o.ProxyClientCertFile) == 0 {
 		errors = append(errors, fmt.Errorf("--proxy-client-cert-file cannot be empty"))
 	}
-	if len(o.ClientCAKeyFile) == 0 {
-		errors = append(errors, fmt.Errorf("--client-ca-key-file cannot be empty"))
+	switch o.ClientCASigner {
+	case ClientCASignerFile:
+		if len(o.ClientCAKeyFile) == 0 {
+			errors = append(errors, fmt.Errorf("--client-ca-key-file cannot be empty"))
+		}
+	case ClientCASignerExec:
+		if len(o.ClientCASignerCommand) == 0 {
+			errors = append(errors, fmt.Errorf("--client-ca-signer-command cannot be empty with the exec signer"))
+		}
+	case ClientCASignerHTTP:
+		if len(o.ClientCASignerURL) == 0 {
+			errors = append(errors, fmt.Errorf("--client-ca-signer-url cannot be empty with the http signer"))
+		}
+		if (len(o.ClientCASignerClientCertFile) == 0) != (len(o.ClientCASignerClientKeyFile) == 0) {
+			errors = append(errors, fmt.Errorf("--client-ca-signer-client-cert-file and --client-ca-signer-client-key-file must be specified together"))
+		}
+	default:
+		errors = append(errors, fmt.Errorf("--client-ca-signer %q must be one of %s, %s and %s", o.ClientCASigner,
+			ClientCASignerFile, ClientCASignerExec, ClientCASignerHTTP))
+	}
+	if o.ClientCASignerTimeout < 0 {
+		errors = append(errors, fmt.Errorf("--client-ca-signer-timeout %v cannot be negative", o.ClientCASignerTimeout))
+	}
+	if _, err := util.NewPrivateKeyWithAlgorithm(util.KeyAlgorithm(o.TenantCertKeyAlgorithm)); err != nil {
+		errors = append(errors, fmt.Errorf("--tenant-cert-key-algorithm %q must be RSA or ECDSA", o.TenantCertKeyAlgorithm))
+	}
+	if o.TenantCertValidity <= 0 {
+		errors = append(errors, fmt.Errorf("--tenant-cert-validity %v must be positive", o.TenantCertValidity))
 	}
 	if len(o.ClientCAFile) == 0 {
 		errors = append(errors, fmt.Errorf("--client-ca-file cannot be empty"))
@@ -84,5 +196,46 @@ func (o *ProxyOptions) Validate() []error {
 	if o.SecurePort < 1 || o.SecurePort > 65535 {
 		errors = append(errors, fmt.Errorf("--proxy-secure-port %v must be between 1 and 65535, inclusive. It cannot be turned off with 0", o.SecurePort))
 	}
+	if o.MaxCachedCRDStorages < 0 {
+		errors = append(errors, fmt.Errorf("--max-cached-crd-storages %v cannot be negative", o.MaxCachedCRDStorages))
+	}
+	if o.CRDStorageIdleTimeout < 0 {
+		errors = append(errors, fmt.Errorf("--crd-storage-idle-timeout %v cannot be negative", o.CRDStorageIdleTimeout))
+	}
+	if o.TenantCSRSigningDuration <= 0 {
+		errors = append(errors, fmt.Errorf("--tenant-csr-signing-duration %v must be positive", o.TenantCSRSigningDuration))
+	}
 	return errors
 }
+
+// NewClientCASigner returns the signer of the client CA configured by the
+// options.
+func (o *ProxyOptions) NewClientCASigner() (util.CASigner, error) {
+	switch o.ClientCASigner {
+	case ClientCASignerExec:
+		return util.NewExecCASigner(o.ClientCAFile, o.ClientCASignerCommand, o.ClientCASignerArgs, o.ClientCASignerTimeout), nil
+	case ClientCASignerHTTP:
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if len(o.ClientCASignerCAFile) != 0 {
+			pool, err := certutil.NewPool(o.ClientCASignerCAFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = pool
+		}
+		if len(o.ClientCASignerClientCertFile) != 0 {
+			cert, err := tls.LoadX509KeyPair(o.ClientCASignerClientCertFile, o.ClientCASignerClientKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		client := &http.Client{Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		}}
+		return util.NewHTTPCASigner(o.ClientCAFile, o.ClientCASignerURL, client, o.ClientCASignerTimeout), nil
+	default:
+		return util.NewFileCASigner(o.ClientCAFile, o.ClientCAKeyFile), nil
+	}
+}
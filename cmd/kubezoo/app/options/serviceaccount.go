@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/util/keyutil"
+
+	"github.com/kubewharf/kubezoo/pkg/authentication"
+)
+
+// TenantServiceAccountOptions configures kubezoo as the issuer of the
+// service account tokens of the tenants. It is separated from the built-in
+// --service-account-* flags, which configure the tokens of kubezoo itself.
+type TenantServiceAccountOptions struct {
+	Issuer         string
+	SigningKeyFile string
+	KeyFiles       []string
+	MaxExpiration  time.Duration
+}
+
+// NewTenantServiceAccountOptions creates a new TenantServiceAccountOptions object
+func NewTenantServiceAccountOptions() *TenantServiceAccountOptions {
+	return &TenantServiceAccountOptions{
+		MaxExpiration: 24 * time.Hour,
+	}
+}
+
+func (o *TenantServiceAccountOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+	fs.StringVar(&o.Issuer, "tenant-service-account-issuer", o.Issuer, "The https URL of kubezoo as the issuer of the service "+
+		"account tokens of the tenants. If set, serviceaccounts/token is served by kubezoo, and the issuer of each tenant is "+
+		"<issuer>/tenants/<tenant>, whose OpenID discovery document is served anonymously.")
+	fs.StringVar(&o.SigningKeyFile, "tenant-service-account-signing-key-file", o.SigningKeyFile, "The PEM encoded RSA or ECDSA "+
+		"private key to sign the service account tokens of the tenants, must be set if tenant-service-account-issuer is set.")
+	fs.StringSliceVar(&o.KeyFiles, "tenant-service-account-key-file", o.KeyFiles, "The PEM encoded public keys to verify "+
+		"the service account tokens of the tenants besides the signing key, e.g. the retired signing keys. Repeat this flag "+
+		"to specify multiple files.")
+	fs.DurationVar(&o.MaxExpiration, "tenant-service-account-max-token-expiration", o.MaxExpiration, "The max expiration "+
+		"of the service account tokens of the tenants, the requests with longer expirations are shortened, 0 means no limit.")
+}
+
+func (o *TenantServiceAccountOptions) Validate() []error {
+	if o == nil || len(o.Issuer) == 0 {
+		return nil
+	}
+
+	errors := []error{}
+
+	if u, err := url.Parse(o.Issuer); err != nil || u.Scheme != "https" || len(u.RawQuery) != 0 || len(u.Fragment) != 0 {
+		errors = append(errors, fmt.Errorf("--tenant-service-account-issuer %q must be a valid https URL without query or fragment", o.Issuer))
+	}
+	if len(o.SigningKeyFile) == 0 {
+		errors = append(errors, fmt.Errorf("--tenant-service-account-signing-key-file must be set if --tenant-service-account-issuer is set"))
+	}
+	if o.MaxExpiration < 0 {
+		errors = append(errors, fmt.Errorf("--tenant-service-account-max-token-expiration cannot be negative"))
+	}
+	return errors
+}
+
+// ToIssuerConfig returns the config of the tenant service account token
+// issuer, nil if the issuer is not enabled.
+func (o *TenantServiceAccountOptions) ToIssuerConfig() (*authentication.ServiceAccountIssuerConfig, error) {
+	if o == nil || len(o.Issuer) == 0 {
+		return nil, nil
+	}
+	signingKey, err := keyutil.PrivateKeyFromFile(o.SigningKeyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load --tenant-service-account-signing-key-file %s", o.SigningKeyFile)
+	}
+	publicKeys := []interface{}{}
+	for _, f := range o.KeyFiles {
+		keys, err := keyutil.PublicKeysFromFile(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load --tenant-service-account-key-file %s", f)
+		}
+		publicKeys = append(publicKeys, keys...)
+	}
+	return &authentication.ServiceAccountIssuerConfig{
+		IssuerURL:     o.Issuer,
+		SigningKey:    signingKey,
+		PublicKeys:    publicKeys,
+		MaxExpiration: o.MaxExpiration,
+	}, nil
+}
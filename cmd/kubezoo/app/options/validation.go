@@ -131,11 +131,15 @@ func (s *ServerRunOptions) Validate() []error {
 	errs = append(errs, s.Authentication.Validate()...)
 	errs = append(errs, s.Authorization.Validate()...)
 	errs = append(errs, s.Audit.Validate()...)
+	errs = append(errs, s.TenantAudit.Validate()...)
 	errs = append(errs, s.Admission.Validate()...)
 	errs = append(errs, s.APIEnablement.Validate(legacyscheme.Scheme, apiextensionsapiserver.Scheme, aggregatorscheme.Scheme)...)
 	errs = append(errs, validateTokenRequest(s)...)
 	errs = append(errs, metrics.ValidateShowHiddenMetricsVersion(s.ShowHiddenMetricsForVersion)...)
 	errs = append(errs, s.Proxy.Validate()...)
+	errs = append(errs, s.TenantOIDC.Validate()...)
+	errs = append(errs, s.TenantWebhook.Validate()...)
+	errs = append(errs, s.TenantServiceAccount.Validate()...)
 
 	return errs
 }
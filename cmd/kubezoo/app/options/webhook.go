@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+	webhookutil "k8s.io/apiserver/pkg/util/webhook"
+
+	"github.com/kubewharf/kubezoo/pkg/authentication"
+	"github.com/kubewharf/kubezoo/pkg/authorization"
+)
+
+// TenantWebhookOptions configures the webhooks which authenticate the tokens
+// of the tenant users and authorize the tenant requests.
+type TenantWebhookOptions struct {
+	AuthenticationConfigFile string
+	AuthenticationCacheTTL   time.Duration
+
+	AuthorizationConfigFile           string
+	AuthorizationCacheAuthorizedTTL   time.Duration
+	AuthorizationCacheUnauthorizedTTL time.Duration
+}
+
+// NewTenantWebhookOptions creates a new TenantWebhookOptions object
+func NewTenantWebhookOptions() *TenantWebhookOptions {
+	return &TenantWebhookOptions{
+		AuthenticationCacheTTL:            2 * time.Minute,
+		AuthorizationCacheAuthorizedTTL:   5 * time.Minute,
+		AuthorizationCacheUnauthorizedTTL: 30 * time.Second,
+	}
+}
+
+func (o *TenantWebhookOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+	fs.StringVar(&o.AuthenticationConfigFile, "tenant-authentication-token-webhook-config-file", o.AuthenticationConfigFile, "File with "+
+		"webhook configuration for token authentication of the tenant users in kubeconfig format. The webhook returns the tenant "+
+		"side user name, and the tenant ID in the 'tenant' extra of the user.")
+	fs.DurationVar(&o.AuthenticationCacheTTL, "tenant-authentication-token-webhook-cache-ttl", o.AuthenticationCacheTTL,
+		"The duration to cache responses from the tenant token webhook authenticator. 0 means no cache.")
+	fs.StringVar(&o.AuthorizationConfigFile, "tenant-authorization-webhook-config-file", o.AuthorizationConfigFile, "File with "+
+		"webhook configuration in kubeconfig format, which is called with the tenant side SubjectAccessReview of the tenant "+
+		"requests. The requests denied by the webhook are rejected before being forwarded to the upstream cluster.")
+	fs.DurationVar(&o.AuthorizationCacheAuthorizedTTL, "tenant-authorization-webhook-cache-authorized-ttl", o.AuthorizationCacheAuthorizedTTL,
+		"The duration to cache 'authorized' responses from the tenant webhook authorizer.")
+	fs.DurationVar(&o.AuthorizationCacheUnauthorizedTTL, "tenant-authorization-webhook-cache-unauthorized-ttl", o.AuthorizationCacheUnauthorizedTTL,
+		"The duration to cache 'unauthorized' responses from the tenant webhook authorizer.")
+}
+
+func (o *TenantWebhookOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	errors := []error{}
+
+	if o.AuthenticationCacheTTL < 0 {
+		errors = append(errors, fmt.Errorf("--tenant-authentication-token-webhook-cache-ttl %v cannot be negative", o.AuthenticationCacheTTL))
+	}
+	if o.AuthorizationCacheAuthorizedTTL < 0 {
+		errors = append(errors, fmt.Errorf("--tenant-authorization-webhook-cache-authorized-ttl %v cannot be negative", o.AuthorizationCacheAuthorizedTTL))
+	}
+	if o.AuthorizationCacheUnauthorizedTTL < 0 {
+		errors = append(errors, fmt.Errorf("--tenant-authorization-webhook-cache-unauthorized-ttl %v cannot be negative", o.AuthorizationCacheUnauthorizedTTL))
+	}
+	return errors
+}
+
+// ToAuthenticationConfig returns the config of the tenant token webhook, nil
+// if the webhook is not configured.
+func (o *TenantWebhookOptions) ToAuthenticationConfig() (*authentication.WebhookConfig, error) {
+	if o == nil || len(o.AuthenticationConfigFile) == 0 {
+		return nil, nil
+	}
+	clientConfig, err := webhookutil.LoadKubeconfig(o.AuthenticationConfigFile, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &authentication.WebhookConfig{
+		ClientConfig: clientConfig,
+		CacheTTL:     o.AuthenticationCacheTTL,
+	}, nil
+}
+
+// ToAuthorizationConfig returns the config of the tenant authorization
+// webhook, nil if the webhook is not configured.
+func (o *TenantWebhookOptions) ToAuthorizationConfig() (*authorization.WebhookConfig, error) {
+	if o == nil || len(o.AuthorizationConfigFile) == 0 {
+		return nil, nil
+	}
+	clientConfig, err := webhookutil.LoadKubeconfig(o.AuthorizationConfigFile, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &authorization.WebhookConfig{
+		ClientConfig:    clientConfig,
+		AuthorizedTTL:   o.AuthorizationCacheAuthorizedTTL,
+		UnauthorizedTTL: o.AuthorizationCacheUnauthorizedTTL,
+	}, nil
+}
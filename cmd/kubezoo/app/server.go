@@ -20,29 +20,38 @@ limitations under the License.
 package app
 
 import (
+	"context"
 	stdx509 "crypto/x509"
 	"fmt"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	extensionsapiserver "k8s.io/apiextensions-apiserver/pkg/apiserver"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	externalinformer "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	util_net "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/sets"
 	utilwait "k8s.io/apimachinery/pkg/util/wait"
+	genericaudit "k8s.io/apiserver/pkg/audit"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/request/bearertoken"
 	"k8s.io/apiserver/pkg/authentication/request/union"
 	"k8s.io/apiserver/pkg/authentication/request/x509"
 	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
 	genericapifilters "k8s.io/apiserver/pkg/endpoints/filters"
 	openapinamer "k8s.io/apiserver/pkg/endpoints/openapi"
 	"k8s.io/apiserver/pkg/registry/generic"
@@ -55,9 +64,12 @@ import (
 	"k8s.io/apiserver/pkg/storage/etcd3/preflight"
 	"k8s.io/apiserver/pkg/util/webhook"
 	clidiscovery "k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
 	clientgoinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/keyutil"
 	cliflag "k8s.io/component-base/cli/flag"
@@ -77,7 +89,6 @@ import (
 	"k8s.io/kubernetes/pkg/controlplane/reconcilers"
 	"k8s.io/kubernetes/pkg/kubeapiserver"
 	kubeauthenticator "k8s.io/kubernetes/pkg/kubeapiserver/authenticator"
-	kubeoptions "k8s.io/kubernetes/pkg/kubeapiserver/options"
 	"k8s.io/kubernetes/pkg/routes"
 	"k8s.io/kubernetes/pkg/serviceaccount"
 
@@ -85,6 +96,10 @@ import (
 	generatedopenapi "github.com/kubewharf/kubezoo/pkg/apis/openapi"
 	quotav1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1"
 	_ "github.com/kubewharf/kubezoo/pkg/apis/tenant/install"
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	kubezooaudit "github.com/kubewharf/kubezoo/pkg/audit"
+	"github.com/kubewharf/kubezoo/pkg/authentication"
+	"github.com/kubewharf/kubezoo/pkg/authorization"
 	"github.com/kubewharf/kubezoo/pkg/common"
 	"github.com/kubewharf/kubezoo/pkg/controller"
 	"github.com/kubewharf/kubezoo/pkg/convert"
@@ -93,7 +108,9 @@ import (
 	"github.com/kubewharf/kubezoo/pkg/generated/clientset/versioned"
 	quotaclient "github.com/kubewharf/kubezoo/pkg/generated/clientset/versioned/typed/quota/v1alpha1"
 	"github.com/kubewharf/kubezoo/pkg/generated/informers/externalversions"
+	tenantlister "github.com/kubewharf/kubezoo/pkg/generated/listers/tenant/v1alpha1"
 	"github.com/kubewharf/kubezoo/pkg/proxy"
+	"github.com/kubewharf/kubezoo/pkg/quota"
 	tenantrest "github.com/kubewharf/kubezoo/pkg/rest"
 	"github.com/kubewharf/kubezoo/pkg/util"
 )
@@ -286,18 +303,56 @@ func CreateKubeZooServer(kubeAPIServerConfig *master.Config,
 			proxyConfig.discoveryClient,
 			proxyConfig.dynamicClient,
 			proxyConfig.crdClient,
+			proxyConfig.crdInformers.Apiextensions().V1().CustomResourceDefinitions().Informer(),
 			proxyConfig.quotaClient,
 			proxyConfig.clientCAFile,
-			proxyConfig.clientCAKeyFile,
+			proxyConfig.caSigner,
+			proxyConfig.certKeyAlgorithm,
+			proxyConfig.certValidity,
 			proxyConfig.proxyBindAddress,
 			proxyConfig.proxySecurePort)
 		return nil
 	})
+	m.GenericAPIServer.AddPostStartHookOrDie("start-tenant-csr-signing-controller", func(context genericapiserver.PostStartHookContext) error {
+		go controller.NewCSRSigningController(proxyConfig.typedClientSet,
+			controlPlaneConfig.tenantInformers.Tenant().V1alpha1().Tenants().Informer(),
+			proxyConfig.caSigner,
+			proxyConfig.tenantCSRSigningDuration).Run(context.StopCh)
+		return nil
+	})
 	m.GenericAPIServer.AddPostStartHookOrDie("tenant-informer-synced", func(context genericapiserver.PostStartHookContext) error {
 		return utilwait.PollImmediateUntil(100*time.Millisecond, func() (bool, error) {
 			return controlPlaneConfig.tenantInformers.Tenant().V1alpha1().Tenants().Informer().HasSynced(), nil
 		}, context.StopCh)
 	})
+	if proxyConfig.quotaInformers != nil {
+		m.GenericAPIServer.AddPostStartHookOrDie("upstream-quota-informer-synced", func(context genericapiserver.PostStartHookContext) error {
+			proxyConfig.quotaInformers.Start(context.StopCh)
+			proxyConfig.clusterQuotaInformers.Start(context.StopCh)
+			for _, synced := range proxyConfig.quotaInformers.WaitForCacheSync(context.StopCh) {
+				if !synced {
+					return fmt.Errorf("failed to sync the upstream quota informers")
+				}
+			}
+			for _, synced := range proxyConfig.clusterQuotaInformers.WaitForCacheSync(context.StopCh) {
+				if !synced {
+					return fmt.Errorf("failed to sync the upstream cluster quota informers")
+				}
+			}
+			return nil
+		})
+	}
+	if proxyConfig.serviceAccountInformers != nil {
+		m.GenericAPIServer.AddPostStartHookOrDie("upstream-service-account-informer-synced", func(context genericapiserver.PostStartHookContext) error {
+			proxyConfig.serviceAccountInformers.Start(context.StopCh)
+			for _, synced := range proxyConfig.serviceAccountInformers.WaitForCacheSync(context.StopCh) {
+				if !synced {
+					return fmt.Errorf("failed to sync the upstream service account informers")
+				}
+			}
+			return nil
+		})
+	}
 
 	return m, nil
 }
@@ -446,6 +501,10 @@ type ProxyConfig struct {
 	quotaClient     quotaclient.QuotaV1alpha1Interface
 
 	crdInformers externalinformer.SharedInformerFactory
+	// quotaInformers and clusterQuotaInformers are nil if the upstream
+	// cluster does not serve the cluster resource quotas.
+	quotaInformers        clientgoinformers.SharedInformerFactory
+	clusterQuotaInformers externalversions.SharedInformerFactory
 
 	nativeConvertor common.ObjectConvertor
 	customConvertor common.ObjectConvertor
@@ -456,8 +515,24 @@ type ProxyConfig struct {
 	proxyBindAddress string
 	proxySecurePort  int
 
-	clientCAFile    string
-	clientCAKeyFile string
+	clientCAFile     string
+	caSigner         util.CASigner
+	certKeyAlgorithm util.KeyAlgorithm
+	certValidity     time.Duration
+
+	maxCachedCRDStorages  int
+	crdStorageIdleTimeout time.Duration
+
+	tenantCSRSigningDuration time.Duration
+
+	serviceAccountIssuer *authentication.ServiceAccountIssuer
+	// serviceAccountInformers are the upstream informers the service
+	// account tokens are validated against, nil if serviceAccountIssuer is.
+	serviceAccountInformers clientgoinformers.SharedInformerFactory
+
+	// quotaReviewer is nil if the upstream cluster does not serve the
+	// cluster resource quotas.
+	quotaReviewer *quota.Reviewer
 }
 
 func (c *ProxyConfig) ApplyToGroup(group *common.APIGroupConfig) {
@@ -477,9 +552,29 @@ func (c *ProxyConfig) ApplyToStorage(config *common.StorageConfig) {
 	} else {
 		config.Convertor = c.nativeConvertor
 	}
+	if c.serviceAccountIssuer != nil {
+		config.ServiceAccountTokenIssuer = c.serviceAccountIssuer
+	}
 }
 
-func buildProxyConfig(o *options.ProxyOptions) (*ProxyConfig, error) {
+// tenantIndex indexes the upstream namespaced objects by the tenants of
+// their namespaces.
+const tenantIndex = "tenant"
+
+// tenantIndexFunc returns the tenant of the namespace of the object, if any.
+func tenantIndexFunc(obj interface{}) ([]string, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	tenantID, err := util.GetTenantIDFromNamespace(accessor.GetNamespace())
+	if err != nil {
+		return nil, nil
+	}
+	return []string{tenantID}, nil
+}
+
+func buildProxyConfig(o *options.ProxyOptions, tenantLister tenantlister.TenantLister) (*ProxyConfig, error) {
 	upstreamConfig, err := clientcmd.BuildConfigFromFlags(o.UpstreamMaster, "")
 	if err != nil {
 		return nil, err
@@ -508,6 +603,12 @@ func buildProxyConfig(o *options.ProxyOptions) (*ProxyConfig, error) {
 
 	crdInformers := externalinformer.NewSharedInformerFactory(crdClient, 5*time.Minute)
 	crdLister := crdInformers.Apiextensions().V1().CustomResourceDefinitions().Lister()
+	// the crd usage of the tenants is counted by the tenant controller from
+	// the crds indexed by the tenants
+	if err := crdInformers.Apiextensions().V1().CustomResourceDefinitions().Informer().AddIndexers(
+		cache.Indexers{controller.CRDTenantIndex: controller.CRDTenantIndexFunc}); err != nil {
+		return nil, err
+	}
 
 	var clusterQuotaClient quotaclient.QuotaV1alpha1Interface
 	apiResourceList, err := discoveryClient.ServerResourcesForGroupVersion(quotav1alpha1.GroupVersion.String())
@@ -538,7 +639,74 @@ func buildProxyConfig(o *options.ProxyOptions) (*ProxyConfig, error) {
 	listTenantCRDs := convert.ListTenantCRDsFunc(func(tenantID string) ([]*apiextensionsv1.CustomResourceDefinition, error) {
 		return util.ListCRDsForTenant(tenantID, crdLister)
 	})
-	nativeConvertor, customConvertor := convert.InitConvertors(checkGroupKind, listTenantCRDs)
+	isSystemCRDGroup := convert.CheckSystemCRDGroupFunc(func(group, tenantID string) (bool, error) {
+		return util.IsSystemCRDGroup(group, tenantID, crdLister, tenantLister)
+	})
+	getTenant := convert.GetTenantFunc(func(tenantID string) (*tenantv1alpha1.Tenant, error) {
+		return tenantLister.Get(tenantID)
+	})
+	var getClusterQuota convert.GetClusterResourceQuotaFunc
+	var clusterQuotaInformers externalversions.SharedInformerFactory
+	if clusterQuotaClient != nil {
+		// the usage is read from the informer on every conversion, including
+		// each item of the lists, instead of the upstream cluster
+		upstreamQuotaClient, err := versioned.NewForConfig(upstreamConfig)
+		if err != nil {
+			return nil, err
+		}
+		clusterQuotaInformers = externalversions.NewSharedInformerFactory(upstreamQuotaClient, 5*time.Minute)
+		clusterQuotaLister := clusterQuotaInformers.Quota().V1alpha1().ClusterResourceQuotas().Lister()
+		getClusterQuota = func(name string) (*quotav1alpha1.ClusterResourceQuota, error) {
+			return clusterQuotaLister.Get(name)
+		}
+	}
+	var listTenantQuotas convert.ListTenantQuotasFunc
+	var quotaReviewer *quota.Reviewer
+	var quotaInformers clientgoinformers.SharedInformerFactory
+	if clusterQuotaClient != nil {
+		// the latest quotas of the tenant are read from the upstream cluster
+		listTenantClusterQuotas := func(tenantID string) ([]*quotav1alpha1.ClusterResourceQuota, error) {
+			clusterquotaList, err := clusterQuotaClient.ClusterResourceQuotas().List(context.TODO(), metav1.ListOptions{
+				LabelSelector: labels.Set{common.TenantNamespaceLabelKey: tenantID}.String(),
+			})
+			if err != nil {
+				return nil, err
+			}
+			clusterquotas := make([]*quotav1alpha1.ClusterResourceQuota, 0, len(clusterquotaList.Items))
+			for i := range clusterquotaList.Items {
+				clusterquotas = append(clusterquotas, &clusterquotaList.Items[i])
+			}
+			return clusterquotas, nil
+		}
+		// the allocations are checked against the latest quotas, the resource
+		// quotas are served from the informer, indexed by the tenants of
+		// their namespaces
+		quotaInformers = clientgoinformers.NewSharedInformerFactory(typedClientSet, 5*time.Minute)
+		quotaInformer := quotaInformers.Core().V1().ResourceQuotas().Informer()
+		if err := quotaInformer.AddIndexers(cache.Indexers{tenantIndex: tenantIndexFunc}); err != nil {
+			return nil, err
+		}
+		listTenantQuotas = func(tenantID string) ([]*quotav1alpha1.ClusterResourceQuota, []*corev1.ResourceQuota, error) {
+			clusterquotas, err := listTenantClusterQuotas(tenantID)
+			if err != nil {
+				return nil, nil, err
+			}
+			objs, err := quotaInformer.GetIndexer().ByIndex(tenantIndex, tenantID)
+			if err != nil {
+				return nil, nil, err
+			}
+			quotas := make([]*corev1.ResourceQuota, 0, len(objs))
+			for _, obj := range objs {
+				quotas = append(quotas, obj.(*corev1.ResourceQuota))
+			}
+			return clusterquotas, quotas, nil
+		}
+		// the kinds of the manifests are mapped by the discovery of the
+		// upstream cluster, which is refreshed on the unknown kinds
+		mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+		quotaReviewer = quota.NewReviewer(listTenantClusterQuotas, mapper)
+	}
+	nativeConvertor, customConvertor := convert.InitConvertors(checkGroupKind, listTenantCRDs, isSystemCRDGroup, getTenant, getClusterQuota, listTenantQuotas)
 
 	// construct transport for connect proxy round trip
 	proxyTransport, err := rest.TransportFor(upstreamConfig)
@@ -556,6 +724,10 @@ func buildProxyConfig(o *options.ProxyOptions) (*ProxyConfig, error) {
 	if err != nil {
 		return nil, err
 	}
+	caSigner, err := o.NewClientCASigner()
+	if err != nil {
+		return nil, err
+	}
 
 	return &ProxyConfig{
 		dynamicClient:    dynamicClient,
@@ -563,6 +735,7 @@ func buildProxyConfig(o *options.ProxyOptions) (*ProxyConfig, error) {
 		crdClient:        crdClient,
 		typedClientSet:   typedClientSet,
 		crdInformers:     crdInformers,
+		quotaInformers:   quotaInformers,
 		quotaClient:      clusterQuotaClient,
 		nativeConvertor:  nativeConvertor,
 		customConvertor:  customConvertor,
@@ -571,7 +744,17 @@ func buildProxyConfig(o *options.ProxyOptions) (*ProxyConfig, error) {
 		proxyBindAddress: o.BindAddress,
 		proxySecurePort:  o.SecurePort,
 		clientCAFile:     o.ClientCAFile,
-		clientCAKeyFile:  o.ClientCAKeyFile,
+		caSigner:         caSigner,
+		certKeyAlgorithm: util.KeyAlgorithm(o.TenantCertKeyAlgorithm),
+		certValidity:     o.TenantCertValidity,
+
+		maxCachedCRDStorages:  o.MaxCachedCRDStorages,
+		crdStorageIdleTimeout: o.CRDStorageIdleTimeout,
+
+		tenantCSRSigningDuration: o.TenantCSRSigningDuration,
+
+		clusterQuotaInformers: clusterQuotaInformers,
+		quotaReviewer:         quotaReviewer,
 	}, nil
 }
 
@@ -605,19 +788,6 @@ func buildGenericConfig(
 	// install resource config without any resource
 	genericConfig.MergedResourceConfig = serverstorage.NewResourceConfig()
 
-	proxyConfig, lastErr = buildProxyConfig(s.Proxy)
-	if lastErr != nil {
-		return
-	}
-
-	var discoveryProxy proxy.DiscoveryProxy
-	discoveryProxy, lastErr = proxy.NewDiscoveryProxy(proxyConfig.discoveryClient,
-		proxyConfig.crdInformers.Apiextensions().V1().CustomResourceDefinitions().Lister())
-	if lastErr != nil {
-		return
-	}
-	genericConfig.BuildHandlerChainFunc = NewBuildHandlerChanFunc(discoveryProxy)
-
 	if lastErr = s.GenericServerRunOptions.ApplyTo(genericConfig); lastErr != nil {
 		return
 	}
@@ -636,6 +806,9 @@ func buildGenericConfig(
 	if lastErr = s.EgressSelector.ApplyTo(genericConfig); lastErr != nil {
 		return
 	}
+	if lastErr = s.Audit.ApplyTo(genericConfig); lastErr != nil {
+		return
+	}
 
 	genericConfig.OpenAPIConfig = genericapiserver.DefaultOpenAPIConfig(generatedopenapi.GetOpenAPIDefinitions, openapinamer.NewDefinitionNamer(legacyscheme.Scheme, extensionsapiserver.Scheme, aggregatorscheme.Scheme))
 	genericConfig.OpenAPIConfig.Info.Title = "Kubernetes"
@@ -680,18 +853,50 @@ func buildGenericConfig(
 		return
 	}
 
-	if lastErr = applyAuthenticationOptions(s.Authentication, genericConfig); lastErr != nil {
+	proxyConfig, lastErr = buildProxyConfig(s.Proxy,
+		controlPlaneConfig.tenantInformers.Tenant().V1alpha1().Tenants().Lister())
+	if lastErr != nil {
 		return
 	}
 
-	ac, _ := s.Authentication.ToAuthenticationConfig()
-	if ac.ClientCAContentProvider != nil {
-		// append the authentication handler that will extract tenant ID from
-		// the x509 certificate
-		ta := x509.NewDynamic(ac.ClientCAContentProvider.VerifyOptions,
-			CommonNameUserConversion)
-		genericConfig.Authentication.Authenticator = union.New(ta,
-			genericConfig.Authentication.Authenticator)
+	proxyConfig.serviceAccountIssuer, proxyConfig.serviceAccountInformers, lastErr = buildServiceAccountIssuer(s.TenantServiceAccount,
+		controlPlaneConfig.tenantInformers.Tenant().V1alpha1().Tenants().Lister(), proxyConfig.typedClientSet)
+	if lastErr != nil {
+		return
+	}
+
+	var discoveryProxy proxy.DiscoveryProxy
+	discoveryProxy, lastErr = proxy.NewDiscoveryProxy(proxyConfig.discoveryClient,
+		proxyConfig.crdInformers.Apiextensions().V1().CustomResourceDefinitions().Lister())
+	if lastErr != nil {
+		return
+	}
+	var tenantAuthorizer authorizer.Authorizer
+	tenantAuthorizer, lastErr = buildTenantAuthorizer(s.TenantWebhook)
+	if lastErr != nil {
+		return
+	}
+	var tenantAuditLogs tenantfilters.TenantAuditLogs
+	tenantAuditLogs, lastErr = applyTenantAuditOptions(s.TenantAudit, genericConfig,
+		controlPlaneConfig.tenantInformers.Tenant().V1alpha1().Tenants().Lister())
+	if lastErr != nil {
+		return
+	}
+	var impersonationAuthorizer authorizer.Authorizer
+	impersonationAuthorizer, lastErr = authorization.NewImpersonationAuthorizer(proxyConfig.typedClientSet.AuthorizationV1())
+	if lastErr != nil {
+		return
+	}
+	var quotaReviewHandler http.Handler
+	if proxyConfig.quotaReviewer != nil {
+		quotaReviewHandler = proxyConfig.quotaReviewer
+	}
+	genericConfig.BuildHandlerChainFunc = NewBuildHandlerChanFunc(discoveryProxy, tenantAuthorizer, impersonationAuthorizer,
+		proxyConfig.serviceAccountIssuer, tenantAuditLogs, quotaReviewHandler)
+
+	if lastErr = applyAuthenticationOptions(s, genericConfig,
+		controlPlaneConfig.tenantInformers.Tenant().V1alpha1().Tenants().Lister(), proxyConfig.serviceAccountIssuer); lastErr != nil {
+		return
 	}
 
 	genericConfig.Authorization.Authorizer, genericConfig.RuleResolver, err = s.Authorization.ToAuthorizationConfig(nil).New()
@@ -702,7 +907,9 @@ func buildGenericConfig(
 	return
 }
 
-func applyAuthenticationOptions(o *kubeoptions.BuiltInAuthenticationOptions, genericConfig *server.Config) error {
+func applyAuthenticationOptions(s *options.ServerRunOptions, genericConfig *server.Config, tenantLister tenantlister.TenantLister,
+	serviceAccountIssuer *authentication.ServiceAccountIssuer) error {
+	o := s.Authentication
 	authenticatorConfig, err := o.ToAuthenticationConfig()
 	if err != nil {
 		return err
@@ -726,10 +933,153 @@ func applyAuthenticationOptions(o *kubeoptions.BuiltInAuthenticationOptions, gen
 	if o.ServiceAccounts != nil && len(o.ServiceAccounts.Issuers) > 0 && o.ServiceAccounts.Issuers[0] != "" && len(o.APIAudiences) == 0 {
 		authInfo.APIAudiences = authenticator.Audiences{o.ServiceAccounts.Issuers[0]}
 	}
-	authInfo.Authenticator, _, err = authenticatorConfig.New()
+	authInfo.Authenticator, err = buildAuthenticator(s, authenticatorConfig, tenantLister, serviceAccountIssuer)
 	return err
 }
 
+// buildAuthenticator builds the authenticator of the requests, in which the
+// tenant authenticators go before the built-in ones. The client certificates
+// are authenticated by the tenant x509 authenticator only, and those it
+// rejects are not passed on to the others, so that they are neither
+// authenticated as the users named after their common names, nor as the
+// anonymous user.
+func buildAuthenticator(s *options.ServerRunOptions, ac kubeauthenticator.Config, tenantLister tenantlister.TenantLister,
+	serviceAccountIssuer *authentication.ServiceAccountIssuer) (authenticator.Request, error) {
+	authenticators, err := buildTenantAuthenticators(s, tenantLister, serviceAccountIssuer)
+	if err != nil {
+		return nil, err
+	}
+	clientCA := ac.ClientCAContentProvider
+	ac.ClientCAContentProvider = nil
+	builtin, _, err := ac.New()
+	if err != nil {
+		return nil, err
+	}
+	if builtin != nil {
+		authenticators = append(authenticators, builtin)
+	}
+
+	var auth authenticator.Request
+	if len(authenticators) > 0 {
+		auth = union.New(authenticators...)
+	}
+	if clientCA == nil {
+		return auth, nil
+	}
+	// the authentication handler that will extract tenant ID from the x509
+	// certificate
+	certAuth := x509.NewDynamic(clientCA.VerifyOptions,
+		NewCommonNameUserConversion(tenantLister, s.AllowUnboundTenantAdminCertificates))
+	if auth == nil {
+		return certAuth, nil
+	}
+	return union.NewFailOnError(certAuth, auth), nil
+}
+
+// buildTenantAuthenticators builds the token authenticators which
+// authenticate the tenant users, whose tenant is recorded in the user extra.
+func buildTenantAuthenticators(s *options.ServerRunOptions, tenantLister tenantlister.TenantLister,
+	serviceAccountIssuer *authentication.ServiceAccountIssuer) ([]authenticator.Request, error) {
+	var tenantAuthenticators []authenticator.Request
+	if oidcConfig := s.TenantOIDC.ToOIDCConfig(); oidcConfig != nil {
+		// append the authentication handler that will map the oidc users to
+		// the tenant in the token claim
+		oidcAuth, err := authentication.NewOIDCAuthenticator(*oidcConfig, tenantLister)
+		if err != nil {
+			return nil, err
+		}
+		tenantAuthenticators = append(tenantAuthenticators, bearertoken.New(oidcAuth))
+	}
+	webhookConfig, err := s.TenantWebhook.ToAuthenticationConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tenant token webhook config: %v", err)
+	}
+	if webhookConfig != nil {
+		// append the authentication handler that will get the user and the
+		// tenant from the token webhook
+		webhookAuth, err := authentication.NewWebhookAuthenticator(*webhookConfig, tenantLister)
+		if err != nil {
+			return nil, err
+		}
+		tenantAuthenticators = append(tenantAuthenticators, bearertoken.New(webhookAuth))
+	}
+	if serviceAccountIssuer != nil {
+		// append the authentication handler that will verify the service
+		// account tokens issued by kubezoo for the tenants
+		tenantAuthenticators = append(tenantAuthenticators, bearertoken.New(serviceAccountIssuer))
+	}
+	return tenantAuthenticators, nil
+}
+
+// buildServiceAccountIssuer builds the issuer of the tenant service account
+// tokens along with the upstream informers it validates the tokens against,
+// nil if the tenant service account issuer is not configured.
+func buildServiceAccountIssuer(o *options.TenantServiceAccountOptions, tenantLister tenantlister.TenantLister,
+	client kubernetes.Interface) (*authentication.ServiceAccountIssuer, clientgoinformers.SharedInformerFactory, error) {
+	issuerConfig, err := o.ToIssuerConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	if issuerConfig == nil {
+		return nil, nil, nil
+	}
+	informers := clientgoinformers.NewSharedInformerFactory(client, 5*time.Minute)
+	issuer, err := authentication.NewServiceAccountIssuer(*issuerConfig, tenantLister, client, informers)
+	if err != nil {
+		return nil, nil, err
+	}
+	return issuer, informers, nil
+}
+
+// applyTenantAuditOptions makes the audit of kubezoo tenant aware. The events
+// of the tenants are annotated with the tenant and sent to the tenant audit
+// backends as seen inside the tenants. The operator backends are given the
+// events by the audit policy of kubezoo, and the tenant backends by the
+// audit policy of the tenant if declared. The audit logs of the tenants are
+// returned if the tenant audit log is enabled.
+func applyTenantAuditOptions(o *options.TenantAuditOptions, genericConfig *server.Config,
+	tenantLister tenantlister.TenantLister) (tenantfilters.TenantAuditLogs, error) {
+	evaluator := kubezooaudit.NewTenantPolicyRuleEvaluator(genericConfig.AuditPolicyRuleEvaluator, tenantLister)
+	var backends []genericaudit.Backend
+	if genericConfig.AuditBackend != nil {
+		backends = append(backends, kubezooaudit.WithPolicy(kubezooaudit.WithTenantAnnotation(genericConfig.AuditBackend), evaluator.Global()))
+	}
+	var logs tenantfilters.TenantAuditLogs
+	if o.Enabled() {
+		if len(o.LogDir) != 0 {
+			logBackend := kubezooaudit.NewTenantLogBackend(o.LogDir, o.LogMaxSize, o.LogMaxBackups)
+			backends = append(backends, kubezooaudit.WithPolicy(logBackend, evaluator.Tenant()))
+			logs = logBackend
+		}
+		if len(o.WebhookConfigFile) != 0 {
+			webhookBackend, err := kubezooaudit.NewTenantWebhookBackend(o.WebhookConfigFile)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tenant audit webhook config: %v", err)
+			}
+			backends = append(backends, kubezooaudit.WithPolicy(webhookBackend, evaluator.Tenant()))
+		}
+	}
+	if len(backends) == 0 {
+		return nil, nil
+	}
+	genericConfig.AuditBackend = genericaudit.Union(backends...)
+	genericConfig.AuditPolicyRuleEvaluator = evaluator
+	return logs, nil
+}
+
+// buildTenantAuthorizer builds the authorizer of the tenant requests, nil if
+// the tenant authorization webhook is not configured.
+func buildTenantAuthorizer(o *options.TenantWebhookOptions) (authorizer.Authorizer, error) {
+	webhookConfig, err := o.ToAuthorizationConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tenant authorization webhook config: %v", err)
+	}
+	if webhookConfig == nil {
+		return nil, nil
+	}
+	return authorization.NewWebhookAuthorizer(*webhookConfig)
+}
+
 // completedServerRunOptions is a private wrapper that enforces a call of Complete() before Run can be invoked.
 type completedServerRunOptions struct {
 	*options.ServerRunOptions
@@ -908,12 +1258,25 @@ func getServiceIPAndRanges(serviceClusterIPRanges string) (net.IP, net.IPNet, ne
 	return apiServerServiceIP, primaryServiceIPRange, secondaryServiceIPRange, nil
 }
 
-func NewBuildHandlerChanFunc(discoveryProxy proxy.DiscoveryProxy) func(apiHandler http.Handler, c *server.Config) (secure http.Handler) {
+func NewBuildHandlerChanFunc(discoveryProxy proxy.DiscoveryProxy, tenantAuthorizer, impersonationAuthorizer authorizer.Authorizer,
+	serviceAccountIssuer *authentication.ServiceAccountIssuer, tenantAuditLogs tenantfilters.TenantAuditLogs,
+	quotaReviewHandler http.Handler) func(apiHandler http.Handler, c *server.Config) (secure http.Handler) {
 	return func(handler http.Handler, c *genericapiserver.Config) (secure http.Handler) {
 		failedHandler := genericapifilters.Unauthorized(c.Serializer)
+		failedHandler = genericapifilters.WithFailedAuthenticationAudit(failedHandler, c.AuditBackend, c.AuditPolicyRuleEvaluator)
+		handler = tenantfilters.WithPatchRequest(handler, c.MaxRequestBodyBytes)
 		handler = tenantfilters.WithDiscoveryProxy(handler, discoveryProxy)
+		handler = tenantfilters.WithQuotaReview(handler, quotaReviewHandler)
+		handler = tenantfilters.WithTenantAuditLog(handler, tenantAuditLogs, c.Serializer)
+		handler = tenantfilters.WithTenantAuthorization(handler, tenantAuthorizer, c.Serializer)
+		handler = tenantfilters.WithTenantImpersonation(handler, impersonationAuthorizer, c.Serializer)
+		// the tenant of the service accounts is known after WithTenantInfo
+		handler = genericapifilters.WithAudit(handler, c.AuditBackend, c.AuditPolicyRuleEvaluator, c.LongRunningFunc)
 		handler = tenantfilters.WithTenantInfo(handler)
 		handler = genericapifilters.WithAuthentication(handler, c.Authentication.Authenticator, failedHandler, c.Authentication.APIAudiences)
+		if serviceAccountIssuer != nil {
+			handler = tenantfilters.WithServiceAccountIssuerDiscovery(handler, serviceAccountIssuer, serviceAccountIssuer.PathPrefix())
+		}
 		handler = genericfilters.WithCORS(handler, c.CorsAllowedOriginList, nil, nil, nil, "true")
 		handler = genericfilters.WithTimeoutForNonLongRunningRequests(handler, c.LongRunningFunc)
 		handler = genericfilters.WithWaitGroup(handler, c.LongRunningFunc, c.HandlerChainWaitGroup)
@@ -927,29 +1290,93 @@ func NewBuildHandlerChanFunc(discoveryProxy proxy.DiscoveryProxy) func(apiHandle
 	}
 }
 
-var CommonNameUserConversion = x509.UserConversionFunc(func(chain []*stdx509.Certificate) (*authenticator.Response, bool, error) {
-	if len(chain[0].Subject.CommonName) == 0 {
-		return nil, false, nil
-	}
+// NewCommonNameUserConversion returns the conversion which extracts the
+// tenant ID from the x509 certificate of the tenant users. The certificates
+// of unknown or deleting tenants, and those issued for a former tenant with
+// the same ID, i.e. bound to another tenant UID, are rejected. The
+// certificates of the named users are checked against the tenant, so that
+// the revoked or superseded ones are rejected, while those issued by the
+// tenant client signer must not pass as the admin or the named users. The
+// certificates of the admins not bound to the tenant UID are accepted only
+// if allowUnboundAdmin is true, until they are reissued by the tenant
+// controller. The UID and the generation of the tenant are attached to the
+// user extra.
+func NewCommonNameUserConversion(tenantLister tenantlister.TenantLister, allowUnboundAdmin bool) x509.UserConversionFunc {
+	return func(chain []*stdx509.Certificate) (*authenticator.Response, bool, error) {
+		if len(chain[0].Subject.CommonName) == 0 {
+			return nil, false, nil
+		}
 
-	OrganizationalUnit := chain[0].Subject.OrganizationalUnit
-	CommonName := chain[0].Subject.CommonName
+		OrganizationalUnit := chain[0].Subject.OrganizationalUnit
+		CommonName := chain[0].Subject.CommonName
 
-	u := user.DefaultInfo{
-		Name:   CommonName,
-		Groups: chain[0].Subject.Organization,
-	}
-	tenantIDLength := 6
-	if len(OrganizationalUnit) > 0 {
-		if len(OrganizationalUnit[0]) == tenantIDLength && len(CommonName) > tenantIDLength {
-			if OrganizationalUnit[0] == CommonName[:tenantIDLength] && CommonName[tenantIDLength] == '-' {
-				tenantName := OrganizationalUnit[0]
-				u.Extra = map[string][]string{"tenant": {tenantName}}
+		u := user.DefaultInfo{
+			Name:   CommonName,
+			Groups: chain[0].Subject.Organization,
+		}
+		tenantIDLength := 6
+		if len(OrganizationalUnit) > 0 {
+			if len(OrganizationalUnit[0]) == tenantIDLength && len(CommonName) > tenantIDLength {
+				if OrganizationalUnit[0] == CommonName[:tenantIDLength] && CommonName[tenantIDLength] == '-' {
+					tenantName := OrganizationalUnit[0]
+					userName := util.TrimTenantIDPrefix(tenantName, CommonName)
+					if tenantLister == nil {
+						return nil, false, fmt.Errorf("unable to check user %s of tenant %s", userName, tenantName)
+					}
+					tenant, err := getCertificateTenant(tenantLister, tenantName, chain[0], allowUnboundAdmin)
+					if err != nil {
+						return nil, false, err
+					}
+					if util.IsTenantCSRCertificate(chain[0]) {
+						// the certificates issued by the tenant client signer
+						// expire instead of being revoked
+						if err := util.CheckTenantCSRUserName(tenant, userName); err != nil {
+							return nil, false, err
+						}
+					} else if userName != util.TenantAdminUserName {
+						if err := util.CheckTenantUserCertificate(tenant, userName, chain[0].SerialNumber); err != nil {
+							return nil, false, err
+						}
+					}
+					u.Extra = map[string][]string{
+						util.TenantIDKey:         {tenantName},
+						util.TenantUIDKey:        {string(tenant.UID)},
+						util.TenantGenerationKey: {strconv.FormatInt(tenant.Generation, 10)},
+					}
+				}
 			}
 		}
+
+		return &authenticator.Response{
+			User: &u,
+		}, true, nil
 	}
+}
 
-	return &authenticator.Response{
-		User: &u,
-	}, true, nil
-})
+// getCertificateTenant returns the tenant the certificate is issued for. The
+// tenant must exist and not be deleting, and the certificate must be bound
+// to the UID of the tenant, except those of the named users issued before
+// the binding is introduced, which are bound by their serial numbers, and
+// those of the admins if allowUnboundAdmin is true.
+func getCertificateTenant(tenantLister tenantlister.TenantLister, tenantName string, cert *stdx509.Certificate,
+	allowUnboundAdmin bool) (*tenantv1alpha1.Tenant, error) {
+	tenant, err := tenantLister.Get(tenantName)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.DeletionTimestamp != nil {
+		return nil, fmt.Errorf("tenant %s is being deleted", tenantName)
+	}
+	uid, ok := util.TenantUIDFromCertificate(cert)
+	if !ok {
+		userName := util.TrimTenantIDPrefix(tenantName, cert.Subject.CommonName)
+		if util.IsTenantCSRCertificate(cert) || userName == util.TenantAdminUserName && !allowUnboundAdmin {
+			return nil, fmt.Errorf("certificate %s of tenant %s is not bound to the tenant", cert.SerialNumber, tenantName)
+		}
+		return tenant, nil
+	}
+	if uid != tenant.UID {
+		return nil, fmt.Errorf("certificate %s is issued for a former tenant %s", cert.SerialNumber, tenantName)
+	}
+	return tenant, nil
+}
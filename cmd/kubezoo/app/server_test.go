@@ -0,0 +1,343 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"crypto/tls"
+	stdx509 "crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/url"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	"k8s.io/client-go/tools/cache"
+	certutil "k8s.io/client-go/util/cert"
+	kubeauthenticator "k8s.io/kubernetes/pkg/kubeapiserver/authenticator"
+
+	"github.com/kubewharf/kubezoo/cmd/kubezoo/app/options"
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	tenantlister "github.com/kubewharf/kubezoo/pkg/generated/listers/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// TestCommonNameUserConversion tests the tenant is extracted from the
+// certificates, the certificates of unknown, deleting or former tenants are
+// rejected, and the certificates of the named users and those issued by the
+// tenant client signer are checked.
+func TestCommonNameUserConversion(t *testing.T) {
+	deleting := metav1.Now()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	indexer.Add(&tenantv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "333333", UID: "uid-3", DeletionTimestamp: &deleting},
+	})
+	indexer.Add(&tenantv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "111111", UID: "uid-1", Generation: 2},
+		Spec: tenantv1alpha1.TenantSpec{
+			Users: []tenantv1alpha1.TenantUser{{Name: "alice"}, {Name: "bob", Revoked: true}},
+		},
+		Status: tenantv1alpha1.TenantStatus{
+			Users: []tenantv1alpha1.TenantUserStatus{{Name: "alice", SerialNumber: "10"}, {Name: "bob", SerialNumber: "11"}},
+		},
+	})
+	conversion := NewCommonNameUserConversion(tenantlister.NewTenantLister(indexer), false)
+
+	tests := []struct {
+		name         string
+		ou           string
+		cn           string
+		uid          types.UID
+		serialNumber int64
+		csr          bool
+		expectTenant string
+		expectErr    bool
+	}{
+		{
+			name:         "admin user",
+			ou:           "111111",
+			uid:          "uid-1",
+			cn:           "111111-admin",
+			serialNumber: 1,
+			expectTenant: "111111",
+		},
+		{
+			name:         "named user",
+			ou:           "111111",
+			uid:          "uid-1",
+			cn:           "111111-alice",
+			serialNumber: 10,
+			expectTenant: "111111",
+		},
+		{
+			name:         "superseded certificate of named user",
+			ou:           "111111",
+			uid:          "uid-1",
+			cn:           "111111-alice",
+			serialNumber: 9,
+			expectErr:    true,
+		},
+		{
+			name:         "revoked user",
+			ou:           "111111",
+			uid:          "uid-1",
+			cn:           "111111-bob",
+			serialNumber: 11,
+			expectErr:    true,
+		},
+		{
+			name:         "unknown tenant",
+			ou:           "222222",
+			uid:          "uid-2",
+			cn:           "222222-alice",
+			serialNumber: 10,
+			expectErr:    true,
+		},
+		{
+			name:         "user of tenant client signer",
+			ou:           "111111",
+			uid:          "uid-1",
+			cn:           "111111-carol",
+			serialNumber: 12,
+			csr:          true,
+			expectTenant: "111111",
+		},
+		{
+			name:         "named user from tenant client signer",
+			ou:           "111111",
+			uid:          "uid-1",
+			cn:           "111111-alice",
+			serialNumber: 10,
+			csr:          true,
+			expectErr:    true,
+		},
+		{
+			name:         "admin user from tenant client signer",
+			ou:           "111111",
+			uid:          "uid-1",
+			cn:           "111111-admin",
+			serialNumber: 1,
+			csr:          true,
+			expectErr:    true,
+		},
+		{
+			name:         "named user without tenant uid",
+			ou:           "111111",
+			cn:           "111111-alice",
+			serialNumber: 10,
+			expectTenant: "111111",
+		},
+		{
+			name:         "admin user without tenant uid",
+			ou:           "111111",
+			cn:           "111111-admin",
+			serialNumber: 1,
+			expectErr:    true,
+		},
+		{
+			name:         "user of tenant client signer without tenant uid",
+			ou:           "111111",
+			cn:           "111111-carol",
+			serialNumber: 12,
+			csr:          true,
+			expectErr:    true,
+		},
+		{
+			name:         "admin user of former tenant",
+			ou:           "111111",
+			cn:           "111111-admin",
+			uid:          "uid-0",
+			serialNumber: 1,
+			expectErr:    true,
+		},
+		{
+			name:         "admin user of deleting tenant",
+			ou:           "333333",
+			cn:           "333333-admin",
+			uid:          "uid-3",
+			serialNumber: 1,
+			expectErr:    true,
+		},
+		{
+			name:         "non-tenant user",
+			cn:           "system:kube-controller-manager",
+			serialNumber: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cert := &stdx509.Certificate{
+				SerialNumber: big.NewInt(test.serialNumber),
+				Subject:      pkix.Name{CommonName: test.cn},
+			}
+			if test.ou != "" {
+				cert.Subject.OrganizationalUnit = []string{test.ou}
+			}
+			if test.uid != "" {
+				cert.URIs = []*url.URL{util.TenantCertificateURI(test.uid)}
+			}
+			if test.csr {
+				cert.Subject.OrganizationalUnit = append(cert.Subject.OrganizationalUnit, util.TenantClientSignerName)
+			}
+			resp, ok, err := conversion([]*stdx509.Certificate{cert})
+			if test.expectErr {
+				if err == nil || ok {
+					t.Errorf("expect error, got %v", resp)
+				}
+				return
+			}
+			if err != nil || !ok {
+				t.Fatalf("expect user, got error %v", err)
+			}
+			if resp.User.GetName() != test.cn {
+				t.Errorf("expect user %s, got %s", test.cn, resp.User.GetName())
+			}
+			tenantIDs := resp.User.GetExtra()[util.TenantIDKey]
+			if test.expectTenant == "" && len(tenantIDs) != 0 ||
+				test.expectTenant != "" && (len(tenantIDs) != 1 || tenantIDs[0] != test.expectTenant) {
+				t.Errorf("expect tenant %q, got %v", test.expectTenant, tenantIDs)
+			}
+			if test.expectTenant != "" {
+				extra := resp.User.GetExtra()
+				if uids := extra[util.TenantUIDKey]; len(uids) != 1 || uids[0] != "uid-1" {
+					t.Errorf("expect tenant uid uid-1, got %v", uids)
+				}
+				if generations := extra[util.TenantGenerationKey]; len(generations) != 1 || generations[0] != "2" {
+					t.Errorf("expect tenant generation 2, got %v", generations)
+				}
+			}
+		})
+	}
+}
+
+// TestBuildAuthenticator tests the client certificates rejected by the
+// tenant x509 authenticator are rejected by the whole authenticator chain,
+// instead of being authenticated by the built-in ones.
+func TestBuildAuthenticator(t *testing.T) {
+	caKey, err := util.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := certutil.NewSelfSignedCACert(certutil.Config{CommonName: "kubezoo-ca"}, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCA, err := dynamiccertificates.NewStaticCAContent("client-ca", util.EncodeCertPEM(caCert))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleting := metav1.Now()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	indexer.Add(&tenantv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "111111", UID: "uid-1"},
+	})
+	indexer.Add(&tenantv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "333333", UID: "uid-3", DeletionTimestamp: &deleting},
+	})
+	tenantLister := tenantlister.NewTenantLister(indexer)
+
+	tests := []struct {
+		name              string
+		config            *util.Config
+		allowUnboundAdmin bool
+		expectUser        string
+		expectErr         bool
+	}{
+		{
+			name:       "admin user",
+			config:     util.NewTenantUserCertConfig("111111", "uid-1", util.TenantAdminUserName),
+			expectUser: "111111-admin",
+		},
+		{
+			name:      "admin user without tenant uid",
+			config:    util.NewTenantUserCertConfig("111111", "", util.TenantAdminUserName),
+			expectErr: true,
+		},
+		{
+			name:              "admin user without tenant uid allowed",
+			config:            util.NewTenantUserCertConfig("111111", "", util.TenantAdminUserName),
+			allowUnboundAdmin: true,
+			expectUser:        "111111-admin",
+		},
+		{
+			name:      "admin user of former tenant",
+			config:    util.NewTenantUserCertConfig("111111", "uid-0", util.TenantAdminUserName),
+			expectErr: true,
+		},
+		{
+			name:      "admin user of deleting tenant",
+			config:    util.NewTenantUserCertConfig("333333", "uid-3", util.TenantAdminUserName),
+			expectErr: true,
+		},
+		{
+			name: "non-tenant user",
+			config: &util.Config{
+				CommonName:         "system:kube-controller-manager",
+				OrganizationalUnit: []string{"ops"},
+				Usages:             []stdx509.ExtKeyUsage{stdx509.ExtKeyUsageClientAuth},
+			},
+			expectUser: "system:kube-controller-manager",
+		},
+		{
+			name:       "anonymous user",
+			expectUser: user.Anonymous,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := options.NewServerRunOptions()
+			s.AllowUnboundTenantAdminCertificates = test.allowUnboundAdmin
+			ac := kubeauthenticator.Config{
+				Anonymous:               true,
+				ClientCAContentProvider: clientCA,
+			}
+			auth, err := buildAuthenticator(s, ac, tenantLister, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := &http.Request{Header: http.Header{}, TLS: &tls.ConnectionState{}}
+			if test.config != nil {
+				key, err := util.NewPrivateKey()
+				if err != nil {
+					t.Fatal(err)
+				}
+				cert, err := util.NewSignedCert(test.config, key, caCert, caKey)
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.TLS.PeerCertificates = []*stdx509.Certificate{cert}
+			}
+			resp, ok, err := auth.AuthenticateRequest(req)
+			if test.expectErr {
+				if err == nil || ok {
+					t.Errorf("expect error, got %v", resp)
+				}
+				return
+			}
+			if err != nil || !ok {
+				t.Fatalf("expect user, got error %v", err)
+			}
+			if resp.User.GetName() != test.expectUser {
+				t.Errorf("expect user %s, got %s", test.expectUser, resp.User.GetName())
+			}
+		})
+	}
+}
@@ -155,53 +155,11 @@ EOF
     cd -
 }
 
-base64file() {
-    input=${1}
-    if base64 --help | grep GNU >/dev/null; then
-        # gnu base64
-        base64 -w 0 "${input}"
-    elif base64 --help | grep "input" >/dev/null; then
-        base64 -b 0 -i "${input}"
-    fi
-}
-
-gen_quota_webhook_cert() {
-
-    QUOTA_WEBHOOK_HOSTNAMES=kubezoo-cluster-resource-quota.default,kubezoo-cluster-resource-quota.default.svc
-
-    cat >$KUBEZOO_DIR/quota-csr.json <<EOF
-{
-  "CN": "kubezoo-cluster-resource-quota",
-  "key": {
-    "algo": "rsa",
-    "size": 2048
-  },
-  "names": [
-    {
-      "C": "US",
-      "L": "Sunnyvale",
-      "O": "system:masters",
-      "OU": "KubeZoo",
-      "ST": "CA"
-    }
-  ]
-}
-EOF
-
-    cd $KUBEZOO_DIR
-    cfssl gencert \
-        -ca=$KUBEZOO_DIR/ca.pem \
-        -ca-key=$KUBEZOO_DIR/ca-key.pem \
-        -config=$KUBEZOO_DIR/ca-config.json \
-        -hostname=127.0.0.1,${QUOTA_WEBHOOK_HOSTNAMES} \
-        -profile=kubernetes \
-        $KUBEZOO_DIR/quota-csr.json | cfssljson -bare quota-webhook
-
-    cd -
-
-    caBase64="$(base64file ${KUBEZOO_DIR}/ca.pem)"
+gen_quota_setup() {
+    # the serving certificate of the quota webhook is generated by the
+    # webhook itself, see -webhook-self-managed
     mkdir -p _output/setup
-    sed "s/{caBundle}/${caBase64}/g" config/setup/quota.tmpl.yaml >_output/setup/quota.yaml
+    cp config/setup/quota.tmpl.yaml _output/setup/quota.yaml
 }
 
 create_pki_secret() {
@@ -223,13 +181,6 @@ create_pki_secret() {
         --from-file=client.crt=$UPSTREAM_DIR/client.crt \
         --from-file=client-key.crt=$UPSTREAM_DIR/client-key.crt \
         --from-file=ca.crt=$UPSTREAM_DIR/ca.crt
-
-    if kubectl get secret quota-webhook-pki; then
-        kubectl delete secret quota-webhook-pki
-    fi
-    kubectl create secret tls quota-webhook-pki \
-        --key=$KUBEZOO_DIR/quota-webhook-key.pem \
-        --cert=$KUBEZOO_DIR/quota-webhook.pem
 }
 
 set_context() {
@@ -287,7 +238,7 @@ print_kubezoo_parameters() {
 gen_pki_setup_ctx() {
     get_upstream_pki_kind
     gen_kubezoo_pki
-    gen_quota_webhook_cert
+    gen_quota_setup
     create_pki_secret
     set_context
 }
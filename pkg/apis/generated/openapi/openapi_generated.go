@@ -38,10 +38,17 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1.ClusterResourceQuotaSpec":   schema_pkg_apis_quota_v1alpha1_ClusterResourceQuotaSpec(ref),
 		"github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1.ClusterResourceQuotaStatus": schema_pkg_apis_quota_v1alpha1_ClusterResourceQuotaStatus(ref),
 		"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.Tenant":                    schema_pkg_apis_tenant_v1alpha1_Tenant(ref),
+		"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantAudit":               schema_pkg_apis_tenant_v1alpha1_TenantAudit(ref),
+		"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantCRDLimits":           schema_pkg_apis_tenant_v1alpha1_TenantCRDLimits(ref),
+		"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantCRDUsage":            schema_pkg_apis_tenant_v1alpha1_TenantCRDUsage(ref),
 		"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantList":                schema_pkg_apis_tenant_v1alpha1_TenantList(ref),
 		"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantQuota":               schema_pkg_apis_tenant_v1alpha1_TenantQuota(ref),
+		"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantQuotaStatus":         schema_pkg_apis_tenant_v1alpha1_TenantQuotaStatus(ref),
+		"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantScopedQuota":         schema_pkg_apis_tenant_v1alpha1_TenantScopedQuota(ref),
 		"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantSpec":                schema_pkg_apis_tenant_v1alpha1_TenantSpec(ref),
 		"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantStatus":              schema_pkg_apis_tenant_v1alpha1_TenantStatus(ref),
+		"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantUser":                schema_pkg_apis_tenant_v1alpha1_TenantUser(ref),
+		"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantUserStatus":          schema_pkg_apis_tenant_v1alpha1_TenantUserStatus(ref),
 		"k8s.io/apimachinery/pkg/api/resource.Quantity":                                   schema_apimachinery_pkg_api_resource_Quantity(ref),
 		"k8s.io/apimachinery/pkg/api/resource.int64Amount":                                schema_apimachinery_pkg_api_resource_int64Amount(ref),
 		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroup":                                   schema_pkg_apis_meta_v1_APIGroup(ref),
@@ -362,6 +369,115 @@ func schema_pkg_apis_tenant_v1alpha1_Tenant(ref common.ReferenceCallback) common
 	}
 }
 
+func schema_pkg_apis_tenant_v1alpha1_TenantAudit(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TenantAudit describes how the requests of the tenant are audited.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"level": {
+						SchemaProps: spec.SchemaProps{
+							Description: "level is the audit level of the requests of the tenant, one of None, Metadata, Request and RequestResponse.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"omitStages": {
+						SchemaProps: spec.SchemaProps{
+							Description: "omitStages are the stages which are not recorded for the requests of the tenant, e.g. RequestReceived.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"level"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_tenant_v1alpha1_TenantCRDLimits(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TenantCRDLimits describes the limits of the custom resource definitions belonged to a tenant, a nil limit means no limit.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maxCRDs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "maxCRDs is the max number of crds the tenant can create.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"maxServedVersions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "maxServedVersions is the max number of served versions summed over all crds of the tenant.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"maxSchemaBytes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "maxSchemaBytes is the max size in bytes of the openapi schemas summed over all versions of all crds of the tenant.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_tenant_v1alpha1_TenantCRDUsage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TenantCRDUsage describes the usage of the custom resource definitions belonged to a tenant.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"crds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "crds is the number of crds of the tenant.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"servedVersions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "servedVersions is the number of served versions summed over all crds of the tenant.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"schemaBytes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "schemaBytes is the size in bytes of the openapi schemas summed over all versions of all crds of the tenant.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+				Required: []string{"crds", "servedVersions", "schemaBytes"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_tenant_v1alpha1_TenantList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -439,6 +555,95 @@ func schema_pkg_apis_tenant_v1alpha1_TenantQuota(ref common.ReferenceCallback) c
 							},
 						},
 					},
+					"scoped": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "scoped are the quotas which only track the usage of the objects matched by their scopes, e.g. the BestEffort pods or the pods of a priority class, each of which is enforced across the namespaces of the tenant separately.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantScopedQuota"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantScopedQuota", "k8s.io/apimachinery/pkg/api/resource.Quantity"},
+	}
+}
+
+func schema_pkg_apis_tenant_v1alpha1_TenantQuotaStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TenantQuotaStatus describes the usage of a quota of the tenant summed over the namespaces of the tenant.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "name is the name of the scoped quota, or empty for the quota without scopes.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"hard": {
+						SchemaProps: spec.SchemaProps{
+							Description: "hard is the set of enforced hard limits for each named resource.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
+						},
+					},
+					"used": {
+						SchemaProps: spec.SchemaProps{
+							Description: "used is the current observed total usage of the resource across the namespaces of the tenant.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
+						},
+					},
+					"allocated": {
+						SchemaProps: spec.SchemaProps{
+							Description: "allocated is the hard limits summed over the resource quotas created by the tenant in its namespaces, which allocate the quota to the namespaces.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -447,6 +652,66 @@ func schema_pkg_apis_tenant_v1alpha1_TenantQuota(ref common.ReferenceCallback) c
 	}
 }
 
+func schema_pkg_apis_tenant_v1alpha1_TenantScopedQuota(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TenantScopedQuota describes a quota of the tenant which is only enforced on the objects matched by its scopes, as the resource quota does.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "name is the name of the scoped quota, which must be a DNS label.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"hard": {
+						SchemaProps: spec.SchemaProps{
+							Description: "hard is the set of desired hard limits for each named resource.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
+						},
+					},
+					"scopes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "scopes is the collection of filters which must all match the objects tracked by the quota.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"scopeSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "scopeSelector is the collection of filters like scopes that must match the objects tracked by the quota, expressed with the operators.",
+							Ref:         ref("k8s.io/api/core/v1.ScopeSelector"),
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.ScopeSelector", "k8s.io/apimachinery/pkg/api/resource.Quantity"},
+	}
+}
+
 func schema_pkg_apis_tenant_v1alpha1_TenantSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -467,12 +732,52 @@ func schema_pkg_apis_tenant_v1alpha1_TenantSpec(ref common.ReferenceCallback) co
 							Ref:     ref("github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantQuota"),
 						},
 					},
+					"crdLimits": {
+						SchemaProps: spec.SchemaProps{
+							Description: "crdLimits limits the custom resource definitions created by the tenant.",
+							Ref:         ref("github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantCRDLimits"),
+						},
+					},
+					"users": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "users are the named users of the tenant besides the admin user.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantUser"),
+									},
+								},
+							},
+						},
+					},
+					"audit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "audit is the audit policy of the requests of the tenant, which takes precedence over the audit policy of kubezoo.",
+							Ref:         ref("github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantAudit"),
+						},
+					},
+					"limitRange": {
+						SchemaProps: spec.SchemaProps{
+							Description: "limitRange is the default limit range kept in every namespace of the tenant, which sets the default requests and limits of the containers and bounds them. It is read-only to the tenant, who can only tighten the bounds with more limit ranges.",
+							Ref:         ref("k8s.io/api/core/v1.LimitRangeSpec"),
+						},
+					},
 				},
 				Required: []string{"id", "quota"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantQuota"},
+			"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantAudit", "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantCRDLimits", "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantQuota", "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantUser", "k8s.io/api/core/v1.LimitRangeSpec"},
 	}
 }
 
@@ -490,9 +795,128 @@ func schema_pkg_apis_tenant_v1alpha1_TenantStatus(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"crdUsage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "crdUsage is the current usage of the crds of the tenant.",
+							Ref:         ref("github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantCRDUsage"),
+						},
+					},
+					"users": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "users are the certificates issued to the named users of the tenant.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantUserStatus"),
+									},
+								},
+							},
+						},
+					},
+					"quota": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "quota is the usage of the quotas of the tenant, one per scope.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantQuotaStatus"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantCRDUsage", "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantQuotaStatus", "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1.TenantUserStatus"},
+	}
+}
+
+func schema_pkg_apis_tenant_v1alpha1_TenantUser(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TenantUser describes a named user of the tenant. The certificate of the user is issued with the common name <tenant>-<name> under the tenant OU, and the user is authorized by the RBAC rules of the tenant.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "name is the name of the user in the tenant, which must be a DNS label other than \"admin\".",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"revoked": {
+						SchemaProps: spec.SchemaProps{
+							Description: "revoked revokes the certificate issued to the user, no certificate is issued to the user until it is unset.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_tenant_v1alpha1_TenantUserStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TenantUserStatus describes the certificate issued to a named user of the tenant.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "name is the name of the user in the tenant.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"serialNumber": {
+						SchemaProps: spec.SchemaProps{
+							Description: "serialNumber is the serial number of the certificate issued to the user, only the certificate with the serial number is accepted.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"notAfter": {
+						SchemaProps: spec.SchemaProps{
+							Description: "notAfter is the expiration time of the certificate.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"name", "serialNumber", "notAfter"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
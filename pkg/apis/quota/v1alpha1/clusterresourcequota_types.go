@@ -37,7 +37,51 @@ type ClusterResourceQuotaSpec struct {
 
 // ClusterResourceQuotaStatus defines the observed state of ClusterResourceQuota
 type ClusterResourceQuotaStatus struct {
+	// Used is the total usage observed in the namespaces plus the
+	// reservations which are neither expired nor observed yet.
 	corev1.ResourceQuotaStatus `json:",inline" protobuf:"bytes,1,opt,name=resourceQuotaStatus"`
+	// Reservations are the usage reserved by the admitted requests, which
+	// may not be observed in the namespaces yet.
+	// +optional
+	Reservations []ClusterResourceQuotaReservation `json:"reservations,omitempty" protobuf:"bytes,2,rep,name=reservations"`
+	// Allocations are the resource quotas created in the namespaces with
+	// the same scopes, which split the hard limits across the namespaces.
+	// +optional
+	Allocations []ClusterResourceQuotaAllocation `json:"allocations,omitempty" protobuf:"bytes,3,rep,name=allocations"`
+	// Allocated is the hard limits summed over the allocations, which is
+	// no more than the hard limits of the cluster resource quota.
+	// +optional
+	Allocated corev1.ResourceList `json:"allocated,omitempty" protobuf:"bytes,4,rep,name=allocated,casttype=ResourceList,castkey=ResourceName"`
+}
+
+// ClusterResourceQuotaAllocation is a resource quota in a namespace of the
+// cluster resource quota, which allocates a part of its hard limits to the
+// namespace.
+type ClusterResourceQuotaAllocation struct {
+	// Namespace is the namespace of the resource quota.
+	Namespace string `json:"namespace" protobuf:"bytes,1,opt,name=namespace"`
+	// Name is the name of the resource quota.
+	Name string `json:"name" protobuf:"bytes,2,opt,name=name"`
+	// Hard is the hard limits of the resource quota.
+	Hard corev1.ResourceList `json:"hard" protobuf:"bytes,3,rep,name=hard,casttype=ResourceList,castkey=ResourceName"`
+}
+
+// ClusterResourceQuotaReservation is the usage reserved by the requests
+// admitted in a namespace, which is counted in the used until it expires.
+type ClusterResourceQuotaReservation struct {
+	// Namespace is the namespace of the admitted requests.
+	Namespace string `json:"namespace" protobuf:"bytes,1,opt,name=namespace"`
+	// Resources is the usage reserved by the admitted requests.
+	Resources corev1.ResourceList `json:"resources" protobuf:"bytes,2,rep,name=resources,casttype=ResourceList,castkey=ResourceName"`
+	// ExpirationTime is the time after which the usage is expected to be
+	// observed in the namespace, and the reservation is dropped.
+	ExpirationTime metav1.Time `json:"expirationTime" protobuf:"bytes,3,opt,name=expirationTime"`
+	// Baseline is the usage of the reserved resources when the usage is
+	// reserved, including the earlier reservations. The reservation is
+	// dropped before it expires once the usage observed in the namespaces
+	// reaches the baseline plus the reserved resources.
+	// +optional
+	Baseline corev1.ResourceList `json:"baseline,omitempty" protobuf:"bytes,4,rep,name=baseline,casttype=ResourceList,castkey=ResourceName"`
 }
 
 // +genclient
@@ -24,6 +24,9 @@ import (
 	io "io"
 
 	proto "github.com/gogo/protobuf/proto"
+	github_com_gogo_protobuf_sortkeys "github.com/gogo/protobuf/sortkeys"
+	k8s_io_api_core_v1 "k8s.io/api/core/v1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	math "math"
@@ -71,10 +74,38 @@ func (m *ClusterResourceQuota) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ClusterResourceQuota proto.InternalMessageInfo
 
+func (m *ClusterResourceQuotaAllocation) Reset()      { *m = ClusterResourceQuotaAllocation{} }
+func (*ClusterResourceQuotaAllocation) ProtoMessage() {}
+func (*ClusterResourceQuotaAllocation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06d0cad8aa51c71d, []int{1}
+}
+func (m *ClusterResourceQuotaAllocation) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ClusterResourceQuotaAllocation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *ClusterResourceQuotaAllocation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterResourceQuotaAllocation.Merge(m, src)
+}
+func (m *ClusterResourceQuotaAllocation) XXX_Size() int {
+	return m.Size()
+}
+func (m *ClusterResourceQuotaAllocation) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterResourceQuotaAllocation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterResourceQuotaAllocation proto.InternalMessageInfo
+
 func (m *ClusterResourceQuotaList) Reset()      { *m = ClusterResourceQuotaList{} }
 func (*ClusterResourceQuotaList) ProtoMessage() {}
 func (*ClusterResourceQuotaList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_06d0cad8aa51c71d, []int{1}
+	return fileDescriptor_06d0cad8aa51c71d, []int{2}
 }
 func (m *ClusterResourceQuotaList) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -99,10 +130,38 @@ func (m *ClusterResourceQuotaList) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ClusterResourceQuotaList proto.InternalMessageInfo
 
+func (m *ClusterResourceQuotaReservation) Reset()      { *m = ClusterResourceQuotaReservation{} }
+func (*ClusterResourceQuotaReservation) ProtoMessage() {}
+func (*ClusterResourceQuotaReservation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_06d0cad8aa51c71d, []int{3}
+}
+func (m *ClusterResourceQuotaReservation) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ClusterResourceQuotaReservation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *ClusterResourceQuotaReservation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterResourceQuotaReservation.Merge(m, src)
+}
+func (m *ClusterResourceQuotaReservation) XXX_Size() int {
+	return m.Size()
+}
+func (m *ClusterResourceQuotaReservation) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterResourceQuotaReservation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterResourceQuotaReservation proto.InternalMessageInfo
+
 func (m *ClusterResourceQuotaSpec) Reset()      { *m = ClusterResourceQuotaSpec{} }
 func (*ClusterResourceQuotaSpec) ProtoMessage() {}
 func (*ClusterResourceQuotaSpec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_06d0cad8aa51c71d, []int{2}
+	return fileDescriptor_06d0cad8aa51c71d, []int{4}
 }
 func (m *ClusterResourceQuotaSpec) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -130,7 +189,7 @@ var xxx_messageInfo_ClusterResourceQuotaSpec proto.InternalMessageInfo
 func (m *ClusterResourceQuotaStatus) Reset()      { *m = ClusterResourceQuotaStatus{} }
 func (*ClusterResourceQuotaStatus) ProtoMessage() {}
 func (*ClusterResourceQuotaStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_06d0cad8aa51c71d, []int{3}
+	return fileDescriptor_06d0cad8aa51c71d, []int{5}
 }
 func (m *ClusterResourceQuotaStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -157,9 +216,15 @@ var xxx_messageInfo_ClusterResourceQuotaStatus proto.InternalMessageInfo
 
 func init() {
 	proto.RegisterType((*ClusterResourceQuota)(nil), "github.com.kubewharf.kubezoo.pkg.apis.quota.v1alpha1.ClusterResourceQuota")
+	proto.RegisterType((*ClusterResourceQuotaAllocation)(nil), "github.com.kubewharf.kubezoo.pkg.apis.quota.v1alpha1.ClusterResourceQuotaAllocation")
+	proto.RegisterMapType((k8s_io_api_core_v1.ResourceList)(nil), "github.com.kubewharf.kubezoo.pkg.apis.quota.v1alpha1.ClusterResourceQuotaAllocation.HardEntry")
 	proto.RegisterType((*ClusterResourceQuotaList)(nil), "github.com.kubewharf.kubezoo.pkg.apis.quota.v1alpha1.ClusterResourceQuotaList")
+	proto.RegisterType((*ClusterResourceQuotaReservation)(nil), "github.com.kubewharf.kubezoo.pkg.apis.quota.v1alpha1.ClusterResourceQuotaReservation")
+	proto.RegisterMapType((k8s_io_api_core_v1.ResourceList)(nil), "github.com.kubewharf.kubezoo.pkg.apis.quota.v1alpha1.ClusterResourceQuotaReservation.BaselineEntry")
+	proto.RegisterMapType((k8s_io_api_core_v1.ResourceList)(nil), "github.com.kubewharf.kubezoo.pkg.apis.quota.v1alpha1.ClusterResourceQuotaReservation.ResourcesEntry")
 	proto.RegisterType((*ClusterResourceQuotaSpec)(nil), "github.com.kubewharf.kubezoo.pkg.apis.quota.v1alpha1.ClusterResourceQuotaSpec")
 	proto.RegisterType((*ClusterResourceQuotaStatus)(nil), "github.com.kubewharf.kubezoo.pkg.apis.quota.v1alpha1.ClusterResourceQuotaStatus")
+	proto.RegisterMapType((k8s_io_api_core_v1.ResourceList)(nil), "github.com.kubewharf.kubezoo.pkg.apis.quota.v1alpha1.ClusterResourceQuotaStatus.AllocatedEntry")
 }
 
 func init() {
@@ -167,43 +232,66 @@ func init() {
 }
 
 var fileDescriptor_06d0cad8aa51c71d = []byte{
-	// 566 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x94, 0xc1, 0x6e, 0xd3, 0x30,
-	0x18, 0xc7, 0x9b, 0x76, 0x9b, 0x86, 0x07, 0x13, 0xcd, 0x40, 0x2a, 0x45, 0xca, 0xa6, 0x4a, 0x88,
-	0x5e, 0x70, 0xe8, 0xa8, 0x10, 0xe7, 0xc0, 0x05, 0x04, 0x05, 0xb2, 0xdb, 0xc4, 0x01, 0xd7, 0xfb,
-	0x96, 0x86, 0x36, 0xb5, 0xb1, 0x9d, 0x32, 0x38, 0xf1, 0x08, 0x1c, 0x78, 0x14, 0x1e, 0xa2, 0xc7,
-	0x1d, 0x77, 0x9a, 0x68, 0xe0, 0xc2, 0x5b, 0x20, 0xbb, 0x6e, 0x1b, 0x2d, 0x9d, 0x36, 0x4d, 0xbb,
-	0xc5, 0x5f, 0xfc, 0xff, 0xff, 0xfc, 0xfd, 0xfd, 0x25, 0xe8, 0x45, 0x14, 0xab, 0x5e, 0xda, 0xc5,
-	0x94, 0x25, 0x7e, 0x3f, 0xed, 0xc2, 0x97, 0x1e, 0x11, 0x87, 0xe6, 0xe9, 0x1b, 0x63, 0x3e, 0xef,
-	0x47, 0x3e, 0xe1, 0xb1, 0xf4, 0x3f, 0xa7, 0x4c, 0x11, 0x7f, 0xd4, 0x22, 0x03, 0xde, 0x23, 0x2d,
-	0x3f, 0x82, 0x21, 0x08, 0xa2, 0xe0, 0x00, 0x73, 0xc1, 0x14, 0x73, 0xdb, 0x0b, 0x17, 0x3c, 0x77,
-	0xc1, 0xd6, 0x05, 0xf3, 0x7e, 0x84, 0xb5, 0x0b, 0x36, 0x2e, 0x78, 0xe6, 0x52, 0x7f, 0x94, 0x63,
-	0x47, 0x2c, 0x62, 0xbe, 0x31, 0xeb, 0xa6, 0x87, 0x66, 0x65, 0x16, 0xe6, 0x69, 0x0a, 0xa9, 0x37,
-	0xfa, 0xcf, 0x24, 0x8e, 0x99, 0x3e, 0x90, 0x4f, 0x99, 0x00, 0x7f, 0x54, 0x38, 0x48, 0xbd, 0xbd,
-	0xd8, 0x93, 0x10, 0xda, 0x8b, 0x87, 0x20, 0xbe, 0x2e, 0xba, 0x48, 0xc0, 0x34, 0x51, 0x50, 0xf9,
-	0xe7, 0xa9, 0x44, 0x3a, 0x54, 0x71, 0x02, 0x05, 0xc1, 0xd3, 0x8b, 0x04, 0x92, 0xf6, 0x20, 0x21,
-	0x67, 0x75, 0x8d, 0xbf, 0x65, 0x74, 0xe7, 0xf9, 0x20, 0x95, 0x0a, 0x44, 0x08, 0x92, 0xa5, 0x82,
-	0xc2, 0x7b, 0x9d, 0x89, 0xfb, 0x11, 0xad, 0xeb, 0xc3, 0x1d, 0x10, 0x45, 0x6a, 0xce, 0x8e, 0xd3,
-	0xdc, 0xd8, 0x7d, 0x8c, 0xa7, 0x0c, 0x9c, 0x67, 0x2c, 0xa2, 0xd4, 0xbb, 0xf1, 0xa8, 0x85, 0xdf,
-	0x76, 0x3f, 0x01, 0x55, 0x6f, 0x40, 0x91, 0xc0, 0x1d, 0x9f, 0x6e, 0x97, 0xb2, 0xd3, 0x6d, 0xb4,
-	0xa8, 0x85, 0x73, 0x57, 0x97, 0xa3, 0x15, 0xc9, 0x81, 0xd6, 0xca, 0xc6, 0xbd, 0x83, 0xaf, 0x72,
-	0x63, 0x78, 0xd9, 0xd9, 0xf7, 0x38, 0xd0, 0xe0, 0xa6, 0x65, 0xaf, 0xe8, 0x55, 0x68, 0x48, 0xee,
-	0x11, 0x5a, 0x93, 0x8a, 0xa8, 0x54, 0xd6, 0x2a, 0x86, 0xf9, 0xee, 0x1a, 0x99, 0xc6, 0x37, 0xd8,
-	0xb4, 0xd4, 0xb5, 0xe9, 0x3a, 0xb4, 0xbc, 0xc6, 0x3f, 0x07, 0xd5, 0x96, 0xc9, 0x5e, 0xc7, 0x52,
-	0xb9, 0x1f, 0x0a, 0x51, 0xe3, 0xcb, 0x45, 0xad, 0xd5, 0x26, 0xe8, 0xdb, 0x16, 0xbb, 0x3e, 0xab,
-	0xe4, 0x62, 0x66, 0x68, 0x35, 0x56, 0x90, 0xc8, 0x5a, 0x79, 0xa7, 0xd2, 0xdc, 0xd8, 0x7d, 0x75,
-	0x7d, 0x3d, 0x07, 0xb7, 0x2c, 0x76, 0xf5, 0xa5, 0x06, 0x84, 0x53, 0x4e, 0xe3, 0x57, 0x79, 0x79,
-	0xaf, 0xfa, 0x22, 0xdc, 0x21, 0xaa, 0x8a, 0xb3, 0x45, 0xdb, 0xf4, 0x83, 0x5c, 0xd3, 0x58, 0x7f,
-	0x4e, 0xba, 0xc5, 0xe2, 0xc5, 0xde, 0xb3, 0xd0, 0x6a, 0xe1, 0x55, 0x58, 0xb4, 0x76, 0x8f, 0x50,
-	0x75, 0x48, 0x12, 0x90, 0x9c, 0x50, 0xd8, 0x83, 0x01, 0x50, 0xc5, 0x84, 0x9d, 0xb8, 0x27, 0x97,
-	0x0c, 0x99, 0x74, 0x61, 0x30, 0x93, 0x06, 0x77, 0x35, 0xb9, 0x43, 0x12, 0xe0, 0x32, 0xe7, 0x18,
-	0x16, 0x21, 0x2e, 0x46, 0x68, 0x5e, 0xd4, 0x03, 0x57, 0x69, 0xde, 0x08, 0x36, 0xf5, 0xc7, 0xd0,
-	0x99, 0x57, 0xc3, 0xdc, 0x8e, 0xc6, 0x4f, 0x07, 0xd5, 0xcf, 0x9f, 0x2c, 0x77, 0x84, 0xb6, 0x44,
-	0xb1, 0x6c, 0xa3, 0x7b, 0x78, 0x71, 0x74, 0xd3, 0xf9, 0xbc, 0x6f, 0xc3, 0xdb, 0x5a, 0xf2, 0x32,
-	0x5c, 0x06, 0x08, 0xf6, 0xc7, 0x13, 0xaf, 0x74, 0x3c, 0xf1, 0x4a, 0x27, 0x13, 0xaf, 0xf4, 0x3d,
-	0xf3, 0x9c, 0x71, 0xe6, 0x39, 0xc7, 0x99, 0xe7, 0x9c, 0x64, 0x9e, 0xf3, 0x3b, 0xf3, 0x9c, 0x1f,
-	0x7f, 0xbc, 0xd2, 0x7e, 0xfb, 0x2a, 0x3f, 0xed, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0xa8, 0xba,
-	0xba, 0xa2, 0xeb, 0x05, 0x00, 0x00,
+	// 937 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0x4f, 0x6f, 0x1b, 0x45,
+	0x14, 0xf7, 0xda, 0x4e, 0x94, 0x9d, 0xa4, 0x56, 0x33, 0x29, 0xc8, 0x18, 0x69, 0x1d, 0x59, 0x42,
+	0x54, 0x48, 0xcc, 0x92, 0x12, 0xa1, 0x8a, 0x5b, 0x97, 0x56, 0x02, 0x04, 0x85, 0x4e, 0x0b, 0x48,
+	0x15, 0x02, 0xc6, 0xeb, 0xa9, 0xbd, 0xf5, 0xfe, 0x63, 0x66, 0xd6, 0x24, 0x9c, 0x10, 0x57, 0x0e,
+	0xf0, 0x25, 0xf8, 0x06, 0x7c, 0x06, 0x94, 0x63, 0xb9, 0x40, 0x25, 0xa4, 0x40, 0x0c, 0x17, 0xbe,
+	0x02, 0x5c, 0xd0, 0xcc, 0xce, 0xfe, 0x71, 0xd6, 0x6d, 0xd2, 0x68, 0x7d, 0xdb, 0x79, 0x33, 0xef,
+	0xf7, 0x7b, 0xef, 0x37, 0xf3, 0xde, 0xb3, 0xc1, 0xcd, 0xb1, 0x27, 0x26, 0xc9, 0x10, 0xb9, 0x51,
+	0x60, 0x4f, 0x93, 0x21, 0xfd, 0x6a, 0x42, 0xd8, 0x03, 0xf5, 0xf5, 0x75, 0x14, 0xd9, 0xf1, 0x74,
+	0x6c, 0x93, 0xd8, 0xe3, 0xf6, 0x97, 0x49, 0x24, 0x88, 0x3d, 0xdb, 0x23, 0x7e, 0x3c, 0x21, 0x7b,
+	0xf6, 0x98, 0x86, 0x94, 0x11, 0x41, 0x47, 0x28, 0x66, 0x91, 0x88, 0xe0, 0x7e, 0x81, 0x82, 0x72,
+	0x14, 0xa4, 0x51, 0x50, 0x3c, 0x1d, 0x23, 0x89, 0x82, 0x14, 0x0a, 0xca, 0x50, 0x7a, 0xaf, 0x96,
+	0xb8, 0xc7, 0xd1, 0x38, 0xb2, 0x15, 0xd8, 0x30, 0x79, 0xa0, 0x56, 0x6a, 0xa1, 0xbe, 0x52, 0x92,
+	0xde, 0x60, 0x7a, 0x9d, 0x23, 0x2f, 0x92, 0x01, 0xd9, 0x6e, 0xc4, 0xa8, 0x3d, 0xab, 0x04, 0xd2,
+	0xdb, 0x2f, 0xce, 0x04, 0xc4, 0x9d, 0x78, 0x21, 0x65, 0x87, 0x59, 0x16, 0x36, 0xa3, 0x3c, 0x4a,
+	0x98, 0x4b, 0x9f, 0xc9, 0x8b, 0xdb, 0x01, 0x55, 0xa9, 0x57, 0xbc, 0xec, 0x27, 0x79, 0xb1, 0x24,
+	0x14, 0x5e, 0x50, 0xa5, 0x79, 0xe3, 0x2c, 0x07, 0xee, 0x4e, 0x68, 0x40, 0x4e, 0xfb, 0x0d, 0xfe,
+	0x6e, 0x82, 0x2b, 0x6f, 0xf9, 0x09, 0x17, 0x94, 0x61, 0x9d, 0xc2, 0x1d, 0xa9, 0x24, 0xfc, 0x02,
+	0x6c, 0xc8, 0xe0, 0x46, 0x44, 0x90, 0xae, 0xb1, 0x6b, 0x5c, 0xdd, 0xbc, 0xf6, 0x1a, 0x4a, 0x39,
+	0x50, 0x99, 0xa3, 0xb8, 0x00, 0x79, 0x1a, 0xcd, 0xf6, 0xd0, 0x07, 0xc3, 0x87, 0xd4, 0x15, 0xef,
+	0x53, 0x41, 0x1c, 0x78, 0x74, 0xdc, 0x6f, 0xcc, 0x8f, 0xfb, 0xa0, 0xb0, 0xe1, 0x1c, 0x15, 0xc6,
+	0xa0, 0xcd, 0x63, 0xea, 0x76, 0x9b, 0x0a, 0xfd, 0x36, 0xba, 0xc8, 0x3d, 0xa3, 0x65, 0xb1, 0xdf,
+	0x8d, 0xa9, 0xeb, 0x6c, 0x69, 0xee, 0xb6, 0x5c, 0x61, 0xc5, 0x04, 0x0f, 0xc0, 0x3a, 0x17, 0x44,
+	0x24, 0xbc, 0xdb, 0x52, 0x9c, 0x1f, 0xd6, 0xc8, 0xa9, 0x70, 0x9d, 0x8e, 0x66, 0x5d, 0x4f, 0xd7,
+	0x58, 0xf3, 0x0d, 0x7e, 0x6c, 0x01, 0x6b, 0x99, 0xdb, 0x0d, 0xdf, 0x8f, 0x5c, 0x22, 0xbc, 0x28,
+	0x84, 0x36, 0x30, 0x43, 0x12, 0x50, 0x1e, 0x13, 0x97, 0x2a, 0xc5, 0x4d, 0x67, 0x5b, 0xa3, 0x99,
+	0xb7, 0xb3, 0x0d, 0x5c, 0x9c, 0x81, 0xbb, 0xa0, 0x2d, 0x17, 0x4a, 0x3f, 0xb3, 0xc8, 0x57, 0x9e,
+	0xc5, 0x6a, 0x07, 0xfe, 0x6c, 0x80, 0xf6, 0x84, 0xb0, 0x51, 0xb7, 0xb5, 0xdb, 0xba, 0xba, 0x79,
+	0xed, 0xb3, 0xfa, 0xd2, 0x2d, 0xe2, 0x46, 0x6f, 0x13, 0x36, 0xba, 0x15, 0x0a, 0x76, 0xe8, 0xe0,
+	0x2c, 0x04, 0x69, 0xfa, 0xf7, 0xb8, 0xdf, 0xaf, 0x16, 0x15, 0xca, 0x50, 0xde, 0xf3, 0xb8, 0xf8,
+	0xf6, 0x8f, 0xa7, 0x1e, 0x49, 0x13, 0x91, 0xf1, 0xf7, 0xc6, 0xc0, 0xcc, 0x69, 0xe0, 0x65, 0xd0,
+	0x9a, 0xd2, 0xc3, 0x54, 0x22, 0x2c, 0x3f, 0xe1, 0x4d, 0xb0, 0x36, 0x23, 0x7e, 0x42, 0xf5, 0x53,
+	0x42, 0x4f, 0x7b, 0xa8, 0x28, 0xab, 0x54, 0x74, 0x27, 0x21, 0xa1, 0xf0, 0xc4, 0x21, 0x4e, 0x9d,
+	0xdf, 0x6c, 0x5e, 0x37, 0x06, 0xff, 0x18, 0xa0, 0xbb, 0x2c, 0x5f, 0x19, 0x2e, 0xfc, 0xb4, 0x52,
+	0x12, 0xe8, 0x7c, 0x25, 0x21, 0xbd, 0x55, 0x41, 0x5c, 0xd6, 0x0a, 0x6d, 0x64, 0x96, 0x52, 0x39,
+	0x44, 0x60, 0xcd, 0x13, 0x34, 0xe0, 0xdd, 0xa6, 0xba, 0xac, 0x77, 0xeb, 0xbb, 0x2c, 0xe7, 0x92,
+	0xa6, 0x5d, 0x7b, 0x47, 0x12, 0xe0, 0x94, 0x67, 0xf0, 0xcb, 0x3a, 0xe8, 0x2f, 0x3b, 0x8e, 0x29,
+	0xa7, 0x6c, 0x76, 0xc1, 0x47, 0xf9, 0xbb, 0x01, 0xcc, 0x4c, 0xe1, 0x2c, 0x95, 0x51, 0x7d, 0xa9,
+	0x94, 0x62, 0xcb, 0xdf, 0x09, 0x4f, 0x5f, 0xdf, 0x27, 0x59, 0x5c, 0xb9, 0xbd, 0xa6, 0x27, 0x58,
+	0xe4, 0x03, 0x1f, 0x82, 0x0e, 0x3d, 0x88, 0x3d, 0xa6, 0x02, 0xb8, 0xe7, 0x05, 0x54, 0x37, 0x92,
+	0x57, 0xce, 0xf7, 0x0e, 0xa4, 0x87, 0xf3, 0xbc, 0x8e, 0xb3, 0x73, 0x6b, 0x01, 0x09, 0x9f, 0x42,
+	0x86, 0xbf, 0x19, 0x60, 0x63, 0x48, 0x38, 0xf5, 0xbd, 0x90, 0x76, 0xdb, 0x4a, 0x48, 0x77, 0x35,
+	0x42, 0x3a, 0x9a, 0x25, 0xd5, 0xf1, 0xe3, 0xec, 0x8d, 0x66, 0xe6, 0x9a, 0x64, 0xcc, 0x93, 0xe9,
+	0xf9, 0xa0, 0xb3, 0x78, 0x77, 0xab, 0x2c, 0xe9, 0xde, 0x14, 0x5c, 0x5a, 0x48, 0x70, 0xa5, 0xfd,
+	0xe3, 0xa7, 0xe6, 0xf2, 0xfe, 0x21, 0x87, 0x10, 0x0c, 0xc1, 0x36, 0x3b, 0x6d, 0xd4, 0x8d, 0xe4,
+	0xa5, 0x12, 0x25, 0x92, 0xf2, 0xa1, 0x92, 0x7c, 0xc5, 0x50, 0x7b, 0x41, 0xdf, 0xcd, 0x76, 0x65,
+	0x0b, 0x57, 0xa1, 0xe1, 0x01, 0xd8, 0xce, 0x0b, 0xf3, 0x2e, 0xf5, 0xa9, 0x2b, 0x22, 0xa6, 0x53,
+	0x7c, 0xfd, 0x9c, 0x8d, 0x8b, 0x0c, 0xa9, 0x9f, 0xb9, 0x3a, 0xcf, 0x49, 0x66, 0x79, 0x97, 0x31,
+	0x2f, 0x21, 0xe2, 0x2a, 0x09, 0x44, 0x00, 0xe4, 0x46, 0xae, 0xa6, 0x8f, 0xe9, 0x74, 0xe4, 0x0f,
+	0x81, 0xbc, 0x67, 0x70, 0x5c, 0x3a, 0x31, 0xf8, 0x6f, 0x0d, 0xf4, 0x9e, 0x3c, 0x55, 0xe1, 0x0c,
+	0xec, 0xb0, 0xaa, 0x59, 0x4b, 0xf7, 0xf2, 0xd9, 0xd2, 0xa5, 0xb3, 0xf9, 0x45, 0x2d, 0xde, 0xce,
+	0x92, 0x4d, 0xbc, 0x8c, 0x00, 0x7e, 0x6f, 0x80, 0x2d, 0x56, 0x14, 0x4a, 0xd6, 0xcf, 0x3e, 0x5a,
+	0x49, 0x19, 0x3a, 0x57, 0x74, 0x7c, 0x5b, 0x25, 0x23, 0xc7, 0x0b, 0x01, 0xc0, 0xef, 0x0c, 0xb0,
+	0x49, 0xf2, 0xd9, 0xcb, 0xf5, 0x60, 0xbf, 0xb7, 0x8a, 0xc1, 0xee, 0xec, 0xe8, 0x78, 0x36, 0x0b,
+	0x1b, 0xc7, 0x65, 0x76, 0xf8, 0xab, 0x01, 0x4c, 0xbd, 0xa6, 0x23, 0xdd, 0xa3, 0x3e, 0xaf, 0xfb,
+	0x37, 0x15, 0xba, 0x91, 0x31, 0x9c, 0xea, 0xf3, 0xb9, 0xbd, 0xae, 0x3e, 0x9f, 0xa7, 0x22, 0x3b,
+	0xd4, 0x22, 0xeb, 0x2a, 0x9b, 0x86, 0x73, 0xff, 0xe8, 0xc4, 0x6a, 0x3c, 0x3a, 0xb1, 0x1a, 0x8f,
+	0x4f, 0xac, 0xc6, 0x37, 0x73, 0xcb, 0x38, 0x9a, 0x5b, 0xc6, 0xa3, 0xb9, 0x65, 0x3c, 0x9e, 0x5b,
+	0xc6, 0x9f, 0x73, 0xcb, 0xf8, 0xe1, 0x2f, 0xab, 0x71, 0x7f, 0xff, 0x22, 0xff, 0xa6, 0xfe, 0x1f,
+	0x00, 0xd1, 0x54, 0x32, 0x77, 0x84, 0x0d, 0x00, 0x00,
 }
 
 func (m *ClusterResourceQuota) Marshal() (dAtA []byte, err error) {
@@ -259,6 +347,68 @@ func (m *ClusterResourceQuota) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *ClusterResourceQuotaAllocation) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ClusterResourceQuotaAllocation) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ClusterResourceQuotaAllocation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Hard) > 0 {
+		keysForHard := make([]string, 0, len(m.Hard))
+		for k := range m.Hard {
+			keysForHard = append(keysForHard, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForHard)
+		for iNdEx := len(keysForHard) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Hard[k8s_io_api_core_v1.ResourceName(keysForHard[iNdEx])]
+			baseI := i
+			{
+				size, err := (&v).MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForHard[iNdEx])
+			copy(dAtA[i:], keysForHard[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(keysForHard[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintGenerated(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	i -= len(m.Name)
+	copy(dAtA[i:], m.Name)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
+	i--
+	dAtA[i] = 0x12
+	i -= len(m.Namespace)
+	copy(dAtA[i:], m.Namespace)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Namespace)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
 func (m *ClusterResourceQuotaList) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -306,6 +456,102 @@ func (m *ClusterResourceQuotaList) MarshalToSizedBuffer(dAtA []byte) (int, error
 	return len(dAtA) - i, nil
 }
 
+func (m *ClusterResourceQuotaReservation) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ClusterResourceQuotaReservation) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ClusterResourceQuotaReservation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Baseline) > 0 {
+		keysForBaseline := make([]string, 0, len(m.Baseline))
+		for k := range m.Baseline {
+			keysForBaseline = append(keysForBaseline, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForBaseline)
+		for iNdEx := len(keysForBaseline) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Baseline[k8s_io_api_core_v1.ResourceName(keysForBaseline[iNdEx])]
+			baseI := i
+			{
+				size, err := (&v).MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForBaseline[iNdEx])
+			copy(dAtA[i:], keysForBaseline[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(keysForBaseline[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintGenerated(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	{
+		size, err := m.ExpirationTime.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x1a
+	if len(m.Resources) > 0 {
+		keysForResources := make([]string, 0, len(m.Resources))
+		for k := range m.Resources {
+			keysForResources = append(keysForResources, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForResources)
+		for iNdEx := len(keysForResources) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Resources[k8s_io_api_core_v1.ResourceName(keysForResources[iNdEx])]
+			baseI := i
+			{
+				size, err := (&v).MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForResources[iNdEx])
+			copy(dAtA[i:], keysForResources[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(keysForResources[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintGenerated(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	i -= len(m.Namespace)
+	copy(dAtA[i:], m.Namespace)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Namespace)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
 func (m *ClusterResourceQuotaSpec) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -380,6 +626,63 @@ func (m *ClusterResourceQuotaStatus) MarshalToSizedBuffer(dAtA []byte) (int, err
 	_ = i
 	var l int
 	_ = l
+	if len(m.Allocated) > 0 {
+		keysForAllocated := make([]string, 0, len(m.Allocated))
+		for k := range m.Allocated {
+			keysForAllocated = append(keysForAllocated, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForAllocated)
+		for iNdEx := len(keysForAllocated) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Allocated[k8s_io_api_core_v1.ResourceName(keysForAllocated[iNdEx])]
+			baseI := i
+			{
+				size, err := (&v).MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForAllocated[iNdEx])
+			copy(dAtA[i:], keysForAllocated[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(keysForAllocated[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintGenerated(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Allocations) > 0 {
+		for iNdEx := len(m.Allocations) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Allocations[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Reservations) > 0 {
+		for iNdEx := len(m.Reservations) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Reservations[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
 	{
 		size, err := m.ResourceQuotaStatus.MarshalToSizedBuffer(dAtA[:i])
 		if err != nil {
@@ -419,6 +722,28 @@ func (m *ClusterResourceQuota) Size() (n int) {
 	return n
 }
 
+func (m *ClusterResourceQuotaAllocation) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Namespace)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Name)
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Hard) > 0 {
+		for k, v := range m.Hard {
+			_ = k
+			_ = v
+			l = v.Size()
+			mapEntrySize := 1 + len(k) + sovGenerated(uint64(len(k))) + 1 + l + sovGenerated(uint64(l))
+			n += mapEntrySize + 1 + sovGenerated(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
 func (m *ClusterResourceQuotaList) Size() (n int) {
 	if m == nil {
 		return 0
@@ -436,6 +761,37 @@ func (m *ClusterResourceQuotaList) Size() (n int) {
 	return n
 }
 
+func (m *ClusterResourceQuotaReservation) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Namespace)
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Resources) > 0 {
+		for k, v := range m.Resources {
+			_ = k
+			_ = v
+			l = v.Size()
+			mapEntrySize := 1 + len(k) + sovGenerated(uint64(len(k))) + 1 + l + sovGenerated(uint64(l))
+			n += mapEntrySize + 1 + sovGenerated(uint64(mapEntrySize))
+		}
+	}
+	l = m.ExpirationTime.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Baseline) > 0 {
+		for k, v := range m.Baseline {
+			_ = k
+			_ = v
+			l = v.Size()
+			mapEntrySize := 1 + len(k) + sovGenerated(uint64(len(k))) + 1 + l + sovGenerated(uint64(l))
+			n += mapEntrySize + 1 + sovGenerated(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
 func (m *ClusterResourceQuotaSpec) Size() (n int) {
 	if m == nil {
 		return 0
@@ -465,6 +821,27 @@ func (m *ClusterResourceQuotaStatus) Size() (n int) {
 	_ = l
 	l = m.ResourceQuotaStatus.Size()
 	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Reservations) > 0 {
+		for _, e := range m.Reservations {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.Allocations) > 0 {
+		for _, e := range m.Allocations {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.Allocated) > 0 {
+		for k, v := range m.Allocated {
+			_ = k
+			_ = v
+			l = v.Size()
+			mapEntrySize := 1 + len(k) + sovGenerated(uint64(len(k))) + 1 + l + sovGenerated(uint64(l))
+			n += mapEntrySize + 1 + sovGenerated(uint64(mapEntrySize))
+		}
+	}
 	return n
 }
 
@@ -486,6 +863,28 @@ func (this *ClusterResourceQuota) String() string {
 	}, "")
 	return s
 }
+func (this *ClusterResourceQuotaAllocation) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForHard := make([]string, 0, len(this.Hard))
+	for k := range this.Hard {
+		keysForHard = append(keysForHard, string(k))
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForHard)
+	mapStringForHard := "k8s_io_api_core_v1.ResourceList{"
+	for _, k := range keysForHard {
+		mapStringForHard += fmt.Sprintf("%v: %v,", k, this.Hard[k8s_io_api_core_v1.ResourceName(k)])
+	}
+	mapStringForHard += "}"
+	s := strings.Join([]string{`&ClusterResourceQuotaAllocation{`,
+		`Namespace:` + fmt.Sprintf("%v", this.Namespace) + `,`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Hard:` + mapStringForHard + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *ClusterResourceQuotaList) String() string {
 	if this == nil {
 		return "nil"
@@ -502,6 +901,39 @@ func (this *ClusterResourceQuotaList) String() string {
 	}, "")
 	return s
 }
+func (this *ClusterResourceQuotaReservation) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForResources := make([]string, 0, len(this.Resources))
+	for k := range this.Resources {
+		keysForResources = append(keysForResources, string(k))
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForResources)
+	mapStringForResources := "k8s_io_api_core_v1.ResourceList{"
+	for _, k := range keysForResources {
+		mapStringForResources += fmt.Sprintf("%v: %v,", k, this.Resources[k8s_io_api_core_v1.ResourceName(k)])
+	}
+	mapStringForResources += "}"
+	keysForBaseline := make([]string, 0, len(this.Baseline))
+	for k := range this.Baseline {
+		keysForBaseline = append(keysForBaseline, string(k))
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForBaseline)
+	mapStringForBaseline := "k8s_io_api_core_v1.ResourceList{"
+	for _, k := range keysForBaseline {
+		mapStringForBaseline += fmt.Sprintf("%v: %v,", k, this.Baseline[k8s_io_api_core_v1.ResourceName(k)])
+	}
+	mapStringForBaseline += "}"
+	s := strings.Join([]string{`&ClusterResourceQuotaReservation{`,
+		`Namespace:` + fmt.Sprintf("%v", this.Namespace) + `,`,
+		`Resources:` + mapStringForResources + `,`,
+		`ExpirationTime:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ExpirationTime), "Time", "v1.Time", 1), `&`, ``, 1) + `,`,
+		`Baseline:` + mapStringForBaseline + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *ClusterResourceQuotaSpec) String() string {
 	if this == nil {
 		return "nil"
@@ -518,8 +950,31 @@ func (this *ClusterResourceQuotaStatus) String() string {
 	if this == nil {
 		return "nil"
 	}
+	repeatedStringForReservations := "[]ClusterResourceQuotaReservation{"
+	for _, f := range this.Reservations {
+		repeatedStringForReservations += strings.Replace(strings.Replace(f.String(), "ClusterResourceQuotaReservation", "ClusterResourceQuotaReservation", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForReservations += "}"
+	repeatedStringForAllocations := "[]ClusterResourceQuotaAllocation{"
+	for _, f := range this.Allocations {
+		repeatedStringForAllocations += strings.Replace(strings.Replace(f.String(), "ClusterResourceQuotaAllocation", "ClusterResourceQuotaAllocation", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForAllocations += "}"
+	keysForAllocated := make([]string, 0, len(this.Allocated))
+	for k := range this.Allocated {
+		keysForAllocated = append(keysForAllocated, string(k))
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForAllocated)
+	mapStringForAllocated := "k8s_io_api_core_v1.ResourceList{"
+	for _, k := range keysForAllocated {
+		mapStringForAllocated += fmt.Sprintf("%v: %v,", k, this.Allocated[k8s_io_api_core_v1.ResourceName(k)])
+	}
+	mapStringForAllocated += "}"
 	s := strings.Join([]string{`&ClusterResourceQuotaStatus{`,
 		`ResourceQuotaStatus:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ResourceQuotaStatus), "ResourceQuotaStatus", "v11.ResourceQuotaStatus", 1), `&`, ``, 1) + `,`,
+		`Reservations:` + repeatedStringForReservations + `,`,
+		`Allocations:` + repeatedStringForAllocations + `,`,
+		`Allocated:` + mapStringForAllocated + `,`,
 		`}`,
 	}, "")
 	return s
@@ -681,7 +1136,7 @@ func (m *ClusterResourceQuota) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ClusterResourceQuotaList) Unmarshal(dAtA []byte) error {
+func (m *ClusterResourceQuotaAllocation) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -704,17 +1159,17 @@ func (m *ClusterResourceQuotaList) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ClusterResourceQuotaList: wiretype end group for non-group")
+			return fmt.Errorf("proto: ClusterResourceQuotaAllocation: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ClusterResourceQuotaList: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ClusterResourceQuotaAllocation: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ListMeta", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -724,30 +1179,29 @@ func (m *ClusterResourceQuotaList) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.ListMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Namespace = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Items", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -757,13 +1211,257 @@ func (m *ClusterResourceQuotaList) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthGenerated
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hard", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Hard == nil {
+				m.Hard = make(k8s_io_api_core_v1.ResourceList)
+			}
+			var mapkey k8s_io_api_core_v1.ResourceName
+			mapvalue := &resource.Quantity{}
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = k8s_io_api_core_v1.ResourceName(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &resource.Quantity{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGenerated(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Hard[k8s_io_api_core_v1.ResourceName(mapkey)] = *mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ClusterResourceQuotaList) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ClusterResourceQuotaList: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ClusterResourceQuotaList: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ListMeta", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.ListMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Items", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
 			}
 			postIndex := iNdEx + msglen
 			if postIndex < 0 {
@@ -798,6 +1496,379 @@ func (m *ClusterResourceQuotaList) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ClusterResourceQuotaReservation) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ClusterResourceQuotaReservation: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ClusterResourceQuotaReservation: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Namespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Resources", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Resources == nil {
+				m.Resources = make(k8s_io_api_core_v1.ResourceList)
+			}
+			var mapkey k8s_io_api_core_v1.ResourceName
+			mapvalue := &resource.Quantity{}
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = k8s_io_api_core_v1.ResourceName(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &resource.Quantity{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGenerated(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Resources[k8s_io_api_core_v1.ResourceName(mapkey)] = *mapvalue
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpirationTime", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.ExpirationTime.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Baseline", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Baseline == nil {
+				m.Baseline = make(k8s_io_api_core_v1.ResourceList)
+			}
+			var mapkey k8s_io_api_core_v1.ResourceName
+			mapvalue := &resource.Quantity{}
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = k8s_io_api_core_v1.ResourceName(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &resource.Quantity{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGenerated(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Baseline[k8s_io_api_core_v1.ResourceName(mapkey)] = *mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *ClusterResourceQuotaSpec) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -1011,6 +2082,203 @@ func (m *ClusterResourceQuotaStatus) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reservations", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Reservations = append(m.Reservations, ClusterResourceQuotaReservation{})
+			if err := m.Reservations[len(m.Reservations)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Allocations", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Allocations = append(m.Allocations, ClusterResourceQuotaAllocation{})
+			if err := m.Allocations[len(m.Allocations)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Allocated", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Allocated == nil {
+				m.Allocated = make(k8s_io_api_core_v1.ResourceList)
+			}
+			var mapkey k8s_io_api_core_v1.ResourceName
+			mapvalue := &resource.Quantity{}
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = k8s_io_api_core_v1.ResourceName(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &resource.Quantity{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGenerated(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Allocated[k8s_io_api_core_v1.ResourceName(mapkey)] = *mapvalue
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -54,6 +55,29 @@ func (in *ClusterResourceQuota) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourceQuotaAllocation) DeepCopyInto(out *ClusterResourceQuotaAllocation) {
+	*out = *in
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceQuotaAllocation.
+func (in *ClusterResourceQuotaAllocation) DeepCopy() *ClusterResourceQuotaAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourceQuotaAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterResourceQuotaList) DeepCopyInto(out *ClusterResourceQuotaList) {
 	*out = *in
@@ -87,6 +111,37 @@ func (in *ClusterResourceQuotaList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourceQuotaReservation) DeepCopyInto(out *ClusterResourceQuotaReservation) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	in.ExpirationTime.DeepCopyInto(&out.ExpirationTime)
+	if in.Baseline != nil {
+		in, out := &in.Baseline, &out.Baseline
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceQuotaReservation.
+func (in *ClusterResourceQuotaReservation) DeepCopy() *ClusterResourceQuotaReservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourceQuotaReservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterResourceQuotaSpec) DeepCopyInto(out *ClusterResourceQuotaSpec) {
 	*out = *in
@@ -118,6 +173,27 @@ func (in *ClusterResourceQuotaSpec) DeepCopy() *ClusterResourceQuotaSpec {
 func (in *ClusterResourceQuotaStatus) DeepCopyInto(out *ClusterResourceQuotaStatus) {
 	*out = *in
 	in.ResourceQuotaStatus.DeepCopyInto(&out.ResourceQuotaStatus)
+	if in.Reservations != nil {
+		in, out := &in.Reservations, &out.Reservations
+		*out = make([]ClusterResourceQuotaReservation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Allocations != nil {
+		in, out := &in.Allocations, &out.Allocations
+		*out = make([]ClusterResourceQuotaAllocation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Allocated != nil {
+		in, out := &in.Allocated, &out.Allocated
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 	return
 }
 
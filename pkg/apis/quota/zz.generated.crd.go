@@ -163,6 +163,55 @@ func NewClusterResourceQuotaCRD() *apiextensionsv1.CustomResourceDefinition {
 							"status": {
 								Description: "ClusterResourceQuotaStatus defines the observed state of ClusterResourceQuota",
 								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"allocated": {
+										AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+											Allows: true,
+											Schema: &apiextensionsv1.JSONSchemaProps{
+												AnyOf: []apiextensionsv1.JSONSchemaProps{
+													{Type: "integer"},
+													{Type: "string"},
+												},
+												Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+												XIntOrString: true,
+											},
+										},
+										Description: "Allocated is the hard limits summed over the allocations, which is no more than the hard limits of the cluster resource quota.",
+										Type:        "object",
+									},
+									"allocations": {
+										Description: "Allocations are the resource quotas created in the namespaces with the same scopes, which split the hard limits across the namespaces.",
+										Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+											Description: "ClusterResourceQuotaAllocation is a resource quota in a namespace of the cluster resource quota, which allocates a part of its hard limits to the namespace.",
+											Properties: map[string]apiextensionsv1.JSONSchemaProps{
+												"hard": {
+													AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+														Allows: true,
+														Schema: &apiextensionsv1.JSONSchemaProps{
+															AnyOf: []apiextensionsv1.JSONSchemaProps{
+																{Type: "integer"},
+																{Type: "string"},
+															},
+															Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+															XIntOrString: true,
+														},
+													},
+													Description: "Hard is the hard limits of the resource quota.",
+													Type:        "object",
+												},
+												"name": {
+													Description: "Name is the name of the resource quota.",
+													Type:        "string",
+												},
+												"namespace": {
+													Description: "Namespace is the namespace of the resource quota.",
+													Type:        "string",
+												},
+											},
+											Required: []string{"hard", "name", "namespace"},
+											Type:     "object",
+										}},
+										Type: "array",
+									},
 									"hard": {
 										AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
 											Allows: true,
@@ -178,6 +227,56 @@ func NewClusterResourceQuotaCRD() *apiextensionsv1.CustomResourceDefinition {
 										Description: "Hard is the set of enforced hard limits for each named resource. More info: https://kubernetes.io/docs/concepts/policy/resource-quotas/",
 										Type:        "object",
 									},
+									"reservations": {
+										Description: "Reservations are the usage reserved by the admitted requests, which may not be observed in the namespaces yet.",
+										Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+											Description: "ClusterResourceQuotaReservation is the usage reserved by the requests admitted in a namespace, which is counted in the used until it expires.",
+											Properties: map[string]apiextensionsv1.JSONSchemaProps{
+												"baseline": {
+													AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+														Allows: true,
+														Schema: &apiextensionsv1.JSONSchemaProps{
+															AnyOf: []apiextensionsv1.JSONSchemaProps{
+																{Type: "integer"},
+																{Type: "string"},
+															},
+															Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+															XIntOrString: true,
+														},
+													},
+													Description: "Baseline is the usage of the reserved resources when the usage is reserved, including the earlier reservations. The reservation is dropped before it expires once the usage observed in the namespaces reaches the baseline plus the reserved resources.",
+													Type:        "object",
+												},
+												"expirationTime": {
+													Description: "ExpirationTime is the time after which the usage is expected to be observed in the namespace, and the reservation is dropped.",
+													Format:      "date-time",
+													Type:        "string",
+												},
+												"namespace": {
+													Description: "Namespace is the namespace of the admitted requests.",
+													Type:        "string",
+												},
+												"resources": {
+													AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+														Allows: true,
+														Schema: &apiextensionsv1.JSONSchemaProps{
+															AnyOf: []apiextensionsv1.JSONSchemaProps{
+																{Type: "integer"},
+																{Type: "string"},
+															},
+															Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+															XIntOrString: true,
+														},
+													},
+													Description: "Resources is the usage reserved by the admitted requests.",
+													Type:        "object",
+												},
+											},
+											Required: []string{"expirationTime", "namespace", "resources"},
+											Type:     "object",
+										}},
+										Type: "array",
+									},
 									"used": {
 										AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
 											Allows: true,
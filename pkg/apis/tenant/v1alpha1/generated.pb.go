@@ -27,6 +27,7 @@ import (
 	github_com_gogo_protobuf_sortkeys "github.com/gogo/protobuf/sortkeys"
 
 	k8s_io_api_core_v1 "k8s.io/api/core/v1"
+	v11 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	math "math"
@@ -74,10 +75,94 @@ func (m *Tenant) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Tenant proto.InternalMessageInfo
 
+func (m *TenantAudit) Reset()      { *m = TenantAudit{} }
+func (*TenantAudit) ProtoMessage() {}
+func (*TenantAudit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c99066acee17a8dc, []int{1}
+}
+func (m *TenantAudit) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TenantAudit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *TenantAudit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TenantAudit.Merge(m, src)
+}
+func (m *TenantAudit) XXX_Size() int {
+	return m.Size()
+}
+func (m *TenantAudit) XXX_DiscardUnknown() {
+	xxx_messageInfo_TenantAudit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TenantAudit proto.InternalMessageInfo
+
+func (m *TenantCRDLimits) Reset()      { *m = TenantCRDLimits{} }
+func (*TenantCRDLimits) ProtoMessage() {}
+func (*TenantCRDLimits) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c99066acee17a8dc, []int{2}
+}
+func (m *TenantCRDLimits) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TenantCRDLimits) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *TenantCRDLimits) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TenantCRDLimits.Merge(m, src)
+}
+func (m *TenantCRDLimits) XXX_Size() int {
+	return m.Size()
+}
+func (m *TenantCRDLimits) XXX_DiscardUnknown() {
+	xxx_messageInfo_TenantCRDLimits.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TenantCRDLimits proto.InternalMessageInfo
+
+func (m *TenantCRDUsage) Reset()      { *m = TenantCRDUsage{} }
+func (*TenantCRDUsage) ProtoMessage() {}
+func (*TenantCRDUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c99066acee17a8dc, []int{3}
+}
+func (m *TenantCRDUsage) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TenantCRDUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *TenantCRDUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TenantCRDUsage.Merge(m, src)
+}
+func (m *TenantCRDUsage) XXX_Size() int {
+	return m.Size()
+}
+func (m *TenantCRDUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_TenantCRDUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TenantCRDUsage proto.InternalMessageInfo
+
 func (m *TenantList) Reset()      { *m = TenantList{} }
 func (*TenantList) ProtoMessage() {}
 func (*TenantList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c99066acee17a8dc, []int{1}
+	return fileDescriptor_c99066acee17a8dc, []int{4}
 }
 func (m *TenantList) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -105,7 +190,7 @@ var xxx_messageInfo_TenantList proto.InternalMessageInfo
 func (m *TenantQuota) Reset()      { *m = TenantQuota{} }
 func (*TenantQuota) ProtoMessage() {}
 func (*TenantQuota) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c99066acee17a8dc, []int{2}
+	return fileDescriptor_c99066acee17a8dc, []int{5}
 }
 func (m *TenantQuota) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -130,10 +215,66 @@ func (m *TenantQuota) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_TenantQuota proto.InternalMessageInfo
 
+func (m *TenantQuotaStatus) Reset()      { *m = TenantQuotaStatus{} }
+func (*TenantQuotaStatus) ProtoMessage() {}
+func (*TenantQuotaStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c99066acee17a8dc, []int{6}
+}
+func (m *TenantQuotaStatus) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TenantQuotaStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *TenantQuotaStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TenantQuotaStatus.Merge(m, src)
+}
+func (m *TenantQuotaStatus) XXX_Size() int {
+	return m.Size()
+}
+func (m *TenantQuotaStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_TenantQuotaStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TenantQuotaStatus proto.InternalMessageInfo
+
+func (m *TenantScopedQuota) Reset()      { *m = TenantScopedQuota{} }
+func (*TenantScopedQuota) ProtoMessage() {}
+func (*TenantScopedQuota) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c99066acee17a8dc, []int{7}
+}
+func (m *TenantScopedQuota) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TenantScopedQuota) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *TenantScopedQuota) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TenantScopedQuota.Merge(m, src)
+}
+func (m *TenantScopedQuota) XXX_Size() int {
+	return m.Size()
+}
+func (m *TenantScopedQuota) XXX_DiscardUnknown() {
+	xxx_messageInfo_TenantScopedQuota.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TenantScopedQuota proto.InternalMessageInfo
+
 func (m *TenantSpec) Reset()      { *m = TenantSpec{} }
 func (*TenantSpec) ProtoMessage() {}
 func (*TenantSpec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c99066acee17a8dc, []int{3}
+	return fileDescriptor_c99066acee17a8dc, []int{8}
 }
 func (m *TenantSpec) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -161,7 +302,7 @@ var xxx_messageInfo_TenantSpec proto.InternalMessageInfo
 func (m *TenantStatus) Reset()      { *m = TenantStatus{} }
 func (*TenantStatus) ProtoMessage() {}
 func (*TenantStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c99066acee17a8dc, []int{4}
+	return fileDescriptor_c99066acee17a8dc, []int{9}
 }
 func (m *TenantStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -186,13 +327,80 @@ func (m *TenantStatus) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_TenantStatus proto.InternalMessageInfo
 
+func (m *TenantUser) Reset()      { *m = TenantUser{} }
+func (*TenantUser) ProtoMessage() {}
+func (*TenantUser) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c99066acee17a8dc, []int{10}
+}
+func (m *TenantUser) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TenantUser) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *TenantUser) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TenantUser.Merge(m, src)
+}
+func (m *TenantUser) XXX_Size() int {
+	return m.Size()
+}
+func (m *TenantUser) XXX_DiscardUnknown() {
+	xxx_messageInfo_TenantUser.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TenantUser proto.InternalMessageInfo
+
+func (m *TenantUserStatus) Reset()      { *m = TenantUserStatus{} }
+func (*TenantUserStatus) ProtoMessage() {}
+func (*TenantUserStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c99066acee17a8dc, []int{11}
+}
+func (m *TenantUserStatus) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TenantUserStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *TenantUserStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TenantUserStatus.Merge(m, src)
+}
+func (m *TenantUserStatus) XXX_Size() int {
+	return m.Size()
+}
+func (m *TenantUserStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_TenantUserStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TenantUserStatus proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*Tenant)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.Tenant")
+	proto.RegisterType((*TenantAudit)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantAudit")
+	proto.RegisterType((*TenantCRDLimits)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantCRDLimits")
+	proto.RegisterType((*TenantCRDUsage)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantCRDUsage")
 	proto.RegisterType((*TenantList)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantList")
 	proto.RegisterType((*TenantQuota)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantQuota")
 	proto.RegisterMapType((k8s_io_api_core_v1.ResourceList)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantQuota.HardEntry")
+	proto.RegisterType((*TenantQuotaStatus)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantQuotaStatus")
+	proto.RegisterMapType((k8s_io_api_core_v1.ResourceList)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantQuotaStatus.AllocatedEntry")
+	proto.RegisterMapType((k8s_io_api_core_v1.ResourceList)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantQuotaStatus.HardEntry")
+	proto.RegisterMapType((k8s_io_api_core_v1.ResourceList)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantQuotaStatus.UsedEntry")
+	proto.RegisterType((*TenantScopedQuota)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantScopedQuota")
+	proto.RegisterMapType((k8s_io_api_core_v1.ResourceList)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantScopedQuota.HardEntry")
 	proto.RegisterType((*TenantSpec)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantSpec")
 	proto.RegisterType((*TenantStatus)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantStatus")
+	proto.RegisterType((*TenantUser)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantUser")
+	proto.RegisterType((*TenantUserStatus)(nil), "github.com.kubewharf.kubezoo.pkg.apis.tenant.v1alpha1.TenantUserStatus")
 }
 
 func init() {
@@ -200,46 +408,86 @@ func init() {
 }
 
 var fileDescriptor_c99066acee17a8dc = []byte{
-	// 613 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x94, 0xc1, 0x6e, 0xd3, 0x4c,
-	0x10, 0xc7, 0x63, 0xb7, 0x89, 0xd2, 0x4d, 0xbf, 0x4f, 0xd5, 0x9e, 0xa2, 0x1c, 0x9c, 0xca, 0x07,
-	0xd4, 0x0b, 0xbb, 0xa4, 0xa2, 0x55, 0x85, 0xc4, 0x01, 0xd3, 0x4a, 0x54, 0x2a, 0x54, 0x5d, 0x38,
-	0x01, 0x07, 0x36, 0xf6, 0xd6, 0x36, 0x8e, 0xbd, 0xc6, 0x5e, 0x07, 0x85, 0x13, 0xe2, 0x09, 0xe0,
-	0x41, 0xb8, 0x71, 0xe4, 0x01, 0x72, 0x42, 0x3d, 0xf6, 0x14, 0x88, 0x79, 0x0b, 0x4e, 0x68, 0xd7,
-	0x4e, 0x1c, 0x11, 0xa8, 0xa0, 0xb9, 0x79, 0x36, 0x33, 0xbf, 0xff, 0xec, 0x7f, 0x66, 0x03, 0x8e,
-	0x5c, 0x5f, 0x78, 0x59, 0x1f, 0xd9, 0x3c, 0xc4, 0x41, 0xd6, 0x67, 0xaf, 0x3d, 0x9a, 0x9c, 0xab,
-	0xaf, 0x37, 0x9c, 0xe3, 0x38, 0x70, 0x31, 0x8d, 0xfd, 0x14, 0x0b, 0x16, 0xd1, 0x48, 0xe0, 0x61,
-	0x8f, 0x0e, 0x62, 0x8f, 0xf6, 0xb0, 0xcb, 0x22, 0x96, 0x50, 0xc1, 0x1c, 0x14, 0x27, 0x5c, 0x70,
-	0xb8, 0x57, 0x61, 0xd0, 0x1c, 0x83, 0x4a, 0x0c, 0x8a, 0x03, 0x17, 0x49, 0x0c, 0x2a, 0x30, 0x68,
-	0x86, 0xe9, 0xdc, 0x5c, 0x50, 0x77, 0xb9, 0xcb, 0xb1, 0xa2, 0xf5, 0xb3, 0x73, 0x15, 0xa9, 0x40,
-	0x7d, 0x15, 0x2a, 0x1d, 0x33, 0x38, 0x48, 0x91, 0xcf, 0x65, 0x4b, 0xd8, 0xe6, 0x09, 0xc3, 0xc3,
-	0xa5, 0x4e, 0x3a, 0xb7, 0xab, 0x9c, 0x90, 0xda, 0x9e, 0x1f, 0xb1, 0x64, 0x34, 0xbb, 0x07, 0x4e,
-	0x58, 0xca, 0xb3, 0xc4, 0x66, 0xff, 0x54, 0x95, 0xe2, 0x90, 0x09, 0xfa, 0x3b, 0xad, 0xfd, 0x3f,
-	0x55, 0x25, 0x59, 0x24, 0xfc, 0x90, 0xe1, 0xd4, 0xf6, 0x58, 0x48, 0x7f, 0xad, 0x33, 0x3f, 0xeb,
-	0xa0, 0xf1, 0x44, 0x59, 0x01, 0x5f, 0x80, 0xa6, 0xa4, 0x3b, 0x54, 0xd0, 0xb6, 0xb6, 0xad, 0xed,
-	0xb4, 0x76, 0x6f, 0xa1, 0x82, 0x8a, 0x16, 0xa9, 0x95, 0x85, 0x32, 0x1b, 0x0d, 0x7b, 0xe8, 0xb4,
-	0xff, 0x92, 0xd9, 0xe2, 0x21, 0x13, 0xd4, 0x82, 0xe3, 0x49, 0xb7, 0x96, 0x4f, 0xba, 0xa0, 0x3a,
-	0x23, 0x73, 0x2a, 0xb4, 0xc1, 0x7a, 0x1a, 0x33, 0xbb, 0xad, 0x2b, 0xfa, 0x3d, 0x74, 0xad, 0x49,
-	0xa1, 0xa2, 0xdd, 0xc7, 0x31, 0xb3, 0xad, 0xcd, 0x52, 0x6e, 0x5d, 0x46, 0x44, 0xc1, 0x61, 0x00,
-	0x1a, 0xa9, 0xa0, 0x22, 0x4b, 0xdb, 0x6b, 0x4a, 0xe6, 0xfe, 0x6a, 0x32, 0x0a, 0x65, 0xfd, 0x5f,
-	0x0a, 0x35, 0x8a, 0x98, 0x94, 0x12, 0xe6, 0x17, 0x0d, 0x80, 0x22, 0xf1, 0xc4, 0x4f, 0x05, 0x7c,
-	0xbe, 0x64, 0x21, 0xfa, 0x3b, 0x0b, 0x65, 0xb5, 0x32, 0x70, 0xab, 0x14, 0x6a, 0xce, 0x4e, 0x16,
-	0xec, 0xeb, 0x83, 0xba, 0x2f, 0x58, 0x98, 0xb6, 0xf5, 0xed, 0xb5, 0x9d, 0xd6, 0xee, 0xdd, 0x95,
-	0x2e, 0x66, 0xfd, 0x57, 0x2a, 0xd5, 0x8f, 0x25, 0x93, 0x14, 0x68, 0xf3, 0xa3, 0x0e, 0x5a, 0x45,
-	0xc2, 0x59, 0xc6, 0x05, 0x85, 0x9f, 0x34, 0xb0, 0xee, 0xd1, 0xc4, 0x69, 0x6b, 0x4a, 0xf3, 0x64,
-	0x25, 0x4d, 0x85, 0x44, 0x0f, 0x68, 0xe2, 0x1c, 0x45, 0x22, 0x19, 0x59, 0x64, 0x36, 0x3e, 0x79,
-	0xf4, 0x63, 0xd2, 0xed, 0x2e, 0x3f, 0x2a, 0x44, 0xca, 0x77, 0x22, 0xfd, 0x78, 0xf7, 0xf5, 0xca,
-	0x94, 0x47, 0x34, 0x64, 0x44, 0x75, 0xdb, 0x71, 0xc1, 0xc6, 0x5c, 0x06, 0x6e, 0x81, 0xb5, 0x80,
-	0x8d, 0xd4, 0x40, 0x36, 0x88, 0xfc, 0x84, 0x87, 0xa0, 0x3e, 0xa4, 0x83, 0x8c, 0x95, 0x9b, 0x78,
-	0xe5, 0x90, 0xd0, 0xec, 0xa5, 0xa2, 0xb3, 0x8c, 0x46, 0xc2, 0x17, 0x23, 0x52, 0x14, 0xdf, 0xd1,
-	0x0f, 0x34, 0xf3, 0xc3, 0x7c, 0x01, 0xe4, 0x0a, 0xc2, 0x0e, 0xd0, 0x7d, 0x47, 0x29, 0xd5, 0x2d,
-	0x50, 0xde, 0x4e, 0x3f, 0x3e, 0x24, 0xba, 0xef, 0x40, 0x17, 0xd4, 0x5f, 0x49, 0x03, 0x4a, 0x51,
-	0x6b, 0x75, 0x2b, 0xab, 0x19, 0xaa, 0x90, 0x14, 0x7c, 0x73, 0x1f, 0x6c, 0x2e, 0x2e, 0x2f, 0xbc,
-	0x01, 0x1a, 0x3c, 0x1a, 0xf8, 0x11, 0x53, 0x8d, 0x35, 0xab, 0x65, 0x3e, 0x55, 0xa7, 0xa4, 0xfc,
-	0xd5, 0x7a, 0x36, 0x9e, 0x1a, 0xb5, 0x8b, 0xa9, 0x51, 0xbb, 0x9c, 0x1a, 0xb5, 0xb7, 0xb9, 0xa1,
-	0x8d, 0x73, 0x43, 0xbb, 0xc8, 0x0d, 0xed, 0x32, 0x37, 0xb4, 0x6f, 0xb9, 0xa1, 0xbd, 0xff, 0x6e,
-	0xd4, 0x9e, 0xee, 0x5d, 0xeb, 0x6f, 0xfa, 0x67, 0x00, 0x00, 0x00, 0xff, 0xff, 0x06, 0xe1, 0x80,
-	0xa2, 0xde, 0x05, 0x00, 0x00,
+	// 1262 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0x4d, 0x6f, 0x1b, 0xc5,
+	0x1b, 0xcf, 0xfa, 0x2d, 0xf1, 0x38, 0x71, 0x93, 0xf9, 0xff, 0x41, 0x56, 0x0e, 0x76, 0x58, 0x54,
+	0x54, 0x90, 0xd8, 0x25, 0x85, 0xa0, 0xa8, 0x12, 0x48, 0xd9, 0x24, 0xd0, 0x8a, 0x24, 0x55, 0x27,
+	0x0d, 0x85, 0xc2, 0x81, 0xf1, 0xee, 0xc4, 0x5e, 0xbc, 0x2f, 0x66, 0x67, 0xd6, 0x34, 0x9c, 0x10,
+	0xea, 0x07, 0xe0, 0x1b, 0x70, 0x44, 0xdc, 0xb9, 0x80, 0x38, 0x70, 0xcc, 0xa9, 0xea, 0xb1, 0x27,
+	0x43, 0xcc, 0xb7, 0xe8, 0x09, 0xcd, 0xcb, 0xbe, 0xc4, 0x09, 0x25, 0xd4, 0x6e, 0x6e, 0x3b, 0xcf,
+	0x3c, 0xf3, 0xfb, 0x3d, 0x2f, 0xf3, 0x3c, 0xfb, 0xec, 0x82, 0xed, 0x8e, 0xcb, 0xba, 0x71, 0xdb,
+	0xb0, 0x43, 0xdf, 0xec, 0xc5, 0x6d, 0xf2, 0x75, 0x17, 0x47, 0x87, 0xe2, 0xe9, 0x9b, 0x30, 0x34,
+	0xfb, 0xbd, 0x8e, 0x89, 0xfb, 0x2e, 0x35, 0x19, 0x09, 0x70, 0xc0, 0xcc, 0xc1, 0x2a, 0xf6, 0xfa,
+	0x5d, 0xbc, 0x6a, 0x76, 0x48, 0x40, 0x22, 0xcc, 0x88, 0x63, 0xf4, 0xa3, 0x90, 0x85, 0x70, 0x2d,
+	0x83, 0x31, 0x52, 0x18, 0x43, 0xc1, 0x18, 0xfd, 0x5e, 0xc7, 0xe0, 0x30, 0x86, 0x84, 0x31, 0x12,
+	0x98, 0xe5, 0x37, 0x73, 0xec, 0x9d, 0xb0, 0x13, 0x9a, 0x02, 0xad, 0x1d, 0x1f, 0x8a, 0x95, 0x58,
+	0x88, 0x27, 0xc9, 0xb2, 0xac, 0xf7, 0xd6, 0xa9, 0xe1, 0x86, 0xdc, 0x24, 0xd3, 0x0e, 0x23, 0x62,
+	0x0e, 0xce, 0x58, 0xb2, 0xfc, 0x4e, 0xa6, 0xe3, 0x63, 0xbb, 0xeb, 0x06, 0x24, 0x3a, 0x4a, 0xfc,
+	0x30, 0x23, 0x42, 0xc3, 0x38, 0xb2, 0xc9, 0x7f, 0x3a, 0x45, 0x4d, 0x9f, 0x30, 0x7c, 0x1e, 0xd7,
+	0xbb, 0xff, 0x74, 0x2a, 0x8a, 0x03, 0xe6, 0xfa, 0xc4, 0xa4, 0x76, 0x97, 0xf8, 0x78, 0xfc, 0x9c,
+	0xfe, 0x5b, 0x01, 0x54, 0xee, 0x8a, 0x50, 0xc0, 0x2f, 0xc0, 0x1c, 0x47, 0x77, 0x30, 0xc3, 0x0d,
+	0x6d, 0x45, 0xbb, 0x56, 0xbb, 0xfe, 0x96, 0x21, 0x51, 0x8d, 0x3c, 0x6a, 0x16, 0x42, 0xae, 0x6d,
+	0x0c, 0x56, 0x8d, 0xdb, 0xed, 0x2f, 0x89, 0xcd, 0x76, 0x09, 0xc3, 0x16, 0x3c, 0x1e, 0xb6, 0x66,
+	0x46, 0xc3, 0x16, 0xc8, 0x64, 0x28, 0x45, 0x85, 0x36, 0x28, 0xd1, 0x3e, 0xb1, 0x1b, 0x05, 0x81,
+	0xbe, 0x61, 0x3c, 0x57, 0xa6, 0x0c, 0x69, 0xee, 0x7e, 0x9f, 0xd8, 0xd6, 0xbc, 0xa2, 0x2b, 0xf1,
+	0x15, 0x12, 0xe0, 0xb0, 0x07, 0x2a, 0x94, 0x61, 0x16, 0xd3, 0x46, 0x51, 0xd0, 0x6c, 0x4e, 0x46,
+	0x23, 0xa0, 0xac, 0xba, 0x22, 0xaa, 0xc8, 0x35, 0x52, 0x14, 0x7a, 0x1b, 0xd4, 0xa4, 0xde, 0x46,
+	0xec, 0xb8, 0x0c, 0xbe, 0x0a, 0xca, 0x1e, 0x19, 0x10, 0x4f, 0xc4, 0xaf, 0x6a, 0x2d, 0xa8, 0x53,
+	0xe5, 0x1d, 0x2e, 0x44, 0x72, 0x0f, 0x1a, 0x00, 0x84, 0xbe, 0xcb, 0x91, 0x3b, 0x84, 0x36, 0x0a,
+	0x2b, 0xc5, 0x6b, 0x55, 0xab, 0x2e, 0x62, 0x96, 0x4a, 0x51, 0x4e, 0x43, 0xff, 0x45, 0x03, 0x57,
+	0x24, 0xc9, 0x26, 0xda, 0xda, 0x71, 0x7d, 0x97, 0x51, 0x78, 0x15, 0xcc, 0xfa, 0xf8, 0xc1, 0x26,
+	0xda, 0xa2, 0x82, 0xaa, 0x6c, 0xd5, 0x46, 0xc3, 0xd6, 0xec, 0xae, 0x14, 0xa1, 0x64, 0x0f, 0x6e,
+	0x82, 0x25, 0x1f, 0x3f, 0xd8, 0x27, 0xd1, 0x80, 0x38, 0x1f, 0x93, 0x88, 0xba, 0x61, 0x40, 0x45,
+	0xf4, 0xcb, 0xd6, 0x4b, 0xa3, 0x61, 0x6b, 0x69, 0x77, 0x7c, 0x13, 0x9d, 0xd5, 0x87, 0x37, 0x40,
+	0x9d, 0x0b, 0xc5, 0xfd, 0xb1, 0x8e, 0x18, 0x91, 0x81, 0x2d, 0x5a, 0x70, 0x34, 0x6c, 0xd5, 0x77,
+	0x4f, 0xed, 0xa0, 0x31, 0x4d, 0xfd, 0x27, 0x0d, 0xd4, 0x53, 0xdb, 0x0f, 0x28, 0xee, 0x10, 0xb8,
+	0x02, 0x4a, 0x76, 0xe4, 0x24, 0x76, 0xa7, 0x19, 0x14, 0x86, 0x8b, 0x1d, 0xf8, 0x3e, 0xa8, 0xd3,
+	0xf3, 0x4c, 0x7e, 0x59, 0xe9, 0xd6, 0xc7, 0x6c, 0x1e, 0xd3, 0x86, 0x6b, 0xa0, 0x46, 0xcf, 0x58,
+	0xfb, 0x3f, 0x75, 0xb8, 0x96, 0x37, 0x37, 0xaf, 0xa7, 0x3f, 0xd2, 0x00, 0x90, 0xb6, 0xee, 0xb8,
+	0x94, 0xc1, 0xcf, 0xcf, 0x94, 0x83, 0x71, 0xb1, 0x72, 0xe0, 0xa7, 0x45, 0x31, 0x2c, 0x2a, 0xca,
+	0xb9, 0x44, 0x92, 0x2b, 0x85, 0x36, 0x28, 0xbb, 0x8c, 0xf8, 0x32, 0xff, 0xb5, 0xeb, 0xef, 0x4d,
+	0x74, 0x49, 0xb3, 0x8b, 0x76, 0x8b, 0x63, 0x22, 0x09, 0xad, 0xff, 0x50, 0x4c, 0x6e, 0xe7, 0x9d,
+	0x38, 0x64, 0x18, 0xfe, 0xac, 0x81, 0x52, 0x17, 0x47, 0x4e, 0x43, 0x13, 0x9c, 0x3b, 0x13, 0x71,
+	0x0a, 0x48, 0xe3, 0x26, 0x8e, 0x9c, 0xed, 0x80, 0x45, 0x47, 0x16, 0x4a, 0x12, 0xc9, 0x45, 0x4f,
+	0x87, 0xad, 0xd6, 0xd9, 0x06, 0x69, 0x20, 0xd5, 0xf3, 0x78, 0x3c, 0xbe, 0xfb, 0xe3, 0x99, 0x2a,
+	0x7b, 0xd8, 0x27, 0x48, 0x58, 0x0b, 0xfb, 0xa0, 0x42, 0xed, 0xb0, 0x4f, 0x1c, 0x15, 0xab, 0x9b,
+	0x93, 0x15, 0xb4, 0x80, 0x12, 0xd6, 0xe7, 0xaa, 0x5a, 0x08, 0x91, 0xe2, 0x59, 0xee, 0x80, 0x6a,
+	0xea, 0x18, 0x5c, 0x04, 0xc5, 0x1e, 0x39, 0x92, 0x15, 0x8d, 0xf8, 0x23, 0xdc, 0x02, 0xe5, 0x01,
+	0xf6, 0x62, 0xd2, 0x28, 0xfc, 0xfb, 0xb5, 0x30, 0x92, 0x3e, 0x6f, 0xdc, 0x89, 0x71, 0xc0, 0x5c,
+	0x76, 0x84, 0xe4, 0xe1, 0x1b, 0x85, 0x75, 0x4d, 0xff, 0x7d, 0x16, 0x2c, 0xe5, 0xc2, 0x29, 0x9b,
+	0x0b, 0xaf, 0x90, 0x00, 0xfb, 0x44, 0x35, 0x91, 0xb4, 0x42, 0x64, 0x48, 0xf8, 0x0e, 0xfc, 0x35,
+	0xc9, 0xa4, 0x8c, 0x08, 0x9a, 0x3c, 0x93, 0x92, 0xfa, 0x52, 0xf2, 0xc9, 0x8d, 0x8f, 0x29, 0x71,
+	0x1a, 0xc5, 0x29, 0x1b, 0x7f, 0x40, 0xc9, 0xb8, 0xf1, 0x5c, 0x34, 0x2d, 0xe3, 0xb9, 0xcd, 0xf0,
+	0x91, 0x06, 0xaa, 0xd8, 0xf3, 0x42, 0x9b, 0xbf, 0x43, 0x1b, 0x25, 0xe1, 0xc1, 0xbd, 0xa9, 0x79,
+	0xb0, 0x91, 0x20, 0x4b, 0x37, 0xee, 0x29, 0x37, 0xaa, 0xa9, 0x7c, 0x4a, 0xbe, 0x64, 0x2e, 0x5c,
+	0xda, 0x5d, 0xe7, 0x44, 0x69, 0x82, 0x5e, 0x28, 0x91, 0x07, 0xea, 0xa7, 0xe3, 0xf8, 0x42, 0x4b,
+	0xf8, 0x61, 0x29, 0x29, 0xe1, 0x5c, 0x67, 0xb9, 0xec, 0x12, 0xce, 0x51, 0x5f, 0x4a, 0x09, 0x7f,
+	0xa4, 0x5a, 0x32, 0x15, 0x35, 0x5c, 0xb5, 0xde, 0x4e, 0x9b, 0x28, 0x7d, 0x3a, 0x6c, 0x5d, 0x7d,
+	0x06, 0x88, 0xbc, 0xed, 0x5c, 0x53, 0x75, 0x5b, 0x0a, 0xef, 0x83, 0x05, 0xf1, 0xb4, 0x4f, 0x3c,
+	0x62, 0xb3, 0x30, 0x6a, 0x94, 0x44, 0x4e, 0x5e, 0xc9, 0xe5, 0xc4, 0xe0, 0x30, 0xfc, 0xdd, 0xba,
+	0x9f, 0x57, 0xb4, 0x96, 0x46, 0xc3, 0xd6, 0xc2, 0x29, 0x11, 0x3a, 0x0d, 0x75, 0x79, 0x9d, 0xfc,
+	0xc7, 0x52, 0x32, 0x3c, 0xf0, 0x51, 0x14, 0x2e, 0x83, 0x82, 0xeb, 0xa8, 0x11, 0x07, 0xa8, 0x34,
+	0x14, 0x6e, 0x6d, 0xa1, 0x82, 0xeb, 0xc0, 0x0e, 0x28, 0x7f, 0xc5, 0xa3, 0xa0, 0x48, 0xad, 0xc9,
+	0xbb, 0x47, 0xf6, 0xfe, 0x17, 0x4b, 0x24, 0xf1, 0x21, 0x05, 0x55, 0x3b, 0x72, 0xe4, 0xc4, 0xa8,
+	0x86, 0xe1, 0x0f, 0x26, 0x22, 0x4b, 0xe7, 0x4f, 0x6b, 0x81, 0x77, 0xa5, 0x74, 0x89, 0x32, 0x1e,
+	0x78, 0x08, 0xca, 0x31, 0x25, 0x11, 0x55, 0xbd, 0x71, 0xb2, 0x21, 0xff, 0x80, 0x92, 0x28, 0x73,
+	0x8e, 0xaf, 0x28, 0x92, 0xf0, 0xd0, 0x06, 0x65, 0xcc, 0x67, 0xee, 0x46, 0x79, 0x0a, 0x51, 0x14,
+	0xd3, 0xbb, 0x55, 0xe5, 0x24, 0xe2, 0x11, 0x49, 0x6c, 0x88, 0x00, 0xf0, 0xb8, 0x5b, 0x08, 0x07,
+	0x1d, 0xd2, 0xa8, 0x08, 0x26, 0xfd, 0xbc, 0x7b, 0xb9, 0x93, 0x6a, 0x89, 0xef, 0x12, 0x31, 0xce,
+	0x67, 0x32, 0x94, 0x43, 0xd1, 0x1f, 0x16, 0xc1, 0x7c, 0xfe, 0xdb, 0x02, 0xbe, 0x06, 0x2a, 0x61,
+	0xe0, 0xb9, 0x81, 0xec, 0x16, 0x73, 0xd9, 0x54, 0x72, 0x5b, 0x48, 0x91, 0xda, 0x85, 0x21, 0x98,
+	0xb3, 0x23, 0x47, 0x0c, 0xd1, 0xea, 0xea, 0x6c, 0x4f, 0x9a, 0x4d, 0x01, 0x66, 0xcd, 0xf3, 0x19,
+	0x35, 0x59, 0xa1, 0x94, 0x04, 0x7a, 0x49, 0x2a, 0xe5, 0x8b, 0xfa, 0xc3, 0x89, 0x53, 0xa9, 0x3e,
+	0xa6, 0xce, 0x4f, 0xa8, 0x9f, 0x94, 0x45, 0x69, 0x0a, 0x53, 0x5e, 0xee, 0xa5, 0x7a, 0x7e, 0x71,
+	0xe8, 0x9f, 0x26, 0xf5, 0xca, 0x8d, 0xb8, 0x40, 0xbf, 0x7e, 0x1d, 0xcc, 0x46, 0x64, 0x10, 0xf6,
+	0xc4, 0x18, 0xca, 0xd3, 0x74, 0x45, 0x29, 0xcd, 0x22, 0x29, 0x46, 0xc9, 0xbe, 0x7e, 0xac, 0x81,
+	0xc5, 0x71, 0xa7, 0x2f, 0xc0, 0xb0, 0x0e, 0xe6, 0x29, 0x89, 0x5c, 0xec, 0xed, 0xc5, 0x7e, 0x9b,
+	0x44, 0x82, 0xa6, 0x6a, 0xfd, 0x5f, 0x69, 0xce, 0xef, 0xe7, 0xf6, 0xd0, 0x29, 0x4d, 0xf8, 0x09,
+	0x98, 0x0b, 0x42, 0xb6, 0x71, 0xc8, 0x48, 0xa4, 0xea, 0xfc, 0x8d, 0x8b, 0x7d, 0xaa, 0xdc, 0x75,
+	0x7d, 0x92, 0x7d, 0xa6, 0xec, 0x29, 0x0c, 0x94, 0xa2, 0x59, 0x9f, 0x1d, 0x9f, 0x34, 0x67, 0x1e,
+	0x9f, 0x34, 0x67, 0x9e, 0x9c, 0x34, 0x67, 0xbe, 0x1d, 0x35, 0xb5, 0xe3, 0x51, 0x53, 0x7b, 0x3c,
+	0x6a, 0x6a, 0x4f, 0x46, 0x4d, 0xed, 0xcf, 0x51, 0x53, 0xfb, 0xfe, 0xaf, 0xe6, 0xcc, 0xfd, 0xb5,
+	0xe7, 0xfa, 0x73, 0xf3, 0xf7, 0x00, 0x1a, 0xdf, 0x9f, 0x2a, 0xf1, 0x11, 0x00, 0x00,
 }
 
 func (m *Tenant) Marshal() (dAtA []byte, err error) {
@@ -295,6 +543,113 @@ func (m *Tenant) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *TenantAudit) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TenantAudit) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TenantAudit) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.OmitStages) > 0 {
+		for iNdEx := len(m.OmitStages) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.OmitStages[iNdEx])
+			copy(dAtA[i:], m.OmitStages[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.OmitStages[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	i -= len(m.Level)
+	copy(dAtA[i:], m.Level)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Level)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *TenantCRDLimits) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TenantCRDLimits) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TenantCRDLimits) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.MaxSchemaBytes != nil {
+		i = encodeVarintGenerated(dAtA, i, uint64(*m.MaxSchemaBytes))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.MaxServedVersions != nil {
+		i = encodeVarintGenerated(dAtA, i, uint64(*m.MaxServedVersions))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.MaxCRDs != nil {
+		i = encodeVarintGenerated(dAtA, i, uint64(*m.MaxCRDs))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TenantCRDUsage) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TenantCRDUsage) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TenantCRDUsage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	i = encodeVarintGenerated(dAtA, i, uint64(m.SchemaBytes))
+	i--
+	dAtA[i] = 0x18
+	i = encodeVarintGenerated(dAtA, i, uint64(m.ServedVersions))
+	i--
+	dAtA[i] = 0x10
+	i = encodeVarintGenerated(dAtA, i, uint64(m.CRDs))
+	i--
+	dAtA[i] = 0x8
+	return len(dAtA) - i, nil
+}
+
 func (m *TenantList) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -362,6 +717,20 @@ func (m *TenantQuota) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Scoped) > 0 {
+		for iNdEx := len(m.Scoped) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Scoped[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
 	if len(m.Hard) > 0 {
 		keysForHard := make([]string, 0, len(m.Hard))
 		for k := range m.Hard {
@@ -394,7 +763,7 @@ func (m *TenantQuota) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *TenantSpec) Marshal() (dAtA []byte, err error) {
+func (m *TenantQuotaStatus) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -404,230 +773,2484 @@ func (m *TenantSpec) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TenantSpec) MarshalTo(dAtA []byte) (int, error) {
+func (m *TenantQuotaStatus) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TenantSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TenantQuotaStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	{
-		size, err := m.Quota.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
+	if len(m.Allocated) > 0 {
+		keysForAllocated := make([]string, 0, len(m.Allocated))
+		for k := range m.Allocated {
+			keysForAllocated = append(keysForAllocated, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForAllocated)
+		for iNdEx := len(keysForAllocated) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Allocated[k8s_io_api_core_v1.ResourceName(keysForAllocated[iNdEx])]
+			baseI := i
+			{
+				size, err := (&v).MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForAllocated[iNdEx])
+			copy(dAtA[i:], keysForAllocated[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(keysForAllocated[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintGenerated(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x22
 		}
-		i -= size
-		i = encodeVarintGenerated(dAtA, i, uint64(size))
-	}
-	i--
-	dAtA[i] = 0x12
-	i = encodeVarintGenerated(dAtA, i, uint64(m.ID))
-	i--
-	dAtA[i] = 0x8
-	return len(dAtA) - i, nil
-}
-
-func (m *TenantStatus) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
 	}
-	return dAtA[:n], nil
-}
-
-func (m *TenantStatus) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *TenantStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	i--
-	if m.Online {
-		dAtA[i] = 1
-	} else {
-		dAtA[i] = 0
+	if len(m.Used) > 0 {
+		keysForUsed := make([]string, 0, len(m.Used))
+		for k := range m.Used {
+			keysForUsed = append(keysForUsed, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForUsed)
+		for iNdEx := len(keysForUsed) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Used[k8s_io_api_core_v1.ResourceName(keysForUsed[iNdEx])]
+			baseI := i
+			{
+				size, err := (&v).MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForUsed[iNdEx])
+			copy(dAtA[i:], keysForUsed[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(keysForUsed[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintGenerated(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Hard) > 0 {
+		keysForHard := make([]string, 0, len(m.Hard))
+		for k := range m.Hard {
+			keysForHard = append(keysForHard, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForHard)
+		for iNdEx := len(keysForHard) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Hard[k8s_io_api_core_v1.ResourceName(keysForHard[iNdEx])]
+			baseI := i
+			{
+				size, err := (&v).MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForHard[iNdEx])
+			copy(dAtA[i:], keysForHard[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(keysForHard[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintGenerated(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x12
+		}
 	}
+	i -= len(m.Name)
+	copy(dAtA[i:], m.Name)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
 	i--
-	dAtA[i] = 0x8
+	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintGenerated(dAtA []byte, offset int, v uint64) int {
-	offset -= sovGenerated(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *TenantScopedQuota) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *Tenant) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = m.ObjectMeta.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	l = m.Spec.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	l = m.Status.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	return n
+
+func (m *TenantScopedQuota) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TenantList) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *TenantScopedQuota) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = m.ListMeta.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	if len(m.Items) > 0 {
-		for _, e := range m.Items {
-			l = e.Size()
-			n += 1 + l + sovGenerated(uint64(l))
+	if m.ScopeSelector != nil {
+		{
+			size, err := m.ScopeSelector.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0x22
 	}
-	return n
+	if len(m.Scopes) > 0 {
+		for iNdEx := len(m.Scopes) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Scopes[iNdEx])
+			copy(dAtA[i:], m.Scopes[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.Scopes[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Hard) > 0 {
+		keysForHard := make([]string, 0, len(m.Hard))
+		for k := range m.Hard {
+			keysForHard = append(keysForHard, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForHard)
+		for iNdEx := len(keysForHard) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Hard[k8s_io_api_core_v1.ResourceName(keysForHard[iNdEx])]
+			baseI := i
+			{
+				size, err := (&v).MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForHard[iNdEx])
+			copy(dAtA[i:], keysForHard[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(keysForHard[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintGenerated(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	i -= len(m.Name)
+	copy(dAtA[i:], m.Name)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
 }
 
-func (m *TenantQuota) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *TenantSpec) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *TenantSpec) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TenantSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Hard) > 0 {
-		for k, v := range m.Hard {
-			_ = k
-			_ = v
-			l = v.Size()
-			mapEntrySize := 1 + len(k) + sovGenerated(uint64(len(k))) + 1 + l + sovGenerated(uint64(l))
-			n += mapEntrySize + 1 + sovGenerated(uint64(mapEntrySize))
+	if m.LimitRange != nil {
+		{
+			size, err := m.LimitRange.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0x32
 	}
-	return n
+	if m.Audit != nil {
+		{
+			size, err := m.Audit.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Users) > 0 {
+		for iNdEx := len(m.Users) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Users[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if m.CRDLimits != nil {
+		{
+			size, err := m.CRDLimits.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	{
+		size, err := m.Quota.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	i = encodeVarintGenerated(dAtA, i, uint64(m.ID))
+	i--
+	dAtA[i] = 0x8
+	return len(dAtA) - i, nil
 }
 
-func (m *TenantSpec) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *TenantStatus) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *TenantStatus) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TenantStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	n += 1 + sovGenerated(uint64(m.ID))
-	l = m.Quota.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	return n
+	if len(m.Quota) > 0 {
+		for iNdEx := len(m.Quota) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Quota[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Users) > 0 {
+		for iNdEx := len(m.Users) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Users[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.CRDUsage != nil {
+		{
+			size, err := m.CRDUsage.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	i--
+	if m.Online {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x8
+	return len(dAtA) - i, nil
 }
 
-func (m *TenantStatus) Size() (n int) {
-	if m == nil {
+func (m *TenantUser) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TenantUser) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TenantUser) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	i--
+	if m.Revoked {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x10
+	i -= len(m.Name)
+	copy(dAtA[i:], m.Name)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *TenantUserStatus) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TenantUserStatus) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TenantUserStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.NotAfter.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x1a
+	i -= len(m.SerialNumber)
+	copy(dAtA[i:], m.SerialNumber)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.SerialNumber)))
+	i--
+	dAtA[i] = 0x12
+	i -= len(m.Name)
+	copy(dAtA[i:], m.Name)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintGenerated(dAtA []byte, offset int, v uint64) int {
+	offset -= sovGenerated(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Tenant) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.ObjectMeta.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	l = m.Spec.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	l = m.Status.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *TenantAudit) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Level)
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.OmitStages) > 0 {
+		for _, s := range m.OmitStages {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TenantCRDLimits) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.MaxCRDs != nil {
+		n += 1 + sovGenerated(uint64(*m.MaxCRDs))
+	}
+	if m.MaxServedVersions != nil {
+		n += 1 + sovGenerated(uint64(*m.MaxServedVersions))
+	}
+	if m.MaxSchemaBytes != nil {
+		n += 1 + sovGenerated(uint64(*m.MaxSchemaBytes))
+	}
+	return n
+}
+
+func (m *TenantCRDUsage) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovGenerated(uint64(m.CRDs))
+	n += 1 + sovGenerated(uint64(m.ServedVersions))
+	n += 1 + sovGenerated(uint64(m.SchemaBytes))
+	return n
+}
+
+func (m *TenantList) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.ListMeta.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Items) > 0 {
+		for _, e := range m.Items {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TenantQuota) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Hard) > 0 {
+		for k, v := range m.Hard {
+			_ = k
+			_ = v
+			l = v.Size()
+			mapEntrySize := 1 + len(k) + sovGenerated(uint64(len(k))) + 1 + l + sovGenerated(uint64(l))
+			n += mapEntrySize + 1 + sovGenerated(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Scoped) > 0 {
+		for _, e := range m.Scoped {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TenantQuotaStatus) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Hard) > 0 {
+		for k, v := range m.Hard {
+			_ = k
+			_ = v
+			l = v.Size()
+			mapEntrySize := 1 + len(k) + sovGenerated(uint64(len(k))) + 1 + l + sovGenerated(uint64(l))
+			n += mapEntrySize + 1 + sovGenerated(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Used) > 0 {
+		for k, v := range m.Used {
+			_ = k
+			_ = v
+			l = v.Size()
+			mapEntrySize := 1 + len(k) + sovGenerated(uint64(len(k))) + 1 + l + sovGenerated(uint64(l))
+			n += mapEntrySize + 1 + sovGenerated(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Allocated) > 0 {
+		for k, v := range m.Allocated {
+			_ = k
+			_ = v
+			l = v.Size()
+			mapEntrySize := 1 + len(k) + sovGenerated(uint64(len(k))) + 1 + l + sovGenerated(uint64(l))
+			n += mapEntrySize + 1 + sovGenerated(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *TenantScopedQuota) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Hard) > 0 {
+		for k, v := range m.Hard {
+			_ = k
+			_ = v
+			l = v.Size()
+			mapEntrySize := 1 + len(k) + sovGenerated(uint64(len(k))) + 1 + l + sovGenerated(uint64(l))
+			n += mapEntrySize + 1 + sovGenerated(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Scopes) > 0 {
+		for _, s := range m.Scopes {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if m.ScopeSelector != nil {
+		l = m.ScopeSelector.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	return n
+}
+
+func (m *TenantSpec) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovGenerated(uint64(m.ID))
+	l = m.Quota.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if m.CRDLimits != nil {
+		l = m.CRDLimits.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if len(m.Users) > 0 {
+		for _, e := range m.Users {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if m.Audit != nil {
+		l = m.Audit.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.LimitRange != nil {
+		l = m.LimitRange.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	return n
+}
+
+func (m *TenantStatus) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 2
+	if m.CRDUsage != nil {
+		l = m.CRDUsage.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if len(m.Users) > 0 {
+		for _, e := range m.Users {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.Quota) > 0 {
+		for _, e := range m.Quota {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TenantUser) Size() (n int) {
+	if m == nil {
 		return 0
 	}
-	var l int
-	_ = l
-	n += 2
-	return n
-}
+	var l int
+	_ = l
+	l = len(m.Name)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 2
+	return n
+}
+
+func (m *TenantUserStatus) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.SerialNumber)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = m.NotAfter.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func sovGenerated(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozGenerated(x uint64) (n int) {
+	return sovGenerated(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *Tenant) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Tenant{`,
+		`ObjectMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ObjectMeta), "ObjectMeta", "v1.ObjectMeta", 1), `&`, ``, 1) + `,`,
+		`Spec:` + strings.Replace(strings.Replace(this.Spec.String(), "TenantSpec", "TenantSpec", 1), `&`, ``, 1) + `,`,
+		`Status:` + strings.Replace(strings.Replace(this.Status.String(), "TenantStatus", "TenantStatus", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TenantAudit) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TenantAudit{`,
+		`Level:` + fmt.Sprintf("%v", this.Level) + `,`,
+		`OmitStages:` + fmt.Sprintf("%v", this.OmitStages) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TenantCRDLimits) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TenantCRDLimits{`,
+		`MaxCRDs:` + valueToStringGenerated(this.MaxCRDs) + `,`,
+		`MaxServedVersions:` + valueToStringGenerated(this.MaxServedVersions) + `,`,
+		`MaxSchemaBytes:` + valueToStringGenerated(this.MaxSchemaBytes) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TenantCRDUsage) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TenantCRDUsage{`,
+		`CRDs:` + fmt.Sprintf("%v", this.CRDs) + `,`,
+		`ServedVersions:` + fmt.Sprintf("%v", this.ServedVersions) + `,`,
+		`SchemaBytes:` + fmt.Sprintf("%v", this.SchemaBytes) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TenantList) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForItems := "[]Tenant{"
+	for _, f := range this.Items {
+		repeatedStringForItems += strings.Replace(strings.Replace(f.String(), "Tenant", "Tenant", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForItems += "}"
+	s := strings.Join([]string{`&TenantList{`,
+		`ListMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ListMeta), "ListMeta", "v1.ListMeta", 1), `&`, ``, 1) + `,`,
+		`Items:` + repeatedStringForItems + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TenantQuota) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForScoped := "[]TenantScopedQuota{"
+	for _, f := range this.Scoped {
+		repeatedStringForScoped += strings.Replace(strings.Replace(f.String(), "TenantScopedQuota", "TenantScopedQuota", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForScoped += "}"
+	keysForHard := make([]string, 0, len(this.Hard))
+	for k := range this.Hard {
+		keysForHard = append(keysForHard, string(k))
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForHard)
+	mapStringForHard := "k8s_io_api_core_v1.ResourceList{"
+	for _, k := range keysForHard {
+		mapStringForHard += fmt.Sprintf("%v: %v,", k, this.Hard[k8s_io_api_core_v1.ResourceName(k)])
+	}
+	mapStringForHard += "}"
+	s := strings.Join([]string{`&TenantQuota{`,
+		`Hard:` + mapStringForHard + `,`,
+		`Scoped:` + repeatedStringForScoped + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TenantQuotaStatus) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForHard := make([]string, 0, len(this.Hard))
+	for k := range this.Hard {
+		keysForHard = append(keysForHard, string(k))
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForHard)
+	mapStringForHard := "k8s_io_api_core_v1.ResourceList{"
+	for _, k := range keysForHard {
+		mapStringForHard += fmt.Sprintf("%v: %v,", k, this.Hard[k8s_io_api_core_v1.ResourceName(k)])
+	}
+	mapStringForHard += "}"
+	keysForUsed := make([]string, 0, len(this.Used))
+	for k := range this.Used {
+		keysForUsed = append(keysForUsed, string(k))
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForUsed)
+	mapStringForUsed := "k8s_io_api_core_v1.ResourceList{"
+	for _, k := range keysForUsed {
+		mapStringForUsed += fmt.Sprintf("%v: %v,", k, this.Used[k8s_io_api_core_v1.ResourceName(k)])
+	}
+	mapStringForUsed += "}"
+	keysForAllocated := make([]string, 0, len(this.Allocated))
+	for k := range this.Allocated {
+		keysForAllocated = append(keysForAllocated, string(k))
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForAllocated)
+	mapStringForAllocated := "k8s_io_api_core_v1.ResourceList{"
+	for _, k := range keysForAllocated {
+		mapStringForAllocated += fmt.Sprintf("%v: %v,", k, this.Allocated[k8s_io_api_core_v1.ResourceName(k)])
+	}
+	mapStringForAllocated += "}"
+	s := strings.Join([]string{`&TenantQuotaStatus{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Hard:` + mapStringForHard + `,`,
+		`Used:` + mapStringForUsed + `,`,
+		`Allocated:` + mapStringForAllocated + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TenantScopedQuota) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForHard := make([]string, 0, len(this.Hard))
+	for k := range this.Hard {
+		keysForHard = append(keysForHard, string(k))
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForHard)
+	mapStringForHard := "k8s_io_api_core_v1.ResourceList{"
+	for _, k := range keysForHard {
+		mapStringForHard += fmt.Sprintf("%v: %v,", k, this.Hard[k8s_io_api_core_v1.ResourceName(k)])
+	}
+	mapStringForHard += "}"
+	s := strings.Join([]string{`&TenantScopedQuota{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Hard:` + mapStringForHard + `,`,
+		`Scopes:` + fmt.Sprintf("%v", this.Scopes) + `,`,
+		`ScopeSelector:` + strings.Replace(fmt.Sprintf("%v", this.ScopeSelector), "ScopeSelector", "v11.ScopeSelector", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TenantSpec) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForUsers := "[]TenantUser{"
+	for _, f := range this.Users {
+		repeatedStringForUsers += strings.Replace(strings.Replace(f.String(), "TenantUser", "TenantUser", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForUsers += "}"
+	s := strings.Join([]string{`&TenantSpec{`,
+		`ID:` + fmt.Sprintf("%v", this.ID) + `,`,
+		`Quota:` + strings.Replace(strings.Replace(this.Quota.String(), "TenantQuota", "TenantQuota", 1), `&`, ``, 1) + `,`,
+		`CRDLimits:` + strings.Replace(this.CRDLimits.String(), "TenantCRDLimits", "TenantCRDLimits", 1) + `,`,
+		`Users:` + repeatedStringForUsers + `,`,
+		`Audit:` + strings.Replace(this.Audit.String(), "TenantAudit", "TenantAudit", 1) + `,`,
+		`LimitRange:` + strings.Replace(fmt.Sprintf("%v", this.LimitRange), "LimitRangeSpec", "v11.LimitRangeSpec", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TenantStatus) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForUsers := "[]TenantUserStatus{"
+	for _, f := range this.Users {
+		repeatedStringForUsers += strings.Replace(strings.Replace(f.String(), "TenantUserStatus", "TenantUserStatus", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForUsers += "}"
+	repeatedStringForQuota := "[]TenantQuotaStatus{"
+	for _, f := range this.Quota {
+		repeatedStringForQuota += strings.Replace(strings.Replace(f.String(), "TenantQuotaStatus", "TenantQuotaStatus", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForQuota += "}"
+	s := strings.Join([]string{`&TenantStatus{`,
+		`Online:` + fmt.Sprintf("%v", this.Online) + `,`,
+		`CRDUsage:` + strings.Replace(this.CRDUsage.String(), "TenantCRDUsage", "TenantCRDUsage", 1) + `,`,
+		`Users:` + repeatedStringForUsers + `,`,
+		`Quota:` + repeatedStringForQuota + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TenantUser) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TenantUser{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Revoked:` + fmt.Sprintf("%v", this.Revoked) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TenantUserStatus) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TenantUserStatus{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`SerialNumber:` + fmt.Sprintf("%v", this.SerialNumber) + `,`,
+		`NotAfter:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.NotAfter), "Time", "v1.Time", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringGenerated(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *Tenant) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Tenant: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Tenant: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ObjectMeta", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.ObjectMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Spec", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Spec.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Status.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TenantAudit) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TenantAudit: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TenantAudit: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Level", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Level = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OmitStages", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OmitStages = append(m.OmitStages, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TenantCRDLimits) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TenantCRDLimits: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TenantCRDLimits: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxCRDs", wireType)
+			}
+			var v int32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.MaxCRDs = &v
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxServedVersions", wireType)
+			}
+			var v int32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.MaxServedVersions = &v
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxSchemaBytes", wireType)
+			}
+			var v int64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.MaxSchemaBytes = &v
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TenantCRDUsage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TenantCRDUsage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TenantCRDUsage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CRDs", wireType)
+			}
+			m.CRDs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CRDs |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServedVersions", wireType)
+			}
+			m.ServedVersions = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ServedVersions |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SchemaBytes", wireType)
+			}
+			m.SchemaBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SchemaBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TenantList) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TenantList: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TenantList: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ListMeta", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.ListMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Items", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Items = append(m.Items, Tenant{})
+			if err := m.Items[len(m.Items)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TenantQuota) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TenantQuota: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TenantQuota: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hard", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Hard == nil {
+				m.Hard = make(k8s_io_api_core_v1.ResourceList)
+			}
+			var mapkey k8s_io_api_core_v1.ResourceName
+			mapvalue := &resource.Quantity{}
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = k8s_io_api_core_v1.ResourceName(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &resource.Quantity{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGenerated(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Hard[k8s_io_api_core_v1.ResourceName(mapkey)] = *mapvalue
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scoped", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Scoped = append(m.Scoped, TenantScopedQuota{})
+			if err := m.Scoped[len(m.Scoped)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TenantQuotaStatus) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TenantQuotaStatus: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TenantQuotaStatus: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hard", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Hard == nil {
+				m.Hard = make(k8s_io_api_core_v1.ResourceList)
+			}
+			var mapkey k8s_io_api_core_v1.ResourceName
+			mapvalue := &resource.Quantity{}
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = k8s_io_api_core_v1.ResourceName(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &resource.Quantity{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGenerated(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Hard[k8s_io_api_core_v1.ResourceName(mapkey)] = *mapvalue
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Used", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Used == nil {
+				m.Used = make(k8s_io_api_core_v1.ResourceList)
+			}
+			var mapkey k8s_io_api_core_v1.ResourceName
+			mapvalue := &resource.Quantity{}
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = k8s_io_api_core_v1.ResourceName(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &resource.Quantity{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGenerated(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Used[k8s_io_api_core_v1.ResourceName(mapkey)] = *mapvalue
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Allocated", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Allocated == nil {
+				m.Allocated = make(k8s_io_api_core_v1.ResourceList)
+			}
+			var mapkey k8s_io_api_core_v1.ResourceName
+			mapvalue := &resource.Quantity{}
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = k8s_io_api_core_v1.ResourceName(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &resource.Quantity{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGenerated(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Allocated[k8s_io_api_core_v1.ResourceName(mapkey)] = *mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TenantScopedQuota) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TenantScopedQuota: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TenantScopedQuota: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hard", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Hard == nil {
+				m.Hard = make(k8s_io_api_core_v1.ResourceList)
+			}
+			var mapkey k8s_io_api_core_v1.ResourceName
+			mapvalue := &resource.Quantity{}
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = k8s_io_api_core_v1.ResourceName(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &resource.Quantity{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGenerated(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Hard[k8s_io_api_core_v1.ResourceName(mapkey)] = *mapvalue
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scopes", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Scopes = append(m.Scopes, k8s_io_api_core_v1.ResourceQuotaScope(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ScopeSelector", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ScopeSelector == nil {
+				m.ScopeSelector = &v11.ScopeSelector{}
+			}
+			if err := m.ScopeSelector.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
 
-func sovGenerated(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozGenerated(x uint64) (n int) {
-	return sovGenerated(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (this *Tenant) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&Tenant{`,
-		`ObjectMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ObjectMeta), "ObjectMeta", "v1.ObjectMeta", 1), `&`, ``, 1) + `,`,
-		`Spec:` + strings.Replace(strings.Replace(this.Spec.String(), "TenantSpec", "TenantSpec", 1), `&`, ``, 1) + `,`,
-		`Status:` + strings.Replace(strings.Replace(this.Status.String(), "TenantStatus", "TenantStatus", 1), `&`, ``, 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *TenantList) String() string {
-	if this == nil {
-		return "nil"
-	}
-	repeatedStringForItems := "[]Tenant{"
-	for _, f := range this.Items {
-		repeatedStringForItems += strings.Replace(strings.Replace(f.String(), "Tenant", "Tenant", 1), `&`, ``, 1) + ","
-	}
-	repeatedStringForItems += "}"
-	s := strings.Join([]string{`&TenantList{`,
-		`ListMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ListMeta), "ListMeta", "v1.ListMeta", 1), `&`, ``, 1) + `,`,
-		`Items:` + repeatedStringForItems + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *TenantQuota) String() string {
-	if this == nil {
-		return "nil"
-	}
-	keysForHard := make([]string, 0, len(this.Hard))
-	for k := range this.Hard {
-		keysForHard = append(keysForHard, string(k))
-	}
-	github_com_gogo_protobuf_sortkeys.Strings(keysForHard)
-	mapStringForHard := "k8s_io_api_core_v1.ResourceList{"
-	for _, k := range keysForHard {
-		mapStringForHard += fmt.Sprintf("%v: %v,", k, this.Hard[k8s_io_api_core_v1.ResourceName(k)])
-	}
-	mapStringForHard += "}"
-	s := strings.Join([]string{`&TenantQuota{`,
-		`Hard:` + mapStringForHard + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *TenantSpec) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&TenantSpec{`,
-		`ID:` + fmt.Sprintf("%v", this.ID) + `,`,
-		`Quota:` + strings.Replace(strings.Replace(this.Quota.String(), "TenantQuota", "TenantQuota", 1), `&`, ``, 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *TenantStatus) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&TenantStatus{`,
-		`Online:` + fmt.Sprintf("%v", this.Online) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func valueToStringGenerated(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	return nil
 }
-func (m *Tenant) Unmarshal(dAtA []byte) error {
+func (m *TenantSpec) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -650,15 +3273,34 @@ func (m *Tenant) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Tenant: wiretype end group for non-group")
+			return fmt.Errorf("proto: TenantSpec: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Tenant: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TenantSpec: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			m.ID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ID |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ObjectMeta", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Quota", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -685,13 +3327,13 @@ func (m *Tenant) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.ObjectMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Quota.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Spec", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CRDLimits", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -718,13 +3360,16 @@ func (m *Tenant) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Spec.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.CRDLimits == nil {
+				m.CRDLimits = &TenantCRDLimits{}
+			}
+			if err := m.CRDLimits.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 3:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Users", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -751,7 +3396,80 @@ func (m *Tenant) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Status.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Users = append(m.Users, TenantUser{})
+			if err := m.Users[len(m.Users)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Audit", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Audit == nil {
+				m.Audit = &TenantAudit{}
+			}
+			if err := m.Audit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LimitRange", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LimitRange == nil {
+				m.LimitRange = &v11.LimitRangeSpec{}
+			}
+			if err := m.LimitRange.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -776,7 +3494,7 @@ func (m *Tenant) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TenantList) Unmarshal(dAtA []byte) error {
+func (m *TenantStatus) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -786,28 +3504,84 @@ func (m *TenantList) Unmarshal(dAtA []byte) error {
 			if shift >= 64 {
 				return ErrIntOverflowGenerated
 			}
-			if iNdEx >= l {
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TenantStatus: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TenantStatus: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Online", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Online = bool(v != 0)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CRDUsage", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			if m.CRDUsage == nil {
+				m.CRDUsage = &TenantCRDUsage{}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: TenantList: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TenantList: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			if err := m.CRDUsage.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ListMeta", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Users", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -834,13 +3608,14 @@ func (m *TenantList) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.ListMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Users = append(m.Users, TenantUserStatus{})
+			if err := m.Users[len(m.Users)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Items", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Quota", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -867,8 +3642,8 @@ func (m *TenantList) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Items = append(m.Items, Tenant{})
-			if err := m.Items[len(m.Items)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Quota = append(m.Quota, TenantQuotaStatus{})
+			if err := m.Quota[len(m.Quota)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -893,7 +3668,7 @@ func (m *TenantList) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TenantQuota) Unmarshal(dAtA []byte) error {
+func (m *TenantUser) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -916,17 +3691,17 @@ func (m *TenantQuota) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TenantQuota: wiretype end group for non-group")
+			return fmt.Errorf("proto: TenantUser: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TenantQuota: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TenantUser: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Hard", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -936,121 +3711,44 @@ func (m *TenantQuota) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Hard == nil {
-				m.Hard = make(k8s_io_api_core_v1.ResourceList)
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Revoked", wireType)
 			}
-			var mapkey k8s_io_api_core_v1.ResourceName
-			mapvalue := &resource.Quantity{}
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowGenerated
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
 				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowGenerated
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthGenerated
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthGenerated
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = k8s_io_api_core_v1.ResourceName(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var mapmsglen int
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowGenerated
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						mapmsglen |= int(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					if mapmsglen < 0 {
-						return ErrInvalidLengthGenerated
-					}
-					postmsgIndex := iNdEx + mapmsglen
-					if postmsgIndex < 0 {
-						return ErrInvalidLengthGenerated
-					}
-					if postmsgIndex > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapvalue = &resource.Quantity{}
-					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
-						return err
-					}
-					iNdEx = postmsgIndex
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipGenerated(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if (skippy < 0) || (iNdEx+skippy) < 0 {
-						return ErrInvalidLengthGenerated
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
 				}
 			}
-			m.Hard[k8s_io_api_core_v1.ResourceName(mapkey)] = *mapvalue
-			iNdEx = postIndex
+			m.Revoked = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -1072,7 +3770,7 @@ func (m *TenantQuota) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TenantSpec) Unmarshal(dAtA []byte) error {
+func (m *TenantUserStatus) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1095,17 +3793,17 @@ func (m *TenantSpec) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TenantSpec: wiretype end group for non-group")
+			return fmt.Errorf("proto: TenantUserStatus: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TenantSpec: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TenantUserStatus: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			m.ID = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -1115,16 +3813,29 @@ func (m *TenantSpec) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ID |= int32(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Quota", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SerialNumber", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -1134,80 +3845,29 @@ func (m *TenantSpec) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Quota.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.SerialNumber = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipGenerated(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *TenantStatus) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowGenerated
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: TenantStatus: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TenantStatus: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Online", wireType)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NotAfter", wireType)
 			}
-			var v int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -1217,12 +3877,25 @@ func (m *TenantStatus) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Online = bool(v != 0)
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.NotAfter.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -63,6 +63,24 @@ type TenantList struct {
 type TenantSpec struct {
 	ID    int32       `json:"id" protobuf:"varint,1,name=id"`
 	Quota TenantQuota `json:"quota" protobuf:"bytes,2,name=quota"`
+	// crdLimits limits the custom resource definitions created by the tenant.
+	// +optional
+	CRDLimits *TenantCRDLimits `json:"crdLimits,omitempty" protobuf:"bytes,3,opt,name=crdLimits"`
+	// users are the named users of the tenant besides the admin user.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Users []TenantUser `json:"users,omitempty" protobuf:"bytes,4,rep,name=users"`
+	// audit is the audit policy of the requests of the tenant, which takes
+	// precedence over the audit policy of kubezoo.
+	// +optional
+	Audit *TenantAudit `json:"audit,omitempty" protobuf:"bytes,5,opt,name=audit"`
+	// limitRange is the default limit range kept in every namespace of the
+	// tenant, which sets the default requests and limits of the containers
+	// and bounds them. It is read-only to the tenant, who can only tighten
+	// the bounds with more limit ranges.
+	// +optional
+	LimitRange *corev1.LimitRangeSpec `json:"limitRange,omitempty" protobuf:"bytes,6,opt,name=limitRange"`
 }
 
 type TenantQuota struct {
@@ -70,12 +88,137 @@ type TenantQuota struct {
 	// More info: https://kubernetes.io/docs/concepts/policy/resource-quotas/
 	// +optional
 	Hard corev1.ResourceList `json:"hard,omitempty" protobuf:"bytes,1,rep,name=hard,casttype=ResourceList,castkey=ResourceName"`
+	// scoped are the quotas which only track the usage of the objects
+	// matched by their scopes, e.g. the BestEffort pods or the pods of a
+	// priority class, each of which is enforced across the namespaces of
+	// the tenant separately.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Scoped []TenantScopedQuota `json:"scoped,omitempty" protobuf:"bytes,2,rep,name=scoped"`
+}
+
+// TenantScopedQuota describes a quota of the tenant which is only enforced
+// on the objects matched by its scopes, as the resource quota does.
+type TenantScopedQuota struct {
+	// name is the name of the scoped quota, which must be a DNS label.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// hard is the set of desired hard limits for each named resource.
+	// +optional
+	Hard corev1.ResourceList `json:"hard,omitempty" protobuf:"bytes,2,rep,name=hard,casttype=ResourceList,castkey=ResourceName"`
+	// scopes is the collection of filters which must all match the objects
+	// tracked by the quota.
+	// +optional
+	Scopes []corev1.ResourceQuotaScope `json:"scopes,omitempty" protobuf:"bytes,3,rep,name=scopes,casttype=k8s.io/api/core/v1.ResourceQuotaScope"`
+	// scopeSelector is the collection of filters like scopes that must match
+	// the objects tracked by the quota, expressed with the operators.
+	// +optional
+	ScopeSelector *corev1.ScopeSelector `json:"scopeSelector,omitempty" protobuf:"bytes,4,opt,name=scopeSelector"`
+}
+
+// TenantCRDLimits describes the limits of the custom resource definitions
+// belonged to a tenant, a nil limit means no limit.
+type TenantCRDLimits struct {
+	// maxCRDs is the max number of crds the tenant can create.
+	// +optional
+	MaxCRDs *int32 `json:"maxCRDs,omitempty" protobuf:"varint,1,opt,name=maxCRDs"`
+	// maxServedVersions is the max number of served versions summed over
+	// all crds of the tenant.
+	// +optional
+	MaxServedVersions *int32 `json:"maxServedVersions,omitempty" protobuf:"varint,2,opt,name=maxServedVersions"`
+	// maxSchemaBytes is the max size in bytes of the openapi schemas summed
+	// over all versions of all crds of the tenant.
+	// +optional
+	MaxSchemaBytes *int64 `json:"maxSchemaBytes,omitempty" protobuf:"varint,3,opt,name=maxSchemaBytes"`
+}
+
+// TenantCRDUsage describes the usage of the custom resource definitions
+// belonged to a tenant.
+type TenantCRDUsage struct {
+	// crds is the number of crds of the tenant.
+	CRDs int32 `json:"crds" protobuf:"varint,1,opt,name=crds"`
+	// servedVersions is the number of served versions summed over all
+	// crds of the tenant.
+	ServedVersions int32 `json:"servedVersions" protobuf:"varint,2,opt,name=servedVersions"`
+	// schemaBytes is the size in bytes of the openapi schemas summed over
+	// all versions of all crds of the tenant.
+	SchemaBytes int64 `json:"schemaBytes" protobuf:"varint,3,opt,name=schemaBytes"`
+}
+
+// TenantUser describes a named user of the tenant. The certificate of the
+// user is issued with the common name <tenant>-<name> under the tenant OU,
+// and the user is authorized by the RBAC rules of the tenant.
+type TenantUser struct {
+	// name is the name of the user in the tenant, which must be a DNS label
+	// other than "admin".
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// revoked revokes the certificate issued to the user, no certificate is
+	// issued to the user until it is unset.
+	// +optional
+	Revoked bool `json:"revoked,omitempty" protobuf:"varint,2,opt,name=revoked"`
+}
+
+// TenantAudit describes how the requests of the tenant are audited.
+type TenantAudit struct {
+	// level is the audit level of the requests of the tenant, one of None,
+	// Metadata, Request and RequestResponse.
+	Level string `json:"level" protobuf:"bytes,1,opt,name=level"`
+	// omitStages are the stages which are not recorded for the requests of
+	// the tenant, e.g. RequestReceived.
+	// +optional
+	OmitStages []string `json:"omitStages,omitempty" protobuf:"bytes,2,rep,name=omitStages"`
+}
+
+// TenantUserStatus describes the certificate issued to a named user of
+// the tenant.
+type TenantUserStatus struct {
+	// name is the name of the user in the tenant.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// serialNumber is the serial number of the certificate issued to the
+	// user, only the certificate with the serial number is accepted.
+	SerialNumber string `json:"serialNumber" protobuf:"bytes,2,opt,name=serialNumber"`
+	// notAfter is the expiration time of the certificate.
+	NotAfter metav1.Time `json:"notAfter" protobuf:"bytes,3,opt,name=notAfter"`
+}
+
+// TenantQuotaStatus describes the usage of a quota of the tenant summed
+// over the namespaces of the tenant.
+type TenantQuotaStatus struct {
+	// name is the name of the scoped quota, or empty for the quota without
+	// scopes.
+	// +optional
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// hard is the set of enforced hard limits for each named resource.
+	// +optional
+	Hard corev1.ResourceList `json:"hard,omitempty" protobuf:"bytes,2,rep,name=hard,casttype=ResourceList,castkey=ResourceName"`
+	// used is the current observed total usage of the resource across the
+	// namespaces of the tenant.
+	// +optional
+	Used corev1.ResourceList `json:"used,omitempty" protobuf:"bytes,3,rep,name=used,casttype=ResourceList,castkey=ResourceName"`
+	// allocated is the hard limits summed over the resource quotas created
+	// by the tenant in its namespaces, which allocate the quota to the
+	// namespaces.
+	// +optional
+	Allocated corev1.ResourceList `json:"allocated,omitempty" protobuf:"bytes,4,rep,name=allocated,casttype=ResourceList,castkey=ResourceName"`
 }
 
 // TenantStatus represents the current state of a rule.
 type TenantStatus struct {
 	// Current state of tenant.
 	Online bool `json:"online,omitempty" protobuf:"bytes,1,name=online"`
+	// crdUsage is the current usage of the crds of the tenant.
+	// +optional
+	CRDUsage *TenantCRDUsage `json:"crdUsage,omitempty" protobuf:"bytes,2,opt,name=crdUsage"`
+	// users are the certificates issued to the named users of the tenant.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Users []TenantUserStatus `json:"users,omitempty" protobuf:"bytes,3,rep,name=users"`
+	// quota is the usage of the quotas of the tenant, one per scope.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Quota []TenantQuotaStatus `json:"quota,omitempty" protobuf:"bytes,4,rep,name=quota"`
 }
 
 var _ resource.Object = &Tenant{}
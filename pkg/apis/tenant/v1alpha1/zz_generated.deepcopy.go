@@ -31,7 +31,7 @@ func (in *Tenant) DeepCopyInto(out *Tenant) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -53,6 +53,74 @@ func (in *Tenant) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantAudit) DeepCopyInto(out *TenantAudit) {
+	*out = *in
+	if in.OmitStages != nil {
+		in, out := &in.OmitStages, &out.OmitStages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantAudit.
+func (in *TenantAudit) DeepCopy() *TenantAudit {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantAudit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantCRDLimits) DeepCopyInto(out *TenantCRDLimits) {
+	*out = *in
+	if in.MaxCRDs != nil {
+		in, out := &in.MaxCRDs, &out.MaxCRDs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxServedVersions != nil {
+		in, out := &in.MaxServedVersions, &out.MaxServedVersions
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxSchemaBytes != nil {
+		in, out := &in.MaxSchemaBytes, &out.MaxSchemaBytes
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantCRDLimits.
+func (in *TenantCRDLimits) DeepCopy() *TenantCRDLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantCRDLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantCRDUsage) DeepCopyInto(out *TenantCRDUsage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantCRDUsage.
+func (in *TenantCRDUsage) DeepCopy() *TenantCRDUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantCRDUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TenantList) DeepCopyInto(out *TenantList) {
 	*out = *in
@@ -96,6 +164,13 @@ func (in *TenantQuota) DeepCopyInto(out *TenantQuota) {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.Scoped != nil {
+		in, out := &in.Scoped, &out.Scoped
+		*out = make([]TenantScopedQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -109,10 +184,100 @@ func (in *TenantQuota) DeepCopy() *TenantQuota {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantQuotaStatus) DeepCopyInto(out *TenantQuotaStatus) {
+	*out = *in
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Allocated != nil {
+		in, out := &in.Allocated, &out.Allocated
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantQuotaStatus.
+func (in *TenantQuotaStatus) DeepCopy() *TenantQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantScopedQuota) DeepCopyInto(out *TenantScopedQuota) {
+	*out = *in
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]v1.ResourceQuotaScope, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScopeSelector != nil {
+		in, out := &in.ScopeSelector, &out.ScopeSelector
+		*out = new(v1.ScopeSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantScopedQuota.
+func (in *TenantScopedQuota) DeepCopy() *TenantScopedQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantScopedQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
 	*out = *in
 	in.Quota.DeepCopyInto(&out.Quota)
+	if in.CRDLimits != nil {
+		in, out := &in.CRDLimits, &out.CRDLimits
+		*out = new(TenantCRDLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]TenantUser, len(*in))
+		copy(*out, *in)
+	}
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(TenantAudit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LimitRange != nil {
+		in, out := &in.LimitRange, &out.LimitRange
+		*out = new(v1.LimitRangeSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -129,6 +294,25 @@ func (in *TenantSpec) DeepCopy() *TenantSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
 	*out = *in
+	if in.CRDUsage != nil {
+		in, out := &in.CRDUsage, &out.CRDUsage
+		*out = new(TenantCRDUsage)
+		**out = **in
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]TenantUserStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = make([]TenantQuotaStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -141,3 +325,36 @@ func (in *TenantStatus) DeepCopy() *TenantStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantUser) DeepCopyInto(out *TenantUser) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantUser.
+func (in *TenantUser) DeepCopy() *TenantUser {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantUserStatus) DeepCopyInto(out *TenantUserStatus) {
+	*out = *in
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantUserStatus.
+func (in *TenantUserStatus) DeepCopy() *TenantUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -56,28 +56,252 @@ func NewTenantCRD() *apiextensionsv1.CustomResourceDefinition {
 							"spec": {
 								Description: "`spec` is the specification of the desired behavior of a flow-schema. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#spec-and-status",
 								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"audit": {
+										Description: "audit is the audit policy of the requests of the tenant, which takes precedence over the audit policy of kubezoo.",
+										Properties: map[string]apiextensionsv1.JSONSchemaProps{
+											"level": {
+												Description: "level is the audit level of the requests of the tenant, one of None, Metadata, Request and RequestResponse.",
+												Type:        "string",
+											},
+											"omitStages": {
+												Description: "omitStages are the stages which are not recorded for the requests of the tenant, e.g. RequestReceived.",
+												Items:       &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}},
+												Type:        "array",
+											},
+										},
+										Required: []string{"level"},
+										Type:     "object",
+									},
+									"crdLimits": {
+										Description: "crdLimits limits the custom resource definitions created by the tenant.",
+										Properties: map[string]apiextensionsv1.JSONSchemaProps{
+											"maxCRDs": {
+												Description: "maxCRDs is the max number of crds the tenant can create.",
+												Format:      "int32",
+												Type:        "integer",
+											},
+											"maxSchemaBytes": {
+												Description: "maxSchemaBytes is the max size in bytes of the openapi schemas summed over all versions of all crds of the tenant.",
+												Format:      "int64",
+												Type:        "integer",
+											},
+											"maxServedVersions": {
+												Description: "maxServedVersions is the max number of served versions summed over all crds of the tenant.",
+												Format:      "int32",
+												Type:        "integer",
+											},
+										},
+										Type: "object",
+									},
 									"id": {
 										Format: "int32",
 										Type:   "integer",
 									},
+									"limitRange": {
+										Description: "limitRange is the default limit range kept in every namespace of the tenant, which sets the default requests and limits of the containers and bounds them. It is read-only to the tenant, who can only tighten the bounds with more limit ranges.",
+										Properties: map[string]apiextensionsv1.JSONSchemaProps{
+											"limits": {
+												Description: "Limits is the list of LimitRangeItem objects that are enforced.",
+												Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+													Description: "LimitRangeItem defines a min/max usage limit for any resource that matches on kind.",
+													Properties: map[string]apiextensionsv1.JSONSchemaProps{
+														"default": {
+															AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+																Allows: true,
+																Schema: &apiextensionsv1.JSONSchemaProps{
+																	AnyOf: []apiextensionsv1.JSONSchemaProps{
+																		{Type: "integer"},
+																		{Type: "string"},
+																	},
+																	Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+																	XIntOrString: true,
+																},
+															},
+															Description: "Default resource requirement limit value by resource name if resource limit is omitted.",
+															Type:        "object",
+														},
+														"defaultRequest": {
+															AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+																Allows: true,
+																Schema: &apiextensionsv1.JSONSchemaProps{
+																	AnyOf: []apiextensionsv1.JSONSchemaProps{
+																		{Type: "integer"},
+																		{Type: "string"},
+																	},
+																	Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+																	XIntOrString: true,
+																},
+															},
+															Description: "DefaultRequest is the default resource requirement request value by resource name if resource request is omitted.",
+															Type:        "object",
+														},
+														"max": {
+															AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+																Allows: true,
+																Schema: &apiextensionsv1.JSONSchemaProps{
+																	AnyOf: []apiextensionsv1.JSONSchemaProps{
+																		{Type: "integer"},
+																		{Type: "string"},
+																	},
+																	Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+																	XIntOrString: true,
+																},
+															},
+															Description: "Max usage constraints on this kind by resource name.",
+															Type:        "object",
+														},
+														"maxLimitRequestRatio": {
+															AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+																Allows: true,
+																Schema: &apiextensionsv1.JSONSchemaProps{
+																	AnyOf: []apiextensionsv1.JSONSchemaProps{
+																		{Type: "integer"},
+																		{Type: "string"},
+																	},
+																	Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+																	XIntOrString: true,
+																},
+															},
+															Description: "MaxLimitRequestRatio if specified, the named resource must have a request and limit that are both non-zero where limit divided by request is less than or equal to the enumerated value; this represents the max burst for the named resource.",
+															Type:        "object",
+														},
+														"min": {
+															AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+																Allows: true,
+																Schema: &apiextensionsv1.JSONSchemaProps{
+																	AnyOf: []apiextensionsv1.JSONSchemaProps{
+																		{Type: "integer"},
+																		{Type: "string"},
+																	},
+																	Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+																	XIntOrString: true,
+																},
+															},
+															Description: "Min usage constraints on this kind by resource name.",
+															Type:        "object",
+														},
+														"type": {
+															Description: "Type of resource that this limit applies to.",
+															Type:        "string",
+														},
+													},
+													Required: []string{"type"},
+													Type:     "object",
+												}},
+												Type: "array",
+											},
+										},
+										Required: []string{"limits"},
+										Type:     "object",
+									},
 									"quota": {
-										Properties: map[string]apiextensionsv1.JSONSchemaProps{"hard": {
-											AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
-												Allows: true,
-												Schema: &apiextensionsv1.JSONSchemaProps{
-													AnyOf: []apiextensionsv1.JSONSchemaProps{
-														{Type: "integer"},
-														{Type: "string"},
+										Properties: map[string]apiextensionsv1.JSONSchemaProps{
+											"hard": {
+												AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+													Allows: true,
+													Schema: &apiextensionsv1.JSONSchemaProps{
+														AnyOf: []apiextensionsv1.JSONSchemaProps{
+															{Type: "integer"},
+															{Type: "string"},
+														},
+														Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+														XIntOrString: true,
 													},
-													Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
-													XIntOrString: true,
 												},
+												Description: "hard is the set of desired hard limits for each named resource. More info: https://kubernetes.io/docs/concepts/policy/resource-quotas/",
+												Type:        "object",
 											},
-											Description: "hard is the set of desired hard limits for each named resource. More info: https://kubernetes.io/docs/concepts/policy/resource-quotas/",
-											Type:        "object",
-										}},
+											"scoped": {
+												Description: "scoped are the quotas which only track the usage of the objects matched by their scopes, e.g. the BestEffort pods or the pods of a priority class, each of which is enforced across the namespaces of the tenant separately.",
+												Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+													Description: "TenantScopedQuota describes a quota of the tenant which is only enforced on the objects matched by its scopes, as the resource quota does.",
+													Properties: map[string]apiextensionsv1.JSONSchemaProps{
+														"hard": {
+															AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+																Allows: true,
+																Schema: &apiextensionsv1.JSONSchemaProps{
+																	AnyOf: []apiextensionsv1.JSONSchemaProps{
+																		{Type: "integer"},
+																		{Type: "string"},
+																	},
+																	Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+																	XIntOrString: true,
+																},
+															},
+															Description: "hard is the set of desired hard limits for each named resource.",
+															Type:        "object",
+														},
+														"name": {
+															Description: "name is the name of the scoped quota, which must be a DNS label.",
+															Type:        "string",
+														},
+														"scopeSelector": {
+															Description: "scopeSelector is the collection of filters like scopes that must match the objects tracked by the quota, expressed with the operators.",
+															Properties: map[string]apiextensionsv1.JSONSchemaProps{"matchExpressions": {
+																Description: "A list of scope selector requirements by scope of the resources.",
+																Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+																	Description: "A scoped-resource selector requirement is a selector that contains values, a scope name, and an operator that relates the scope name and values.",
+																	Properties: map[string]apiextensionsv1.JSONSchemaProps{
+																		"operator": {
+																			Description: "Represents a scope's relationship to a set of values. Valid operators are In, NotIn, Exists, DoesNotExist.",
+																			Type:        "string",
+																		},
+																		"scopeName": {
+																			Description: "The name of the scope that the selector applies to.",
+																			Type:        "string",
+																		},
+																		"values": {
+																			Description: "An array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.",
+																			Items:       &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}},
+																			Type:        "array",
+																		},
+																	},
+																	Required: []string{
+																		"operator",
+																		"scopeName",
+																	},
+																	Type: "object",
+																}},
+																Type: "array",
+															}},
+															Type: "object",
+														},
+														"scopes": {
+															Description: "scopes is the collection of filters which must all match the objects tracked by the quota.",
+															Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+																Description: "A ResourceQuotaScope defines a filter that must match each object tracked by a quota",
+																Type:        "string",
+															}},
+															Type: "array",
+														},
+													},
+													Required: []string{"name"},
+													Type:     "object",
+												}},
+												Type: "array",
+											},
+										},
 										Type: "object",
 									},
+									"users": {
+										Description: "users are the named users of the tenant besides the admin user.",
+										Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+											Description: "TenantUser describes a named user of the tenant. The certificate of the user is issued with the common name <tenant>-<name> under the tenant OU, and the user is authorized by the RBAC rules of the tenant.",
+											Properties: map[string]apiextensionsv1.JSONSchemaProps{
+												"name": {
+													Description: "name is the name of the user in the tenant, which must be a DNS label other than \"admin\".",
+													Type:        "string",
+												},
+												"revoked": {
+													Description: "revoked revokes the certificate issued to the user, no certificate is issued to the user until it is unset.",
+													Type:        "boolean",
+												},
+											},
+											Required: []string{"name"},
+											Type:     "object",
+										}},
+										Type: "array",
+									},
 								},
 								Required: []string{
 									"id",
@@ -87,10 +311,125 @@ func NewTenantCRD() *apiextensionsv1.CustomResourceDefinition {
 							},
 							"status": {
 								Description: "`status` is the current status of a flow-schema. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#spec-and-status",
-								Properties: map[string]apiextensionsv1.JSONSchemaProps{"online": {
-									Description: "Current state of tenant.",
-									Type:        "boolean",
-								}},
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"crdUsage": {
+										Description: "crdUsage is the current usage of the crds of the tenant.",
+										Properties: map[string]apiextensionsv1.JSONSchemaProps{
+											"crds": {
+												Description: "crds is the number of crds of the tenant.",
+												Format:      "int32",
+												Type:        "integer",
+											},
+											"schemaBytes": {
+												Description: "schemaBytes is the size in bytes of the openapi schemas summed over all versions of all crds of the tenant.",
+												Format:      "int64",
+												Type:        "integer",
+											},
+											"servedVersions": {
+												Description: "servedVersions is the number of served versions summed over all crds of the tenant.",
+												Format:      "int32",
+												Type:        "integer",
+											},
+										},
+										Required: []string{
+											"crds",
+											"schemaBytes",
+											"servedVersions",
+										},
+										Type: "object",
+									},
+									"online": {
+										Description: "Current state of tenant.",
+										Type:        "boolean",
+									},
+									"quota": {
+										Description: "quota is the usage of the quotas of the tenant, one per scope.",
+										Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+											Description: "TenantQuotaStatus describes the usage of a quota of the tenant summed over the namespaces of the tenant.",
+											Properties: map[string]apiextensionsv1.JSONSchemaProps{
+												"allocated": {
+													AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+														Allows: true,
+														Schema: &apiextensionsv1.JSONSchemaProps{
+															AnyOf: []apiextensionsv1.JSONSchemaProps{
+																{Type: "integer"},
+																{Type: "string"},
+															},
+															Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+															XIntOrString: true,
+														},
+													},
+													Description: "allocated is the hard limits summed over the resource quotas created by the tenant in its namespaces, which allocate the quota to the namespaces.",
+													Type:        "object",
+												},
+												"hard": {
+													AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+														Allows: true,
+														Schema: &apiextensionsv1.JSONSchemaProps{
+															AnyOf: []apiextensionsv1.JSONSchemaProps{
+																{Type: "integer"},
+																{Type: "string"},
+															},
+															Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+															XIntOrString: true,
+														},
+													},
+													Description: "hard is the set of enforced hard limits for each named resource.",
+													Type:        "object",
+												},
+												"name": {
+													Description: "name is the name of the scoped quota, or empty for the quota without scopes.",
+													Type:        "string",
+												},
+												"used": {
+													AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+														Allows: true,
+														Schema: &apiextensionsv1.JSONSchemaProps{
+															AnyOf: []apiextensionsv1.JSONSchemaProps{
+																{Type: "integer"},
+																{Type: "string"},
+															},
+															Pattern:      "^(\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\\+|-)?(([0-9]+(\\.[0-9]*)?)|(\\.[0-9]+))))?$",
+															XIntOrString: true,
+														},
+													},
+													Description: "used is the current observed total usage of the resource across the namespaces of the tenant.",
+													Type:        "object",
+												},
+											},
+											Type: "object",
+										}},
+										Type: "array",
+									},
+									"users": {
+										Description: "users are the certificates issued to the named users of the tenant.",
+										Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+											Description: "TenantUserStatus describes the certificate issued to a named user of the tenant.",
+											Properties: map[string]apiextensionsv1.JSONSchemaProps{
+												"name": {
+													Description: "name is the name of the user in the tenant.",
+													Type:        "string",
+												},
+												"notAfter": {
+													Description: "notAfter is the expiration time of the certificate.",
+													Format:      "date-time",
+													Type:        "string",
+												},
+												"serialNumber": {
+													Description: "serialNumber is the serial number of the certificate issued to the user, only the certificate with the serial number is accepted.",
+													Type:        "string",
+												},
+											},
+											Required: []string{
+												"name",
+												"notAfter",
+												"serialNumber",
+											},
+											Type: "object",
+										}},
+										Type: "array",
+									},
+								},
 								Type: "object",
 							},
 						},
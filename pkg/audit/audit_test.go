@@ -0,0 +1,264 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	genericaudit "k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/client-go/tools/cache"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	tenantlister "github.com/kubewharf/kubezoo/pkg/generated/listers/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+type fakeEvaluator struct{}
+
+func (fakeEvaluator) EvaluatePolicyRule(authorizer.Attributes) genericaudit.RequestAuditConfigWithLevel {
+	return genericaudit.RequestAuditConfigWithLevel{Level: auditinternal.LevelMetadata}
+}
+
+type fakeBackend struct {
+	events []*auditinternal.Event
+}
+
+func (b *fakeBackend) ProcessEvents(events ...*auditinternal.Event) bool {
+	b.events = append(b.events, events...)
+	return true
+}
+
+func (b *fakeBackend) Run(<-chan struct{}) error { return nil }
+func (b *fakeBackend) Shutdown()                 {}
+func (b *fakeBackend) String() string            { return "fake" }
+
+func tenantUser(tenantID, name string) user.Info {
+	return &user.DefaultInfo{
+		Name:   util.AddTenantIDPrefix(tenantID, name),
+		Groups: []string{"system:serviceaccounts:" + util.AddTenantIDPrefix(tenantID, "default"), user.AllAuthenticated},
+		Extra:  map[string][]string{util.TenantIDKey: {tenantID}},
+	}
+}
+
+func TestValidateTenantAudit(t *testing.T) {
+	cases := []struct {
+		audit *tenantv1alpha1.TenantAudit
+		errs  int
+	}{
+		{audit: nil},
+		{audit: &tenantv1alpha1.TenantAudit{Level: "Metadata", OmitStages: []string{"RequestReceived"}}},
+		{audit: &tenantv1alpha1.TenantAudit{Level: "All"}, errs: 1},
+		{audit: &tenantv1alpha1.TenantAudit{Level: "None", OmitStages: []string{"Done"}}, errs: 1},
+	}
+	for i, c := range cases {
+		if errs := ValidateTenantAudit(c.audit, field.NewPath("spec", "audit")); len(errs) != c.errs {
+			t.Errorf("case %d: expect %d errors, got %v", i, c.errs, errs)
+		}
+	}
+}
+
+func TestTenantPolicyRuleEvaluator(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	indexer.Add(&tenantv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "foofoo"},
+		Spec: tenantv1alpha1.TenantSpec{
+			Audit: &tenantv1alpha1.TenantAudit{Level: "RequestResponse", OmitStages: []string{"RequestReceived"}},
+		},
+	})
+	indexer.Add(&tenantv1alpha1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "barbar"}})
+	lister := tenantlister.NewTenantLister(indexer)
+
+	cases := []struct {
+		delegate   genericaudit.PolicyRuleEvaluator
+		user       user.Info
+		level      auditinternal.Level
+		omitStages int
+		// the levels enforced on the operator and the tenant backends
+		globalLevel auditinternal.Level
+		tenantLevel auditinternal.Level
+	}{
+		{delegate: fakeEvaluator{}, user: tenantUser("foofoo", "bob"), level: auditinternal.LevelRequestResponse,
+			globalLevel: auditinternal.LevelMetadata, tenantLevel: auditinternal.LevelRequestResponse},
+		{delegate: fakeEvaluator{}, user: tenantUser("barbar", "bob"), level: auditinternal.LevelMetadata,
+			globalLevel: auditinternal.LevelMetadata, tenantLevel: auditinternal.LevelMetadata},
+		{delegate: fakeEvaluator{}, user: &user.DefaultInfo{Name: "bob"}, level: auditinternal.LevelMetadata,
+			globalLevel: auditinternal.LevelMetadata, tenantLevel: auditinternal.LevelMetadata},
+		{delegate: nil, user: tenantUser("foofoo", "bob"), level: auditinternal.LevelRequestResponse, omitStages: 1,
+			globalLevel: auditinternal.LevelNone, tenantLevel: auditinternal.LevelRequestResponse},
+		{delegate: nil, user: tenantUser("barbar", "bob"), level: auditinternal.LevelNone,
+			globalLevel: auditinternal.LevelNone, tenantLevel: auditinternal.LevelNone},
+	}
+	for i, c := range cases {
+		evaluator := NewTenantPolicyRuleEvaluator(c.delegate, lister)
+		attrs := &authorizer.AttributesRecord{User: c.user}
+		got := evaluator.EvaluatePolicyRule(attrs)
+		if got.Level != c.level || len(got.OmitStages) != c.omitStages {
+			t.Errorf("case %d: expect level %s with %d omitted stages, got %s with %v", i, c.level, c.omitStages, got.Level, got.OmitStages)
+		}
+		if got := evaluator.Global().EvaluatePolicyRule(attrs).Level; got != c.globalLevel {
+			t.Errorf("case %d: expect global level %s, got %s", i, c.globalLevel, got)
+		}
+		if got := evaluator.Tenant().EvaluatePolicyRule(attrs).Level; got != c.tenantLevel {
+			t.Errorf("case %d: expect tenant level %s, got %s", i, c.tenantLevel, got)
+		}
+	}
+}
+
+func TestWithPolicy(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	indexer.Add(&tenantv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "foofoo"},
+		Spec: tenantv1alpha1.TenantSpec{
+			Audit: &tenantv1alpha1.TenantAudit{Level: "RequestResponse", OmitStages: []string{"RequestReceived"}},
+		},
+	})
+	evaluator := NewTenantPolicyRuleEvaluator(fakeEvaluator{}, tenantlister.NewTenantLister(indexer))
+	operator, tenant := &fakeBackend{}, &fakeBackend{}
+	backend := genericaudit.Union(WithPolicy(operator, evaluator.Global()), WithPolicy(tenant, evaluator.Tenant()))
+
+	newEvent := func(stage auditinternal.Stage) *auditinternal.Event {
+		return &auditinternal.Event{
+			Level:          auditinternal.LevelRequestResponse,
+			Stage:          stage,
+			User:           *toUserInfo(tenantUser("foofoo", "bob")),
+			Verb:           "create",
+			RequestURI:     "/api/v1/namespaces/default/configmaps?dryRun=All",
+			ObjectRef:      &auditinternal.ObjectReference{Resource: "configmaps", Namespace: "default", APIVersion: "v1"},
+			RequestObject:  &runtime.Unknown{Raw: []byte("{}")},
+			ResponseObject: &runtime.Unknown{Raw: []byte("{}")},
+		}
+	}
+	received, completed := newEvent(auditinternal.StageRequestReceived), newEvent(auditinternal.StageResponseComplete)
+	backend.ProcessEvents(received, completed)
+
+	if len(operator.events) != 2 {
+		t.Fatalf("expect 2 events for the operator, got %d", len(operator.events))
+	}
+	for _, ev := range operator.events {
+		if ev.Level != auditinternal.LevelMetadata || ev.RequestObject != nil || ev.ResponseObject != nil {
+			t.Errorf("expect the operator event at the metadata level, got %s", ev.Level)
+		}
+	}
+	if len(tenant.events) != 1 || tenant.events[0].Stage != auditinternal.StageResponseComplete {
+		t.Fatalf("expect only the completed event for the tenant, got %v", tenant.events)
+	}
+	if ev := tenant.events[0]; ev.Level != auditinternal.LevelRequestResponse || ev.ResponseObject == nil {
+		t.Errorf("expect the tenant event at the request response level, got %s", ev.Level)
+	}
+	if completed.RequestObject == nil {
+		t.Errorf("expect original event to be untouched")
+	}
+}
+
+func TestWithTenantAnnotation(t *testing.T) {
+	delegate := &fakeBackend{}
+	backend := WithTenantAnnotation(delegate)
+	tenantEvent := &auditinternal.Event{User: *toUserInfo(tenantUser("foofoo", "bob"))}
+	otherEvent := &auditinternal.Event{User: authenticationv1.UserInfo{Username: "bob"}}
+	backend.ProcessEvents(tenantEvent, otherEvent)
+
+	if len(delegate.events) != 2 {
+		t.Fatalf("expect 2 events, got %d", len(delegate.events))
+	}
+	if got := delegate.events[0].Annotations[TenantAnnotationKey]; got != "foofoo" {
+		t.Errorf("expect tenant annotation foofoo, got %q", got)
+	}
+	if got := delegate.events[0].User.Username; got != util.AddTenantIDPrefix("foofoo", "bob") {
+		t.Errorf("expect upstream user name to be kept, got %s", got)
+	}
+	if _, ok := delegate.events[1].Annotations[TenantAnnotationKey]; ok {
+		t.Errorf("expect no tenant annotation on event out of tenants")
+	}
+	if len(tenantEvent.Annotations) != 0 {
+		t.Errorf("expect original event to be untouched, got %v", tenantEvent.Annotations)
+	}
+}
+
+func TestTenantLogBackend(t *testing.T) {
+	backend := NewTenantLogBackend(t.TempDir(), 1, 1)
+	if err := backend.Run(nil); err != nil {
+		t.Fatalf("failed to run backend: %v", err)
+	}
+	defer backend.Shutdown()
+
+	backend.ProcessEvents(
+		&auditinternal.Event{AuditID: "1", User: *toUserInfo(tenantUser("foofoo", "bob"))},
+		&auditinternal.Event{AuditID: "2", User: authenticationv1.UserInfo{Username: "bob"}},
+		&auditinternal.Event{AuditID: "3", User: *toUserInfo(tenantUser("barbar", "alice"))},
+	)
+
+	log, err := backend.OpenTenantLog("foofoo")
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	defer log.Close()
+	decoder := json.NewDecoder(log)
+	var events []auditv1.Event
+	for {
+		ev := auditv1.Event{}
+		if err := decoder.Decode(&ev); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("failed to decode audit log: %v", err)
+		}
+		events = append(events, ev)
+	}
+	if len(events) != 1 || events[0].AuditID != "1" {
+		t.Fatalf("expect only event 1 in the audit log of foofoo, got %v", events)
+	}
+	ev := events[0]
+	if ev.User.Username != "bob" {
+		t.Errorf("expect tenant side user name bob, got %s", ev.User.Username)
+	}
+	if ev.User.Groups[0] != "system:serviceaccounts:default" {
+		t.Errorf("expect tenant side groups, got %v", ev.User.Groups)
+	}
+	if ev.Annotations[TenantAnnotationKey] != "foofoo" {
+		t.Errorf("expect tenant annotation foofoo, got %v", ev.Annotations)
+	}
+
+	empty, err := backend.OpenTenantLog("bazbaz")
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	defer empty.Close()
+	if content, _ := io.ReadAll(empty); len(content) != 0 {
+		t.Errorf("expect empty audit log of bazbaz, got %s", content)
+	}
+}
+
+func toUserInfo(u user.Info) *authenticationv1.UserInfo {
+	extra := map[string]authenticationv1.ExtraValue{}
+	for k, v := range u.GetExtra() {
+		extra[k] = v
+	}
+	return &authenticationv1.UserInfo{
+		Username: u.GetName(),
+		Groups:   u.GetGroups(),
+		Extra:    extra,
+	}
+}
@@ -0,0 +1,309 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	genericaudit "k8s.io/apiserver/pkg/audit"
+	auditpolicy "k8s.io/apiserver/pkg/audit/policy"
+	"k8s.io/apiserver/pkg/authentication/user"
+	auditlog "k8s.io/apiserver/plugin/pkg/audit/log"
+	"k8s.io/klog"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+const (
+	// TenantAnnotationKey is the audit annotation of the tenant of the
+	// request, by which the audit events of kubezoo can be filtered.
+	TenantAnnotationKey = "kubezoo.io/tenant"
+	// UpstreamUserAnnotationKey is the audit annotation of the user in the
+	// upstream cluster, which is recorded in the audit events of the tenant
+	// instead of the user seen inside the tenant.
+	UpstreamUserAnnotationKey = "kubezoo.io/upstream-user"
+)
+
+// tenantOfEvent returns the tenant recorded in the extra of the user of the
+// event, the events of the requests out of the tenants have no tenant.
+func tenantOfEvent(ev *auditinternal.Event) string {
+	if tenants := ev.User.Extra[util.TenantIDKey]; len(tenants) > 0 && util.ValidateTenantName(tenants[0]) == nil {
+		return tenants[0]
+	}
+	return ""
+}
+
+// withTenantAnnotation returns a copy of the event with the tenant annotated.
+func withTenantAnnotation(tenantID string, ev *auditinternal.Event) *auditinternal.Event {
+	ev = ev.DeepCopy()
+	if ev.Annotations == nil {
+		ev.Annotations = map[string]string{}
+	}
+	ev.Annotations[TenantAnnotationKey] = tenantID
+	return ev
+}
+
+// toTenantEvent returns the event as seen inside the tenant, i.e. the user
+// is the tenant user and the upstream user is annotated.
+func toTenantEvent(tenantID string, ev *auditinternal.Event) *auditinternal.Event {
+	ev = withTenantAnnotation(tenantID, ev)
+	ev.Annotations[UpstreamUserAnnotationKey] = ev.User.Username
+	u := util.TrimTenantIDFromUserInfo(tenantID, &user.DefaultInfo{
+		Name:   ev.User.Username,
+		UID:    ev.User.UID,
+		Groups: ev.User.Groups,
+	})
+	ev.User.Username = u.GetName()
+	ev.User.Groups = u.GetGroups()
+	return ev
+}
+
+// tenantAnnotatingBackend annotates the events of the tenants with the
+// tenant before they are sent to the delegate.
+type tenantAnnotatingBackend struct {
+	delegate genericaudit.Backend
+}
+
+// WithTenantAnnotation returns the backend which annotates the events of the
+// tenants with the tenant ID before they are sent to the delegate.
+func WithTenantAnnotation(delegate genericaudit.Backend) genericaudit.Backend {
+	return &tenantAnnotatingBackend{delegate: delegate}
+}
+
+// ProcessEvents implements audit.Backend.
+func (b *tenantAnnotatingBackend) ProcessEvents(events ...*auditinternal.Event) bool {
+	annotated := make([]*auditinternal.Event, 0, len(events))
+	for _, ev := range events {
+		if tenantID := tenantOfEvent(ev); len(tenantID) != 0 {
+			ev = withTenantAnnotation(tenantID, ev)
+		}
+		annotated = append(annotated, ev)
+	}
+	return b.delegate.ProcessEvents(annotated...)
+}
+
+// Run implements audit.Backend.
+func (b *tenantAnnotatingBackend) Run(stopCh <-chan struct{}) error {
+	return b.delegate.Run(stopCh)
+}
+
+// Shutdown implements audit.Backend.
+func (b *tenantAnnotatingBackend) Shutdown() {
+	b.delegate.Shutdown()
+}
+
+// String implements audit.Backend.
+func (b *tenantAnnotatingBackend) String() string {
+	return "tenant annotating<" + b.delegate.String() + ">"
+}
+
+// tenantViewBackend sends the events of the tenants, as seen inside the
+// tenants, to the delegate.
+type tenantViewBackend struct {
+	delegate genericaudit.Backend
+}
+
+// WithTenantView returns the backend which drops the events out of the
+// tenants, and sends the others as seen inside the tenants to the delegate,
+// e.g. the webhook of the tenant audit sinks.
+func WithTenantView(delegate genericaudit.Backend) genericaudit.Backend {
+	return &tenantViewBackend{delegate: delegate}
+}
+
+// ProcessEvents implements audit.Backend.
+func (b *tenantViewBackend) ProcessEvents(events ...*auditinternal.Event) bool {
+	tenantEvents := make([]*auditinternal.Event, 0, len(events))
+	for _, ev := range events {
+		if tenantID := tenantOfEvent(ev); len(tenantID) != 0 {
+			tenantEvents = append(tenantEvents, toTenantEvent(tenantID, ev))
+		}
+	}
+	if len(tenantEvents) == 0 {
+		return true
+	}
+	return b.delegate.ProcessEvents(tenantEvents...)
+}
+
+// Run implements audit.Backend.
+func (b *tenantViewBackend) Run(stopCh <-chan struct{}) error {
+	return b.delegate.Run(stopCh)
+}
+
+// Shutdown implements audit.Backend.
+func (b *tenantViewBackend) Shutdown() {
+	b.delegate.Shutdown()
+}
+
+// String implements audit.Backend.
+func (b *tenantViewBackend) String() string {
+	return "tenant view<" + b.delegate.String() + ">"
+}
+
+// policyEnforcingBackend enforces the audit policy on the events before
+// they are sent to the delegate.
+type policyEnforcingBackend struct {
+	delegate  genericaudit.Backend
+	evaluator genericaudit.PolicyRuleEvaluator
+}
+
+// WithPolicy returns the backend which evaluates the audit policy again for
+// each event, drops the event or the parts of it the policy does not audit,
+// and sends the rest to the delegate. The events are recorded by the union
+// of the policies of the backends, see TenantPolicyRuleEvaluator.
+func WithPolicy(delegate genericaudit.Backend, evaluator genericaudit.PolicyRuleEvaluator) genericaudit.Backend {
+	return &policyEnforcingBackend{delegate: delegate, evaluator: evaluator}
+}
+
+// ProcessEvents implements audit.Backend.
+func (b *policyEnforcingBackend) ProcessEvents(events ...*auditinternal.Event) bool {
+	enforced := make([]*auditinternal.Event, 0, len(events))
+	for _, ev := range events {
+		config := b.evaluator.EvaluatePolicyRule(attributesOfEvent(ev))
+		if ev.Level.Less(config.Level) {
+			// the details above the level of the event are not recorded
+			config.Level = ev.Level
+		}
+		enforcedEvent, err := auditpolicy.EnforcePolicy(ev.DeepCopy(), config.Level, config.OmitStages)
+		if err != nil {
+			klog.Errorf("failed to enforce the audit policy on event %s: %v", ev.AuditID, err)
+			continue
+		}
+		if enforcedEvent != nil {
+			enforced = append(enforced, enforcedEvent)
+		}
+	}
+	if len(enforced) == 0 {
+		return true
+	}
+	return b.delegate.ProcessEvents(enforced...)
+}
+
+// Run implements audit.Backend.
+func (b *policyEnforcingBackend) Run(stopCh <-chan struct{}) error {
+	return b.delegate.Run(stopCh)
+}
+
+// Shutdown implements audit.Backend.
+func (b *policyEnforcingBackend) Shutdown() {
+	b.delegate.Shutdown()
+}
+
+// String implements audit.Backend.
+func (b *policyEnforcingBackend) String() string {
+	return "policy enforcing<" + b.delegate.String() + ">"
+}
+
+// TenantLogBackend writes the events of each tenant into its own log file
+// in the directory, as seen inside the tenant, one JSON event per line.
+type TenantLogBackend struct {
+	dir        string
+	maxSize    int
+	maxBackups int
+
+	lock     sync.Mutex
+	writers  map[string]*lumberjack.Logger
+	backends map[string]genericaudit.Backend
+}
+
+var _ genericaudit.Backend = &TenantLogBackend{}
+
+// NewTenantLogBackend returns the backend writing the events of the tenant
+// into <dir>/<tenant>.log, which is rotated once it reaches maxSize in
+// megabytes, with at most maxBackups rotated files kept.
+func NewTenantLogBackend(dir string, maxSize, maxBackups int) *TenantLogBackend {
+	return &TenantLogBackend{
+		dir:        dir,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+		writers:    map[string]*lumberjack.Logger{},
+		backends:   map[string]genericaudit.Backend{},
+	}
+}
+
+// logFile returns the path of the log file of the tenant.
+func (b *TenantLogBackend) logFile(tenantID string) string {
+	return filepath.Join(b.dir, tenantID+".log")
+}
+
+// backendFor returns the log backend of the tenant.
+func (b *TenantLogBackend) backendFor(tenantID string) genericaudit.Backend {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if backend, ok := b.backends[tenantID]; ok {
+		return backend
+	}
+	w := &lumberjack.Logger{
+		Filename:   b.logFile(tenantID),
+		MaxSize:    b.maxSize,
+		MaxBackups: b.maxBackups,
+	}
+	backend := auditlog.NewBackend(w, auditlog.FormatJson, auditv1.SchemeGroupVersion)
+	b.writers[tenantID] = w
+	b.backends[tenantID] = backend
+	return backend
+}
+
+// ProcessEvents implements audit.Backend.
+func (b *TenantLogBackend) ProcessEvents(events ...*auditinternal.Event) bool {
+	success := true
+	for _, ev := range events {
+		tenantID := tenantOfEvent(ev)
+		if len(tenantID) == 0 {
+			continue
+		}
+		success = b.backendFor(tenantID).ProcessEvents(toTenantEvent(tenantID, ev)) && success
+	}
+	return success
+}
+
+// Run implements audit.Backend.
+func (b *TenantLogBackend) Run(stopCh <-chan struct{}) error {
+	return os.MkdirAll(b.dir, 0700)
+}
+
+// Shutdown implements audit.Backend.
+func (b *TenantLogBackend) Shutdown() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for tenantID, w := range b.writers {
+		if err := w.Close(); err != nil {
+			klog.Errorf("failed to close the audit log of tenant %s: %v", tenantID, err)
+		}
+	}
+}
+
+// String implements audit.Backend.
+func (b *TenantLogBackend) String() string {
+	return "tenant log<" + b.dir + ">"
+}
+
+// OpenTenantLog opens the current audit log of the tenant, an empty reader
+// is returned if no event of the tenant has been logged.
+func (b *TenantLogBackend) OpenTenantLog(tenantID string) (io.ReadCloser, error) {
+	f, err := os.Open(b.logFile(tenantID))
+	if os.IsNotExist(err) {
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+	return f, err
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	genericaudit "k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	tenantlister "github.com/kubewharf/kubezoo/pkg/generated/listers/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+var (
+	validLevels = []string{
+		string(auditinternal.LevelNone),
+		string(auditinternal.LevelMetadata),
+		string(auditinternal.LevelRequest),
+		string(auditinternal.LevelRequestResponse),
+	}
+	validStages = []string{
+		string(auditinternal.StageRequestReceived),
+		string(auditinternal.StageResponseStarted),
+		string(auditinternal.StageResponseComplete),
+		string(auditinternal.StagePanic),
+	}
+)
+
+// ValidateTenantAudit validates the audit policy of the tenant.
+func ValidateTenantAudit(audit *tenantv1alpha1.TenantAudit, fldPath *field.Path) field.ErrorList {
+	if audit == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	if !util.ContainString(validLevels, audit.Level) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("level"), audit.Level, validLevels))
+	}
+	for i, stage := range audit.OmitStages {
+		if !util.ContainString(validStages, stage) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("omitStages").Index(i), stage, validStages))
+		}
+	}
+	return allErrs
+}
+
+// TenantPolicyRuleEvaluator evaluates the audit policy of kubezoo for the
+// operator backends, and the audit policy declared on the tenant for the
+// tenant backends. The audit filter is given the union of both, so that the
+// events are recorded in enough detail for every backend, and each backend
+// enforces its own policy by WithPolicy.
+type TenantPolicyRuleEvaluator struct {
+	delegate     genericaudit.PolicyRuleEvaluator
+	tenantLister tenantlister.TenantLister
+}
+
+var _ genericaudit.PolicyRuleEvaluator = &TenantPolicyRuleEvaluator{}
+
+// NewTenantPolicyRuleEvaluator returns the evaluator of the audit policy of
+// kubezoo, i.e. the delegate, which may be nil if no audit policy is given
+// to kubezoo, and of the audit policies of the tenants.
+func NewTenantPolicyRuleEvaluator(delegate genericaudit.PolicyRuleEvaluator, tenantLister tenantlister.TenantLister) *TenantPolicyRuleEvaluator {
+	return &TenantPolicyRuleEvaluator{delegate: delegate, tenantLister: tenantLister}
+}
+
+// EvaluatePolicyRule implements audit.PolicyRuleEvaluator, the request is
+// audited at the higher of the two levels and in the stages of either.
+func (e *TenantPolicyRuleEvaluator) EvaluatePolicyRule(attrs authorizer.Attributes) genericaudit.RequestAuditConfigWithLevel {
+	global := e.evaluateGlobal(attrs)
+	tenant, ok := e.evaluateTenantPolicy(attrs)
+	if !ok || tenant.Level == auditinternal.LevelNone {
+		return global
+	}
+	if global.Level == auditinternal.LevelNone {
+		return tenant
+	}
+	ret := genericaudit.RequestAuditConfigWithLevel{Level: global.Level}
+	if global.Level.Less(tenant.Level) {
+		ret.Level = tenant.Level
+	}
+	for _, stage := range global.OmitStages {
+		if containStage(tenant.OmitStages, stage) {
+			ret.OmitStages = append(ret.OmitStages, stage)
+		}
+	}
+	return ret
+}
+
+// Global returns the evaluator of the audit policy of kubezoo, which is
+// enforced on the operator backends.
+func (e *TenantPolicyRuleEvaluator) Global() genericaudit.PolicyRuleEvaluator {
+	return policyRuleEvaluatorFunc(e.evaluateGlobal)
+}
+
+// Tenant returns the evaluator of the audit policies of the tenants, which
+// is enforced on the tenant backends. The requests of the tenants without
+// an audit policy are evaluated by the audit policy of kubezoo.
+func (e *TenantPolicyRuleEvaluator) Tenant() genericaudit.PolicyRuleEvaluator {
+	return policyRuleEvaluatorFunc(func(attrs authorizer.Attributes) genericaudit.RequestAuditConfigWithLevel {
+		if tenant, ok := e.evaluateTenantPolicy(attrs); ok {
+			return tenant
+		}
+		return e.evaluateGlobal(attrs)
+	})
+}
+
+// evaluateGlobal evaluates the audit policy of kubezoo.
+func (e *TenantPolicyRuleEvaluator) evaluateGlobal(attrs authorizer.Attributes) genericaudit.RequestAuditConfigWithLevel {
+	if e.delegate != nil {
+		return e.delegate.EvaluatePolicyRule(attrs)
+	}
+	return genericaudit.RequestAuditConfigWithLevel{Level: auditinternal.LevelNone}
+}
+
+// evaluateTenantPolicy evaluates the audit policy declared on the tenant of
+// the request, or returns false if there is none.
+func (e *TenantPolicyRuleEvaluator) evaluateTenantPolicy(attrs authorizer.Attributes) (genericaudit.RequestAuditConfigWithLevel, bool) {
+	tenantID := tenantFromUser(attrs.GetUser())
+	if len(tenantID) == 0 {
+		return genericaudit.RequestAuditConfigWithLevel{}, false
+	}
+	tenant, err := e.tenantLister.Get(tenantID)
+	if err != nil || tenant.Spec.Audit == nil {
+		return genericaudit.RequestAuditConfigWithLevel{}, false
+	}
+	omitStages := make([]auditinternal.Stage, 0, len(tenant.Spec.Audit.OmitStages))
+	for _, stage := range tenant.Spec.Audit.OmitStages {
+		omitStages = append(omitStages, auditinternal.Stage(stage))
+	}
+	return genericaudit.RequestAuditConfigWithLevel{
+		Level:              auditinternal.Level(tenant.Spec.Audit.Level),
+		RequestAuditConfig: genericaudit.RequestAuditConfig{OmitStages: omitStages},
+	}, true
+}
+
+// policyRuleEvaluatorFunc adapts the function to audit.PolicyRuleEvaluator.
+type policyRuleEvaluatorFunc func(attrs authorizer.Attributes) genericaudit.RequestAuditConfigWithLevel
+
+// EvaluatePolicyRule implements audit.PolicyRuleEvaluator.
+func (f policyRuleEvaluatorFunc) EvaluatePolicyRule(attrs authorizer.Attributes) genericaudit.RequestAuditConfigWithLevel {
+	return f(attrs)
+}
+
+// containStage checks if the stage is in the stages.
+func containStage(stages []auditinternal.Stage, stage auditinternal.Stage) bool {
+	for _, s := range stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// attributesOfEvent returns the attributes of the request of the event, by
+// which the audit policy is evaluated again for a backend.
+func attributesOfEvent(ev *auditinternal.Event) authorizer.Attributes {
+	extra := make(map[string][]string, len(ev.User.Extra))
+	for k, v := range ev.User.Extra {
+		extra[k] = v
+	}
+	attrs := &authorizer.AttributesRecord{
+		User: &user.DefaultInfo{
+			Name:   ev.User.Username,
+			UID:    ev.User.UID,
+			Groups: ev.User.Groups,
+			Extra:  extra,
+		},
+		Verb: ev.Verb,
+		Path: strings.SplitN(ev.RequestURI, "?", 2)[0],
+	}
+	if ev.ObjectRef != nil {
+		attrs.ResourceRequest = true
+		attrs.Namespace = ev.ObjectRef.Namespace
+		attrs.Name = ev.ObjectRef.Name
+		attrs.APIGroup = ev.ObjectRef.APIGroup
+		attrs.APIVersion = ev.ObjectRef.APIVersion
+		attrs.Resource = ev.ObjectRef.Resource
+		attrs.Subresource = ev.ObjectRef.Subresource
+	}
+	return attrs
+}
+
+// tenantFromUser returns the tenant recorded in the extra of the user.
+func tenantFromUser(u user.Info) string {
+	if u == nil {
+		return ""
+	}
+	if tenants := u.GetExtra()[util.TenantIDKey]; len(tenants) > 0 {
+		return tenants[0]
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"time"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	genericaudit "k8s.io/apiserver/pkg/audit"
+	webhookutil "k8s.io/apiserver/pkg/util/webhook"
+	"k8s.io/apiserver/plugin/pkg/audit/buffered"
+	auditwebhook "k8s.io/apiserver/plugin/pkg/audit/webhook"
+)
+
+// webhookBatchConfig is the batch config of the tenant audit webhook, which
+// is the same as the default of the audit webhook of kubezoo.
+var webhookBatchConfig = buffered.BatchConfig{
+	BufferSize:     10000,
+	MaxBatchSize:   400,
+	MaxBatchWait:   30 * time.Second,
+	ThrottleEnable: true,
+	ThrottleQPS:    10,
+	ThrottleBurst:  15,
+	AsyncDelegate:  true,
+}
+
+// NewTenantWebhookBackend returns the backend which sends the audit events of
+// the tenants, as seen inside the tenants, to the webhook in batches.
+func NewTenantWebhookBackend(kubeConfigFile string) (genericaudit.Backend, error) {
+	webhook, err := auditwebhook.NewBackend(kubeConfigFile, auditv1.SchemeGroupVersion,
+		webhookutil.DefaultRetryBackoffWithInitialDelay(10*time.Second), nil)
+	if err != nil {
+		return nil, err
+	}
+	return WithTenantView(buffered.NewBackend(webhook, webhookBatchConfig)), nil
+}
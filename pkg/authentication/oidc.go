@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package authentication contains the authenticators which map the users of
+// external identity providers to tenants.
+package authentication
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	"k8s.io/apiserver/plugin/pkg/authenticator/token/oidc"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	tenantlister "github.com/kubewharf/kubezoo/pkg/generated/listers/tenant/v1alpha1"
+)
+
+// OIDCConfig is the configuration of the tenant OIDC authenticator.
+type OIDCConfig struct {
+	// IssuerURL is the https URL of the OIDC issuer.
+	IssuerURL string
+	// ClientID is the audience the ID tokens must be issued for.
+	ClientID string
+	// CAFile is the CA bundle to verify the issuer, the host's root CA set
+	// is used if empty.
+	CAFile string
+	// UsernameClaim is the claim used as the user name.
+	UsernameClaim string
+	// UsernamePrefix is prepended to the user name, so that the OIDC users
+	// never collide with the users whose certificates are issued by kubezoo.
+	UsernamePrefix string
+	// TenantClaim is the string or string array claim holding the tenant ID.
+	TenantClaim string
+	// TenantPrefix filters the values of the tenant claim, only the values
+	// with the prefix are taken as tenant IDs after the prefix is trimmed.
+	TenantPrefix string
+	// SigningAlgs are the accepted signing algorithms, RS256 if empty.
+	SigningAlgs []string
+	// RequiredClaims are the claims that must be present in the ID tokens
+	// with the given values.
+	RequiredClaims map[string]string
+	// Client is used to talk to the issuer instead of the one built from
+	// CAFile if not nil.
+	Client *http.Client
+}
+
+// tenantOIDCAuthenticator authenticates the OIDC ID tokens and maps the
+// users to the tenant named in the tenant claim.
+type tenantOIDCAuthenticator struct {
+	delegate     authenticator.Token
+	tenantPrefix string
+	tenantLister tenantlister.TenantLister
+}
+
+var _ authenticator.Token = &tenantOIDCAuthenticator{}
+
+// NewOIDCAuthenticator returns a token authenticator which verifies the OIDC
+// ID tokens, and maps the user to the existing tenant named in the tenant
+// claim. The user name is prefixed with the tenant ID and the tenant is
+// recorded in the user extra, the groups are dropped as they are not
+// isolated between tenants.
+func NewOIDCAuthenticator(c OIDCConfig, tenantLister tenantlister.TenantLister) (authenticator.Token, error) {
+	if len(c.TenantClaim) == 0 {
+		return nil, errors.New("no tenant claim provided")
+	}
+	if tenantLister == nil {
+		return nil, errors.New("no tenant lister provided")
+	}
+
+	opts := oidc.Options{
+		IssuerURL:      c.IssuerURL,
+		ClientID:       c.ClientID,
+		Client:         c.Client,
+		UsernameClaim:  c.UsernameClaim,
+		UsernamePrefix: c.UsernamePrefix,
+		// the tenant claim is resolved as the groups, which handles both
+		// the string and the string array claims.
+		GroupsClaim:          c.TenantClaim,
+		SupportedSigningAlgs: c.SigningAlgs,
+		RequiredClaims:       c.RequiredClaims,
+	}
+	if len(c.CAFile) > 0 && c.Client == nil {
+		ca, err := dynamiccertificates.NewDynamicCAContentFromFile("tenant-oidc-authenticator", c.CAFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load tenant oidc ca file %s", c.CAFile)
+		}
+		opts.CAContentProvider = ca
+	}
+	delegate, err := oidc.New(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tenantOIDCAuthenticator{
+		delegate:     delegate,
+		tenantPrefix: c.TenantPrefix,
+		tenantLister: tenantLister,
+	}, nil
+}
+
+// AuthenticateToken implements authenticator.Token.
+func (a *tenantOIDCAuthenticator) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	resp, ok, err := a.delegate.AuthenticateToken(ctx, token)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	userName := resp.User.GetName()
+	tenant, err := a.tenantFor(resp.User.GetGroups())
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "oidc user %s", userName)
+	}
+	if err := checkTenantUserName(tenant, userName); err != nil {
+		return nil, false, errors.Wrap(err, "oidc")
+	}
+
+	return &authenticator.Response{
+		Audiences: resp.Audiences,
+		User:      newTenantUser(tenant.Name, &user.DefaultInfo{Name: userName}),
+	}, true, nil
+}
+
+// tenantFor returns the only existing tenant among the values of the tenant
+// claim, the deleting tenants are ignored.
+func (a *tenantOIDCAuthenticator) tenantFor(values []string) (*tenantv1alpha1.Tenant, error) {
+	tenants := map[string]*tenantv1alpha1.Tenant{}
+	for _, v := range values {
+		if !strings.HasPrefix(v, a.tenantPrefix) {
+			continue
+		}
+		tenantID := strings.TrimPrefix(v, a.tenantPrefix)
+		tenant, ok, err := getActiveTenant(a.tenantLister, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			tenants[tenantID] = tenant
+		}
+	}
+
+	switch len(tenants) {
+	case 0:
+		return nil, errors.New("is not mapped to any tenant")
+	case 1:
+		for _, tenant := range tenants {
+			return tenant, nil
+		}
+	}
+	names := make([]string, 0, len(tenants))
+	for name := range tenants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return nil, errors.Errorf("is mapped to multiple tenants %v", names)
+}
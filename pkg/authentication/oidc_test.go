@@ -0,0 +1,232 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authentication
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jose "gopkg.in/square/go-jose.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	tenantlister "github.com/kubewharf/kubezoo/pkg/generated/listers/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// fakeIssuer is a local OIDC issuer serving the discovery document and the
+// signing keys.
+type fakeIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	issuer := &fakeIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                issuer.server.URL,
+			"jwks_uri":                              issuer.server.URL + "/keys",
+			"authorization_endpoint":                issuer.server.URL + "/auth",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "test", Algorithm: "RS256", Use: "sig"},
+		}})
+	})
+	issuer.server = httptest.NewTLSServer(mux)
+	return issuer
+}
+
+func (f *fakeIssuer) token(t *testing.T, claims map[string]interface{}) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: f.key},
+		(&jose.SignerOptions{}).WithHeader("kid", "test"))
+	if err != nil {
+		t.Fatalf("failed to create signer: %v", err)
+	}
+	payload := map[string]interface{}{
+		"iss": f.server.URL,
+		"aud": "kubezoo",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range claims {
+		payload[k] = v
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal claims: %v", err)
+	}
+	jws, err := signer.Sign(data)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	token, err := jws.CompactSerialize()
+	if err != nil {
+		t.Fatalf("failed to serialize token: %v", err)
+	}
+	return token
+}
+
+func TestOIDCAuthenticator(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	defer issuer.server.Close()
+
+	now := metav1.Now()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, tenant := range []*tenantv1alpha1.Tenant{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "foofoo"},
+			Spec: tenantv1alpha1.TenantSpec{
+				Users: []tenantv1alpha1.TenantUser{{Name: "alice"}},
+			},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "barbar"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "deldel", DeletionTimestamp: &now}},
+	} {
+		indexer.Add(tenant)
+	}
+
+	auth, err := NewOIDCAuthenticator(OIDCConfig{
+		IssuerURL:     issuer.server.URL,
+		ClientID:      "kubezoo",
+		UsernameClaim: "sub",
+		TenantClaim:   "groups",
+		TenantPrefix:  "kubezoo:",
+		Client:        issuer.server.Client(),
+	}, tenantlister.NewTenantLister(indexer))
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+
+	// the verifier is initialized asynchronously by the discovery
+	validToken := issuer.token(t, map[string]interface{}{"sub": "bob", "groups": []string{"kubezoo:foofoo"}})
+	if err := wait.PollImmediate(100*time.Millisecond, 10*time.Second, func() (bool, error) {
+		_, ok, _ := auth.AuthenticateToken(context.TODO(), validToken)
+		return ok, nil
+	}); err != nil {
+		t.Fatalf("authenticator is not initialized: %v", err)
+	}
+
+	testCases := map[string]struct {
+		claims     map[string]interface{}
+		expectOK   bool
+		expectUser string
+		expectID   string
+	}{
+		"tenant in array claim": {
+			claims:     map[string]interface{}{"sub": "bob", "groups": []string{"dev", "kubezoo:foofoo"}},
+			expectOK:   true,
+			expectUser: util.AddTenantIDPrefix("foofoo", "bob"),
+			expectID:   "foofoo",
+		},
+		"tenant in string claim": {
+			claims:     map[string]interface{}{"sub": "bob", "groups": "kubezoo:barbar"},
+			expectOK:   true,
+			expectUser: util.AddTenantIDPrefix("barbar", "bob"),
+			expectID:   "barbar",
+		},
+		"values without prefix are ignored": {
+			claims: map[string]interface{}{"sub": "bob", "groups": []string{"foofoo"}},
+		},
+		"tenant not found": {
+			claims: map[string]interface{}{"sub": "bob", "groups": []string{"kubezoo:bazbaz"}},
+		},
+		"tenant is deleting": {
+			claims: map[string]interface{}{"sub": "bob", "groups": []string{"kubezoo:deldel"}},
+		},
+		"multiple tenants": {
+			claims: map[string]interface{}{"sub": "bob", "groups": []string{"kubezoo:foofoo", "kubezoo:barbar"}},
+		},
+		"no tenant claim": {
+			claims: map[string]interface{}{"sub": "bob"},
+		},
+		"admin user is reserved": {
+			claims: map[string]interface{}{"sub": util.TenantAdminUserName, "groups": []string{"kubezoo:foofoo"}},
+		},
+		"named user of tenant is reserved": {
+			claims: map[string]interface{}{"sub": "alice", "groups": []string{"kubezoo:foofoo"}},
+		},
+		"named user of another tenant": {
+			claims:     map[string]interface{}{"sub": "alice", "groups": []string{"kubezoo:barbar"}},
+			expectOK:   true,
+			expectUser: util.AddTenantIDPrefix("barbar", "alice"),
+			expectID:   "barbar",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			resp, ok, err := auth.AuthenticateToken(context.TODO(), issuer.token(t, tc.claims))
+			if ok != tc.expectOK {
+				t.Fatalf("expect ok %v, got %v, err: %v", tc.expectOK, ok, err)
+			}
+			if !ok {
+				if err == nil {
+					t.Errorf("expect error for rejected token")
+				}
+				return
+			}
+			if resp.User.GetName() != tc.expectUser {
+				t.Errorf("expect user %s, got %s", tc.expectUser, resp.User.GetName())
+			}
+			if len(resp.User.GetGroups()) != 0 {
+				t.Errorf("expect no groups, got %v", resp.User.GetGroups())
+			}
+			if got := resp.User.GetExtra()[util.TenantIDKey]; len(got) != 1 || got[0] != tc.expectID {
+				t.Errorf("expect tenant %s, got %v", tc.expectID, got)
+			}
+		})
+	}
+}
+
+func TestOIDCAuthenticatorIgnoresOtherIssuers(t *testing.T) {
+	issuer, other := newFakeIssuer(t), newFakeIssuer(t)
+	defer issuer.server.Close()
+	defer other.server.Close()
+
+	auth, err := NewOIDCAuthenticator(OIDCConfig{
+		IssuerURL:     issuer.server.URL,
+		ClientID:      "kubezoo",
+		UsernameClaim: "sub",
+		TenantClaim:   "groups",
+		Client:        issuer.server.Client(),
+	}, tenantlister.NewTenantLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})))
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+	_, ok, err := auth.AuthenticateToken(context.TODO(), other.token(t, map[string]interface{}{"sub": "bob", "groups": "foofoo"}))
+	if ok || err != nil {
+		t.Errorf("expect token of other issuers to be skipped, got ok %v, err %v", ok, err)
+	}
+}
@@ -0,0 +1,324 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authentication
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/square/go-jose.v2/jwt"
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	clientgoinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+	"k8s.io/kubernetes/pkg/apis/core"
+	serviceaccountcontroller "k8s.io/kubernetes/pkg/controller/serviceaccount"
+	"k8s.io/kubernetes/pkg/serviceaccount"
+
+	tenantlister "github.com/kubewharf/kubezoo/pkg/generated/listers/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// tenantIssuerPath is appended to the issuer URL of kubezoo, followed by the
+// tenant ID, to form the issuer URL of the tenant.
+const tenantIssuerPath = "/tenants/"
+
+// ServiceAccountIssuerConfig is the configuration of the tenant service
+// account token issuer.
+type ServiceAccountIssuerConfig struct {
+	// IssuerURL is the https URL of kubezoo as an issuer, the issuer of
+	// each tenant is IssuerURL/tenants/<tenant>.
+	IssuerURL string
+	// SigningKey is the private key to sign the tokens.
+	SigningKey interface{}
+	// PublicKeys are the keys to verify the tokens, e.g. the ones of the
+	// retired signing keys, besides the public key of SigningKey.
+	PublicKeys []interface{}
+	// MaxExpiration is the max expiration of the issued tokens, 0 means
+	// no limit.
+	MaxExpiration time.Duration
+}
+
+// ServiceAccountIssuer issues the service account tokens of the tenants on
+// behalf of kubezoo, serves the OIDC discovery documents of the tenant
+// issuers and authenticates the tokens it issues. The claims of the tokens
+// are those seen inside the tenant, i.e. the issuer is the one of the
+// tenant and the namespace is not prefixed with the tenant ID.
+type ServiceAccountIssuer struct {
+	issuerURL     string
+	pathPrefix    string
+	signingKey    interface{}
+	publicKeys    []interface{}
+	maxExpiration time.Duration
+	tenantLister  tenantlister.TenantLister
+	getter        serviceaccount.ServiceAccountTokenGetter
+}
+
+var _ authenticator.Token = &ServiceAccountIssuer{}
+
+// NewServiceAccountIssuer returns the tenant service account token issuer.
+// The service accounts, the pods and the secrets the tokens are bound to are
+// looked up from the upstream informers, and from the upstream cluster by
+// the client only if missing in the informers, e.g. just created. The
+// informers must be started by the caller.
+func NewServiceAccountIssuer(c ServiceAccountIssuerConfig, tenantLister tenantlister.TenantLister, client kubernetes.Interface,
+	informers clientgoinformers.SharedInformerFactory) (*ServiceAccountIssuer, error) {
+	if tenantLister == nil {
+		return nil, errors.New("no tenant lister provided")
+	}
+	if client == nil {
+		return nil, errors.New("no upstream client provided")
+	}
+	if informers == nil {
+		return nil, errors.New("no upstream informers provided")
+	}
+	u, err := url.Parse(c.IssuerURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid issuer url")
+	}
+	if u.Scheme != "https" || len(u.RawQuery) != 0 || len(u.Fragment) != 0 {
+		return nil, errors.Errorf("issuer url %s must be https without query or fragment", c.IssuerURL)
+	}
+	signer, ok := c.SigningKey.(interface{ Public() crypto.PublicKey })
+	if !ok {
+		return nil, errors.Errorf("unsupported signing key type %T", c.SigningKey)
+	}
+	issuerURL := strings.TrimSuffix(c.IssuerURL, "/")
+	return &ServiceAccountIssuer{
+		issuerURL:     issuerURL,
+		pathPrefix:    strings.TrimSuffix(u.Path, "/") + tenantIssuerPath,
+		signingKey:    c.SigningKey,
+		publicKeys:    append([]interface{}{signer.Public()}, c.PublicKeys...),
+		maxExpiration: c.MaxExpiration,
+		tenantLister:  tenantLister,
+		getter: serviceaccountcontroller.NewGetterFromClient(client,
+			informers.Core().V1().Secrets().Lister(),
+			informers.Core().V1().ServiceAccounts().Lister(),
+			informers.Core().V1().Pods().Lister()),
+	}, nil
+}
+
+// TenantIssuerURL returns the issuer URL of the tenant.
+func (i *ServiceAccountIssuer) TenantIssuerURL(tenantID string) string {
+	return i.issuerURL + tenantIssuerPath + tenantID
+}
+
+// MaxExpiration returns the max expiration of the tokens, 0 means no limit.
+func (i *ServiceAccountIssuer) MaxExpiration() time.Duration {
+	return i.maxExpiration
+}
+
+// PathPrefix returns the URL path prefix of the discovery documents of the
+// tenant issuers.
+func (i *ServiceAccountIssuer) PathPrefix() string {
+	return i.pathPrefix
+}
+
+// GenerateToken generates the token of the service account in the tenant,
+// which is optionally bound to the pod or the secret. The objects are those
+// seen inside the tenant, with the UIDs of the upstream objects.
+func (i *ServiceAccountIssuer) GenerateToken(tenantID string, serviceAccount core.ServiceAccount, pod *core.Pod, secret *core.Secret,
+	expirationSeconds int64, audiences []string) (string, *jwt.Claims, error) {
+	generator, err := serviceaccount.JWTTokenGenerator(i.TenantIssuerURL(tenantID), i.signingKey)
+	if err != nil {
+		return "", nil, err
+	}
+	claims, privateClaims := serviceaccount.Claims(serviceAccount, pod, secret, expirationSeconds, 0, audiences)
+	token, err := generator.GenerateToken(claims, privateClaims)
+	if err != nil {
+		return "", nil, err
+	}
+	// the issuer is only set in the token by the generator
+	claims.Issuer = i.TenantIssuerURL(tenantID)
+	return token, claims, nil
+}
+
+// tenantFromIssuer returns the tenant ID in the issuer URL of the tenant.
+func (i *ServiceAccountIssuer) tenantFromIssuer(issuer string) (string, bool) {
+	prefix := i.issuerURL + tenantIssuerPath
+	if !strings.HasPrefix(issuer, prefix) {
+		return "", false
+	}
+	tenantID := strings.TrimPrefix(issuer, prefix)
+	if len(tenantID) == 0 || strings.Contains(tenantID, "/") {
+		return "", false
+	}
+	return tenantID, true
+}
+
+// unverifiedIssuer returns the issuer claim of the token without verifying
+// the signature, which is only used to pick the tenant.
+func unverifiedIssuer(token string) (string, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", false
+	}
+	claims := struct {
+		// WARNING: this JWT is not verified. Do not trust these claims.
+		Issuer string `json:"iss"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", false
+	}
+	return claims.Issuer, true
+}
+
+// AuthenticateToken implements authenticator.Token. The tokens issued for
+// the tenant are accepted with the audience of the tenant issuer or the
+// audiences of kubezoo, and the service account, the pod and the secret
+// they are bound to must still exist in the tenant. The user is the service
+// account in the upstream cluster with the tenant recorded in the extra.
+func (i *ServiceAccountIssuer) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	issuer, ok := unverifiedIssuer(token)
+	if !ok {
+		return nil, false, nil
+	}
+	tenantID, ok := i.tenantFromIssuer(issuer)
+	if !ok {
+		return nil, false, nil
+	}
+	tok, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	tenant, found, err := getActiveTenant(i.tenantLister, tenantID)
+	if err != nil {
+		return nil, false, err
+	}
+	if !found {
+		return nil, false, errors.Errorf("tenant %s of the service account token is not found", tenantID)
+	}
+
+	validator := serviceaccount.NewValidator(&tenantServiceAccountGetter{tenantID: tenant.Name, getter: i.getter})
+	public := &jwt.Claims{}
+	private := validator.NewPrivateClaims()
+	var errs []error
+	for _, key := range i.publicKeys {
+		if err := tok.Claims(key, public, private); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		errs = nil
+		break
+	}
+	if len(errs) != 0 {
+		return nil, false, utilerrors.NewAggregate(errs)
+	}
+	if public.Issuer != issuer {
+		return nil, false, errors.New("invalid issuer of the service account token")
+	}
+
+	tokenAudiences := authenticator.Audiences(public.Audience)
+	apiAudiences, _ := authenticator.AudiencesFrom(ctx)
+	audiences := tokenAudiences.Intersect(apiAudiences)
+	if len(audiences) == 0 && !tokenAudiences.Has(i.TenantIssuerURL(tenant.Name)) {
+		return nil, false, errors.Errorf("token audiences %q is invalid for the target audiences %q", tokenAudiences,
+			append(apiAudiences, i.TenantIssuerURL(tenant.Name)))
+	}
+
+	info, err := validator.Validate(ctx, token, public, private)
+	if err != nil {
+		return nil, false, err
+	}
+	info.Namespace = util.AddTenantIDPrefix(tenant.Name, info.Namespace)
+	return &authenticator.Response{
+		Audiences: audiences,
+		User:      util.AddTenantIDToUserInfo(tenant.Name, info.UserInfo()),
+	}, true, nil
+}
+
+// ServeHTTP serves the OIDC discovery document and the JWKS of the tenant
+// issuers, i.e. <prefix><tenant>/.well-known/openid-configuration and
+// <prefix><tenant>/openid/v1/jwks.
+func (i *ServiceAccountIssuer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	path := strings.TrimPrefix(req.URL.Path, i.pathPrefix)
+	var tenantID, document string
+	for _, suffix := range []string{serviceaccount.OpenIDConfigPath, serviceaccount.JWKSPath} {
+		if strings.HasSuffix(path, suffix) {
+			tenantID, document = strings.TrimSuffix(path, suffix), suffix
+			break
+		}
+	}
+	if len(tenantID) == 0 || strings.Contains(tenantID, "/") {
+		http.NotFound(w, req)
+		return
+	}
+	if _, found, err := getActiveTenant(i.tenantLister, tenantID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	} else if !found {
+		http.NotFound(w, req)
+		return
+	}
+
+	tenantIssuerURL := i.TenantIssuerURL(tenantID)
+	metadata, err := serviceaccount.NewOpenIDMetadata(tenantIssuerURL, tenantIssuerURL+serviceaccount.JWKSPath, "", i.publicKeys)
+	if err != nil {
+		klog.Errorf("failed to build the openid metadata of tenant %s: %v", tenantID, err)
+		http.Error(w, "failed to build the openid metadata", http.StatusInternalServerError)
+		return
+	}
+	body, contentType := metadata.ConfigJSON, "application/json"
+	if document == serviceaccount.JWKSPath {
+		body, contentType = metadata.PublicKeysetJSON, "application/jwk-set+json"
+	}
+	// the documents only change with the signing keys, hence cacheable
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// tenantServiceAccountGetter gets the objects of the tenant in the upstream
+// cluster for the service account token validator, the namespaces are those
+// seen inside the tenant.
+type tenantServiceAccountGetter struct {
+	tenantID string
+	getter   serviceaccount.ServiceAccountTokenGetter
+}
+
+var _ serviceaccount.ServiceAccountTokenGetter = &tenantServiceAccountGetter{}
+
+func (g *tenantServiceAccountGetter) GetServiceAccount(namespace, name string) (*corev1.ServiceAccount, error) {
+	return g.getter.GetServiceAccount(util.AddTenantIDPrefix(g.tenantID, namespace), name)
+}
+
+func (g *tenantServiceAccountGetter) GetPod(namespace, name string) (*corev1.Pod, error) {
+	return g.getter.GetPod(util.AddTenantIDPrefix(g.tenantID, namespace), name)
+}
+
+func (g *tenantServiceAccountGetter) GetSecret(namespace, name string) (*corev1.Secret, error) {
+	return g.getter.GetSecret(util.AddTenantIDPrefix(g.tenantID, namespace), name)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authentication
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	clientgoinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/apis/core"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	tenantlister "github.com/kubewharf/kubezoo/pkg/generated/listers/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// newTestServiceAccountIssuer returns the issuer of the tenants foofoo and
+// deldel, which is being deleted, and the upstream service account default
+// in namespace ns of foofoo, along with the fake upstream client.
+func newTestServiceAccountIssuer(t *testing.T, issuerURL string) (*ServiceAccountIssuer, *fake.Clientset) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := metav1.Now()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	indexer.Add(&tenantv1alpha1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "foofoo"}})
+	indexer.Add(&tenantv1alpha1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "deldel", DeletionTimestamp: &now}})
+	client := fake.NewSimpleClientset(
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "foofoo-ns", UID: "sa-uid"}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "deldel-ns", UID: "sa-uid"}},
+	)
+	informers := clientgoinformers.NewSharedInformerFactory(client, 0)
+	issuer, err := NewServiceAccountIssuer(ServiceAccountIssuerConfig{
+		IssuerURL:  issuerURL,
+		SigningKey: key,
+	}, tenantlister.NewTenantLister(indexer), client, informers)
+	if err != nil {
+		t.Fatalf("failed to create issuer: %v", err)
+	}
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	informers.Start(stopCh)
+	informers.WaitForCacheSync(stopCh)
+	return issuer, client
+}
+
+func TestServiceAccountIssuerAuthenticateToken(t *testing.T) {
+	issuer, client := newTestServiceAccountIssuer(t, "https://kubezoo.example.com/")
+	other, _ := newTestServiceAccountIssuer(t, "https://other.example.com")
+	if got := issuer.TenantIssuerURL("foofoo"); got != "https://kubezoo.example.com/tenants/foofoo" {
+		t.Fatalf("unexpected tenant issuer %s", got)
+	}
+
+	sa := core.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "ns", UID: "sa-uid"}}
+	token := func(tenantID string, sa core.ServiceAccount, audiences []string) string {
+		tok, claims, err := issuer.GenerateToken(tenantID, sa, nil, nil, 3600, audiences)
+		if err != nil {
+			t.Fatalf("failed to generate token: %v", err)
+		}
+		if claims.Issuer != issuer.TenantIssuerURL(tenantID) {
+			t.Fatalf("unexpected issuer claim %s", claims.Issuer)
+		}
+		return tok
+	}
+
+	resp, ok, err := issuer.AuthenticateToken(context.TODO(), token("foofoo", sa, []string{issuer.TenantIssuerURL("foofoo")}))
+	if !ok || err != nil {
+		t.Fatalf("expect token to be authenticated, got ok %v, err %v", ok, err)
+	}
+	if name := resp.User.GetName(); name != "system:serviceaccount:foofoo-ns:default" {
+		t.Errorf("expect upstream service account user, got %s", name)
+	}
+	if got := resp.User.GetExtra()[util.TenantIDKey]; len(got) != 1 || got[0] != "foofoo" {
+		t.Errorf("expect tenant foofoo, got %v", got)
+	}
+	// the service account is looked up from the informer
+	for _, action := range client.Actions() {
+		if action.GetVerb() != "list" && action.GetVerb() != "watch" {
+			t.Errorf("unexpected action on the upstream cluster: %v", action)
+		}
+	}
+
+	// the audiences of kubezoo are accepted as well
+	ctx := authenticator.WithAudiences(context.TODO(), authenticator.Audiences{"kubezoo"})
+	if resp, ok, err := issuer.AuthenticateToken(ctx, token("foofoo", sa, []string{"kubezoo"})); !ok || err != nil {
+		t.Errorf("expect token with api audience to be authenticated, got ok %v, err %v", ok, err)
+	} else if len(resp.Audiences) != 1 || resp.Audiences[0] != "kubezoo" {
+		t.Errorf("expect api audience, got %v", resp.Audiences)
+	}
+
+	otherToken, _, err := other.GenerateToken("foofoo", sa, nil, nil, 3600, []string{issuer.TenantIssuerURL("foofoo")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := issuer.AuthenticateToken(context.TODO(), otherToken); ok || err != nil {
+		t.Errorf("expect token of other issuer to be ignored, got ok %v, err %v", ok, err)
+	}
+
+	recreated := *sa.DeepCopy()
+	recreated.UID = "old-uid"
+	for name, tok := range map[string]string{
+		"other audience":  token("foofoo", sa, []string{"vault"}),
+		"recreated sa":    token("foofoo", recreated, []string{issuer.TenantIssuerURL("foofoo")}),
+		"deleting tenant": token("deldel", sa, []string{issuer.TenantIssuerURL("deldel")}),
+		"unknown tenant":  token("bazbaz", sa, []string{issuer.TenantIssuerURL("bazbaz")}),
+	} {
+		if _, ok, _ := issuer.AuthenticateToken(context.TODO(), tok); ok {
+			t.Errorf("expect token with %s to be rejected", name)
+		}
+	}
+}
+
+func TestServiceAccountIssuerDiscovery(t *testing.T) {
+	issuer, _ := newTestServiceAccountIssuer(t, "https://kubezoo.example.com/")
+	if prefix := issuer.PathPrefix(); prefix != "/tenants/" {
+		t.Fatalf("unexpected path prefix %s", prefix)
+	}
+
+	tests := []struct {
+		path       string
+		expectCode int
+	}{
+		{path: "/tenants/foofoo/.well-known/openid-configuration", expectCode: http.StatusOK},
+		{path: "/tenants/foofoo/openid/v1/jwks", expectCode: http.StatusOK},
+		{path: "/tenants/deldel/openid/v1/jwks", expectCode: http.StatusNotFound},
+		{path: "/tenants/bazbaz/.well-known/openid-configuration", expectCode: http.StatusNotFound},
+		{path: "/tenants/foofoo/openid/v1/keys", expectCode: http.StatusNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			issuer.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+			if w.Code != test.expectCode {
+				t.Fatalf("expect code %d, got %d: %s", test.expectCode, w.Code, w.Body.String())
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			doc := map[string]interface{}{}
+			if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+				t.Fatalf("invalid document: %v", err)
+			}
+			if _, ok := doc["keys"]; ok {
+				return
+			}
+			if doc["issuer"] != issuer.TenantIssuerURL("foofoo") {
+				t.Errorf("unexpected issuer %v", doc["issuer"])
+			}
+			if doc["jwks_uri"] != issuer.TenantIssuerURL("foofoo")+"/openid/v1/jwks" {
+				t.Errorf("unexpected jwks uri %v", doc["jwks_uri"])
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authentication
+
+import (
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/authentication/user"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	tenantlister "github.com/kubewharf/kubezoo/pkg/generated/listers/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// getActiveTenant returns the tenant unless it is not found or being deleted.
+func getActiveTenant(tenantLister tenantlister.TenantLister, tenantID string) (*tenantv1alpha1.Tenant, bool, error) {
+	if util.ValidateTenantName(tenantID) != nil {
+		return nil, false, nil
+	}
+	tenant, err := tenantLister.Get(tenantID)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	if tenant.DeletionTimestamp != nil {
+		return nil, false, nil
+	}
+	return tenant, true, nil
+}
+
+// checkTenantUserName checks that the externally authenticated user does not
+// pass as the users whose certificates are issued by kubezoo.
+func checkTenantUserName(tenant *tenantv1alpha1.Tenant, userName string) error {
+	if len(userName) == 0 {
+		return errors.Errorf("empty user name in tenant %s", tenant.Name)
+	}
+	if userName == util.TenantAdminUserName {
+		return errors.Errorf("user %s is reserved in tenant %s", userName, tenant.Name)
+	}
+	for _, u := range tenant.Spec.Users {
+		if u.Name == userName {
+			return errors.Errorf("user %s conflicts with the user of tenant %s", userName, tenant.Name)
+		}
+	}
+	return nil
+}
+
+// newTenantUser returns the user of the tenant, whose name is prefixed with
+// the tenant ID. The groups are dropped as they are not isolated between
+// tenants.
+func newTenantUser(tenantID string, u user.Info) user.Info {
+	extra := map[string][]string{}
+	for k, v := range u.GetExtra() {
+		extra[k] = v
+	}
+	extra[util.TenantIDKey] = []string{tenantID}
+	return &user.DefaultInfo{
+		Name:  util.AddTenantIDPrefix(tenantID, u.GetName()),
+		UID:   u.GetUID(),
+		Extra: extra,
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authentication
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/token/cache"
+	webhookutil "k8s.io/apiserver/pkg/util/webhook"
+	"k8s.io/apiserver/plugin/pkg/authenticator/token/webhook"
+	"k8s.io/client-go/rest"
+
+	tenantlister "github.com/kubewharf/kubezoo/pkg/generated/listers/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// WebhookConfig is the configuration of the tenant token webhook.
+type WebhookConfig struct {
+	// ClientConfig is used to send the TokenReview to the webhook.
+	ClientConfig *rest.Config
+	// CacheTTL is the duration to cache the responses of the webhook,
+	// 0 means no cache.
+	CacheTTL time.Duration
+}
+
+// tenantWebhookAuthenticator authenticates the tokens by the TokenReview
+// webhook, which returns the tenant user with the tenant in the user extra.
+type tenantWebhookAuthenticator struct {
+	delegate     authenticator.Token
+	tenantLister tenantlister.TenantLister
+}
+
+var _ authenticator.Token = &tenantWebhookAuthenticator{}
+
+// NewWebhookAuthenticator returns a token authenticator which sends the
+// TokenReview to the webhook. The webhook returns the tenant side user name
+// and the tenant ID in the "tenant" extra, the user is then prefixed with the
+// existing tenant. The responses are cached by the token, while the tenant is
+// checked on every request so that the users of deleted tenants are rejected
+// immediately.
+func NewWebhookAuthenticator(c WebhookConfig, tenantLister tenantlister.TenantLister) (authenticator.Token, error) {
+	if c.ClientConfig == nil {
+		return nil, errors.New("no webhook client config provided")
+	}
+	if tenantLister == nil {
+		return nil, errors.New("no tenant lister provided")
+	}
+	webhookAuthenticator, err := webhook.New(c.ClientConfig, "v1", nil, webhookutil.DefaultRetryBackoffWithInitialDelay(500*time.Millisecond))
+	if err != nil {
+		return nil, err
+	}
+	var delegate authenticator.Token = webhookAuthenticator
+	if c.CacheTTL > 0 {
+		delegate = cache.New(delegate, false, c.CacheTTL, c.CacheTTL)
+	}
+	return &tenantWebhookAuthenticator{
+		delegate:     delegate,
+		tenantLister: tenantLister,
+	}, nil
+}
+
+// AuthenticateToken implements authenticator.Token.
+func (a *tenantWebhookAuthenticator) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	resp, ok, err := a.delegate.AuthenticateToken(ctx, token)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	userName := resp.User.GetName()
+	tenantIDs := resp.User.GetExtra()[util.TenantIDKey]
+	if len(tenantIDs) != 1 {
+		return nil, false, errors.Errorf("webhook user %s must belong to exactly one tenant, got %v", userName, tenantIDs)
+	}
+	tenant, found, err := getActiveTenant(a.tenantLister, tenantIDs[0])
+	if err != nil {
+		return nil, false, err
+	}
+	if !found {
+		return nil, false, errors.Errorf("tenant %s of webhook user %s is not found", tenantIDs[0], userName)
+	}
+	if err := checkTenantUserName(tenant, userName); err != nil {
+		return nil, false, errors.Wrap(err, "webhook")
+	}
+
+	return &authenticator.Response{
+		Audiences: resp.Audiences,
+		User:      newTenantUser(tenant.Name, resp.User),
+	}, true, nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authentication
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	tenantlister "github.com/kubewharf/kubezoo/pkg/generated/listers/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// newFakeTokenWebhook returns a TokenReview webhook which authenticates the
+// tokens in users.
+func newFakeTokenWebhook(users map[string]authenticationv1.UserInfo, calls *int32) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		review := &authenticationv1.TokenReview{}
+		if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		u, ok := users[review.Spec.Token]
+		review.Status = authenticationv1.TokenReviewStatus{Authenticated: ok, User: u}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(review)
+	}))
+}
+
+func TestWebhookAuthenticator(t *testing.T) {
+	var calls int32
+	server := newFakeTokenWebhook(map[string]authenticationv1.UserInfo{
+		"bob": {
+			Username: "bob",
+			UID:      "1",
+			Groups:   []string{"system:masters"},
+			Extra:    map[string]authenticationv1.ExtraValue{util.TenantIDKey: {"foofoo"}},
+		},
+		"no-tenant":       {Username: "bob"},
+		"multiple-tenant": {Username: "bob", Extra: map[string]authenticationv1.ExtraValue{util.TenantIDKey: {"foofoo", "barbar"}}},
+		"unknown-tenant":  {Username: "bob", Extra: map[string]authenticationv1.ExtraValue{util.TenantIDKey: {"bazbaz"}}},
+		"deleting-tenant": {Username: "bob", Extra: map[string]authenticationv1.ExtraValue{util.TenantIDKey: {"deldel"}}},
+		"admin":           {Username: util.TenantAdminUserName, Extra: map[string]authenticationv1.ExtraValue{util.TenantIDKey: {"foofoo"}}},
+		"alice":           {Username: "alice", Extra: map[string]authenticationv1.ExtraValue{util.TenantIDKey: {"foofoo"}}},
+	}, &calls)
+	defer server.Close()
+
+	now := metav1.Now()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, tenant := range []*tenantv1alpha1.Tenant{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "foofoo"},
+			Spec: tenantv1alpha1.TenantSpec{
+				Users: []tenantv1alpha1.TenantUser{{Name: "alice"}},
+			},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "barbar"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "deldel", DeletionTimestamp: &now}},
+	} {
+		indexer.Add(tenant)
+	}
+
+	auth, err := NewWebhookAuthenticator(WebhookConfig{
+		ClientConfig: &rest.Config{Host: server.URL, TLSClientConfig: rest.TLSClientConfig{Insecure: true}},
+		CacheTTL:     time.Minute,
+	}, tenantlister.NewTenantLister(indexer))
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+
+	resp, ok, err := auth.AuthenticateToken(context.TODO(), "bob")
+	if !ok || err != nil {
+		t.Fatalf("expect token to be authenticated, got ok %v, err %v", ok, err)
+	}
+	if name := resp.User.GetName(); name != util.AddTenantIDPrefix("foofoo", "bob") {
+		t.Errorf("expect prefixed user name, got %s", name)
+	}
+	if resp.User.GetUID() != "1" {
+		t.Errorf("expect uid 1, got %s", resp.User.GetUID())
+	}
+	if len(resp.User.GetGroups()) != 0 {
+		t.Errorf("expect no groups, got %v", resp.User.GetGroups())
+	}
+	if got := resp.User.GetExtra()[util.TenantIDKey]; len(got) != 1 || got[0] != "foofoo" {
+		t.Errorf("expect tenant foofoo, got %v", got)
+	}
+
+	for _, token := range []string{"unknown", "no-tenant", "multiple-tenant", "unknown-tenant", "deleting-tenant", "admin", "alice"} {
+		if _, ok, _ := auth.AuthenticateToken(context.TODO(), token); ok {
+			t.Errorf("expect token %s to be rejected", token)
+		}
+	}
+
+	// the cached response is checked against the tenant again
+	before := atomic.LoadInt32(&calls)
+	if _, ok, _ := auth.AuthenticateToken(context.TODO(), "bob"); !ok {
+		t.Errorf("expect cached token to be authenticated")
+	}
+	if after := atomic.LoadInt32(&calls); after != before {
+		t.Errorf("expect response to be cached, got %d more calls", after-before)
+	}
+	foofoo, _, _ := indexer.GetByKey("foofoo")
+	deleting := foofoo.(*tenantv1alpha1.Tenant).DeepCopy()
+	deleting.DeletionTimestamp = &now
+	indexer.Update(deleting)
+	if _, ok, _ := auth.AuthenticateToken(context.TODO(), "bob"); ok {
+		t.Errorf("expect cached token of deleting tenant to be rejected")
+	}
+}
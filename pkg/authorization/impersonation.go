@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
+	webhookutil "k8s.io/apiserver/pkg/util/webhook"
+	authorizationclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// tenantImpersonationAuthorizer authorizes the tenant users to impersonate
+// the identities of their tenants by the RBAC of the tenants, which lives in
+// the upstream cluster.
+type tenantImpersonationAuthorizer struct {
+	delegate authorizer.Authorizer
+}
+
+var _ authorizer.Authorizer = &tenantImpersonationAuthorizer{}
+
+// NewImpersonationAuthorizer returns the authorizer of the impersonation of
+// the tenant users. The users, the service accounts and the groups to
+// impersonate are those seen inside the tenant, which are authorized by the
+// upstream cluster after being prefixed with the tenant ID. Only the groups
+// isolated between the tenants, i.e. those prefixed with the tenant ID, the
+// service account groups, system:authenticated and system:serviceaccounts,
+// can be impersonated, the latter two authorized as their equivalents
+// prefixed with the tenant ID, and the tenant extras are reserved.
+func NewImpersonationAuthorizer(client authorizationclient.AuthorizationV1Interface) (authorizer.Authorizer, error) {
+	backoff := webhookutil.DefaultRetryBackoffWithInitialDelay(500 * time.Millisecond)
+	delegate, err := authorizerfactory.DelegatingAuthorizerConfig{
+		SubjectAccessReviewClient: client,
+		AllowCacheTTL:             10 * time.Second,
+		DenyCacheTTL:              10 * time.Second,
+		WebhookRetryBackoff:       &backoff,
+	}.New()
+	if err != nil {
+		return nil, err
+	}
+	return &tenantImpersonationAuthorizer{delegate: delegate}, nil
+}
+
+// Authorize implements authorizer.Authorizer.
+func (a *tenantImpersonationAuthorizer) Authorize(ctx context.Context, attrs authorizer.Attributes) (authorizer.Decision, string, error) {
+	u := attrs.GetUser()
+	if u == nil || len(u.GetExtra()[util.TenantIDKey]) == 0 {
+		return authorizer.DecisionDeny, "impersonation is only supported inside the tenants", nil
+	}
+	tenantID := u.GetExtra()[util.TenantIDKey][0]
+	if attrs.GetVerb() != "impersonate" || attrs.GetAPIGroup() != "" && attrs.GetAPIGroup() != "authentication.k8s.io" {
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+
+	upstream := authorizer.AttributesRecord{
+		User:            u,
+		Verb:            attrs.GetVerb(),
+		APIGroup:        attrs.GetAPIGroup(),
+		APIVersion:      attrs.GetAPIVersion(),
+		Resource:        attrs.GetResource(),
+		Subresource:     attrs.GetSubresource(),
+		Name:            attrs.GetName(),
+		ResourceRequest: attrs.IsResourceRequest(),
+	}
+	switch attrs.GetResource() {
+	case "users":
+		upstream.Name = util.AddTenantIDToUserName(tenantID, attrs.GetName())
+	case "serviceaccounts":
+		upstream.Namespace = util.AddTenantIDPrefix(tenantID, attrs.GetNamespace())
+	case "groups":
+		group, ok := upstreamTenantGroup(tenantID, attrs.GetName())
+		if !ok {
+			return authorizer.DecisionDeny, fmt.Sprintf("group %q is not isolated between the tenants", attrs.GetName()), nil
+		}
+		upstream.Name = group
+	case "userextras":
+		switch attrs.GetSubresource() {
+		case util.TenantIDKey, util.TenantUIDKey, util.TenantGenerationKey:
+			return authorizer.DecisionDeny, fmt.Sprintf("user extra %q is reserved", attrs.GetSubresource()), nil
+		}
+	case "uids":
+	default:
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+	return a.delegate.Authorize(ctx, upstream)
+}
+
+// upstreamTenantGroup returns the upstream group the impersonation of the
+// group is authorized against, which carries the prefix of the tenant, and
+// false if the group is not isolated between the tenants. The groups of all
+// the users and of all the service accounts are mapped to the equivalents
+// prefixed with the tenant ID, and the namespaces of the service account
+// groups are prefixed.
+func upstreamTenantGroup(tenantID, group string) (string, bool) {
+	switch {
+	case group == user.AllAuthenticated || group == serviceaccount.AllServiceAccountsGroup:
+		return util.AddTenantIDPrefix(tenantID, group), true
+	case strings.HasPrefix(group, serviceaccount.ServiceAccountGroupPrefix):
+		if len(strings.TrimPrefix(group, serviceaccount.ServiceAccountGroupPrefix)) == 0 {
+			return "", false
+		}
+		return util.AddTenantIDToGroups(tenantID, []string{group})[0], true
+	case strings.HasPrefix(group, tenantID+util.TenantIDSeparator):
+		return group, true
+	}
+	return "", false
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+func TestImpersonationAuthorizer(t *testing.T) {
+	var (
+		lock    sync.Mutex
+		reviews []authorizationv1.SubjectAccessReviewSpec
+	)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		review := &authorizationv1.SubjectAccessReview{}
+		if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		lock.Lock()
+		reviews = append(reviews, review.Spec)
+		lock.Unlock()
+		// the admin of tenant foofoo may impersonate anything of the tenant
+		allowed := review.Spec.User == util.AddTenantIDPrefix("foofoo", util.TenantAdminUserName)
+		review.Status = authorizationv1.SubjectAccessReviewStatus{Allowed: allowed}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(review)
+	}))
+	defer server.Close()
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL, TLSClientConfig: rest.TLSClientConfig{Insecure: true}})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	a, err := NewImpersonationAuthorizer(client.AuthorizationV1())
+	if err != nil {
+		t.Fatalf("failed to create authorizer: %v", err)
+	}
+
+	admin := &user.DefaultInfo{
+		Name:  util.AddTenantIDPrefix("foofoo", util.TenantAdminUserName),
+		Extra: map[string][]string{util.TenantIDKey: {"foofoo"}},
+	}
+	bob := &user.DefaultInfo{
+		Name:  util.AddTenantIDPrefix("foofoo", "bob"),
+		Extra: map[string][]string{util.TenantIDKey: {"foofoo"}},
+	}
+	cases := []struct {
+		name      string
+		attrs     authorizer.AttributesRecord
+		decision  authorizer.Decision
+		namespace string
+		resName   string
+	}{
+		{
+			name:     "user",
+			attrs:    authorizer.AttributesRecord{User: admin, Verb: "impersonate", Resource: "users", Name: "alice", ResourceRequest: true},
+			decision: authorizer.DecisionAllow,
+			resName:  "foofoo-alice",
+		},
+		{
+			name:      "service account",
+			attrs:     authorizer.AttributesRecord{User: admin, Verb: "impersonate", Resource: "serviceaccounts", Namespace: "default", Name: "sa", ResourceRequest: true},
+			decision:  authorizer.DecisionAllow,
+			namespace: "foofoo-default",
+			resName:   "sa",
+		},
+		{
+			name:     "service account group",
+			attrs:    authorizer.AttributesRecord{User: admin, Verb: "impersonate", Resource: "groups", Name: "system:serviceaccounts:default", ResourceRequest: true},
+			decision: authorizer.DecisionAllow,
+			resName:  "system:serviceaccounts:foofoo-default",
+		},
+		{
+			name:     "all authenticated group",
+			attrs:    authorizer.AttributesRecord{User: admin, Verb: "impersonate", Resource: "groups", Name: user.AllAuthenticated, ResourceRequest: true},
+			decision: authorizer.DecisionAllow,
+			resName:  "foofoo-system:authenticated",
+		},
+		{
+			name:     "all service accounts group",
+			attrs:    authorizer.AttributesRecord{User: admin, Verb: "impersonate", Resource: "groups", Name: "system:serviceaccounts", ResourceRequest: true},
+			decision: authorizer.DecisionAllow,
+			resName:  "foofoo-system:serviceaccounts",
+		},
+		{
+			name:     "tenant group",
+			attrs:    authorizer.AttributesRecord{User: admin, Verb: "impersonate", Resource: "groups", Name: "foofoo-devs", ResourceRequest: true},
+			decision: authorizer.DecisionAllow,
+			resName:  "foofoo-devs",
+		},
+		{
+			name:     "group of other tenant",
+			attrs:    authorizer.AttributesRecord{User: admin, Verb: "impersonate", Resource: "groups", Name: "barbar-devs", ResourceRequest: true},
+			decision: authorizer.DecisionDeny,
+		},
+		{
+			name:     "service account group without namespace",
+			attrs:    authorizer.AttributesRecord{User: admin, Verb: "impersonate", Resource: "groups", Name: "system:serviceaccounts:", ResourceRequest: true},
+			decision: authorizer.DecisionDeny,
+		},
+		{
+			name:     "user extra",
+			attrs:    authorizer.AttributesRecord{User: admin, Verb: "impersonate", APIGroup: "authentication.k8s.io", Resource: "userextras", Subresource: "scopes", Name: "view", ResourceRequest: true},
+			decision: authorizer.DecisionAllow,
+			resName:  "view",
+		},
+		{
+			name:     "shared group",
+			attrs:    authorizer.AttributesRecord{User: admin, Verb: "impersonate", Resource: "groups", Name: user.SystemPrivilegedGroup, ResourceRequest: true},
+			decision: authorizer.DecisionDeny,
+		},
+		{
+			name:     "tenant extra",
+			attrs:    authorizer.AttributesRecord{User: admin, Verb: "impersonate", APIGroup: "authentication.k8s.io", Resource: "userextras", Subresource: util.TenantIDKey, Name: "barbar", ResourceRequest: true},
+			decision: authorizer.DecisionDeny,
+		},
+		{
+			name:     "tenant uid extra",
+			attrs:    authorizer.AttributesRecord{User: admin, Verb: "impersonate", APIGroup: "authentication.k8s.io", Resource: "userextras", Subresource: util.TenantUIDKey, Name: "uid-1", ResourceRequest: true},
+			decision: authorizer.DecisionDeny,
+		},
+		{
+			name:     "user out of tenants",
+			attrs:    authorizer.AttributesRecord{User: &user.DefaultInfo{Name: "bob"}, Verb: "impersonate", Resource: "users", Name: "alice", ResourceRequest: true},
+			decision: authorizer.DecisionDeny,
+		},
+		{
+			name:     "forbidden by rbac",
+			attrs:    authorizer.AttributesRecord{User: bob, Verb: "impersonate", Resource: "users", Name: "alice", ResourceRequest: true},
+			decision: authorizer.DecisionNoOpinion,
+			resName:  "foofoo-alice",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			lock.Lock()
+			reviews = nil
+			lock.Unlock()
+
+			decision, reason, err := a.Authorize(context.TODO(), c.attrs)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decision != c.decision {
+				t.Errorf("expect decision %v, got %v: %s", c.decision, decision, reason)
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			if len(c.resName) == 0 {
+				if len(reviews) != 0 {
+					t.Errorf("expect no review sent upstream, got %v", reviews)
+				}
+				return
+			}
+			if len(reviews) != 1 {
+				t.Fatalf("expect 1 review sent upstream, got %d", len(reviews))
+			}
+			if got := reviews[0].ResourceAttributes; got.Namespace != c.namespace || got.Name != c.resName {
+				t.Errorf("expect upstream %s/%s, got %s/%s", c.namespace, c.resName, got.Namespace, got.Name)
+			}
+		})
+	}
+}
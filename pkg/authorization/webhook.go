@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package authorization contains the authorizers which authorize the tenant
+// requests before they are forwarded to the upstream cluster.
+package authorization
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	webhookutil "k8s.io/apiserver/pkg/util/webhook"
+	"k8s.io/apiserver/plugin/pkg/authorizer/webhook"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// WebhookConfig is the configuration of the tenant authorization webhook.
+type WebhookConfig struct {
+	// ClientConfig is used to send the SubjectAccessReview to the webhook.
+	ClientConfig *rest.Config
+	// AuthorizedTTL is the duration to cache the authorized responses.
+	AuthorizedTTL time.Duration
+	// UnauthorizedTTL is the duration to cache the unauthorized responses.
+	UnauthorizedTTL time.Duration
+}
+
+// tenantWebhookAuthorizer authorizes the tenant requests by the
+// SubjectAccessReview webhook.
+type tenantWebhookAuthorizer struct {
+	delegate authorizer.Authorizer
+}
+
+var _ authorizer.Authorizer = &tenantWebhookAuthorizer{}
+
+// NewWebhookAuthorizer returns an authorizer which sends the tenant side
+// SubjectAccessReview to the webhook, i.e. the user, the namespace and the
+// resource are those seen inside the tenant, and the tenant ID is carried
+// in the "tenant" extra of the user. The responses are cached by the review,
+// hence per tenant. The requests not belonging to any tenant get no opinion.
+func NewWebhookAuthorizer(c WebhookConfig) (authorizer.Authorizer, error) {
+	if c.ClientConfig == nil {
+		return nil, errors.New("no webhook client config provided")
+	}
+	delegate, err := webhook.New(c.ClientConfig, "v1", c.AuthorizedTTL, c.UnauthorizedTTL,
+		webhookutil.DefaultRetryBackoffWithInitialDelay(500*time.Millisecond))
+	if err != nil {
+		return nil, err
+	}
+	return &tenantWebhookAuthorizer{delegate: delegate}, nil
+}
+
+// Authorize implements authorizer.Authorizer.
+func (a *tenantWebhookAuthorizer) Authorize(ctx context.Context, attrs authorizer.Attributes) (authorizer.Decision, string, error) {
+	u := attrs.GetUser()
+	if u == nil {
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+	tenantIDs := u.GetExtra()[util.TenantIDKey]
+	if len(tenantIDs) == 0 {
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+	return a.delegate.Authorize(ctx, tenantAttributes(tenantIDs[0], attrs))
+}
+
+// tenantAttributes returns the attributes with the user seen inside the tenant.
+func tenantAttributes(tenantID string, attrs authorizer.Attributes) authorizer.Attributes {
+	return authorizer.AttributesRecord{
+		User:            util.TrimTenantIDFromUserInfo(tenantID, attrs.GetUser()),
+		Verb:            attrs.GetVerb(),
+		Namespace:       attrs.GetNamespace(),
+		APIGroup:        attrs.GetAPIGroup(),
+		APIVersion:      attrs.GetAPIVersion(),
+		Resource:        attrs.GetResource(),
+		Subresource:     attrs.GetSubresource(),
+		Name:            attrs.GetName(),
+		ResourceRequest: attrs.IsResourceRequest(),
+		Path:            attrs.GetPath(),
+	}
+}
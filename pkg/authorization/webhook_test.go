@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+func TestWebhookAuthorizer(t *testing.T) {
+	var (
+		lock    sync.Mutex
+		reviews []authorizationv1.SubjectAccessReviewSpec
+	)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		review := &authorizationv1.SubjectAccessReview{}
+		if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		lock.Lock()
+		reviews = append(reviews, review.Spec)
+		lock.Unlock()
+		// only alice of tenant foofoo may get the pods in default
+		allowed := review.Spec.User == "alice" && review.Spec.Extra[util.TenantIDKey][0] == "foofoo" &&
+			review.Spec.ResourceAttributes != nil && review.Spec.ResourceAttributes.Namespace == "default"
+		review.Status = authorizationv1.SubjectAccessReviewStatus{Allowed: allowed, Denied: !allowed}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(review)
+	}))
+	defer server.Close()
+
+	a, err := NewWebhookAuthorizer(WebhookConfig{
+		ClientConfig:    &rest.Config{Host: server.URL, TLSClientConfig: rest.TLSClientConfig{Insecure: true}},
+		AuthorizedTTL:   time.Minute,
+		UnauthorizedTTL: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("failed to create authorizer: %v", err)
+	}
+
+	attrs := func(u user.Info) authorizer.Attributes {
+		return authorizer.AttributesRecord{
+			User:            u,
+			Verb:            "get",
+			Namespace:       "default",
+			Resource:        "pods",
+			Name:            "foo",
+			ResourceRequest: true,
+		}
+	}
+	tenantUser := func(tenantID, name string) user.Info {
+		return &user.DefaultInfo{
+			Name:  util.AddTenantIDPrefix(tenantID, name),
+			Extra: map[string][]string{util.TenantIDKey: {tenantID}},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		user   user.Info
+		expect authorizer.Decision
+	}{
+		{name: "allowed tenant user", user: tenantUser("foofoo", "alice"), expect: authorizer.DecisionAllow},
+		{name: "denied tenant user", user: tenantUser("foofoo", "bob"), expect: authorizer.DecisionDeny},
+		{name: "same user in another tenant", user: tenantUser("barbar", "alice"), expect: authorizer.DecisionDeny},
+		{name: "user without tenant", user: &user.DefaultInfo{Name: "alice"}, expect: authorizer.DecisionNoOpinion},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decision, _, err := a.Authorize(context.TODO(), attrs(tc.user))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decision != tc.expect {
+				t.Errorf("expect decision %v, got %v", tc.expect, decision)
+			}
+		})
+	}
+
+	// the responses are cached per tenant
+	if decision, _, _ := a.Authorize(context.TODO(), attrs(tenantUser("foofoo", "alice"))); decision != authorizer.DecisionAllow {
+		t.Errorf("expect cached decision allow, got %v", decision)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if len(reviews) != 3 {
+		t.Fatalf("expect 3 reviews sent to the webhook, got %d", len(reviews))
+	}
+	if reviews[0].User != "alice" || reviews[0].ResourceAttributes.Namespace != "default" {
+		t.Errorf("expect tenant side review, got %#v", reviews[0])
+	}
+}
@@ -18,6 +18,8 @@ package controllers
 
 import (
 	"context"
+	"sort"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -31,22 +33,40 @@ import (
 	quotautil "k8s.io/apiserver/pkg/quota/v1"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	quotav1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
 )
 
 var (
 	ClusterResourceQuotaKind = "ClusterResourceQuota"
 
 	LabelClusterResourceQuotaAutoUpdate = "clusterresourcequota.quota.kubezoo.io/autoupdate"
+
+	// DefaultReservationTTL is the default duration the usage reserved by
+	// the admission is counted, which covers the requests from the quota
+	// webhook to the resource quota admission of the upstream cluster.
+	DefaultReservationTTL = 10 * time.Second
+
+	// MaxReservations is the maximum number of the pending reservations of
+	// a cluster resource quota, beyond which the admission is throttled
+	// until they are observed or expire.
+	MaxReservations = 500
+
+	// DefaultDebouncePeriod is the default duration the changes of the usage
+	// are coalesced before the cluster resource quotas are reconciled.
+	DefaultDebouncePeriod = time.Second
 )
 
 // ClusterResourceQuotaReconciler reconciles a ClusterResourceQuota object
@@ -56,17 +76,44 @@ type ClusterResourceQuotaReconciler struct {
 	Cache     cache.Cache
 	APIReader client.Reader
 	Logger    logr.Logger
+	// ReservationTTL is how long the usage reserved by the admission is
+	// counted, DefaultReservationTTL is used if zero.
+	ReservationTTL time.Duration
+	// DebouncePeriod is how long the changes of the resource quotas and the
+	// namespaces are coalesced before the cluster resource quotas are
+	// reconciled, DefaultDebouncePeriod is used if zero.
+	DebouncePeriod time.Duration
+
+	staleness *stalenessTracker
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClusterResourceQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.staleness == nil {
+		r.staleness = newStalenessTracker()
+	}
+	debouncePeriod := r.DebouncePeriod
+	if debouncePeriod == 0 {
+		debouncePeriod = DefaultDebouncePeriod
+	}
 	err := ctrl.NewControllerManagedBy(mgr).
 		For(&quotav1alpha1.ClusterResourceQuota{}).
-		Owns(&corev1.ResourceQuota{}).
-		Watches(&source.Kind{Type: &corev1.Namespace{}}, &namesapceForClusterResourceQuotaHandler{
-			cache:  r.Cache,
-			logger: r.Logger,
-		}).
+		// the usage in the status of the resource quotas created by the
+		// cluster resource quotas is aggregated, and the allocations are
+		// shown in the status of the cluster resource quotas of their
+		// namespaces
+		Watches(&source.Kind{Type: &corev1.ResourceQuota{}}, &debouncedHandler{
+			mapFn:     r.clusterResourceQuotasForResourceQuota,
+			period:    debouncePeriod,
+			staleness: r.staleness,
+		}, builder.WithPredicates(resourceQuotaChangedPredicate)).
+		// the namespaces may be created, deleted or relabeled into or out
+		// of the cluster resource quotas
+		Watches(&source.Kind{Type: &corev1.Namespace{}}, &debouncedHandler{
+			mapFn:     r.clusterResourceQuotasForNamespace,
+			period:    debouncePeriod,
+			staleness: r.staleness,
+		}, builder.WithPredicates(predicate.LabelChangedPredicate{})).
 		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
 		Complete(r)
 	if err != nil {
@@ -74,8 +121,12 @@ func (r *ClusterResourceQuotaReconciler) SetupWithManager(mgr ctrl.Manager) erro
 	}
 
 	server := mgr.GetWebhookServer()
-	a := NewAdmission(context.TODO(), r.Client)
-	server.Register("/admission/validating/clusterresourcequotas", &admission.Webhook{Handler: a})
+	reservationTTL := r.ReservationTTL
+	if reservationTTL == 0 {
+		reservationTTL = DefaultReservationTTL
+	}
+	a := NewAdmission(context.TODO(), r.Client, r.APIReader, reservationTTL)
+	server.Register(WebhookPath, &admission.Webhook{Handler: a})
 	return nil
 }
 
@@ -93,9 +144,13 @@ func (r *ClusterResourceQuotaReconciler) SetupWithManager(mgr ctrl.Manager) erro
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.13.0/pkg/reconcile
 func (r *ClusterResourceQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	start := time.Now()
 	var clusterquota quotav1alpha1.ClusterResourceQuota
 	err := r.Client.Get(ctx, req.NamespacedName, &clusterquota)
 	if err != nil {
+		if errors.IsNotFound(err) {
+			r.staleness.forget(req.Name)
+		}
 		return reconcile.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -128,44 +183,113 @@ func (r *ClusterResourceQuotaReconciler) Reconcile(ctx context.Context, req ctrl
 		}
 	}
 
-	if err := r.syncStatus(ctx, &clusterquota, expectedQuotas); err != nil {
+	allocations, err := GetQuotaAllocationsForClusterQuota(ctx, r.Client, &clusterquota, namespaces)
+	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{}, nil
-}
+	clusterObjectUsage, err := r.countClusterObjects(ctx, &clusterquota)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 
-func (r *ClusterResourceQuotaReconciler) syncStatus(ctx context.Context, clusterquota *quotav1alpha1.ClusterResourceQuota, quotas []*corev1.ResourceQuota) error {
-	statusLimitsDirty := !apiequality.Semantic.DeepEqual(clusterquota.Spec.Hard, clusterquota.Status.Hard)
-	dirty := statusLimitsDirty || clusterquota.Status.Hard == nil || clusterquota.Status.Used == nil
+	requeueAfter, err := r.syncStatus(ctx, &clusterquota, expectedQuotas, allocations, clusterObjectUsage)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(clusterObjectUsage) > 0 && (requeueAfter == 0 || requeueAfter > ClusterObjectResyncPeriod) {
+		requeueAfter = ClusterObjectResyncPeriod
+	}
+	r.staleness.synced(clusterquota.Name, start, time.Now())
 
-	hardLimits := quotautil.Add(corev1.ResourceList{}, clusterquota.Spec.Hard)
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
 
-	newUsage := corev1.ResourceList{}
+// syncStatus reconciles the usage in the status of the cluster resource
+// quota, which is the usage observed in the namespaces plus the reservations
+// of the admission which are not expired, and the allocations in the
+// namespaces. The usage of the cluster scoped objects is the one counted
+// by the controller instead. It returns the duration after which the
+// earliest reservation expires.
+func (r *ClusterResourceQuotaReconciler) syncStatus(ctx context.Context, clusterquota *quotav1alpha1.ClusterResourceQuota, quotas, allocations []*corev1.ResourceQuota, clusterObjectUsage corev1.ResourceList) (time.Duration, error) {
+	observedUsage := corev1.ResourceList{}
 	for _, quota := range quotas {
-		newUsage = quotautil.Add(newUsage, quota.Status.Used)
+		observedUsage = quotautil.Add(observedUsage, quota.Status.Used)
+	}
+	for name, quantity := range clusterObjectUsage {
+		observedUsage[name] = quantity
+	}
+
+	var (
+		requeueAfter time.Duration
+		from         quotav1alpha1.ClusterResourceQuotaStatus
+	)
+	// the status is computed on every try, as the reservations may be added
+	// by the admission in the meantime
+	result, err := UpdateOnConflict(ctx, DefaultRetry, r.APIReader, r.Client.Status(), clusterquota, func() error {
+		from = *clusterquota.Status.DeepCopy()
+		clusterquota.Status, requeueAfter = newClusterResourceQuotaStatus(clusterquota, observedUsage, allocations, time.Now())
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
+	if result == controllerutil.OperationResultUpdated {
+		r.Logger.Info("sync status of cluster resource quota", "clusterResourceQuota", clusterquota.Name, "from", from, "to", clusterquota.Status)
+	}
+	return requeueAfter, nil
+}
 
-	usage := quotav1alpha1.ClusterResourceQuotaStatus{
+// newClusterResourceQuotaStatus returns the status of the cluster resource
+// quota with the observed usage, the allocations and the reservations which
+// are neither expired at now nor covered by the observed usage, and the
+// duration after which the earliest of them expires.
+func newClusterResourceQuotaStatus(clusterquota *quotav1alpha1.ClusterResourceQuota, observedUsage corev1.ResourceList, allocations []*corev1.ResourceQuota, now time.Time) (quotav1alpha1.ClusterResourceQuotaStatus, time.Duration) {
+	status := quotav1alpha1.ClusterResourceQuotaStatus{
 		ResourceQuotaStatus: corev1.ResourceQuotaStatus{
-			Hard: hardLimits,
-			Used: newUsage,
+			Hard: quotautil.Add(corev1.ResourceList{}, clusterquota.Spec.Hard),
+			Used: quotautil.Add(corev1.ResourceList{}, observedUsage),
 		},
 	}
-
-	dirty = dirty || !quotautil.Equals(clusterquota.Status.Used, usage.Used)
-
-	if dirty {
-		r.Logger.Info("sync status of cluster resource quota", "clusterResourceQuota", clusterquota.Name, "from", clusterquota.Status, "to", usage)
-		_, err := UpdateOnConflict(ctx, DefaultRetry, r.APIReader, r.Client.Status(), clusterquota, func() error {
-			clusterquota.Status = usage
-			return nil
+	for _, allocation := range allocations {
+		status.Allocations = append(status.Allocations, quotav1alpha1.ClusterResourceQuotaAllocation{
+			Namespace: allocation.Namespace,
+			Name:      allocation.Name,
+			Hard:      quotautil.Add(corev1.ResourceList{}, allocation.Spec.Hard),
 		})
-		if err != nil {
-			return err
+		status.Allocated = quotautil.Add(status.Allocated, allocation.Spec.Hard)
+	}
+	var requeueAfter time.Duration
+	for _, reservation := range clusterquota.Status.Reservations {
+		remaining := reservation.ExpirationTime.Sub(now)
+		if remaining <= 0 || reservationObserved(reservation, observedUsage) {
+			continue
+		}
+		status.Used = quotautil.Add(status.Used, reservation.Resources)
+		status.Reservations = append(status.Reservations, reservation)
+		if requeueAfter == 0 || remaining < requeueAfter {
+			requeueAfter = remaining
 		}
 	}
-	return nil
+	return status, requeueAfter
+}
+
+// reservationObserved returns whether the observed usage reaches the
+// baseline plus the resources of the reservation, i.e. the usage reserved is
+// already counted in the observed usage.
+func reservationObserved(reservation quotav1alpha1.ClusterResourceQuotaReservation, observedUsage corev1.ResourceList) bool {
+	if len(reservation.Baseline) == 0 {
+		return false
+	}
+	for resourceName, reserved := range reservation.Resources {
+		expected := reservation.Baseline[resourceName].DeepCopy()
+		expected.Add(reserved)
+		observed := observedUsage[resourceName]
+		if observed.Cmp(expected) < 0 {
+			return false
+		}
+	}
+	return true
 }
 
 func (r *ClusterResourceQuotaReconciler) ensureResourceQuotaInNamespace(ctx context.Context, clusterquota *quotav1alpha1.ClusterResourceQuota, namespace string, quotas []*corev1.ResourceQuota) (*corev1.ResourceQuota, error) {
@@ -264,6 +388,32 @@ func GetResourceQuotaForClusterQuota(ctx context.Context, c client.Client, clust
 	return namespaces, namesapceToQuota, err
 }
 
+// GetQuotaAllocationsForClusterQuota returns the resource quotas created in
+// the namespaces of the cluster resource quota with the same scopes, which
+// allocate its hard limits to the namespaces, sorted by namespace and name.
+func GetQuotaAllocationsForClusterQuota(ctx context.Context, c client.Client, clusterquota *quotav1alpha1.ClusterResourceQuota, namespaces sets.String) ([]*corev1.ResourceQuota, error) {
+	allocations := []*corev1.ResourceQuota{}
+	for _, ns := range namespaces.List() {
+		var quotaList corev1.ResourceQuotaList
+		if err := c.List(ctx, &quotaList, &client.ListOptions{Namespace: ns}); err != nil {
+			return nil, err
+		}
+		for i := range quotaList.Items {
+			quota := &quotaList.Items[i]
+			if util.IsQuotaAllocation(quota) && util.QuotaScopesMatch(&quota.Spec, &clusterquota.Spec.ResourceQuotaSpec) {
+				allocations = append(allocations, quota)
+			}
+		}
+	}
+	sort.SliceStable(allocations, func(i, j int) bool {
+		if allocations[i].Namespace != allocations[j].Namespace {
+			return allocations[i].Namespace < allocations[j].Namespace
+		}
+		return allocations[i].Name < allocations[j].Name
+	})
+	return allocations, nil
+}
+
 func GetNamespacesForClusterQuota(ctx context.Context, c client.Client, clusterquota *quotav1alpha1.ClusterResourceQuota) (sets.String, error) {
 	namespaces := sets.NewString()
 	if clusterquota.Spec.NamepsaceSelector != nil {
@@ -296,47 +446,121 @@ func GetNamespacesForClusterQuota(ctx context.Context, c client.Client, clusterq
 	return namespaces, nil
 }
 
-var _ handler.EventHandler = &namesapceForClusterResourceQuotaHandler{}
-
-type namesapceForClusterResourceQuotaHandler struct {
-	cache  cache.Cache
-	logger logr.Logger
+// clusterResourceQuotasForResourceQuota maps the resource quota to the
+// cluster resource quota which creates it, whose usage is aggregated from
+// its status, or to the cluster resource quotas of its namespace if it is an
+// allocation.
+func (r *ClusterResourceQuotaReconciler) clusterResourceQuotasForResourceQuota(obj client.Object) []reconcile.Request {
+	quota, ok := obj.(*corev1.ResourceQuota)
+	if !ok {
+		return nil
+	}
+	if name := quota.Labels[quotav1alpha1.ClusterResourceQuotaCreatedby]; name != "" {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+	}
+	if !util.IsQuotaAllocation(quota) {
+		return nil
+	}
+	var ns corev1.Namespace
+	if err := r.Cache.Get(context.TODO(), types.NamespacedName{Name: quota.Namespace}, &ns); err != nil {
+		if !errors.IsNotFound(err) {
+			r.Logger.Error(err, "failed to get namespace of resource quota", "resourceQuota", client.ObjectKeyFromObject(quota).String())
+		}
+		return nil
+	}
+	return r.clusterResourceQuotasForNamespace(&ns)
 }
 
-func (h *namesapceForClusterResourceQuotaHandler) Create(e event.CreateEvent, q workqueue.RateLimitingInterface) {
-	ns, ok := e.Object.(*corev1.Namespace)
+// clusterResourceQuotasForNamespace maps the namespace to the cluster
+// resource quotas it matches.
+func (r *ClusterResourceQuotaReconciler) clusterResourceQuotasForNamespace(obj client.Object) []reconcile.Request {
+	ns, ok := obj.(*corev1.Namespace)
 	if !ok {
-		return
+		return nil
 	}
-
 	var quotaList quotav1alpha1.ClusterResourceQuotaList
-	err := h.cache.List(context.TODO(), &quotaList)
-	if err != nil {
-		h.logger.Error(err, "failed to list cluster resource quota")
-		return
+	if err := r.Cache.List(context.TODO(), &quotaList); err != nil {
+		r.Logger.Error(err, "failed to list cluster resource quota")
+		return nil
 	}
-
+	var requests []reconcile.Request
 	for i := range quotaList.Items {
-		quota := quotaList.Items[i]
-
-		if matches(&quota, ns) {
-			// enqueue quota
-			q.Add(reconcile.Request{
-				NamespacedName: types.NamespacedName{
-					Name: quota.Name,
-				},
+		if matches(&quotaList.Items[i], ns) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: quotaList.Items[i].Name},
 			})
 		}
 	}
+	return requests
+}
+
+// resourceQuotaChangedPredicate filters the updates of the resource quotas
+// which change neither the usage nor the mapping to the cluster resource
+// quotas, e.g. the periodic resyncs.
+var resourceQuotaChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldQuota, ok := e.ObjectOld.(*corev1.ResourceQuota)
+		if !ok {
+			return true
+		}
+		newQuota, ok := e.ObjectNew.(*corev1.ResourceQuota)
+		if !ok {
+			return true
+		}
+		return !apiequality.Semantic.DeepEqual(oldQuota.Spec, newQuota.Spec) ||
+			!apiequality.Semantic.DeepEqual(oldQuota.Status, newQuota.Status) ||
+			!apiequality.Semantic.DeepEqual(oldQuota.Labels, newQuota.Labels) ||
+			!apiequality.Semantic.DeepEqual(oldQuota.OwnerReferences, newQuota.OwnerReferences)
+	},
+}
+
+var _ handler.EventHandler = &debouncedHandler{}
+
+// debouncedHandler enqueues the cluster resource quotas mapped from the
+// objects after the debounce period, so that the bursts of the events, e.g.
+// the status updates of the resource quotas across the namespaces, are
+// coalesced into a single reconcile of each cluster resource quota. The
+// updates are mapped from both the old and the new objects, e.g. for a
+// namespace relabeled out of a cluster resource quota.
+type debouncedHandler struct {
+	mapFn     handler.MapFunc
+	period    time.Duration
+	staleness *stalenessTracker
 }
 
-func (h *namesapceForClusterResourceQuotaHandler) Update(event.UpdateEvent, workqueue.RateLimitingInterface) {
+func (h *debouncedHandler) Create(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(q, e.Object)
 }
 
-func (h *namesapceForClusterResourceQuotaHandler) Delete(event.DeleteEvent, workqueue.RateLimitingInterface) {
+func (h *debouncedHandler) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(q, e.ObjectOld, e.ObjectNew)
 }
 
-func (h *namesapceForClusterResourceQuotaHandler) Generic(event.GenericEvent, workqueue.RateLimitingInterface) {
+func (h *debouncedHandler) Delete(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(q, e.Object)
+}
+
+func (h *debouncedHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(q, e.Object)
+}
+
+func (h *debouncedHandler) enqueue(q workqueue.RateLimitingInterface, objs ...client.Object) {
+	now := time.Now()
+	requests := map[reconcile.Request]struct{}{}
+	for _, obj := range objs {
+		if obj == nil {
+			continue
+		}
+		for _, req := range h.mapFn(obj) {
+			requests[req] = struct{}{}
+		}
+	}
+	for req := range requests {
+		h.staleness.markStale(req.Name, now)
+		// the delaying queue keeps the earliest time of the same request,
+		// the later events within the period are merged into it
+		q.AddAfter(req, h.period)
+	}
 }
 
 func matches(quota *quotav1alpha1.ClusterResourceQuota, ns *corev1.Namespace) bool {
@@ -0,0 +1,248 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	quotautil "k8s.io/apiserver/pkg/quota/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	quotav1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/common"
+)
+
+// TestNewClusterResourceQuotaStatus tests the reservations are counted in
+// the used until they expire or are covered by the observed usage, and the
+// allocations are summed up.
+func TestNewClusterResourceQuotaStatus(t *testing.T) {
+	now := time.Now()
+	clusterquota := &quotav1alpha1.ClusterResourceQuota{
+		Spec: quotav1alpha1.ClusterResourceQuotaSpec{
+			ResourceQuotaSpec: corev1.ResourceQuotaSpec{
+				Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+			},
+		},
+		Status: quotav1alpha1.ClusterResourceQuotaStatus{
+			Reservations: []quotav1alpha1.ClusterResourceQuotaReservation{
+				{
+					Namespace:      "111111-default",
+					Resources:      corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")},
+					ExpirationTime: metav1.NewTime(now.Add(-time.Second)),
+				},
+				{
+					Namespace:      "111111-default",
+					Resources:      corev1.ResourceList{corev1.ResourcePods: resource.MustParse("2")},
+					ExpirationTime: metav1.NewTime(now.Add(5 * time.Second)),
+					Baseline:       corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")},
+				},
+				{
+					Namespace:      "111111-other",
+					Resources:      corev1.ResourceList{corev1.ResourcePods: resource.MustParse("3")},
+					ExpirationTime: metav1.NewTime(now.Add(3 * time.Second)),
+					Baseline:       corev1.ResourceList{corev1.ResourcePods: resource.MustParse("3")},
+				},
+			},
+		},
+	}
+	observed := corev1.ResourceList{corev1.ResourcePods: resource.MustParse("4")}
+	allocations := []*corev1.ResourceQuota{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "111111-default", Name: "budget"},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("4")},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "111111-other", Name: "budget"},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("5")},
+			},
+		},
+	}
+
+	status, requeueAfter := newClusterResourceQuotaStatus(clusterquota, observed, allocations, now)
+	if expect := (corev1.ResourceList{corev1.ResourcePods: resource.MustParse("7")}); !quotautil.Equals(status.Used, expect) {
+		t.Errorf("expect used %v, got %v", expect, status.Used)
+	}
+	if !quotautil.Equals(status.Hard, clusterquota.Spec.Hard) {
+		t.Errorf("expect hard %v, got %v", clusterquota.Spec.Hard, status.Hard)
+	}
+	if len(status.Reservations) != 1 || status.Reservations[0].Namespace != "111111-other" {
+		t.Errorf("expect the expired and the observed reservations dropped, got %v", status.Reservations)
+	}
+	if len(status.Allocations) != 2 || status.Allocations[1].Namespace != "111111-other" {
+		t.Errorf("expect the allocations of both namespaces, got %v", status.Allocations)
+	}
+	if expect := (corev1.ResourceList{corev1.ResourcePods: resource.MustParse("9")}); !quotautil.Equals(status.Allocated, expect) {
+		t.Errorf("expect allocated %v, got %v", expect, status.Allocated)
+	}
+	if requeueAfter != 3*time.Second {
+		t.Errorf("expect requeue after 3s, got %v", requeueAfter)
+	}
+}
+
+// TestCountClusterObjects tests the cluster scoped objects are counted for
+// the tenant of the cluster resource quota by their names.
+func TestCountClusterObjects(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("PersistentVolume"), meta.RESTScopeRoot)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "111111-pv-1"}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "111111-pv-2"}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "222222-pv-1"}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-1"}},
+	).Build()
+	r := &ClusterResourceQuotaReconciler{Client: c, APIReader: c}
+
+	clusterquota := &quotav1alpha1.ClusterResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "kubezoo-tenant-quota-111111",
+			Labels: map[string]string{common.TenantNamespaceLabelKey: "111111"},
+		},
+		Spec: quotav1alpha1.ClusterResourceQuotaSpec{
+			ResourceQuotaSpec: corev1.ResourceQuotaSpec{
+				Hard: corev1.ResourceList{
+					"count/persistentvolumes": resource.MustParse("10"),
+					"count/configmaps":        resource.MustParse("10"),
+					"count/foos.unknown.io":   resource.MustParse("10"),
+					corev1.ResourcePods:       resource.MustParse("10"),
+				},
+			},
+		},
+	}
+	usage, err := r.countClusterObjects(context.TODO(), clusterquota)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := (corev1.ResourceList{"count/persistentvolumes": resource.MustParse("2")}); !quotautil.Equals(usage, expect) {
+		t.Errorf("expect usage %v, got %v", expect, usage)
+	}
+}
+
+// TestTenantOfClusterObject tests the tenant of the cluster scoped objects
+// is taken from the name, or the group of the custom resource definitions.
+func TestTenantOfClusterObject(t *testing.T) {
+	tests := []struct {
+		gr     schema.GroupResource
+		name   string
+		expect string
+	}{
+		{gr: schema.GroupResource{Resource: "persistentvolumes"}, name: "111111-pv", expect: "111111"},
+		{gr: schema.GroupResource{Resource: "persistentvolumes"}, name: "pv-1", expect: ""},
+		{gr: customResourceDefinitionResource, name: "foos.111111-a.com", expect: "111111"},
+		{gr: customResourceDefinitionResource, name: "111111-foos.a.com", expect: ""},
+		{gr: namespaceResource, name: "111111-default", expect: "111111"},
+	}
+	for _, test := range tests {
+		if got := tenantOfClusterObject(test.gr, test.name); got != test.expect {
+			t.Errorf("expect tenant %q of %s %s, got %q", test.expect, test.gr, test.name, got)
+		}
+	}
+}
+
+// TestDebouncedHandler tests the cluster resource quotas mapped from both
+// the old and the new objects are enqueued after the debounce period, and
+// the bursts of the events are coalesced.
+func TestDebouncedHandler(t *testing.T) {
+	h := &debouncedHandler{
+		mapFn: func(obj client.Object) []reconcile.Request {
+			if name := obj.GetLabels()["quota"]; name != "" {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+			}
+			return nil
+		},
+		period:    50 * time.Millisecond,
+		staleness: newStalenessTracker(),
+	}
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+
+	oldNs := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "111111-default", Labels: map[string]string{"quota": "a"}}}
+	newNs := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "111111-default", Labels: map[string]string{"quota": "b"}}}
+	for i := 0; i < 10; i++ {
+		h.Update(event.UpdateEvent{ObjectOld: oldNs, ObjectNew: newNs}, q)
+	}
+	h.Delete(event.DeleteEvent{Object: newNs}, q)
+	if q.Len() != 0 {
+		t.Fatalf("expect no request before the debounce period, got %d", q.Len())
+	}
+	if len(h.staleness.since) != 2 {
+		t.Errorf("expect 2 stale cluster resource quotas, got %d", len(h.staleness.since))
+	}
+
+	err := wait.PollImmediate(10*time.Millisecond, time.Second, func() (bool, error) {
+		return q.Len() == 2, nil
+	})
+	if err != nil {
+		t.Fatalf("expect 2 requests after the debounce period, got %d", q.Len())
+	}
+	got := sets.NewString()
+	for i := 0; i < 2; i++ {
+		item, _ := q.Get()
+		got.Insert(item.(reconcile.Request).Name)
+		q.Done(item)
+	}
+	if !got.Equal(sets.NewString("a", "b")) {
+		t.Errorf("expect requests of a and b, got %v", got.List())
+	}
+}
+
+// TestStalenessTracker tests the staleness is observed by the reconcile
+// started after the status became stale.
+func TestStalenessTracker(t *testing.T) {
+	tracker := newStalenessTracker()
+	now := time.Now()
+	tracker.markStale("a", now)
+	tracker.markStale("a", now.Add(time.Second))
+
+	if staleness := tracker.synced("a", now.Add(-time.Second), now.Add(time.Second)); staleness != 0 {
+		t.Errorf("expect the status to stay stale, got staleness %v", staleness)
+	}
+	if staleness := tracker.synced("a", now.Add(time.Second), now.Add(2*time.Second)); staleness != 2*time.Second {
+		t.Errorf("expect staleness 2s, got %v", staleness)
+	}
+	if staleness := tracker.synced("a", now.Add(3*time.Second), now.Add(4*time.Second)); staleness != 0 {
+		t.Errorf("expect no staleness after synced, got %v", staleness)
+	}
+	tracker.markStale("b", now)
+	tracker.forget("b")
+	if len(tracker.since) != 0 {
+		t.Errorf("expect no stale cluster resource quota, got %v", tracker.since)
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	quotav1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/common"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// objectCountQuotaPrefix prefixes the object count quota of the resources,
+// i.e. count/<resource>.<group>.
+const objectCountQuotaPrefix = "count/"
+
+var (
+	// ClusterObjectResyncPeriod is how often the cluster scoped objects of
+	// the tenants are counted again, whose changes do not trigger the
+	// controller, so that the deleted ones are released from the usage.
+	ClusterObjectResyncPeriod = time.Minute
+
+	customResourceDefinitionResource = schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}
+	namespaceResource                = schema.GroupResource{Resource: "namespaces"}
+)
+
+// objectCountResource returns the resource of the object count quota, i.e.
+// count/<resource>.<group>, or false for the other quotas.
+func objectCountResource(name corev1.ResourceName) (schema.GroupResource, bool) {
+	if !strings.HasPrefix(string(name), objectCountQuotaPrefix) {
+		return schema.GroupResource{}, false
+	}
+	return schema.ParseGroupResource(strings.TrimPrefix(string(name), objectCountQuotaPrefix)), true
+}
+
+// objectCountResourceName returns the name of the object count quota of the
+// resource, i.e. count/<resource>.<group>.
+func objectCountResourceName(gr schema.GroupResource) corev1.ResourceName {
+	return corev1.ResourceName(objectCountQuotaPrefix + gr.String())
+}
+
+// tenantOfClusterObject returns the tenant of the upstream cluster scoped
+// object, or empty if it does not belong to any tenant. The names of the
+// objects are prefixed with the tenant ID, except the custom resource
+// definitions, whose groups are prefixed instead.
+func tenantOfClusterObject(gr schema.GroupResource, name string) string {
+	if gr == customResourceDefinitionResource {
+		parts := strings.SplitN(name, ".", 2)
+		if len(parts) < 2 {
+			return ""
+		}
+		name = parts[1]
+	}
+	tenantID, err := util.GetTenantIDFromNamespace(name)
+	if err != nil {
+		return ""
+	}
+	return tenantID
+}
+
+// isScoped returns true if the cluster resource quota only tracks the
+// objects matched by its scopes, which does not count the cluster scoped
+// objects.
+func isScoped(clusterquota *quotav1alpha1.ClusterResourceQuota) bool {
+	return len(clusterquota.Spec.Scopes) > 0 || clusterquota.Spec.ScopeSelector != nil
+}
+
+// countClusterObjects counts the cluster scoped objects of the tenant of the
+// cluster resource quota, e.g. persistentvolumes, customresourcedefinitions
+// and namespaces, for each object count quota of the cluster scoped
+// resources, which are not tracked by the resource quotas in the namespaces.
+// The objects are listed from the cache of the manager, which watches only
+// their metadata.
+func (r *ClusterResourceQuotaReconciler) countClusterObjects(ctx context.Context, clusterquota *quotav1alpha1.ClusterResourceQuota) (corev1.ResourceList, error) {
+	tenantID := clusterquota.Labels[common.TenantNamespaceLabelKey]
+	mapper := r.Client.RESTMapper()
+	if tenantID == "" || mapper == nil || isScoped(clusterquota) {
+		return nil, nil
+	}
+	usage := corev1.ResourceList{}
+	for name := range clusterquota.Spec.Hard {
+		gr, ok := objectCountResource(name)
+		if !ok {
+			continue
+		}
+		gvk, err := mapper.KindFor(gr.WithVersion(""))
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, err
+		}
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, err
+		}
+		if mapping.Scope.Name() != meta.RESTScopeNameRoot {
+			continue
+		}
+
+		var objList metav1.PartialObjectMetadataList
+		objList.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := r.Client.List(ctx, &objList); err != nil {
+			return nil, err
+		}
+		var count int64
+		for _, obj := range objList.Items {
+			if tenantOfClusterObject(gr, obj.Name) == tenantID {
+				count++
+			}
+		}
+		usage[name] = *resource.NewQuantity(count, resource.DecimalSI)
+	}
+	return usage, nil
+}
+
+// listTenantClusterQuotas lists the cluster resource quotas of the tenant.
+func listTenantClusterQuotas(ctx context.Context, c client.Client, tenantID string) ([]quotav1alpha1.ClusterResourceQuota, error) {
+	var clusterquotaList quotav1alpha1.ClusterResourceQuotaList
+	if err := c.List(ctx, &clusterquotaList, client.MatchingLabels{common.TenantNamespaceLabelKey: tenantID}); err != nil {
+		return nil, err
+	}
+	return clusterquotaList.Items, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// statusStaleness observes how long the status of the cluster resource
+	// quotas lags behind the changes of the usage, i.e. from the first
+	// event changing the usage to the reconcile which syncs the status.
+	statusStaleness = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "kubezoo_clusterresourcequota_status_staleness_seconds",
+		Help:    "Duration from the first change of the usage to the sync of the status of the cluster resource quotas.",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300},
+	})
+	// statusLastSync is the time the status of each cluster resource quota
+	// is synced last, the age of which alerts on the stuck quotas.
+	statusLastSync = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kubezoo_clusterresourcequota_status_last_sync_timestamp_seconds",
+		Help: "Unix time the status of the cluster resource quota is synced last.",
+	}, []string{"clusterresourcequota"})
+	// pendingStatusSyncs is the number of cluster resource quotas with the
+	// changes of the usage not synced to the status yet.
+	pendingStatusSyncs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kubezoo_clusterresourcequota_status_pending_syncs",
+		Help: "Number of cluster resource quotas whose status is not synced with the changes of the usage yet.",
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(statusStaleness, statusLastSync, pendingStatusSyncs)
+}
+
+// stalenessTracker tracks the cluster resource quotas whose status is stale,
+// i.e. since when the usage is changed without being synced to the status.
+// The methods are no-op on nil, e.g. the reconciler which is not set up with
+// the manager in the tests.
+type stalenessTracker struct {
+	lock  sync.Mutex
+	since map[string]time.Time
+}
+
+func newStalenessTracker() *stalenessTracker {
+	return &stalenessTracker{since: map[string]time.Time{}}
+}
+
+// markStale records the status of the cluster resource quota becomes stale
+// at now, unless it is stale already.
+func (t *stalenessTracker) markStale(name string, now time.Time) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if _, ok := t.since[name]; ok {
+		return
+	}
+	t.since[name] = now
+	pendingStatusSyncs.Set(float64(len(t.since)))
+}
+
+// synced records the status of the cluster resource quota is synced at now
+// by the reconcile started at start, and returns how long it has been stale.
+// The status stays stale if it became stale after the reconcile started.
+func (t *stalenessTracker) synced(name string, start, now time.Time) time.Duration {
+	if t == nil {
+		return 0
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	statusLastSync.WithLabelValues(name).Set(float64(now.Unix()))
+	since, ok := t.since[name]
+	if !ok || since.After(start) {
+		return 0
+	}
+	delete(t.since, name)
+	pendingStatusSyncs.Set(float64(len(t.since)))
+	staleness := now.Sub(since)
+	statusStaleness.Observe(staleness.Seconds())
+	return staleness
+}
+
+// forget drops the cluster resource quota which is deleted.
+func (t *stalenessTracker) forget(name string) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.since, name)
+	pendingStatusSyncs.Set(float64(len(t.since)))
+	statusLastSync.DeleteLabelValues(name)
+}
@@ -0,0 +1,514 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubewharf/kubezoo/pkg/common"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+const (
+	// WebhookPath is the path the admission webhook is served at.
+	WebhookPath = "/admission/validating/clusterresourcequotas"
+
+	// DefaultWebhookConfigurationName is the default name of the validating
+	// webhook configuration registered by the webhook itself.
+	DefaultWebhookConfigurationName = "clusterresourcequota.kubezoo.io"
+
+	// DefaultWebhookCertValidity is the default validity of the serving
+	// certificate of the webhook.
+	DefaultWebhookCertValidity = 365 * 24 * time.Hour
+
+	// DefaultWebhookResyncPeriod is the default period the serving
+	// certificate and the webhook configuration are reconciled.
+	DefaultWebhookResyncPeriod = 10 * time.Minute
+
+	// the keys of the certificate authority in the webhook secret, the
+	// certificate of the certificate authority may be followed by the
+	// former ones, which are kept in the ca bundle until they expire.
+	caCertKey = "ca.crt"
+	caKeyKey  = "ca.key"
+)
+
+// WebhookRegistration manages the serving certificate of the quota webhook
+// and registers the webhook in the validating webhook configuration, so that
+// they are not crafted by hand. The certificate authority and the serving
+// certificate are kept in a secret shared by the replicas, which is used to
+// fill the caBundle of the webhooks.
+type WebhookRegistration struct {
+	// Client reads and writes the secret and the webhook configuration.
+	Client client.Client
+	Logger logr.Logger
+
+	// Namespace and ServiceName are the namespace and name of the service
+	// of the webhook, the secret is created in the same namespace.
+	Namespace   string
+	ServiceName string
+	// Port is the port of the service of the webhook.
+	Port int32
+	// SecretName is the name of the secret of the certificates.
+	SecretName string
+	// CertDir is the directory the serving certificate and key are written
+	// to, which are reloaded by the webhook server on changes.
+	CertDir string
+	// ConfigurationName is the name of the validating webhook configuration,
+	// DefaultWebhookConfigurationName is used if empty.
+	ConfigurationName string
+	// CertValidity is the validity of the serving certificate, it is rotated
+	// in the last fifth of the validity. DefaultWebhookCertValidity is used
+	// if zero.
+	CertValidity time.Duration
+	// ResyncPeriod is the period the certificate and the webhook
+	// configuration are reconciled, DefaultWebhookResyncPeriod is used if
+	// zero.
+	ResyncPeriod time.Duration
+}
+
+// Start reconciles the serving certificate and the webhook configuration
+// periodically until the context is done.
+func (w *WebhookRegistration) Start(ctx context.Context) error {
+	period := w.ResyncPeriod
+	if period == 0 {
+		period = DefaultWebhookResyncPeriod
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := w.Sync(ctx); err != nil {
+				w.Logger.Error(err, "unable to sync webhook registration")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false since every replica serves the webhook
+// and needs the serving certificate.
+func (w *WebhookRegistration) NeedLeaderElection() bool {
+	return false
+}
+
+// Sync ensures the certificates in the secret, writes the serving certificate
+// and key to the cert dir and registers the webhooks with the ca bundle.
+func (w *WebhookRegistration) Sync(ctx context.Context) error {
+	var secret *corev1.Secret
+	err := retry.OnError(DefaultRetry, func(err error) bool {
+		return errors.IsConflict(err) || errors.IsAlreadyExists(err)
+	}, func() error {
+		var err error
+		secret, err = w.syncSecret(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("unable to sync webhook secret: %v", err)
+	}
+	if err := w.writeCerts(secret); err != nil {
+		return fmt.Errorf("unable to write serving certificate: %v", err)
+	}
+	err = retry.RetryOnConflict(DefaultRetry, func() error {
+		return w.syncConfiguration(ctx, secret.Data[caCertKey])
+	})
+	if err != nil {
+		return fmt.Errorf("unable to sync webhook configuration: %v", err)
+	}
+	return nil
+}
+
+// syncSecret creates or updates the secret of the certificates, the
+// certificate authority and the serving certificate are regenerated if they
+// are missing, invalid or about to expire.
+func (w *WebhookRegistration) syncSecret(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := w.Client.Get(ctx, client.ObjectKey{Namespace: w.Namespace, Name: w.SecretName}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	exists := err == nil
+	if !exists {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: w.Namespace,
+				Name:      w.SecretName,
+			},
+			Type: corev1.SecretTypeTLS,
+		}
+	}
+
+	data, changed, err := w.ensureCerts(secret.Data)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return secret, nil
+	}
+	secret.Data = data
+	if !exists {
+		err = w.Client.Create(ctx, secret)
+	} else {
+		err = w.Client.Update(ctx, secret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	w.Logger.Info("webhook certificates are updated", "secret", client.ObjectKeyFromObject(secret))
+	return secret, nil
+}
+
+// ensureCerts returns the data of the secret with the valid certificates and
+// true if any of them is regenerated.
+func (w *WebhookRegistration) ensureCerts(data map[string][]byte) (map[string][]byte, bool, error) {
+	newData := make(map[string][]byte, len(data))
+	for k, v := range data {
+		newData[k] = v
+	}
+	changed := false
+
+	caCerts, caKey, ok := parseCA(newData[caCertKey], newData[caKeyKey])
+	if !ok || certNeedsRenewal(caCerts[0], util.CertificateValidity) {
+		key, err := util.NewPrivateKey()
+		if err != nil {
+			return nil, false, err
+		}
+		caCert, err := certutil.NewSelfSignedCACert(certutil.Config{
+			CommonName:   fmt.Sprintf("%s-ca@%d", w.ServiceName, time.Now().Unix()),
+			Organization: []string{"KubeZoo"},
+		}, key)
+		if err != nil {
+			return nil, false, err
+		}
+		keyPEM, err := util.EncodePrivateKeyPEM(key)
+		if err != nil {
+			return nil, false, err
+		}
+		// the former certificate authorities are trusted until they
+		// expire, which signed the certificates still being served
+		bundle := util.EncodeCertPEM(caCert)
+		for _, c := range caCerts {
+			if time.Now().Before(c.NotAfter) {
+				bundle = append(bundle, util.EncodeCertPEM(c)...)
+			}
+		}
+		newData[caCertKey] = bundle
+		newData[caKeyKey] = keyPEM
+		caCerts, caKey = append([]*x509.Certificate{caCert}, caCerts...), key
+		changed = true
+	}
+
+	validity := w.CertValidity
+	if validity == 0 {
+		validity = DefaultWebhookCertValidity
+	}
+	if !changed && !w.servingCertNeedsRenewal(newData[corev1.TLSCertKey], newData[corev1.TLSPrivateKeyKey], caCerts[0], validity) {
+		return data, false, nil
+	}
+	key, err := util.NewPrivateKey()
+	if err != nil {
+		return nil, false, err
+	}
+	cert, err := util.NewSignedCert(&util.Config{
+		CommonName:         fmt.Sprintf("%s.%s.svc", w.ServiceName, w.Namespace),
+		OrganizationalUnit: []string{"KubeZoo"},
+		AltNames:           util.AltNames{DNSNames: w.dnsNames()},
+		Usages:             []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		Validity:           validity,
+	}, key, caCerts[0], caKey)
+	if err != nil {
+		return nil, false, err
+	}
+	keyPEM, err := util.EncodePrivateKeyPEM(key)
+	if err != nil {
+		return nil, false, err
+	}
+	newData[corev1.TLSCertKey] = util.EncodeCertPEM(cert)
+	newData[corev1.TLSPrivateKeyKey] = keyPEM
+	return newData, true, nil
+}
+
+// servingCertNeedsRenewal returns true if the serving certificate is missing,
+// invalid, not signed by the certificate authority, not issued for the
+// service, or about to expire.
+func (w *WebhookRegistration) servingCertNeedsRenewal(certPEM, keyPEM []byte, caCert *x509.Certificate, validity time.Duration) bool {
+	certs, err := certutil.ParseCertsPEM(certPEM)
+	if err != nil || len(certs) == 0 {
+		return true
+	}
+	if _, err := keyutil.ParsePrivateKeyPEM(keyPEM); err != nil {
+		return true
+	}
+	cert := certs[0]
+	if err := cert.CheckSignatureFrom(caCert); err != nil {
+		return true
+	}
+	for _, name := range w.dnsNames() {
+		if err := cert.VerifyHostname(name); err != nil {
+			return true
+		}
+	}
+	return certNeedsRenewal(cert, validity)
+}
+
+// dnsNames returns the names the webhook is served at by the service.
+func (w *WebhookRegistration) dnsNames() []string {
+	return []string{
+		w.ServiceName,
+		fmt.Sprintf("%s.%s", w.ServiceName, w.Namespace),
+		fmt.Sprintf("%s.%s.svc", w.ServiceName, w.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", w.ServiceName, w.Namespace),
+	}
+}
+
+// writeCerts writes the serving certificate and key in the secret to the
+// cert dir if they are changed.
+func (w *WebhookRegistration) writeCerts(secret *corev1.Secret) error {
+	if err := os.MkdirAll(w.CertDir, 0o700); err != nil {
+		return err
+	}
+	// the key is written first, the certificate watcher of the webhook
+	// server reloads the pair on the change of either file
+	for _, key := range []string{corev1.TLSPrivateKeyKey, corev1.TLSCertKey} {
+		path := filepath.Join(w.CertDir, key)
+		existing, err := os.ReadFile(path)
+		if err == nil && bytes.Equal(existing, secret.Data[key]) {
+			continue
+		}
+		if err := os.WriteFile(path, secret.Data[key], 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncConfiguration creates or updates the validating webhook configuration
+// with the ca bundle.
+func (w *WebhookRegistration) syncConfiguration(ctx context.Context, caBundle []byte) error {
+	name := w.ConfigurationName
+	if name == "" {
+		name = DefaultWebhookConfigurationName
+	}
+	webhooks := w.webhooks(caBundle)
+
+	configuration := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	err := w.Client.Get(ctx, client.ObjectKey{Name: name}, configuration)
+	if errors.IsNotFound(err) {
+		configuration = &admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Webhooks:   webhooks,
+		}
+		if err := w.Client.Create(ctx, configuration); err != nil {
+			return err
+		}
+		w.Logger.Info("webhook configuration is created", "name", name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if apiequality.Semantic.DeepEqual(configuration.Webhooks, webhooks) {
+		return nil
+	}
+	configuration.Webhooks = webhooks
+	if err := w.Client.Update(ctx, configuration); err != nil {
+		return err
+	}
+	w.Logger.Info("webhook configuration is updated", "name", name)
+	return nil
+}
+
+// tenantClusterResources are the cluster scoped resources the tenants can
+// create through kubezoo, whose creation is evaluated against the object
+// count quota of the tenants. The cluster scoped custom resources of the
+// tenants are only counted by the controller, since their groups are not
+// known ahead.
+var tenantClusterResources = []schema.GroupResource{
+	{Resource: "persistentvolumes"},
+	{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"},
+	{Group: "rbac.authorization.k8s.io", Resource: "clusterroles"},
+	{Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings"},
+	{Group: "scheduling.k8s.io", Resource: "priorityclasses"},
+	{Group: "admissionregistration.k8s.io", Resource: "validatingwebhookconfigurations"},
+	{Group: "admissionregistration.k8s.io", Resource: "mutatingwebhookconfigurations"},
+}
+
+// webhooks returns the webhooks of the quota. The requests in the tenant
+// namespaces, and the creation of the tenant namespaces, are rejected if the
+// webhook is unavailable, since they are charged to the quotas of the
+// tenants. The creation of the other cluster scoped objects is ignored on
+// failure instead, otherwise the webhook would block the whole cluster, e.g.
+// the creation of the objects of the webhook itself.
+func (w *WebhookRegistration) webhooks(caBundle []byte) []admissionregistrationv1.ValidatingWebhook {
+	var (
+		fail              = admissionregistrationv1.Fail
+		ignore            = admissionregistrationv1.Ignore
+		equivalent        = admissionregistrationv1.Equivalent
+		sideEffects       = admissionregistrationv1.SideEffectClassNone
+		namespaced        = admissionregistrationv1.NamespacedScope
+		cluster           = admissionregistrationv1.ClusterScope
+		path              = WebhookPath
+		port              = w.Port
+		timeout     int32 = 5
+	)
+	clientConfig := admissionregistrationv1.WebhookClientConfig{
+		Service: &admissionregistrationv1.ServiceReference{
+			Namespace: w.Namespace,
+			Name:      w.ServiceName,
+			Path:      &path,
+			Port:      &port,
+		},
+		CABundle: caBundle,
+	}
+	anyObject := admissionregistrationv1.Rule{
+		APIGroups:   []string{"*"},
+		APIVersions: []string{"*"},
+		Resources:   []string{"*"},
+	}
+	tenantSelector := func(op metav1.LabelSelectorOperator) *metav1.LabelSelector {
+		return &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      common.TenantNamespaceLabelKey,
+				Operator: op,
+			}},
+		}
+	}
+	namespacedRule := anyObject
+	namespacedRule.Scope = &namespaced
+	var clusterRules []admissionregistrationv1.RuleWithOperations
+	for _, gr := range tenantClusterResources {
+		clusterRules = append(clusterRules, admissionregistrationv1.RuleWithOperations{
+			Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{gr.Group},
+				APIVersions: []string{"*"},
+				Resources:   []string{gr.Resource},
+				Scope:       &cluster,
+			},
+		})
+	}
+
+	return []admissionregistrationv1.ValidatingWebhook{
+		{
+			Name:         DefaultWebhookConfigurationName,
+			ClientConfig: clientConfig,
+			Rules: []admissionregistrationv1.RuleWithOperations{
+				// the creation of any object is evaluated, e.g. by the
+				// object count quota count/<resource>.<group> of the
+				// custom resources of the tenants
+				{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+					Rule:       namespacedRule,
+				},
+				// the namespaces of the tenants are counted by the object
+				// count quota of the tenants, which are matched by the
+				// namespace selector on their own labels
+				{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{""},
+						APIVersions: []string{"v1"},
+						Resources:   []string{"namespaces"},
+						Scope:       &cluster,
+					},
+				},
+				// the updates may change the usage, e.g. the expansion of
+				// persistentvolumeclaims, or the allocation of the
+				// resource quotas
+				{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Update},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{""},
+						APIVersions: []string{"v1"},
+						Resources:   []string{"pods", "services", "persistentvolumeclaims", "resourcequotas"},
+						Scope:       &namespaced,
+					},
+				},
+			},
+			FailurePolicy:           &fail,
+			MatchPolicy:             &equivalent,
+			NamespaceSelector:       tenantSelector(metav1.LabelSelectorOpExists),
+			ObjectSelector:          &metav1.LabelSelector{},
+			SideEffects:             &sideEffects,
+			TimeoutSeconds:          &timeout,
+			AdmissionReviewVersions: []string{"v1"},
+		},
+		{
+			// the cluster scoped objects of the tenants, e.g.
+			// persistentvolumes and customresourcedefinitions, are
+			// counted by the object count quota of the tenants, the
+			// namespaces of the tenants are left to the webhook above
+			Name:                    "cluster." + DefaultWebhookConfigurationName,
+			ClientConfig:            clientConfig,
+			Rules:                   clusterRules,
+			FailurePolicy:           &ignore,
+			MatchPolicy:             &equivalent,
+			NamespaceSelector:       tenantSelector(metav1.LabelSelectorOpDoesNotExist),
+			ObjectSelector:          &metav1.LabelSelector{},
+			SideEffects:             &sideEffects,
+			TimeoutSeconds:          &timeout,
+			AdmissionReviewVersions: []string{"v1"},
+		},
+	}
+}
+
+// parseCA parses the certificates and the key of the certificate authority,
+// the first certificate is the current one which signs the serving
+// certificates.
+func parseCA(certPEM, keyPEM []byte) ([]*x509.Certificate, crypto.Signer, bool) {
+	certs, err := certutil.ParseCertsPEM(certPEM)
+	if err != nil || len(certs) == 0 {
+		return nil, nil, false
+	}
+	key, err := keyutil.ParsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, nil, false
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, nil, false
+	}
+	return certs, signer, true
+}
+
+// certNeedsRenewal returns true if the certificate is in the last fifth of
+// its validity, or outlives the validity, e.g. issued before the validity is
+// shortened.
+func certNeedsRenewal(cert *x509.Certificate, validity time.Duration) bool {
+	remaining := time.Until(cert.NotAfter)
+	return remaining < validity/5 || remaining > validity
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	certutil "k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubewharf/kubezoo/pkg/common"
+)
+
+// TestWebhookRegistrationSync tests the certificates are generated and
+// written to the cert dir, the webhooks are registered with the ca bundle,
+// and the serving certificate is rotated.
+func TestWebhookRegistrationSync(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	w := &WebhookRegistration{
+		Client:       c,
+		Logger:       logr.Discard(),
+		Namespace:    "default",
+		ServiceName:  "kubezoo-cluster-resource-quota",
+		Port:         6443,
+		SecretName:   "quota-webhook-pki",
+		CertDir:      t.TempDir(),
+		CertValidity: time.Hour,
+	}
+	ctx := context.TODO()
+	if err := w.Sync(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var secret corev1.Secret
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "quota-webhook-pki"}, &secret); err != nil {
+		t.Fatalf("unable to get secret: %v", err)
+	}
+	var configuration admissionregistrationv1.ValidatingWebhookConfiguration
+	if err := c.Get(ctx, client.ObjectKey{Name: DefaultWebhookConfigurationName}, &configuration); err != nil {
+		t.Fatalf("unable to get webhook configuration: %v", err)
+	}
+	if len(configuration.Webhooks) != 2 {
+		t.Fatalf("expected 2 webhooks, got %d", len(configuration.Webhooks))
+	}
+	for _, webhook := range configuration.Webhooks {
+		if !bytes.Equal(webhook.ClientConfig.CABundle, secret.Data[caCertKey]) {
+			t.Errorf("webhook %s: unexpected ca bundle", webhook.Name)
+		}
+		if webhook.NamespaceSelector.MatchExpressions[0].Key != common.TenantNamespaceLabelKey {
+			t.Errorf("webhook %s: unexpected namespace selector %v", webhook.Name, webhook.NamespaceSelector)
+		}
+	}
+	if *configuration.Webhooks[0].FailurePolicy != admissionregistrationv1.Fail {
+		t.Errorf("expected the webhook of the tenant namespaces to fail closed")
+	}
+	if *configuration.Webhooks[1].FailurePolicy != admissionregistrationv1.Ignore {
+		t.Errorf("expected the webhook of the cluster scoped objects to fail open")
+	}
+	for _, rule := range configuration.Webhooks[1].Rules {
+		for _, resource := range rule.Resources {
+			if resource == "*" {
+				t.Errorf("expected the webhook of the cluster scoped objects to match the resources of the tenants only, got %v", rule)
+			}
+		}
+	}
+
+	// the serving certificate in the cert dir is trusted by the ca bundle
+	cert, err := tls.LoadX509KeyPair(filepath.Join(w.CertDir, corev1.TLSCertKey), filepath.Join(w.CertDir, corev1.TLSPrivateKeyKey))
+	if err != nil {
+		t.Fatalf("unable to load serving certificate: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("unable to parse serving certificate: %v", err)
+	}
+	verifyServingCert(t, leaf, configuration.Webhooks[0].ClientConfig.CABundle)
+
+	// the certificates are kept if they are valid
+	if err := w.Sync(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var synced corev1.Secret
+	if err := c.Get(ctx, client.ObjectKeyFromObject(&secret), &synced); err != nil {
+		t.Fatalf("unable to get secret: %v", err)
+	}
+	if synced.ResourceVersion != secret.ResourceVersion {
+		t.Errorf("expected the secret to be unchanged")
+	}
+
+	// the serving certificate outliving the validity is rotated by the
+	// same certificate authority, and the tampered ca bundle is restored
+	configuration.Webhooks[0].ClientConfig.CABundle = nil
+	if err := c.Update(ctx, &configuration); err != nil {
+		t.Fatalf("unable to update webhook configuration: %v", err)
+	}
+	w.CertValidity = 30 * time.Minute
+	if err := w.Sync(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(&secret), &synced); err != nil {
+		t.Fatalf("unable to get secret: %v", err)
+	}
+	if !bytes.Equal(synced.Data[caCertKey], secret.Data[caCertKey]) {
+		t.Errorf("expected the certificate authority to be kept")
+	}
+	if bytes.Equal(synced.Data[corev1.TLSCertKey], secret.Data[corev1.TLSCertKey]) {
+		t.Errorf("expected the serving certificate to be rotated")
+	}
+	written, err := os.ReadFile(filepath.Join(w.CertDir, corev1.TLSCertKey))
+	if err != nil {
+		t.Fatalf("unable to read serving certificate: %v", err)
+	}
+	if !bytes.Equal(written, synced.Data[corev1.TLSCertKey]) {
+		t.Errorf("expected the rotated serving certificate to be written")
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(&configuration), &configuration); err != nil {
+		t.Fatalf("unable to get webhook configuration: %v", err)
+	}
+	if !bytes.Equal(configuration.Webhooks[0].ClientConfig.CABundle, synced.Data[caCertKey]) {
+		t.Errorf("expected the ca bundle to be restored")
+	}
+}
+
+func verifyServingCert(t *testing.T, cert *x509.Certificate, caBundle []byte) {
+	t.Helper()
+	cas, err := certutil.ParseCertsPEM(caBundle)
+	if err != nil {
+		t.Fatalf("unable to parse ca bundle: %v", err)
+	}
+	roots := x509.NewCertPool()
+	for _, ca := range cas {
+		roots.AddCert(ca)
+	}
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName:   "kubezoo-cluster-resource-quota.default.svc",
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	if err != nil {
+		t.Errorf("unable to verify serving certificate: %v", err)
+	}
+}
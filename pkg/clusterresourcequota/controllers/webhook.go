@@ -21,44 +21,73 @@ import (
 	goerrors "errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	pkgadmission "k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/admission/plugin/resourcequota"
 	"k8s.io/apiserver/pkg/authentication/user"
+	quotautil "k8s.io/apiserver/pkg/quota/v1"
 	quotageneric "k8s.io/apiserver/pkg/quota/v1/generic"
+	"k8s.io/client-go/util/retry"
 	quotainstall "k8s.io/kubernetes/pkg/quota/v1/install"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	quotav1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
 )
 
 type Admission struct {
+	client    client.Client
+	accessor  *quotaAccessor
 	evaluator resourcequota.Evaluator
+	scheme    *runtime.Scheme
 	decoder   *admission.Decoder
 	logger    logr.Logger
 }
 
-func NewAdmission(ctx context.Context, client client.Client) *Admission {
-	accessor := &quotaAccessor{client: client}
+// NewAdmission returns the admission which evaluates the requests against
+// the cluster resource quotas. Besides the resources known to the quota
+// registry, e.g. pods, services and persistentvolumeclaims, the creation of
+// any other object, e.g. the custom resources of the tenants, is evaluated
+// against the object count quota, i.e. count/<resource>.<group>. The usage
+// of the admitted requests is reserved in the status of the cluster resource
+// quotas for the reservation TTL, with the resource versions read from the
+// apiReader, so that the admission in the namespaces of a cluster resource
+// quota is serialized by the optimistic concurrency. Besides, the resource
+// quotas created in the namespaces can not allocate more than the hard
+// limits of the cluster resource quotas, and the creation of the cluster
+// scoped objects of the tenants is evaluated against the object count quota
+// of the cluster resource quotas of the tenants.
+func NewAdmission(ctx context.Context, client client.Client, apiReader client.Reader, reservationTTL time.Duration) *Admission {
+	accessor := &quotaAccessor{client: client, apiReader: apiReader, reservationTTL: reservationTTL}
+	config := quotainstall.NewQuotaConfigurationForAdmission()
 	return &Admission{
+		client:   client,
+		accessor: accessor,
 		evaluator: resourcequota.NewQuotaEvaluator(
 			accessor,
-			nil,
-			quotageneric.NewRegistry(quotainstall.NewQuotaConfigurationForAdmission().Evaluators()),
+			config.IgnoredResources(),
+			quotageneric.NewRegistry(config.Evaluators()),
 			nil,
 			nil,
 			10,
 			ctx.Done(),
 		),
+		scheme: client.Scheme(),
 	}
 }
 
@@ -76,25 +105,169 @@ func (a *Admission) InjectLogger(logger logr.Logger) error {
 	return nil
 }
 
+// Handle evaluates the creation and the update of the objects, e.g. the
+// expansion of persistentvolumeclaims, against the cluster resource quotas.
 func (a *Admission) Handle(ctx context.Context, req admission.Request) admission.Response {
-	if req.Operation == admissionv1.Create {
-		var obj corev1.Pod
-		err := a.decoder.Decode(req, &obj)
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	var err error
+	gvk := schema.GroupVersionKind(req.Kind)
+	if req.Object.Object, err = a.decodeObject(req.Object, gvk); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if req.OldObject.Object, err = a.decodeObject(req.OldObject, gvk); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if isClusterObjectRequest(req) {
+		if req.Operation == admissionv1.Create {
+			if err := a.admitClusterObject(ctx, req); err != nil {
+				return deniedResponse(err)
+			}
+		}
+		return admission.Allowed("")
+	}
+	if quota, ok := req.Object.Object.(*corev1.ResourceQuota); ok && util.IsQuotaAllocation(quota) {
+		if err := a.checkAllocation(ctx, quota); err != nil {
+			return deniedResponse(err)
+		}
+	}
+	attributes := CreateAdmissionAttributes(req.AdmissionRequest)
+	err = a.evaluator.Evaluate(attributes)
+	if err != nil {
+		return deniedResponse(err)
+	}
+	return admission.Allowed("")
+}
+
+// isClusterObjectRequest returns true if the request is for a cluster
+// scoped object, including the namespace, which is not evaluated by the
+// resource quotas.
+func isClusterObjectRequest(req admission.Request) bool {
+	return req.Namespace == "" ||
+		(schema.GroupResource{Group: req.Resource.Group, Resource: req.Resource.Resource} == namespaceResource && req.SubResource == "")
+}
+
+// admitClusterObject evaluates the creation of the cluster scoped object of
+// a tenant against the object count quota of each cluster resource quota of
+// the tenant, and reserves the usage in the status as the resource quota
+// admission does, with the optimistic concurrency.
+func (a *Admission) admitClusterObject(ctx context.Context, req admission.Request) error {
+	gr := schema.GroupResource{Group: req.Resource.Group, Resource: req.Resource.Resource}
+	name := req.Name
+	if name == "" && req.Object.Object != nil {
+		// the name is generated by the upstream cluster later
+		if accessor, err := meta.Accessor(req.Object.Object); err == nil {
+			name = accessor.GetGenerateName()
+		}
+	}
+	tenantID := tenantOfClusterObject(gr, name)
+	if tenantID == "" {
+		return nil
+	}
+	clusterquotas, err := listTenantClusterQuotas(ctx, a.client, tenantID)
+	if err != nil {
+		return err
+	}
+	resourceName := objectCountResourceName(gr)
+	dryRun := req.DryRun != nil && *req.DryRun
+	for i := range clusterquotas {
+		clusterquota := &clusterquotas[i]
+		if _, ok := clusterquota.Spec.Hard[resourceName]; !ok || isScoped(clusterquota) {
+			continue
+		}
+		err := retry.RetryOnConflict(DefaultRetry, func() error {
+			return a.reserveClusterObject(ctx, clusterquota.Name, gr, name, resourceName, dryRun)
+		})
 		if err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
+			return err
 		}
-		req.Object.Object = &obj
-		attributes := CreateAdmissionAttributes(req.AdmissionRequest)
-		err = a.evaluator.Evaluate(attributes)
+	}
+	return nil
+}
+
+// reserveClusterObject reserves one more object of the cluster scoped
+// resource in the status of the cluster resource quota, or rejects it if
+// the object count quota is exceeded.
+func (a *Admission) reserveClusterObject(ctx context.Context, quotaName string, gr schema.GroupResource, name string, resourceName corev1.ResourceName, dryRun bool) error {
+	var clusterquota quotav1alpha1.ClusterResourceQuota
+	if err := a.accessor.apiReader.Get(ctx, types.NamespacedName{Name: quotaName}, &clusterquota); err != nil {
+		return err
+	}
+	hard := clusterquota.Spec.Hard[resourceName]
+	used := clusterquota.Status.Used[resourceName]
+	requested := resource.MustParse("1")
+	newUsed := used.DeepCopy()
+	newUsed.Add(requested)
+	if newUsed.Cmp(hard) > 0 {
+		return errors.NewForbidden(gr, name, fmt.Errorf("exceeded quota: %s, requested: %s=%s, used: %s=%s, limited: %s=%s",
+			quotaName, resourceName, requested.String(), resourceName, used.String(), resourceName, hard.String()))
+	}
+	if dryRun {
+		return nil
+	}
+	return a.accessor.UpdateQuotaStatus(&corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            clusterResourceQuotaPrefix + quotaName,
+			ResourceVersion: clusterquota.ResourceVersion,
+		},
+		Status: corev1.ResourceQuotaStatus{
+			Used: quotautil.Add(clusterquota.Status.Used, corev1.ResourceList{resourceName: requested}),
+		},
+	})
+}
+
+// checkAllocation checks the hard limits allocated by the resource quota,
+// summed with the other allocations, against each cluster resource quota of
+// its namespace.
+func (a *Admission) checkAllocation(ctx context.Context, quota *corev1.ResourceQuota) error {
+	var ns corev1.Namespace
+	if err := a.client.Get(ctx, types.NamespacedName{Name: quota.Namespace}, &ns); err != nil {
+		return err
+	}
+	var clusterquotaList quotav1alpha1.ClusterResourceQuotaList
+	if err := a.client.List(ctx, &clusterquotaList); err != nil {
+		return err
+	}
+	for i := range clusterquotaList.Items {
+		clusterquota := &clusterquotaList.Items[i]
+		if !matches(clusterquota, &ns) {
+			continue
+		}
+		namespaces, err := GetNamespacesForClusterQuota(ctx, a.client, clusterquota)
 		if err != nil {
-			var apiStatus errors.APIStatus
-			if goerrors.As(err, &apiStatus) {
-				return validationResponseFromStatus(false, apiStatus.Status())
-			}
-			return admission.Denied(err.Error())
+			return err
+		}
+		allocations, err := GetQuotaAllocationsForClusterQuota(ctx, a.client, clusterquota, namespaces)
+		if err != nil {
+			return err
+		}
+		if err := util.CheckQuotaAllocation(quota, allocations, []*quotav1alpha1.ClusterResourceQuota{clusterquota}); err != nil {
+			return errors.NewForbidden(corev1.Resource("resourcequotas"), quota.Name, err)
 		}
 	}
-	return admission.Allowed("")
+	return nil
+}
+
+// decodeObject decodes the raw object of the kind. The kinds unknown to the
+// scheme, e.g. the custom resources, are decoded as unstructured objects,
+// which are only counted by the object count quota.
+func (a *Admission) decodeObject(raw runtime.RawExtension, gvk schema.GroupVersionKind) (runtime.Object, error) {
+	if len(raw.Raw) == 0 {
+		return nil, nil
+	}
+	var obj runtime.Object = &unstructured.Unstructured{}
+	if a.scheme != nil && a.scheme.Recognizes(gvk) {
+		typed, err := a.scheme.New(gvk)
+		if err != nil {
+			return nil, err
+		}
+		obj = typed
+	}
+	if err := a.decoder.DecodeRaw(raw, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
 }
 
 func CreateAdmissionAttributes(req admissionv1.AdmissionRequest) pkgadmission.Attributes {
@@ -108,6 +281,7 @@ func CreateAdmissionAttributes(req admissionv1.AdmissionRequest) pkgadmission.At
 		info.Extra[k] = []string(req.UserInfo.Extra[k])
 	}
 
+	dryRun := req.DryRun != nil && *req.DryRun
 	return pkgadmission.NewAttributesRecord(
 		req.Object.Object,
 		req.OldObject.Object,
@@ -118,15 +292,26 @@ func CreateAdmissionAttributes(req admissionv1.AdmissionRequest) pkgadmission.At
 		req.SubResource,
 		pkgadmission.Operation(req.Operation),
 		req.Options.Object,
-		*req.DryRun,
+		dryRun,
 		info,
 	)
 }
 
+// clusterResourceQuotaPrefix prefixes the name of the cluster resource quota
+// in the resource quotas evaluated by the admission.
+const clusterResourceQuotaPrefix = "clusterresourcequota/"
+
 type quotaAccessor struct {
-	client client.Client
+	client    client.Client
+	apiReader client.Reader
+	// reservationTTL is how long the usage reserved by the admitted requests
+	// is counted before it is expected to be observed in the namespaces.
+	reservationTTL time.Duration
 }
 
+// GetQuotas returns the cluster resource quotas of the namespace in the form
+// of the resource quotas, whose status and resource version are those of
+// the cluster resource quotas.
 func (a *quotaAccessor) GetQuotas(namespace string) ([]corev1.ResourceQuota, error) {
 	var quotaList corev1.ResourceQuotaList
 	selector := labels.Set{
@@ -140,11 +325,11 @@ func (a *quotaAccessor) GetQuotas(namespace string) ([]corev1.ResourceQuota, err
 	for i := range quotaList.Items {
 		quota := &quotaList.Items[i]
 		owner := metav1.GetControllerOf(quota)
-		if owner.Kind != ClusterResourceQuotaKind {
+		if owner == nil || owner.Kind != ClusterResourceQuotaKind {
 			continue
 		}
 		var clusterquota quotav1alpha1.ClusterResourceQuota
-		err := a.client.Get(context.TODO(), types.NamespacedName{Name: owner.Name}, &clusterquota)
+		err := a.apiReader.Get(context.TODO(), types.NamespacedName{Name: owner.Name}, &clusterquota)
 		if err != nil {
 			if errors.IsNotFound(err) {
 				continue
@@ -153,15 +338,68 @@ func (a *quotaAccessor) GetQuotas(namespace string) ([]corev1.ResourceQuota, err
 		}
 		// use cluster resource quota usage
 		quota.Status = clusterquota.Status.ResourceQuotaStatus
+		quota.ResourceVersion = clusterquota.ResourceVersion
 		// change quota name for debug
-		quota.Name = fmt.Sprintf("clusterresourcequota/%s", clusterquota.Name)
+		quota.Name = clusterResourceQuotaPrefix + clusterquota.Name
 		quotas = append(quotas, *quota)
 	}
 	return quotas, nil
 }
 
+// UpdateQuotaStatus reserves the usage of the admitted requests in the
+// status of the cluster resource quota. The update conflicts if the cluster
+// resource quota is changed since GetQuotas, e.g. by the admission in the
+// other namespaces or by the other replicas, and the requests are evaluated
+// again against the latest usage.
 func (a *quotaAccessor) UpdateQuotaStatus(newQuota *corev1.ResourceQuota) error {
-	return nil
+	name := strings.TrimPrefix(newQuota.Name, clusterResourceQuotaPrefix)
+	var clusterquota quotav1alpha1.ClusterResourceQuota
+	if err := a.apiReader.Get(context.TODO(), types.NamespacedName{Name: name}, &clusterquota); err != nil {
+		return err
+	}
+	if clusterquota.ResourceVersion != newQuota.ResourceVersion {
+		return errors.NewConflict(quotav1alpha1.Resource("clusterresourcequotas"), name,
+			fmt.Errorf("the usage is changed since resource version %s", newQuota.ResourceVersion))
+	}
+
+	reserved := quotautil.RemoveZeros(quotautil.SubtractWithNonNegativeResult(newQuota.Status.Used, clusterquota.Status.Used))
+	if len(reserved) == 0 {
+		return nil
+	}
+	now := time.Now()
+	reservations := make([]quotav1alpha1.ClusterResourceQuotaReservation, 0, len(clusterquota.Status.Reservations)+1)
+	for _, reservation := range clusterquota.Status.Reservations {
+		if !reservation.ExpirationTime.Time.After(now) {
+			clusterquota.Status.Used = quotautil.SubtractWithNonNegativeResult(clusterquota.Status.Used, reservation.Resources)
+			continue
+		}
+		reservations = append(reservations, reservation)
+	}
+	if len(reservations) >= MaxReservations {
+		return errors.NewTooManyRequests(fmt.Sprintf("too many pending reservations of cluster resource quota %s", name), 1)
+	}
+	baseline := corev1.ResourceList{}
+	for resourceName := range reserved {
+		baseline[resourceName] = clusterquota.Status.Used[resourceName].DeepCopy()
+	}
+	clusterquota.Status.Used = quotautil.Add(clusterquota.Status.Used, reserved)
+	clusterquota.Status.Reservations = append(reservations, quotav1alpha1.ClusterResourceQuotaReservation{
+		Namespace:      newQuota.Namespace,
+		Resources:      reserved,
+		ExpirationTime: metav1.NewTime(now.Add(a.reservationTTL)),
+		Baseline:       baseline,
+	})
+	return a.client.Status().Update(context.TODO(), &clusterquota)
+}
+
+// deniedResponse returns a response for denying a request with the error,
+// whose status is kept if it is an API status error.
+func deniedResponse(err error) admission.Response {
+	var apiStatus errors.APIStatus
+	if goerrors.As(err, &apiStatus) {
+		return validationResponseFromStatus(false, apiStatus.Status())
+	}
+	return admission.Denied(err.Error())
 }
 
 // validationResponseFromStatus returns a response for admitting a request with provided Status object.
@@ -0,0 +1,487 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	quotautil "k8s.io/apiserver/pkg/quota/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	quotav1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/common"
+)
+
+// newTestAdmission returns the admission with the cluster resource quota of
+// the tenant 111111 in two namespaces, whose usage is recorded in the status.
+func newTestAdmission(t *testing.T, hard, used corev1.ResourceList) (*Admission, client.Client) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := quotav1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	clusterquota := &quotav1alpha1.ClusterResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "kubezoo-tenant-quota-111111",
+			UID:    "uid-1",
+			Labels: map[string]string{common.TenantNamespaceLabelKey: "111111"},
+		},
+		Spec: quotav1alpha1.ClusterResourceQuotaSpec{
+			ResourceQuotaSpec: corev1.ResourceQuotaSpec{Hard: hard},
+			Namespaces:        []string{"111111-default", "111111-other"},
+		},
+		Status: quotav1alpha1.ClusterResourceQuotaStatus{
+			ResourceQuotaStatus: corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+		},
+	}
+	objs := []client.Object{clusterquota}
+	for _, namespace := range clusterquota.Spec.Namespaces {
+		objs = append(objs, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})
+		objs = append(objs, &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "kubezoo-tenant-quota-111111-abcde",
+				Labels: map[string]string{
+					quotav1alpha1.ClusterResourceQuotaCreatedby: clusterquota.Name,
+					LabelClusterResourceQuotaAutoUpdate:         "true",
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(clusterquota, quotav1alpha1.SchemeGroupVersion.WithKind(ClusterResourceQuotaKind)),
+				},
+			},
+			Spec: clusterquota.Spec.ResourceQuotaSpec,
+		})
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	a := NewAdmission(ctx, c, c, time.Minute)
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.InjectDecoder(decoder); err != nil {
+		t.Fatal(err)
+	}
+	return a, c
+}
+
+func rawObject(t *testing.T, obj interface{}) runtime.RawExtension {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return runtime.RawExtension{Raw: raw}
+}
+
+func newTestConfigMapRequest(t *testing.T, namespace, name string) admission.Request {
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+		Resource:  metav1.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+		Namespace: namespace,
+		Name:      name,
+		Operation: admissionv1.Create,
+		Object: rawObject(t, &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		}),
+	}}
+}
+
+func newTestPVC(storage string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaim"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "111111-default", Name: "data", ResourceVersion: "1"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(storage)},
+			},
+		},
+	}
+}
+
+// TestAdmissionHandle tests the creation and the update of the objects are
+// evaluated against the cluster resource quota.
+func TestAdmissionHandle(t *testing.T) {
+	hard := corev1.ResourceList{
+		corev1.ResourceRequestsStorage:          resource.MustParse("10Gi"),
+		"count/foos.111111-kubezoo.io":          resource.MustParse("1"),
+		corev1.ResourceName("count/configmaps"): resource.MustParse("1"),
+	}
+	used := corev1.ResourceList{
+		corev1.ResourceRequestsStorage:          resource.MustParse("5Gi"),
+		"count/foos.111111-kubezoo.io":          resource.MustParse("1"),
+		corev1.ResourceName("count/configmaps"): resource.MustParse("0"),
+	}
+	a, c := newTestAdmission(t, hard, used)
+
+	foo := map[string]interface{}{
+		"apiVersion": "111111-kubezoo.io/v1",
+		"kind":       "Foo",
+		"metadata":   map[string]interface{}{"namespace": "111111-default", "name": "foo"},
+	}
+	cases := []struct {
+		name    string
+		req     admissionv1.AdmissionRequest
+		allowed bool
+	}{
+		{
+			name: "create custom resource over object count quota",
+			req: admissionv1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Group: "111111-kubezoo.io", Version: "v1", Kind: "Foo"},
+				Resource:  metav1.GroupVersionResource{Group: "111111-kubezoo.io", Version: "v1", Resource: "foos"},
+				Namespace: "111111-default",
+				Name:      "foo",
+				Operation: admissionv1.Create,
+				Object:    rawObject(t, foo),
+			},
+		},
+		{
+			name: "create configmap within quota",
+			req: admissionv1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+				Resource:  metav1.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+				Namespace: "111111-default",
+				Name:      "cm",
+				Operation: admissionv1.Create,
+				Object: rawObject(t, &corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "111111-default", Name: "cm"},
+				}),
+			},
+			allowed: true,
+		},
+		{
+			name: "create configmap over quota reserved",
+			req: admissionv1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+				Resource:  metav1.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+				Namespace: "111111-default",
+				Name:      "cm2",
+				Operation: admissionv1.Create,
+				Object: rawObject(t, &corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "111111-default", Name: "cm2"},
+				}),
+			},
+		},
+		{
+			name: "expand persistentvolumeclaim over storage quota",
+			req: admissionv1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "PersistentVolumeClaim"},
+				Resource:  metav1.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"},
+				Namespace: "111111-default",
+				Name:      "data",
+				Operation: admissionv1.Update,
+				Object:    rawObject(t, newTestPVC("20Gi")),
+				OldObject: rawObject(t, newTestPVC("5Gi")),
+			},
+		},
+		{
+			name: "expand persistentvolumeclaim within storage quota",
+			req: admissionv1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "PersistentVolumeClaim"},
+				Resource:  metav1.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"},
+				Namespace: "111111-default",
+				Name:      "data",
+				Operation: admissionv1.Update,
+				Object:    rawObject(t, newTestPVC("8Gi")),
+				OldObject: rawObject(t, newTestPVC("5Gi")),
+			},
+			allowed: true,
+		},
+		{
+			name: "delete custom resource",
+			req: admissionv1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Group: "111111-kubezoo.io", Version: "v1", Kind: "Foo"},
+				Resource:  metav1.GroupVersionResource{Group: "111111-kubezoo.io", Version: "v1", Resource: "foos"},
+				Namespace: "111111-default",
+				Name:      "foo",
+				Operation: admissionv1.Delete,
+				OldObject: rawObject(t, foo),
+			},
+			allowed: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			resp := a.Handle(context.TODO(), admission.Request{AdmissionRequest: c.req})
+			if resp.Allowed != c.allowed {
+				t.Errorf("expect allowed %v, got %v: %v", c.allowed, resp.Allowed, resp.Result)
+			}
+		})
+	}
+
+	var clusterquota quotav1alpha1.ClusterResourceQuota
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: "kubezoo-tenant-quota-111111"}, &clusterquota); err != nil {
+		t.Fatal(err)
+	}
+	expectUsed := corev1.ResourceList{
+		corev1.ResourceRequestsStorage:          resource.MustParse("8Gi"),
+		"count/foos.111111-kubezoo.io":          resource.MustParse("1"),
+		corev1.ResourceName("count/configmaps"): resource.MustParse("1"),
+	}
+	if !quotautil.Equals(clusterquota.Status.Used, expectUsed) {
+		t.Errorf("expect used %v, got %v", expectUsed, clusterquota.Status.Used)
+	}
+	if len(clusterquota.Status.Reservations) != 2 {
+		t.Errorf("expect 2 reservations, got %v", clusterquota.Status.Reservations)
+	}
+	for _, reservation := range clusterquota.Status.Reservations {
+		for resourceName := range reservation.Resources {
+			if _, ok := reservation.Baseline[resourceName]; !ok {
+				t.Errorf("expect the baseline of %s recorded, got %v", resourceName, reservation.Baseline)
+			}
+		}
+	}
+}
+
+// TestUpdateQuotaStatusReservations tests the expired reservations are
+// released when the usage is reserved, and the pending reservations are
+// capped.
+func TestUpdateQuotaStatusReservations(t *testing.T) {
+	hard := corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1000")}
+	used := corev1.ResourceList{corev1.ResourcePods: resource.MustParse("5")}
+	a, c := newTestAdmission(t, hard, used)
+
+	var clusterquota quotav1alpha1.ClusterResourceQuota
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: "kubezoo-tenant-quota-111111"}, &clusterquota); err != nil {
+		t.Fatal(err)
+	}
+	clusterquota.Status.Reservations = []quotav1alpha1.ClusterResourceQuotaReservation{{
+		Namespace:      "111111-default",
+		Resources:      corev1.ResourceList{corev1.ResourcePods: resource.MustParse("2")},
+		ExpirationTime: metav1.NewTime(time.Now().Add(-time.Second)),
+	}}
+	if err := c.Status().Update(context.TODO(), &clusterquota); err != nil {
+		t.Fatal(err)
+	}
+	reserve := func() error {
+		var clusterquota quotav1alpha1.ClusterResourceQuota
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: "kubezoo-tenant-quota-111111"}, &clusterquota); err != nil {
+			t.Fatal(err)
+		}
+		return a.accessor.UpdateQuotaStatus(&corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "111111-default",
+				Name:            clusterResourceQuotaPrefix + clusterquota.Name,
+				ResourceVersion: clusterquota.ResourceVersion,
+			},
+			Status: corev1.ResourceQuotaStatus{
+				Used: quotautil.Add(clusterquota.Status.Used, corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")}),
+			},
+		})
+	}
+	if err := reserve(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: "kubezoo-tenant-quota-111111"}, &clusterquota); err != nil {
+		t.Fatal(err)
+	}
+	if expect := (corev1.ResourceList{corev1.ResourcePods: resource.MustParse("4")}); !quotautil.Equals(clusterquota.Status.Used, expect) {
+		t.Errorf("expect used %v, got %v", expect, clusterquota.Status.Used)
+	}
+	if len(clusterquota.Status.Reservations) != 1 {
+		t.Fatalf("expect the expired reservation released, got %v", clusterquota.Status.Reservations)
+	}
+	if expect := (corev1.ResourceList{corev1.ResourcePods: resource.MustParse("3")}); !quotautil.Equals(clusterquota.Status.Reservations[0].Baseline, expect) {
+		t.Errorf("expect baseline %v, got %v", expect, clusterquota.Status.Reservations[0].Baseline)
+	}
+
+	for i := 1; i < MaxReservations; i++ {
+		if err := reserve(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := reserve(); !errors.IsTooManyRequests(err) {
+		t.Errorf("expect too many requests beyond %d reservations, got %v", MaxReservations, err)
+	}
+}
+
+// TestAdmissionConcurrentReservation tests the concurrent requests in the
+// namespaces of a cluster resource quota can not overcommit it, even if they
+// are admitted by different webhook replicas.
+func TestAdmissionConcurrentReservation(t *testing.T) {
+	hard := corev1.ResourceList{corev1.ResourceName("count/configmaps"): resource.MustParse("1")}
+	used := corev1.ResourceList{corev1.ResourceName("count/configmaps"): resource.MustParse("0")}
+	a, c := newTestAdmission(t, hard, used)
+	replica := NewAdmission(context.TODO(), c, c, time.Minute)
+	replica.InjectDecoder(a.decoder)
+
+	var (
+		wg      sync.WaitGroup
+		allowed int32
+	)
+	for i, admission := range []*Admission{a, replica} {
+		wg.Add(1)
+		go func(admission *Admission, namespace string) {
+			defer wg.Done()
+			if resp := admission.Handle(context.TODO(), newTestConfigMapRequest(t, namespace, "cm")); resp.Allowed {
+				atomic.AddInt32(&allowed, 1)
+			}
+		}(admission, []string{"111111-default", "111111-other"}[i])
+	}
+	wg.Wait()
+	if allowed != 1 {
+		t.Errorf("expect 1 request allowed, got %d", allowed)
+	}
+}
+
+func newTestResourceQuotaRequest(t *testing.T, namespace, pods string, operation admissionv1.Operation) admission.Request {
+	quota := &corev1.ResourceQuota{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ResourceQuota"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "budget"},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse(pods)},
+		},
+	}
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "ResourceQuota"},
+		Resource:  metav1.GroupVersionResource{Version: "v1", Resource: "resourcequotas"},
+		Namespace: namespace,
+		Name:      quota.Name,
+		Operation: operation,
+		Object:    rawObject(t, quota),
+	}}
+}
+
+// TestAdmissionQuotaAllocation tests the resource quotas created in the
+// namespaces can not allocate more than the cluster resource quota.
+func TestAdmissionQuotaAllocation(t *testing.T) {
+	hard := corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}
+	a, c := newTestAdmission(t, hard, corev1.ResourceList{corev1.ResourcePods: resource.MustParse("0")})
+	if err := c.Create(context.TODO(), &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "111111-default", Name: "budget"},
+		Spec:       corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("6")}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		req     admission.Request
+		allowed bool
+	}{
+		{
+			name:    "allocate within quota",
+			req:     newTestResourceQuotaRequest(t, "111111-other", "4", admissionv1.Create),
+			allowed: true,
+		},
+		{
+			name: "allocate over quota",
+			req:  newTestResourceQuotaRequest(t, "111111-other", "5", admissionv1.Create),
+		},
+		{
+			name:    "decrease allocation",
+			req:     newTestResourceQuotaRequest(t, "111111-default", "2", admissionv1.Update),
+			allowed: true,
+		},
+		{
+			name: "increase allocation over quota",
+			req:  newTestResourceQuotaRequest(t, "111111-default", "11", admissionv1.Update),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			resp := a.Handle(context.TODO(), c.req)
+			if resp.Allowed != c.allowed {
+				t.Errorf("expect allowed %v, got %v: %v", c.allowed, resp.Allowed, resp.Result)
+			}
+		})
+	}
+}
+
+func newTestPersistentVolumeRequest(t *testing.T, name string) admission.Request {
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "PersistentVolume"},
+		Resource:  metav1.GroupVersionResource{Version: "v1", Resource: "persistentvolumes"},
+		Name:      name,
+		Operation: admissionv1.Create,
+		Object: rawObject(t, &corev1.PersistentVolume{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolume"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+		}),
+	}}
+}
+
+// TestAdmissionClusterObject tests the creation of the cluster scoped objects
+// of the tenant is evaluated against the object count quota.
+func TestAdmissionClusterObject(t *testing.T) {
+	hard := corev1.ResourceList{"count/persistentvolumes": resource.MustParse("1")}
+	used := corev1.ResourceList{"count/persistentvolumes": resource.MustParse("0")}
+	a, c := newTestAdmission(t, hard, used)
+
+	cases := []struct {
+		name    string
+		req     admission.Request
+		allowed bool
+	}{
+		{
+			name:    "create persistentvolume within quota",
+			req:     newTestPersistentVolumeRequest(t, "111111-pv-1"),
+			allowed: true,
+		},
+		{
+			name: "create persistentvolume over quota reserved",
+			req:  newTestPersistentVolumeRequest(t, "111111-pv-2"),
+		},
+		{
+			name:    "create persistentvolume of other tenant",
+			req:     newTestPersistentVolumeRequest(t, "222222-pv-1"),
+			allowed: true,
+		},
+		{
+			name:    "create persistentvolume of no tenant",
+			req:     newTestPersistentVolumeRequest(t, "pv-1"),
+			allowed: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			resp := a.Handle(context.TODO(), c.req)
+			if resp.Allowed != c.allowed {
+				t.Errorf("expect allowed %v, got %v: %v", c.allowed, resp.Allowed, resp.Result)
+			}
+		})
+	}
+
+	var clusterquota quotav1alpha1.ClusterResourceQuota
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: "kubezoo-tenant-quota-111111"}, &clusterquota); err != nil {
+		t.Fatal(err)
+	}
+	if !quotautil.Equals(clusterquota.Status.Used, hard) {
+		t.Errorf("expect used %v, got %v", hard, clusterquota.Status.Used)
+	}
+}
@@ -67,6 +67,10 @@ type StorageConfig struct {
 	ProxyTransport       http.RoundTripper
 	UpstreamMaster       *url.URL
 	GroupVersionKindFunc GroupVersionKindFunc
+
+	// ServiceAccountTokenIssuer issues the tokens of serviceaccounts/token
+	// instead of the upstream cluster if it is set.
+	ServiceAccountTokenIssuer ServiceAccountTokenIssuer
 }
 
 type GroupVersionKindFunc func(containingGV schema.GroupVersion) schema.GroupVersionKind
@@ -0,0 +1,37 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"time"
+
+	"gopkg.in/square/go-jose.v2/jwt"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// ServiceAccountTokenIssuer issues the service account tokens of the tenants
+// on behalf of kubezoo.
+type ServiceAccountTokenIssuer interface {
+	// TenantIssuerURL returns the issuer URL of the tenant.
+	TenantIssuerURL(tenantID string) string
+	// MaxExpiration returns the max expiration of the tokens, 0 means no limit.
+	MaxExpiration() time.Duration
+	// GenerateToken generates the token of the service account in the tenant,
+	// the objects are those seen inside the tenant.
+	GenerateToken(tenantID string, serviceAccount core.ServiceAccount, pod *core.Pod, secret *core.Secret,
+		expirationSeconds int64, audiences []string) (string, *jwt.Claims, error)
+}
@@ -18,11 +18,11 @@ package controller
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"net"
-	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -31,15 +31,22 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	rbacclient "k8s.io/client-go/kubernetes/typed/rbac/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/component-helpers/auth/rbac/reconciliation"
@@ -48,6 +55,7 @@ import (
 	rbacv1helpers "k8s.io/kubernetes/pkg/apis/rbac/v1"
 
 	quotav1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1"
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
 	"github.com/kubewharf/kubezoo/pkg/common"
 	"github.com/kubewharf/kubezoo/pkg/dynamic"
 	quotaclient "github.com/kubewharf/kubezoo/pkg/generated/clientset/versioned/typed/quota/v1alpha1"
@@ -62,6 +70,11 @@ const (
 	Create = iota
 	Update
 	Delete
+	// SyncLimitRanges syncs the default limit range of the tenant into its
+	// namespaces only.
+	SyncLimitRanges
+	// SyncCRDUsage syncs the crd usage in the status of the tenant only.
+	SyncCRDUsage
 )
 
 const (
@@ -84,6 +97,10 @@ type TenantController struct {
 	tenantInformer          cache.SharedIndexInformer
 	tenantLister            tenantlister.TenantLister
 	tenantClient            tenantclient.TenantV1alpha1Interface
+	limitRangeInformers     []cache.SharedIndexInformer
+	namespaceLister         corelisters.NamespaceLister
+	limitRangeLister        corelisters.LimitRangeLister
+	crdInformer             cache.SharedIndexInformer
 	clusterquotaCli         quotaclient.QuotaV1alpha1Interface
 	upstreamDiscoveryClient *discovery.DiscoveryClient
 	upstreamDynamicClient   dynamic.Interface
@@ -91,12 +108,14 @@ type TenantController struct {
 	upstreamCoreClient      v1.CoreV1Interface
 	upstreamRbacClient      rbacclient.RbacV1Interface
 	clientCAFile            string
-	clientCAKeyFile         string
+	caSigner                util.CASigner
+	certKeyAlgorithm        util.KeyAlgorithm
+	certValidity            time.Duration
 	kubeZooHostAddress      string
 }
 
 // newTenantController create a controller to handler the events of tenant.
-func newTenantController(ti cache.SharedIndexInformer, tenantCli tenantclient.TenantV1alpha1Interface, coreCli v1.CoreV1Interface, rbacCli rbacclient.RbacV1Interface, quotaClient quotaclient.QuotaV1alpha1Interface, discoveryCli *discovery.DiscoveryClient, dynamicCli dynamic.Interface, crdClient *apiextensions.Clientset, clientCAFile, clientCAKeyFile, kubeZooBindAddress string, kubeZooSecurePort int) *TenantController {
+func newTenantController(ti cache.SharedIndexInformer, tenantCli tenantclient.TenantV1alpha1Interface, coreCli v1.CoreV1Interface, rbacCli rbacclient.RbacV1Interface, quotaClient quotaclient.QuotaV1alpha1Interface, discoveryCli *discovery.DiscoveryClient, dynamicCli dynamic.Interface, crdClient *apiextensions.Clientset, clientCAFile string, caSigner util.CASigner, certKeyAlgorithm util.KeyAlgorithm, certValidity time.Duration, kubeZooBindAddress string, kubeZooSecurePort int) *TenantController {
 	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
 	var (
 		newEvent Event
@@ -110,7 +129,10 @@ func newTenantController(ti cache.SharedIndexInformer, tenantCli tenantclient.Te
 				queue.Add(newEvent)
 			}
 		},
-		UpdateFunc: func(_, new interface{}) {
+		UpdateFunc: func(old, new interface{}) {
+			if isTenantStatusUpdate(old, new) {
+				return
+			}
 			newEvent.tenantId, err = cache.MetaNamespaceKeyFunc(new)
 			if err == nil {
 				newEvent.eventType = Update
@@ -138,20 +160,50 @@ func newTenantController(ti cache.SharedIndexInformer, tenantCli tenantclient.Te
 		upstreamDynamicClient:   dynamicCli,
 		upstreamCRDClient:       crdClient,
 		clientCAFile:            clientCAFile,
-		clientCAKeyFile:         clientCAKeyFile,
+		caSigner:                caSigner,
+		certKeyAlgorithm:        certKeyAlgorithm,
+		certValidity:            certValidity,
 		kubeZooHostAddress:      net.JoinHostPort(kubeZooBindAddress, strconv.Itoa(kubeZooSecurePort)),
 	}
 }
 
+// isTenantStatusUpdate returns whether only the status of the tenant is
+// updated, e.g. the quota status or the crd usage written by the controller
+// itself, which needs no sync of the tenant.
+func isTenantStatusUpdate(old, new interface{}) bool {
+	oldTenant, ok := old.(*tenantv1alpha1.Tenant)
+	if !ok {
+		return false
+	}
+	newTenant, ok := new.(*tenantv1alpha1.Tenant)
+	if !ok || oldTenant.ResourceVersion == newTenant.ResourceVersion {
+		return false
+	}
+	return newTenant.Generation == oldTenant.Generation &&
+		newTenant.DeletionTimestamp.Equal(oldTenant.DeletionTimestamp) &&
+		apiequality.Semantic.DeepEqual(newTenant.Finalizers, oldTenant.Finalizers) &&
+		apiequality.Semantic.DeepEqual(newTenant.Labels, oldTenant.Labels) &&
+		apiequality.Semantic.DeepEqual(newTenant.Annotations, oldTenant.Annotations) &&
+		apiequality.Semantic.DeepEqual(newTenant.Spec, oldTenant.Spec)
+}
+
 // Run starts the tenant controller
-func Run(stopCh <-chan struct{}, ti cache.SharedIndexInformer, tenantCli tenantclient.TenantV1alpha1Interface, typedCli kubernetes.Interface, discoveryCli *discovery.DiscoveryClient, dynamicCli dynamic.Interface, crdClient *apiextensions.Clientset, quotaClient quotaclient.QuotaV1alpha1Interface, clientCAFile, clientCAKeyFile, kubeZooBindAddress string, kubeZooSecurePort int) {
-	tc := newTenantController(ti, tenantCli, typedCli.CoreV1(), typedCli.RbacV1(), quotaClient, discoveryCli, dynamicCli, crdClient, clientCAFile, clientCAKeyFile, kubeZooBindAddress, kubeZooSecurePort)
+func Run(stopCh <-chan struct{}, ti cache.SharedIndexInformer, tenantCli tenantclient.TenantV1alpha1Interface, typedCli kubernetes.Interface, discoveryCli *discovery.DiscoveryClient, dynamicCli dynamic.Interface, crdClient *apiextensions.Clientset, crdInformer cache.SharedIndexInformer, quotaClient quotaclient.QuotaV1alpha1Interface, clientCAFile string, caSigner util.CASigner, certKeyAlgorithm util.KeyAlgorithm, certValidity time.Duration, kubeZooBindAddress string, kubeZooSecurePort int) {
+	tc := newTenantController(ti, tenantCli, typedCli.CoreV1(), typedCli.RbacV1(), quotaClient, discoveryCli, dynamicCli, crdClient, clientCAFile, caSigner, certKeyAlgorithm, certValidity, kubeZooBindAddress, kubeZooSecurePort)
+	tc.addLimitRangeInformers(typedCli)
+	if crdInformer != nil {
+		// the shared crd informer is started along with the proxy
+		tc.setCRDInformer(crdInformer)
+	}
 	defer utilruntime.HandleCrash()
 	defer tc.queue.ShutDown()
 
 	klog.V(4).Info("Starting Tenant Controller")
 
 	go tc.tenantInformer.Run(stopCh)
+	for _, informer := range tc.limitRangeInformers {
+		go informer.Run(stopCh)
+	}
 
 	if !cache.WaitForCacheSync(stopCh, tc.HasSynced) {
 		utilruntime.HandleError(fmt.Errorf("Timed out waiting for caches to sync"))
@@ -166,6 +218,14 @@ func Run(stopCh <-chan struct{}, ti cache.SharedIndexInformer, tenantCli tenantc
 // informed by at least one full LIST of the authoritative state
 // of the informer's object collection.
 func (tc *TenantController) HasSynced() bool {
+	for _, informer := range tc.limitRangeInformers {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+	if tc.crdInformer != nil && !tc.crdInformer.HasSynced() {
+		return false
+	}
 	return tc.tenantInformer.HasSynced()
 }
 
@@ -211,6 +271,10 @@ func (tc *TenantController) processItem(e Event) error {
 	case Delete:
 		klog.Warningf("deleting tenant %v", e.tenantId)
 		return nil
+	case SyncLimitRanges:
+		return tc.syncLimitRanges(e.tenantId)
+	case SyncCRDUsage:
+		return tc.syncCRDUsage(e.tenantId)
 	}
 	return nil
 }
@@ -224,9 +288,25 @@ func (tc *TenantController) onTenantCreate(tenantID string) error {
 		return err
 	}
 
+	if err := tc.syncLimitRanges(tenantID); err != nil {
+		return err
+	}
+
 	if err := tc.syncClusterResourceQuota(tenantID); err != nil {
 		return err
 	}
+
+	if err := tc.syncQuotaStatus(tenantID); err != nil {
+		return err
+	}
+
+	if err := tc.syncCRDUsage(tenantID); err != nil {
+		return err
+	}
+
+	if err := tc.syncUsers(tenantID); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -235,9 +315,25 @@ func (tc *TenantController) onTenantUpdate(tenantID string) error {
 		return err
 	}
 
+	if err := tc.syncLimitRanges(tenantID); err != nil {
+		return err
+	}
+
 	if err := tc.syncClusterResourceQuota(tenantID); err != nil {
 		return err
 	}
+
+	if err := tc.syncQuotaStatus(tenantID); err != nil {
+		return err
+	}
+
+	if err := tc.syncCRDUsage(tenantID); err != nil {
+		return err
+	}
+
+	if err := tc.syncUsers(tenantID); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -274,6 +370,141 @@ func (tc *TenantController) onTenantAddOrUpdate(tenantId string) error {
 	return nil
 }
 
+// syncCRDUsage updates the crd usage in the status of the tenant. The
+// usage is counted from the informer of the crds and refreshed on every
+// resync of the tenant informer.
+func (tc *TenantController) syncCRDUsage(tenantID string) error {
+	if tc.tenantClient == nil || tc.crdInformer == nil {
+		klog.Warning("Skip synchronize crd usage since nil tenant client or crd informer.")
+		return nil
+	}
+
+	tenantCRDs, err := tc.listTenantCRDs(tenantID)
+	if err != nil {
+		return err
+	}
+	usage := util.GetCRDUsage(tenantCRDs...)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		tenant, err := tc.tenantClient.Tenants().Get(context.TODO(), tenantID, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if !tenant.DeletionTimestamp.IsZero() ||
+			(tenant.Status.CRDUsage != nil && *tenant.Status.CRDUsage == usage) {
+			return nil
+		}
+		tenant.Status.CRDUsage = &usage
+		_, err = tc.tenantClient.Tenants().UpdateStatus(context.TODO(), tenant, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// syncUsers issues the certificates to the named users of the tenant, and
+// revokes the ones of the revoked or removed users. The kubeconfig of each
+// user is attached in the annotation of the tenant, and the serial number
+// of its certificate is recorded in the status, so that the superseded
+// certificates are rejected by the authenticator.
+func (tc *TenantController) syncUsers(tenantID string) error {
+	if tc.tenantClient == nil {
+		klog.Warning("Skip synchronize tenant users since nil tenant client.")
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		tenant, err := tc.tenantClient.Tenants().Get(context.TODO(), tenantID, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if !tenant.DeletionTimestamp.IsZero() {
+			return nil
+		}
+		changed, err := tc.reconcileUsers(tenant)
+		if err != nil || !changed {
+			return err
+		}
+		_, err = tc.tenantClient.Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// reconcileUsers updates the user kubeconfigs and statuses of the tenant
+// in place, and returns whether the tenant is changed.
+func (tc *TenantController) reconcileUsers(tenant *tenantv1alpha1.Tenant) (bool, error) {
+	issued := make(map[string]tenantv1alpha1.TenantUserStatus, len(tenant.Status.Users))
+	for _, s := range tenant.Status.Users {
+		issued[s.Name] = s
+	}
+
+	var (
+		changed  bool
+		err      error
+		active   = make(map[string]bool, len(tenant.Spec.Users))
+		statuses = make([]tenantv1alpha1.TenantUserStatus, 0, len(tenant.Spec.Users))
+	)
+	// renew the certificate of the admin, which is issued on creation
+	if kubeconfig := tenant.Annotations[util.AnnotationTenantKubeConfigBase64]; kubeconfig != "" && tc.kubeconfigNeedsRenewal(tenant, kubeconfig) {
+		if tenant.Annotations[util.AnnotationTenantKubeConfigBase64], _, err = tc.genUserCertAndKubeconfig(tenant, util.TenantAdminUserName); err != nil {
+			return false, err
+		}
+		changed = true
+		klog.V(4).Infof("certificate of tenant %s is renewed", tenant.Name)
+	}
+	for _, u := range tenant.Spec.Users {
+		if u.Revoked {
+			continue
+		}
+		if errs := util.ValidateTenantUsers([]tenantv1alpha1.TenantUser{u}, field.NewPath("spec", "users")); len(errs) != 0 {
+			klog.Warningf("skip invalid user %s of tenant %s: %v", u.Name, tenant.Name, errs.ToAggregate())
+			continue
+		}
+		active[u.Name] = true
+		annotation := util.TenantUserKubeConfigAnnotation(u.Name)
+		if s, ok := issued[u.Name]; ok && tenant.Annotations[annotation] != "" && !tc.certNeedsRenewal(s.NotAfter.Time) {
+			statuses = append(statuses, s)
+			continue
+		}
+
+		kubeconfig, cert, err := tc.genUserCertAndKubeconfig(tenant, u.Name)
+		if err != nil {
+			return false, err
+		}
+		if tenant.Annotations == nil {
+			tenant.Annotations = make(map[string]string)
+		}
+		tenant.Annotations[annotation] = kubeconfig
+		statuses = append(statuses, tenantv1alpha1.TenantUserStatus{
+			Name:         u.Name,
+			SerialNumber: cert.SerialNumber.String(),
+			NotAfter:     metav1.NewTime(cert.NotAfter),
+		})
+		changed = true
+		klog.V(4).Infof("certificate of user %s in tenant %s is issued", u.Name, tenant.Name)
+	}
+
+	// drop the kubeconfigs of the revoked or removed users
+	for key := range tenant.Annotations {
+		if userName, ok := util.TenantUserFromKubeConfigAnnotation(key); ok && !active[userName] {
+			delete(tenant.Annotations, key)
+			changed = true
+			klog.V(4).Infof("certificate of user %s in tenant %s is revoked", userName, tenant.Name)
+		}
+	}
+	if len(statuses) != len(tenant.Status.Users) {
+		changed = true
+	}
+	if changed {
+		tenant.Status.Users = statuses
+	}
+	return changed, nil
+}
+
 // deleteResources deletes resources belonging to the tenant from the upstream cluster.
 func (tc *TenantController) deleteResources(tenantId string) error {
 	klog.V(4).Infof("delete resources for tenant %s", tenantId)
@@ -407,31 +638,25 @@ func (tc *TenantController) syncResources(tenantId string) error {
 	if err := syncClusterRoleBindings(tc.upstreamCoreClient, tc.upstreamRbacClient, tenantId); err != nil {
 		return err
 	}
-	if err := genCertAndKubeconfig(tc.tenantClient, tenantId, tc.tenantLister, tc.clientCAFile, tc.clientCAKeyFile, tc.kubeZooHostAddress); err != nil {
+	if err := tc.genCertAndKubeconfig(tenantId); err != nil {
 		return err
 	}
 	return nil
 }
 
+// syncClusterResourceQuota syncs the cluster resource quotas of the tenant,
+// one for the quota without scopes and one for each scoped quota, and
+// deletes the ones of the removed scoped quotas.
 func (tc *TenantController) syncClusterResourceQuota(tenantID string) error {
 	if tc.tenantClient == nil || tc.clusterquotaCli == nil {
 		klog.Warning("Skip synchronize cluster resource quota since nil tenant or clusterResourceQuota client.")
 		return nil
 	}
-	tenantQuotaName := fmt.Sprintf("%s-%s", common.TenantQuotaNamePrefix, tenantID)
 
 	tenant, err := tc.tenantClient.Tenants().Get(context.TODO(), tenantID, metav1.GetOptions{})
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			nerr := tc.clusterquotaCli.ClusterResourceQuotas().Delete(context.TODO(), tenantQuotaName, metav1.DeleteOptions{})
-			if apierrors.IsNotFound(nerr) {
-				// ignore notFound
-				nerr = nil
-			}
-			if nerr == nil {
-				klog.Infof("delete cluster resource quota (%v) successfully", tenantQuotaName)
-			}
-			return nerr
+			return tc.deleteStaleClusterResourceQuotas(tenantID, nil)
 		}
 		return err
 	}
@@ -441,25 +666,24 @@ func (tc *TenantController) syncClusterResourceQuota(tenantID string) error {
 		return nil
 	}
 
-	clusterquota, err := tc.clusterquotaCli.ClusterResourceQuotas().Get(context.TODO(), tenantQuotaName, metav1.GetOptions{})
+	expectedQuotas := newTenantClusterResourceQuotas(tenant)
+	expectedNames := sets.NewString()
+	for _, expectedQuota := range expectedQuotas {
+		if err := tc.syncOneClusterResourceQuota(expectedQuota); err != nil {
+			return err
+		}
+		expectedNames.Insert(expectedQuota.Name)
+	}
+	return tc.deleteStaleClusterResourceQuotas(tenantID, expectedNames)
+}
+
+// syncOneClusterResourceQuota creates or updates the cluster resource quota
+// as expected.
+func (tc *TenantController) syncOneClusterResourceQuota(expectedQuota *quotav1alpha1.ClusterResourceQuota) error {
+	clusterquota, err := tc.clusterquotaCli.ClusterResourceQuotas().Get(context.TODO(), expectedQuota.Name, metav1.GetOptions{})
 	if err != nil && !apierrors.IsNotFound(err) {
 		return err
 	}
-	expectedQuota := &quotav1alpha1.ClusterResourceQuota{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: tenantQuotaName,
-		},
-		Spec: quotav1alpha1.ClusterResourceQuotaSpec{
-			ResourceQuotaSpec: corev1.ResourceQuotaSpec{
-				Hard: tenant.Spec.Quota.Hard,
-			},
-			NamepsaceSelector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					common.TenantNamespaceLabelKey: tenantID,
-				},
-			},
-		},
-	}
 	if apierrors.IsNotFound(err) {
 		// create
 		_, err := tc.clusterquotaCli.ClusterResourceQuotas().Create(context.TODO(), expectedQuota, metav1.CreateOptions{})
@@ -467,11 +691,15 @@ func (tc *TenantController) syncClusterResourceQuota(tenantID string) error {
 	}
 
 	// update
-	if !reflect.DeepEqual(clusterquota.Spec.Hard, tenant.Spec.Quota.Hard) ||
-		!reflect.DeepEqual(clusterquota.Spec.NamepsaceSelector, expectedQuota.Spec.NamepsaceSelector) {
+	tenantID := expectedQuota.Labels[common.TenantNamespaceLabelKey]
+	if !apiequality.Semantic.DeepEqual(clusterquota.Spec, expectedQuota.Spec) ||
+		clusterquota.Labels[common.TenantNamespaceLabelKey] != tenantID {
 		mutator := func(quota *quotav1alpha1.ClusterResourceQuota) error {
-			quota.Spec.Hard = tenant.Spec.Quota.Hard
-			quota.Spec.NamepsaceSelector = expectedQuota.Spec.NamepsaceSelector
+			quota.Spec = expectedQuota.Spec
+			if quota.Labels == nil {
+				quota.Labels = make(map[string]string)
+			}
+			quota.Labels[common.TenantNamespaceLabelKey] = tenantID
 			return nil
 		}
 		//TODO: retry
@@ -481,7 +709,7 @@ func (tc *TenantController) syncClusterResourceQuota(tenantID string) error {
 			Factor:   1.0,
 			Jitter:   0.1},
 			func() error {
-				quota, err := tc.clusterquotaCli.ClusterResourceQuotas().Get(context.TODO(), tenantQuotaName, metav1.GetOptions{})
+				quota, err := tc.clusterquotaCli.ClusterResourceQuotas().Get(context.TODO(), expectedQuota.Name, metav1.GetOptions{})
 				if err != nil {
 					return err
 				}
@@ -498,6 +726,138 @@ func (tc *TenantController) syncClusterResourceQuota(tenantID string) error {
 	return nil
 }
 
+// deleteStaleClusterResourceQuotas deletes the cluster resource quotas of
+// the tenant which are not expected, all of them are deleted if expected
+// is nil.
+func (tc *TenantController) deleteStaleClusterResourceQuotas(tenantID string, expectedNames sets.String) error {
+	quotaList, err := tc.clusterquotaCli.ClusterResourceQuotas().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.Set{common.TenantNamespaceLabelKey: tenantID}.String(),
+	})
+	if err != nil {
+		return err
+	}
+	// the quota without scopes may be created before it is labeled
+	names := sets.NewString(tenantQuotaName(tenantID, ""))
+	for _, quota := range quotaList.Items {
+		names.Insert(quota.Name)
+	}
+	for _, name := range names.Difference(expectedNames).List() {
+		err := tc.clusterquotaCli.ClusterResourceQuotas().Delete(context.TODO(), name, metav1.DeleteOptions{})
+		if apierrors.IsNotFound(err) {
+			// ignore notFound
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		klog.Infof("delete cluster resource quota (%v) successfully", name)
+	}
+	return nil
+}
+
+// syncQuotaStatus updates the usage of the quotas in the status of the
+// tenant from their cluster resource quotas. The usage is refreshed on
+// every resync of the tenant informer.
+func (tc *TenantController) syncQuotaStatus(tenantID string) error {
+	if tc.tenantClient == nil || tc.clusterquotaCli == nil {
+		klog.Warning("Skip synchronize quota status since nil tenant or clusterResourceQuota client.")
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		tenant, err := tc.tenantClient.Tenants().Get(context.TODO(), tenantID, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if !tenant.DeletionTimestamp.IsZero() {
+			return nil
+		}
+		var statuses []tenantv1alpha1.TenantQuotaStatus
+		for _, expectedQuota := range newTenantClusterResourceQuotas(tenant) {
+			clusterquota, err := tc.clusterquotaCli.ClusterResourceQuotas().Get(context.TODO(), expectedQuota.Name, metav1.GetOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return err
+			}
+			statuses = append(statuses, tenantv1alpha1.TenantQuotaStatus{
+				Name:      expectedQuota.Labels[tenantQuotaScopeLabelKey],
+				Hard:      clusterquota.Status.Hard,
+				Used:      clusterquota.Status.Used,
+				Allocated: clusterquota.Status.Allocated,
+			})
+		}
+		if apiequality.Semantic.DeepEqual(tenant.Status.Quota, statuses) {
+			return nil
+		}
+		tenant.Status.Quota = statuses
+		_, err = tc.tenantClient.Tenants().UpdateStatus(context.TODO(), tenant, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// tenantQuotaScopeLabelKey labels the cluster resource quota of a scoped
+// quota of the tenant with the name of the scoped quota.
+const tenantQuotaScopeLabelKey = "kubezoo.io/tenant-quota-scope"
+
+// tenantQuotaName returns the name of the cluster resource quota of the
+// scoped quota of the tenant, or of the quota without scopes if scopedName
+// is empty. The names never collide as the tenant ID is of fixed length.
+func tenantQuotaName(tenantID, scopedName string) string {
+	name := fmt.Sprintf("%s-%s", common.TenantQuotaNamePrefix, tenantID)
+	if scopedName != "" {
+		name += "-" + scopedName
+	}
+	return name
+}
+
+// newTenantClusterResourceQuotas returns the expected cluster resource
+// quotas of the tenant, the one of the quota without scopes comes first.
+// The object count quotas of the custom resources of the tenant are
+// converted to the upstream ones.
+func newTenantClusterResourceQuotas(tenant *tenantv1alpha1.Tenant) []*quotav1alpha1.ClusterResourceQuota {
+	newQuota := func(scopedName string, spec corev1.ResourceQuotaSpec) *quotav1alpha1.ClusterResourceQuota {
+		quota := &quotav1alpha1.ClusterResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: tenantQuotaName(tenant.Name, scopedName),
+				Labels: map[string]string{
+					common.TenantNamespaceLabelKey: tenant.Name,
+				},
+			},
+			Spec: quotav1alpha1.ClusterResourceQuotaSpec{
+				ResourceQuotaSpec: spec,
+				NamepsaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						common.TenantNamespaceLabelKey: tenant.Name,
+					},
+				},
+			},
+		}
+		if scopedName != "" {
+			quota.Labels[tenantQuotaScopeLabelKey] = scopedName
+		}
+		return quota
+	}
+
+	quotas := []*quotav1alpha1.ClusterResourceQuota{
+		newQuota("", corev1.ResourceQuotaSpec{
+			Hard: util.ConvertTenantResourceListToUpstream(tenant.Name, tenant.Spec.Quota.Hard),
+		}),
+	}
+	for _, scoped := range tenant.Spec.Quota.Scoped {
+		quotas = append(quotas, newQuota(scoped.Name, corev1.ResourceQuotaSpec{
+			Hard:          util.ConvertTenantResourceListToUpstream(tenant.Name, scoped.Hard),
+			Scopes:        scoped.Scopes,
+			ScopeSelector: scoped.ScopeSelector.DeepCopy(),
+		}))
+	}
+	return quotas
+}
+
 // syncNamespaces synchronize the system namespaces to upstream cluster.
 func syncNamespaces(coreClient v1.CoreV1Interface, tenantId string) error {
 	systemNamespaces := []string{metav1.NamespaceSystem, metav1.NamespacePublic, corev1.NamespaceNodeLease, corev1.NamespaceDefault}
@@ -648,8 +1008,8 @@ func syncClusterRoleBindings(coreClient v1.CoreV1Interface, rbacClient rbacclien
 
 // genCertAndKubeconfig signs the certificate/key and generates the kubeconfig for the tenant;
 // the generated kubeconfig will be attached in the tenant's annotation.
-func genCertAndKubeconfig(tenantCli tenantclient.TenantV1alpha1Interface, tenantId string, tenantlister tenantlister.TenantLister, clientCAFile, clientCAKeyFile, kubeZooHostAddress string) error {
-	tenant, err := tenantlister.Get(tenantId)
+func (tc *TenantController) genCertAndKubeconfig(tenantId string) error {
+	tenant, err := tc.tenantLister.Get(tenantId)
 	if err != nil {
 		return errors.Errorf("Error fetching object with key %s from store: %v", tenantId, err)
 	}
@@ -658,35 +1018,100 @@ func genCertAndKubeconfig(tenantCli tenantclient.TenantV1alpha1Interface, tenant
 		return nil
 	}
 
-	// 2. Generate the certificate and the key
-	cert, key, err := util.NewTenantCertAndKey(clientCAFile, clientCAKeyFile, tenantId)
-	if err != nil {
-		klog.Warningf("fail to generate the certificate for the tenant(%s): %v", tenantId, err)
-		return err
-	}
-
-	// 3. Generate the kubeconfig
-	caCertByts, err := ioutil.ReadFile(clientCAFile)
-	if err != nil {
-		klog.Warningf("fail to read CA from file(%s): %v", clientCAFile, err)
-		return err
-	}
-	kbcfgByts, err := util.GenKubeconfig("https://"+kubeZooHostAddress, tenantId, caCertByts, util.EncodePrivateKeyPEM(key), util.EncodeCertPEM(cert))
+	// 2. Generate the certificate, the key and the kubeconfig
+	kbcfgB64Str, _, err := tc.genUserCertAndKubeconfig(tenant, util.TenantAdminUserName)
 	if err != nil {
-		klog.Warningf("fail to generate the kubeconfig for tenant(%s): %v", tenantId, err)
 		return err
 	}
-	kbcfgB64Str := base64.StdEncoding.EncodeToString(kbcfgByts)
 
-	// 4. Attach the kubeconfig to the annotation
+	// 3. Attach the kubeconfig to the annotation
 	if tenant.Annotations == nil {
 		tenant.Annotations = make(map[string]string)
 	}
 	tenant.Annotations[util.AnnotationTenantKubeConfigBase64] = kbcfgB64Str
-	if _, err := tenantCli.Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{}); err != nil {
+	if _, err := tc.tenantClient.Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{}); err != nil {
 		klog.Warningf("fail to update the tenant with new annotation(%s): %v", util.AnnotationTenantKubeConfigBase64, err)
 		return err
 	}
 	klog.V(4).Infof("kubeconfig of tenant(%s) is created", tenantId)
 	return nil
 }
+
+// genUserCertAndKubeconfig signs the certificate/key for the named user of
+// the tenant, and returns the base64 encoded kubeconfig with the certificate.
+// The certificate is bound to the UID of the tenant.
+func (tc *TenantController) genUserCertAndKubeconfig(tenant *tenantv1alpha1.Tenant, userName string) (string, *x509.Certificate, error) {
+	tenantId := tenant.Name
+	config := util.NewTenantUserCertConfig(tenantId, tenant.UID, userName)
+	config.KeyAlgorithm = tc.certKeyAlgorithm
+	config.Validity = tc.certValidity
+	cert, key, err := util.NewCertAndKey(tc.caSigner, config)
+	if err != nil {
+		klog.Warningf("fail to generate the certificate for the user(%s) of tenant(%s): %v", userName, tenantId, err)
+		return "", nil, err
+	}
+
+	caCertByts, err := ioutil.ReadFile(tc.clientCAFile)
+	if err != nil {
+		klog.Warningf("fail to read CA from file(%s): %v", tc.clientCAFile, err)
+		return "", nil, err
+	}
+	// the kubeconfig user of the admin is named after the tenant for compatibility
+	authInfoName := tenantId
+	if userName != util.TenantAdminUserName {
+		authInfoName = util.AddTenantIDPrefix(tenantId, userName)
+	}
+	keyByts, err := util.EncodePrivateKeyPEM(key)
+	if err != nil {
+		return "", nil, err
+	}
+	kbcfgByts, err := util.GenKubeconfig("https://"+tc.kubeZooHostAddress, authInfoName, caCertByts, keyByts, util.EncodeCertPEM(cert))
+	if err != nil {
+		klog.Warningf("fail to generate the kubeconfig for the user(%s) of tenant(%s): %v", userName, tenantId, err)
+		return "", nil, err
+	}
+	return base64.StdEncoding.EncodeToString(kbcfgByts), cert, nil
+}
+
+// certNeedsRenewal returns true if the certificate expiring at notAfter is
+// in the last fifth of its validity, or outlives the configured validity,
+// e.g. issued before the validity is shortened.
+func (tc *TenantController) certNeedsRenewal(notAfter time.Time) bool {
+	validity := tc.certValidity
+	if validity == 0 {
+		validity = util.CertificateValidity
+	}
+	remaining := time.Until(notAfter)
+	return remaining < validity/5 || remaining > validity
+}
+
+// kubeconfigNeedsRenewal returns true if the certificate of the admin in the
+// base64 encoded kubeconfig needs renewal, can not be parsed, or is not bound
+// to the UID of the tenant, e.g. issued before the binding is introduced.
+func (tc *TenantController) kubeconfigNeedsRenewal(tenant *tenantv1alpha1.Tenant, kubeconfig string) bool {
+	tenantId := tenant.Name
+	kbcfgByts, err := base64.StdEncoding.DecodeString(kubeconfig)
+	if err != nil {
+		klog.Warningf("fail to decode the kubeconfig of tenant(%s): %v", tenantId, err)
+		return true
+	}
+	config, err := clientcmd.Load(kbcfgByts)
+	if err != nil {
+		klog.Warningf("fail to load the kubeconfig of tenant(%s): %v", tenantId, err)
+		return true
+	}
+	authInfo, ok := config.AuthInfos[tenantId]
+	if !ok {
+		klog.Warningf("user %s is not found in the kubeconfig of tenant(%s)", tenantId, tenantId)
+		return true
+	}
+	certs, err := certutil.ParseCertsPEM(authInfo.ClientCertificateData)
+	if err != nil {
+		klog.Warningf("fail to parse the certificate in the kubeconfig of tenant(%s): %v", tenantId, err)
+		return true
+	}
+	if uid, ok := util.TenantUIDFromCertificate(certs[0]); !ok || uid != tenant.UID {
+		return true
+	}
+	return tc.certNeedsRenewal(certs[0].NotAfter)
+}
@@ -196,6 +196,33 @@ var _ = Describe("Tenant controller", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("issue and revoke certificates of tenant users", func() {
+		var err error
+		tenant, err = controlPlaneClient.TenantV1alpha1().Tenants().Get(ctx, testTenantName, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		tenant.Spec.Users = []tenantv1alpha1.TenantUser{{Name: "alice"}}
+		_, err = controlPlaneClient.TenantV1alpha1().Tenants().Update(ctx, tenant, metav1.UpdateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		time.Sleep(2 * time.Second)
+
+		tenant, err = controlPlaneClient.TenantV1alpha1().Tenants().Get(ctx, testTenantName, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tenant.Annotations[util.TenantUserKubeConfigAnnotation("alice")]).NotTo(BeEmpty())
+		Expect(tenant.Status.Users).To(HaveLen(1))
+		Expect(tenant.Status.Users[0].Name).To(Equal("alice"))
+		Expect(tenant.Status.Users[0].SerialNumber).NotTo(BeEmpty())
+
+		tenant.Spec.Users[0].Revoked = true
+		_, err = controlPlaneClient.TenantV1alpha1().Tenants().Update(ctx, tenant, metav1.UpdateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		time.Sleep(2 * time.Second)
+
+		tenant, err = controlPlaneClient.TenantV1alpha1().Tenants().Get(ctx, testTenantName, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tenant.Annotations).NotTo(HaveKey(util.TenantUserKubeConfigAnnotation("alice")))
+		Expect(tenant.Status.Users).To(BeEmpty())
+	})
+
 	It("delete tenant", func() {
 		err := controlPlaneClient.TenantV1alpha1().Tenants().Delete(ctx, testTenantName, metav1.DeleteOptions{})
 		Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// CRDTenantIndex indexes the upstream crds by the tenants of their groups.
+const CRDTenantIndex = "tenant"
+
+// CRDTenantIndexFunc returns the tenant of the upstream crd, whose name is
+// in the form <plural>.<tenant>-<group>, if any.
+func CRDTenantIndexFunc(obj interface{}) ([]string, error) {
+	crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+	if !ok {
+		return nil, nil
+	}
+	parts := strings.SplitN(crd.Name, ".", 2)
+	if len(parts) < 2 {
+		return nil, nil
+	}
+	prefix := strings.SplitN(parts[1], util.TenantIDSeparator, 2)
+	if len(prefix) < 2 || util.ValidateTenantName(prefix[0]) != nil {
+		return nil, nil
+	}
+	return []string{prefix[0]}, nil
+}
+
+// setCRDInformer sets the shared informer of the upstream crds, which must
+// be indexed by CRDTenantIndex. The crd usage of the tenants is counted from
+// the informer, and synced once the crds of the tenants are changed.
+func (tc *TenantController) setCRDInformer(crdInformer cache.SharedIndexInformer) {
+	enqueue := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		tenantIDs, _ := CRDTenantIndexFunc(obj)
+		for _, tenantID := range tenantIDs {
+			tc.queue.Add(Event{tenantId: tenantID, eventType: SyncCRDUsage})
+		}
+	}
+	crdInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueue,
+		UpdateFunc: func(old, new interface{}) {
+			oldCRD, ok := old.(*apiextensionsv1.CustomResourceDefinition)
+			newCRD, ok2 := new.(*apiextensionsv1.CustomResourceDefinition)
+			if ok && ok2 && util.GetCRDUsage(oldCRD) != util.GetCRDUsage(newCRD) {
+				enqueue(new)
+			}
+		},
+		DeleteFunc: enqueue,
+	})
+	tc.crdInformer = crdInformer
+}
+
+// listTenantCRDs lists the upstream crds of the tenant from the informer.
+func (tc *TenantController) listTenantCRDs(tenantID string) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	objs, err := tc.crdInformer.GetIndexer().ByIndex(CRDTenantIndex, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	crds := make([]*apiextensionsv1.CustomResourceDefinition, 0, len(objs))
+	for _, obj := range objs {
+		if crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition); ok {
+			crds = append(crds, crd)
+		}
+	}
+	return crds, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/generated/clientset/versioned/fake"
+)
+
+// TestSyncCRDUsage tests the crd usage of the tenant is counted from the
+// crds of the tenant in the informer only, and the tenant is enqueued once
+// its crds are added or deleted.
+func TestSyncCRDUsage(t *testing.T) {
+	newCRD := func(name string, versions ...string) *apiextensionsv1.CustomResourceDefinition {
+		crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		for _, version := range versions {
+			crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{Name: version, Served: true})
+		}
+		return crd
+	}
+	crdCli := apiextensionsfake.NewSimpleClientset(
+		newCRD("foos.111111-example.com", "v1", "v2"),
+		newCRD("bars.111111-example.com", "v1"),
+		newCRD("foos.222222-example.com", "v1"),
+		newCRD("foos.example.com", "v1"),
+	)
+	tenantCli := fake.NewSimpleClientset(&tenantv1alpha1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "111111"}})
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	tc := &TenantController{queue: queue, tenantClient: tenantCli.TenantV1alpha1()}
+	crdInformer := apiextensionsinformers.NewCustomResourceDefinitionInformer(crdCli, 0,
+		cache.Indexers{CRDTenantIndex: CRDTenantIndexFunc})
+	tc.setCRDInformer(crdInformer)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go crdInformer.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, crdInformer.HasSynced) {
+		t.Fatal("failed to sync the informer")
+	}
+	expectEnqueued := func(tenantIDs ...string) {
+		enqueued := map[string]bool{}
+		for queue.Len() > 0 {
+			item, _ := queue.Get()
+			if e, ok := item.(Event); ok && e.eventType == SyncCRDUsage {
+				enqueued[e.tenantId] = true
+			}
+			queue.Done(item)
+		}
+		if len(enqueued) != len(tenantIDs) {
+			t.Errorf("expect tenants %v enqueued, got %v", tenantIDs, enqueued)
+		}
+		for _, tenantID := range tenantIDs {
+			if !enqueued[tenantID] {
+				t.Errorf("expect tenant %s enqueued, got %v", tenantID, enqueued)
+			}
+		}
+	}
+	expectEnqueued("111111", "222222")
+
+	if err := tc.syncCRDUsage("111111"); err != nil {
+		t.Fatalf("failed to sync crd usage: %v", err)
+	}
+	tenant, err := tenantCli.TenantV1alpha1().Tenants().Get(context.TODO(), "111111", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenant.Status.CRDUsage == nil || tenant.Status.CRDUsage.CRDs != 2 || tenant.Status.CRDUsage.ServedVersions != 3 {
+		t.Errorf("expect 2 crds with 3 served versions, got %+v", tenant.Status.CRDUsage)
+	}
+	// the usage is written to the status only, and the unchanged usage
+	// is not written again
+	if err := tc.syncCRDUsage("111111"); err != nil {
+		t.Fatalf("failed to sync crd usage: %v", err)
+	}
+	updates := 0
+	for _, action := range tenantCli.Actions() {
+		if action.GetVerb() != "update" {
+			continue
+		}
+		updates++
+		if action.GetSubresource() != "status" {
+			t.Errorf("expect update of the tenant status, got %v", action)
+		}
+	}
+	if updates != 1 {
+		t.Errorf("expect 1 update of the tenant status, got %d", updates)
+	}
+	for _, action := range crdCli.Actions() {
+		if action.GetVerb() != "list" && action.GetVerb() != "watch" {
+			t.Errorf("unexpected action on the crds: %v", action)
+		}
+	}
+
+	// the owning tenant is enqueued once its crd is deleted
+	if err := crdCli.ApiextensionsV1().CustomResourceDefinitions().Delete(context.TODO(), "bars.111111-example.com", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return queue.Len() > 0, nil
+	}); err != nil {
+		t.Fatal("tenant is not enqueued on the crd deletion")
+	}
+	expectEnqueued("111111")
+	if err := tc.syncCRDUsage("111111"); err != nil {
+		t.Fatalf("failed to sync crd usage: %v", err)
+	}
+	tenant, err = tenantCli.TenantV1alpha1().Tenants().Get(context.TODO(), "111111", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenant.Status.CRDUsage == nil || tenant.Status.CRDUsage.CRDs != 1 || tenant.Status.CRDUsage.ServedVersions != 2 {
+		t.Errorf("expect 1 crd with 2 served versions, got %+v", tenant.Status.CRDUsage)
+	}
+}
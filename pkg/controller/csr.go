@@ -0,0 +1,231 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	certificatesinformers "k8s.io/client-go/informers/certificates/v1"
+	"k8s.io/client-go/kubernetes"
+	certificatesclient "k8s.io/client-go/kubernetes/typed/certificates/v1"
+	certificateslisters "k8s.io/client-go/listers/certificates/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	tenantlister "github.com/kubewharf/kubezoo/pkg/generated/listers/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// CSRSigningController signs the approved certificate signing requests of
+// the tenant client signer in the upstream cluster with the client CA of
+// kubezoo, so that the tenant admins are able to onboard users by approving
+// their certificate signing requests.
+type CSRSigningController struct {
+	queue          workqueue.RateLimitingInterface
+	csrInformer    cache.SharedIndexInformer
+	csrLister      certificateslisters.CertificateSigningRequestLister
+	csrClient      certificatesclient.CertificateSigningRequestInterface
+	tenantInformer cache.SharedIndexInformer
+	tenantLister   tenantlister.TenantLister
+	caSigner       util.CASigner
+	// maxValidity is the max validity of the issued certificates, which
+	// can only be shortened by the expirationSeconds of the requests.
+	maxValidity time.Duration
+}
+
+// NewCSRSigningController creates a controller to sign the certificate
+// signing requests of the tenant client signer.
+func NewCSRSigningController(typedCli kubernetes.Interface, ti cache.SharedIndexInformer, caSigner util.CASigner, maxValidity time.Duration) *CSRSigningController {
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	csrInformer := certificatesinformers.NewFilteredCertificateSigningRequestInformer(typedCli, 0, cache.Indexers{},
+		func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("spec.signerName", util.TenantClientSignerName).String()
+		})
+	enqueue := func(obj interface{}) {
+		key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+		if err == nil {
+			queue.Add(key)
+		}
+	}
+	csrInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueue,
+		UpdateFunc: func(_, new interface{}) {
+			enqueue(new)
+		},
+	})
+
+	return &CSRSigningController{
+		queue:          queue,
+		csrInformer:    csrInformer,
+		csrLister:      certificateslisters.NewCertificateSigningRequestLister(csrInformer.GetIndexer()),
+		csrClient:      typedCli.CertificatesV1().CertificateSigningRequests(),
+		tenantInformer: ti,
+		tenantLister:   tenantlister.NewTenantLister(ti.GetIndexer()),
+		caSigner:       caSigner,
+		maxValidity:    maxValidity,
+	}
+}
+
+// Run starts the csr signing controller, the tenant informer is expected to
+// be run by the tenant controller.
+func (c *CSRSigningController) Run(stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.V(4).Info("Starting CSR signing controller")
+
+	go c.csrInformer.Run(stopCh)
+
+	if !cache.WaitForCacheSync(stopCh, c.csrInformer.HasSynced, c.tenantInformer.HasSynced) {
+		utilruntime.HandleError(fmt.Errorf("Timed out waiting for caches to sync"))
+		return
+	}
+
+	klog.V(4).Info("CSR signing controller synced and ready")
+	wait.Until(c.runWorker, time.Second, stopCh)
+}
+
+// runWorker start to process the csrs.
+func (c *CSRSigningController) runWorker() {
+	for c.processNextItem() {
+	}
+}
+
+// processNextItem gets csr from queue and process it.
+func (c *CSRSigningController) processNextItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	err := c.sync(key.(string))
+	if err == nil {
+		c.queue.Forget(key)
+	} else if c.queue.NumRequeues(key) < maxRetries {
+		klog.Errorf("Error signing csr %s (will retry): %v", key, err)
+		c.queue.AddRateLimited(key)
+	} else {
+		klog.Errorf("Error signing csr %s (giving up): %v", key, err)
+		c.queue.Forget(key)
+		utilruntime.HandleError(err)
+	}
+	return true
+}
+
+// sync signs the csr if it is approved and not signed yet. The csrs which
+// are not allowed to be signed are marked as failed.
+func (c *CSRSigningController) sync(name string) error {
+	csr, err := c.csrLister.Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if csr.Spec.SignerName != util.TenantClientSignerName || len(csr.Status.Certificate) != 0 ||
+		!isCSRApproved(csr) || hasCSRCondition(csr, certificatesv1.CertificateFailed) {
+		return nil
+	}
+
+	tenantID, err := util.GetTenantIDFromNamespace(csr.Name)
+	if err != nil {
+		return c.fail(csr, "TenantNotFound", "the csr is not created by a tenant")
+	}
+	tenant, err := c.tenantLister.Get(tenantID)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return c.fail(csr, "TenantNotFound", fmt.Sprintf("tenant %s is not found", tenantID))
+		}
+		return err
+	}
+	if tenant.DeletionTimestamp != nil {
+		return c.fail(csr, "TenantNotFound", fmt.Sprintf("tenant %s is being deleted", tenantID))
+	}
+
+	x509CSR, err := util.ParseCSR(csr.Spec.Request)
+	if err != nil {
+		return c.fail(csr, "InvalidRequest", err.Error())
+	}
+	validity := c.maxValidity
+	if csr.Spec.ExpirationSeconds != nil {
+		if d := time.Duration(*csr.Spec.ExpirationSeconds) * time.Second; d < validity {
+			validity = d
+		}
+	}
+	config, err := util.NewTenantCSRCertConfig(tenant, x509CSR, validity)
+	if err != nil {
+		return c.fail(csr, "InvalidRequest", err.Error())
+	}
+
+	caCert, caKey, err := c.caSigner.CA()
+	if err != nil {
+		return err
+	}
+	cert, err := util.NewSignedCertForPublicKey(config, x509CSR.PublicKey, caCert, caKey)
+	if err != nil {
+		return err
+	}
+
+	csr = csr.DeepCopy()
+	csr.Status.Certificate = util.EncodeCertPEM(cert)
+	if _, err := c.csrClient.UpdateStatus(context.TODO(), csr, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	klog.V(4).Infof("signed csr %s for user %s", csr.Name, config.CommonName)
+	return nil
+}
+
+// fail marks the csr as failed with the reason.
+func (c *CSRSigningController) fail(csr *certificatesv1.CertificateSigningRequest, reason, message string) error {
+	klog.V(4).Infof("failed to sign csr %s: %s", csr.Name, message)
+	csr = csr.DeepCopy()
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Type:           certificatesv1.CertificateFailed,
+		Status:         corev1.ConditionTrue,
+		Reason:         reason,
+		Message:        message,
+		LastUpdateTime: metav1.Now(),
+	})
+	_, err := c.csrClient.UpdateStatus(context.TODO(), csr, metav1.UpdateOptions{})
+	return err
+}
+
+// isCSRApproved returns true if the csr is approved and not denied.
+func isCSRApproved(csr *certificatesv1.CertificateSigningRequest) bool {
+	return hasCSRCondition(csr, certificatesv1.CertificateApproved) && !hasCSRCondition(csr, certificatesv1.CertificateDenied)
+}
+
+// hasCSRCondition returns true if the csr has the true condition of the type.
+func hasCSRCondition(csr *certificatesv1.CertificateSigningRequest, conditionType certificatesv1.RequestConditionType) bool {
+	for _, c := range csr.Status.Conditions {
+		if c.Type == conditionType && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// writeTestCA writes a self signed CA into the dir and returns the paths of
+// the certificate and the key.
+func writeTestCA(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	cert, err := certutil.NewSelfSignedCACert(certutil.Config{CommonName: "test-ca"}, key)
+	if err != nil {
+		t.Fatalf("failed to create ca: %v", err)
+	}
+	keyPEM, err := keyutil.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	certFile, keyFile := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+	if err := os.WriteFile(certFile, util.EncodeCertPEM(cert), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func newTestCSRObject(t *testing.T, name, commonName string, approved bool) *certificatesv1.CertificateSigningRequest {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: commonName, Organization: []string{"system:masters"}},
+	}, key)
+	if err != nil {
+		t.Fatalf("failed to create csr: %v", err)
+	}
+	csr := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}),
+			SignerName: util.TenantClientSignerName,
+			Usages:     []certificatesv1.KeyUsage{certificatesv1.UsageClientAuth},
+		},
+	}
+	if approved {
+		csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{{
+			Type:   certificatesv1.CertificateApproved,
+			Status: corev1.ConditionTrue,
+		}}
+	}
+	return csr
+}
+
+// TestCSRSigningControllerSync tests the csr signing controller signs the
+// approved csrs of the existing tenants and fails the invalid ones.
+func TestCSRSigningControllerSync(t *testing.T) {
+	caFile, caKeyFile := writeTestCA(t, t.TempDir())
+	tenant := &tenantv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "111111"},
+		Spec: tenantv1alpha1.TenantSpec{
+			Users: []tenantv1alpha1.TenantUser{{Name: "alice"}},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		csr          *certificatesv1.CertificateSigningRequest
+		expectCN     string
+		expectReason string
+	}{
+		{
+			name:     "approved csr",
+			csr:      newTestCSRObject(t, "111111-carol", "carol", true),
+			expectCN: "111111-carol",
+		},
+		{
+			name: "pending csr",
+			csr:  newTestCSRObject(t, "111111-carol", "carol", false),
+		},
+		{
+			name:         "csr of unknown tenant",
+			csr:          newTestCSRObject(t, "222222-carol", "carol", true),
+			expectReason: "TenantNotFound",
+		},
+		{
+			name:         "csr of admin",
+			csr:          newTestCSRObject(t, "111111-admin", util.TenantAdminUserName, true),
+			expectReason: "InvalidRequest",
+		},
+		{
+			name:         "csr of named user",
+			csr:          newTestCSRObject(t, "111111-alice", "alice", true),
+			expectReason: "InvalidRequest",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(test.csr)
+			tenantInformer := cache.NewSharedIndexInformer(&cache.ListWatch{}, &tenantv1alpha1.Tenant{}, 0, cache.Indexers{})
+			if err := tenantInformer.GetIndexer().Add(tenant); err != nil {
+				t.Fatal(err)
+			}
+			c := NewCSRSigningController(client, tenantInformer, util.NewFileCASigner(caFile, caKeyFile), time.Hour)
+			if err := c.csrInformer.GetIndexer().Add(test.csr); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := c.sync(test.csr.Name); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			csr, err := client.CertificatesV1().CertificateSigningRequests().Get(context.TODO(), test.csr.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(test.expectReason) != 0 {
+				if !hasCSRCondition(csr, certificatesv1.CertificateFailed) ||
+					csr.Status.Conditions[len(csr.Status.Conditions)-1].Reason != test.expectReason {
+					t.Errorf("expect failed condition with reason %s, got %+v", test.expectReason, csr.Status.Conditions)
+				}
+			}
+			if len(test.expectCN) == 0 {
+				if len(csr.Status.Certificate) != 0 {
+					t.Errorf("expect no certificate issued")
+				}
+				return
+			}
+			certs, err := certutil.ParseCertsPEM(csr.Status.Certificate)
+			if err != nil {
+				t.Fatalf("failed to parse certificate: %v", err)
+			}
+			if certs[0].Subject.CommonName != test.expectCN {
+				t.Errorf("expect common name %s, got %s", test.expectCN, certs[0].Subject.CommonName)
+			}
+			if len(certs[0].Subject.Organization) != 0 {
+				t.Errorf("expect no organization, got %v", certs[0].Subject.Organization)
+			}
+			if !util.IsTenantCSRCertificate(certs[0]) {
+				t.Errorf("expect certificate issued by the tenant client signer, got %v", certs[0].Subject.OrganizationalUnit)
+			}
+			if validity := certs[0].NotAfter.Sub(certs[0].NotBefore); validity > time.Hour {
+				t.Errorf("expect validity no longer than %v, got %v", time.Hour, validity)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/common"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// addLimitRangeInformers adds the informers of the namespaces of the tenants
+// and the default limit ranges in them, which the limit ranges are synced
+// from. The tenant is enqueued once one of its namespaces appears, or its
+// default limit range is changed or deleted, so that the default limit range
+// is synced into the new namespaces and restored in the others.
+func (tc *TenantController) addLimitRangeInformers(typedCli kubernetes.Interface) {
+	enqueue := func(tenantID string) {
+		if tenantID != "" {
+			tc.queue.Add(Event{tenantId: tenantID, eventType: SyncLimitRanges})
+		}
+	}
+	tenantOf := func(obj interface{}) string {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return ""
+		}
+		return accessor.GetLabels()[common.TenantNamespaceLabelKey]
+	}
+	// the label of the limit range may be removed by the change, the
+	// tenant is found by the old object or the namespace instead
+	tenantOfLimitRange := func(objs ...interface{}) string {
+		for _, obj := range objs {
+			if tenantID := tenantOf(obj); tenantID != "" {
+				return tenantID
+			}
+		}
+		obj := objs[0]
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return ""
+		}
+		namespace, err := tc.namespaceLister.Get(accessor.GetNamespace())
+		if err != nil {
+			return ""
+		}
+		return namespace.Labels[common.TenantNamespaceLabelKey]
+	}
+
+	namespaceInformer := coreinformers.NewFilteredNamespaceInformer(typedCli, 0, cache.Indexers{},
+		func(options *metav1.ListOptions) {
+			options.LabelSelector = common.TenantNamespaceLabelKey
+		})
+	namespaceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			enqueue(tenantOf(obj))
+		},
+		UpdateFunc: func(old, new interface{}) {
+			if tenantID := tenantOf(new); tenantID != tenantOf(old) {
+				enqueue(tenantID)
+			}
+		},
+	})
+
+	limitRangeInformer := coreinformers.NewFilteredLimitRangeInformer(typedCli, metav1.NamespaceAll, 0, cache.Indexers{},
+		func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", util.TenantLimitRangeName).String()
+		})
+	limitRangeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			enqueue(tenantOfLimitRange(new, old))
+		},
+		DeleteFunc: func(obj interface{}) {
+			enqueue(tenantOfLimitRange(obj))
+		},
+	})
+
+	tc.limitRangeInformers = []cache.SharedIndexInformer{namespaceInformer, limitRangeInformer}
+	tc.namespaceLister = corelisters.NewNamespaceLister(namespaceInformer.GetIndexer())
+	tc.limitRangeLister = corelisters.NewLimitRangeLister(limitRangeInformer.GetIndexer())
+}
+
+// syncLimitRanges syncs the default limit range of the tenant into each
+// namespace of the tenant, or deletes them if the tenant has none. The
+// tenant, its namespaces and the limit ranges are read from the informers.
+func (tc *TenantController) syncLimitRanges(tenantID string) error {
+	if tc.upstreamCoreClient == nil || tc.namespaceLister == nil || tc.limitRangeLister == nil {
+		klog.Warning("Skip synchronize limit ranges since nil core client or listers.")
+		return nil
+	}
+
+	tenant, err := tc.tenantLister.Get(tenantID)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !tenant.DeletionTimestamp.IsZero() {
+		// the namespaces are deleted along with the tenant
+		return nil
+	}
+
+	namespaces, err := tc.namespaceLister.List(labels.SelectorFromSet(labels.Set{common.TenantNamespaceLabelKey: tenantID}))
+	if err != nil {
+		return err
+	}
+	for _, namespace := range namespaces {
+		if namespace.Status.Phase == corev1.NamespaceTerminating {
+			continue
+		}
+		if err := tc.syncOneLimitRange(tenant, namespace.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncOneLimitRange creates, updates or deletes the default limit range of
+// the tenant in the namespace as expected.
+func (tc *TenantController) syncOneLimitRange(tenant *tenantv1alpha1.Tenant, namespace string) error {
+	client := tc.upstreamCoreClient.LimitRanges(namespace)
+	limitRange, err := tc.limitRangeLister.LimitRanges(namespace).Get(util.TenantLimitRangeName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	notFound := apierrors.IsNotFound(err)
+
+	if tenant.Spec.LimitRange == nil {
+		if notFound {
+			return nil
+		}
+		err := client.Delete(context.TODO(), util.TenantLimitRangeName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		klog.Infof("delete limit range %s/%s of tenant %s", namespace, util.TenantLimitRangeName, tenant.Name)
+		return nil
+	}
+
+	if notFound {
+		// create
+		_, err := client.Create(context.TODO(), newTenantLimitRange(tenant, namespace), metav1.CreateOptions{})
+		return err
+	}
+
+	// update, the conflicts are retried by the queue
+	if apiequality.Semantic.DeepEqual(limitRange.Spec, *tenant.Spec.LimitRange) &&
+		limitRange.Labels[common.TenantNamespaceLabelKey] == tenant.Name {
+		return nil
+	}
+	limitRange = limitRange.DeepCopy()
+	limitRange.Spec = *tenant.Spec.LimitRange.DeepCopy()
+	if limitRange.Labels == nil {
+		limitRange.Labels = make(map[string]string)
+	}
+	limitRange.Labels[common.TenantNamespaceLabelKey] = tenant.Name
+	_, err = client.Update(context.TODO(), limitRange, metav1.UpdateOptions{})
+	return err
+}
+
+// newTenantLimitRange returns the expected default limit range of the
+// tenant in the namespace.
+func newTenantLimitRange(tenant *tenantv1alpha1.Tenant, namespace string) *corev1.LimitRange {
+	return &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.TenantLimitRangeName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				common.TenantNamespaceLabelKey: tenant.Name,
+			},
+		},
+		Spec: *tenant.Spec.LimitRange.DeepCopy(),
+	}
+}
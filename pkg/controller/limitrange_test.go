@@ -0,0 +1,181 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/common"
+	tenantlister "github.com/kubewharf/kubezoo/pkg/generated/listers/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// TestSyncLimitRanges tests the default limit range of the tenant is synced
+// into each namespace of the tenant, restored once it is modified, and
+// deleted once it is removed from the tenant.
+func TestSyncLimitRanges(t *testing.T) {
+	tenant := &tenantv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "111111"},
+		Spec: tenantv1alpha1.TenantSpec{
+			LimitRange: &corev1.LimitRangeSpec{
+				Limits: []corev1.LimitRangeItem{{
+					Type:           corev1.LimitTypeContainer,
+					Default:        corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+					DefaultRequest: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				}},
+			},
+		},
+	}
+	newNamespace := func(name, tenantID string, phase corev1.NamespacePhase) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{common.TenantNamespaceLabelKey: tenantID},
+			},
+			Status: corev1.NamespaceStatus{Phase: phase},
+		}
+	}
+	kubeCli := kubefake.NewSimpleClientset(
+		newNamespace("111111-default", "111111", corev1.NamespaceActive),
+		newNamespace("111111-team", "111111", corev1.NamespaceActive),
+		newNamespace("111111-deleting", "111111", corev1.NamespaceTerminating),
+		newNamespace("222222-default", "222222", corev1.NamespaceActive),
+	)
+	tenantIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := tenantIndexer.Add(tenant); err != nil {
+		t.Fatal(err)
+	}
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	tc := &TenantController{
+		queue:              queue,
+		tenantLister:       tenantlister.NewTenantLister(tenantIndexer),
+		upstreamCoreClient: kubeCli.CoreV1(),
+	}
+	tc.addLimitRangeInformers(kubeCli)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	for _, informer := range tc.limitRangeInformers {
+		go informer.Run(stopCh)
+	}
+	if !cache.WaitForCacheSync(stopCh, tc.limitRangeInformers[0].HasSynced, tc.limitRangeInformers[1].HasSynced) {
+		t.Fatal("failed to sync the informers")
+	}
+	getLimitRange := func(namespace string) (*corev1.LimitRange, error) {
+		return kubeCli.CoreV1().LimitRanges(namespace).Get(context.TODO(), util.TenantLimitRangeName, metav1.GetOptions{})
+	}
+	// syncLimitRanges syncs the limit ranges once the informer catches up
+	// with the upstream cluster
+	syncLimitRanges := func() {
+		err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+			for _, namespace := range []string{"111111-default", "111111-team"} {
+				expected, err := getLimitRange(namespace)
+				if err != nil && !apierrors.IsNotFound(err) {
+					return false, err
+				}
+				cached, cachedErr := tc.limitRangeLister.LimitRanges(namespace).Get(util.TenantLimitRangeName)
+				if apierrors.IsNotFound(err) != apierrors.IsNotFound(cachedErr) ||
+					(err == nil && cached.ResourceVersion != expected.ResourceVersion) {
+					return false, nil
+				}
+			}
+			return true, nil
+		})
+		if err != nil {
+			t.Fatalf("failed to wait for the informer: %v", err)
+		}
+		if err := tc.syncLimitRanges(tenant.Name); err != nil {
+			t.Fatalf("failed to sync limit ranges: %v", err)
+		}
+	}
+
+	syncLimitRanges()
+	for _, namespace := range []string{"111111-default", "111111-team"} {
+		limitRange, err := getLimitRange(namespace)
+		if err != nil {
+			t.Fatalf("expect the limit range in namespace %s: %v", namespace, err)
+		}
+		if !apiequality.Semantic.DeepEqual(limitRange.Spec, *tenant.Spec.LimitRange) {
+			t.Errorf("expect the limit range of the tenant in namespace %s, got %v", namespace, limitRange.Spec)
+		}
+		if limitRange.Labels[common.TenantNamespaceLabelKey] != tenant.Name {
+			t.Errorf("expect the limit range in namespace %s labeled with the tenant, got %v", namespace, limitRange.Labels)
+		}
+	}
+	for _, namespace := range []string{"111111-deleting", "222222-default"} {
+		if _, err := getLimitRange(namespace); !apierrors.IsNotFound(err) {
+			t.Errorf("expect no limit range in namespace %s, got %v", namespace, err)
+		}
+	}
+
+	// the loosened limit range is restored
+	loosened, err := getLimitRange("111111-team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loosened.Spec.Limits = nil
+	loosened.Labels = nil
+	if _, err := kubeCli.CoreV1().LimitRanges("111111-team").Update(context.TODO(), loosened, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	syncLimitRanges()
+	// the tenant is enqueued by the old object, although the label is removed
+	enqueued := false
+	for queue.Len() > 0 {
+		item, _ := queue.Get()
+		if item == (Event{tenantId: tenant.Name, eventType: SyncLimitRanges}) {
+			enqueued = true
+		}
+		queue.Done(item)
+	}
+	if !enqueued {
+		t.Errorf("expect tenant %s enqueued by the change of its limit range", tenant.Name)
+	}
+	restored, err := getLimitRange("111111-team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !apiequality.Semantic.DeepEqual(restored.Spec, *tenant.Spec.LimitRange) {
+		t.Errorf("expect the limit range restored, got %v", restored.Spec)
+	}
+
+	// the limit ranges are deleted along with the one of the tenant
+	tenant = tenant.DeepCopy()
+	tenant.Spec.LimitRange = nil
+	if err := tenantIndexer.Update(tenant); err != nil {
+		t.Fatal(err)
+	}
+	syncLimitRanges()
+	for _, namespace := range []string{"111111-default", "111111-team"} {
+		if _, err := getLimitRange(namespace); !apierrors.IsNotFound(err) {
+			t.Errorf("expect the limit range in namespace %s deleted, got %v", namespace, err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	quotav1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1"
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/common"
+	"github.com/kubewharf/kubezoo/pkg/generated/clientset/versioned/fake"
+)
+
+// TestSyncScopedClusterResourceQuota tests a cluster resource quota is
+// synced for each scoped quota of the tenant, the ones of the removed scoped
+// quotas are deleted, and the usage is aggregated per scope in the status.
+func TestSyncScopedClusterResourceQuota(t *testing.T) {
+	tenant := &tenantv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "111111"},
+		Spec: tenantv1alpha1.TenantSpec{
+			Quota: tenantv1alpha1.TenantQuota{
+				Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+				Scoped: []tenantv1alpha1.TenantScopedQuota{
+					{
+						Name:   "best-effort",
+						Hard:   corev1.ResourceList{corev1.ResourcePods: resource.MustParse("2")},
+						Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort},
+					},
+					{
+						Name: "high",
+						Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4")},
+						ScopeSelector: &corev1.ScopeSelector{
+							MatchExpressions: []corev1.ScopedResourceSelectorRequirement{{
+								ScopeName: corev1.ResourceQuotaScopePriorityClass,
+								Operator:  corev1.ScopeSelectorOpIn,
+								Values:    []string{"high"},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+	stale := &quotav1alpha1.ClusterResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   tenantQuotaName(tenant.Name, "removed"),
+			Labels: map[string]string{common.TenantNamespaceLabelKey: tenant.Name},
+		},
+	}
+	other := &quotav1alpha1.ClusterResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   tenantQuotaName("222222", "removed"),
+			Labels: map[string]string{common.TenantNamespaceLabelKey: "222222"},
+		},
+	}
+	cli := fake.NewSimpleClientset(tenant, stale, other)
+	tc := &TenantController{tenantClient: cli.TenantV1alpha1(), clusterquotaCli: cli.QuotaV1alpha1()}
+
+	if err := tc.syncClusterResourceQuota(tenant.Name); err != nil {
+		t.Fatalf("failed to sync cluster resource quota: %v", err)
+	}
+	quotaList, err := cli.QuotaV1alpha1().ClusterResourceQuotas().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := sets.NewString()
+	for _, quota := range quotaList.Items {
+		names.Insert(quota.Name)
+	}
+	expectNames := sets.NewString(
+		tenantQuotaName(tenant.Name, ""),
+		tenantQuotaName(tenant.Name, "best-effort"),
+		tenantQuotaName(tenant.Name, "high"),
+		other.Name,
+	)
+	if !names.Equal(expectNames) {
+		t.Fatalf("expect cluster resource quotas %v, got %v", expectNames.List(), names.List())
+	}
+
+	bestEffort, err := cli.QuotaV1alpha1().ClusterResourceQuotas().Get(context.TODO(), tenantQuotaName(tenant.Name, "best-effort"), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bestEffort.Spec.Scopes) != 1 || bestEffort.Spec.Scopes[0] != corev1.ResourceQuotaScopeBestEffort {
+		t.Errorf("expect the BestEffort scope, got %v", bestEffort.Spec.Scopes)
+	}
+	if selector := bestEffort.Spec.NamepsaceSelector; selector == nil || selector.MatchLabels[common.TenantNamespaceLabelKey] != tenant.Name {
+		t.Errorf("expect the namespaces of the tenant are selected, got %v", selector)
+	}
+
+	bestEffort.Status.Hard = bestEffort.Spec.Hard
+	bestEffort.Status.Used = corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")}
+	if _, err := cli.QuotaV1alpha1().ClusterResourceQuotas().UpdateStatus(context.TODO(), bestEffort, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tc.syncQuotaStatus(tenant.Name); err != nil {
+		t.Fatalf("failed to sync quota status: %v", err)
+	}
+	got, err := cli.TenantV1alpha1().Tenants().Get(context.TODO(), tenant.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Status.Quota) != 3 {
+		t.Fatalf("expect 3 quota statuses, got %v", got.Status.Quota)
+	}
+	if s := got.Status.Quota[1]; s.Name != "best-effort" || !s.Used.Pods().Equal(resource.MustParse("1")) {
+		t.Errorf("expect 1 pod used by the best-effort quota, got %v", s)
+	}
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/kubewharf/kubezoo/pkg/dynamic"
 	tenantclientset "github.com/kubewharf/kubezoo/pkg/generated/clientset/versioned"
 	tenantinformer "github.com/kubewharf/kubezoo/pkg/generated/informers/externalversions/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
 )
 
 var (
@@ -145,8 +146,11 @@ var _ = BeforeSuite(func() {
 			dynamicClient,
 			crdClient,
 			nil,
+			nil,
 			clientCACert,
-			clientCAKey,
+			util.NewFileCASigner(clientCACert, clientCAKey),
+			util.KeyAlgorithmRSA,
+			util.CertificateValidity,
 			host,
 			portInt,
 		)
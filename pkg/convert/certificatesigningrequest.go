@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package convert
+
+import (
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/authentication/user"
+	certificatesinternal "k8s.io/kubernetes/pkg/apis/certificates"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// CertificateSigningRequestTransformer implements the transformation between
+// client and upstream server for CertificateSigningRequest resource.
+type CertificateSigningRequestTransformer struct{}
+
+var _ ObjectTransformer = &CertificateSigningRequestTransformer{}
+
+// NewCertificateSigningRequestTransformer initiates a
+// CertificateSigningRequestTransformer which implements the
+// ObjectTransformer interfaces.
+func NewCertificateSigningRequestTransformer() ObjectTransformer {
+	return &CertificateSigningRequestTransformer{}
+}
+
+// Forward transforms the tenant csr to the upstream csr.
+// The tenants may only request the tenant client signer, as the certificates
+// of the other signers are not trusted by kubezoo, and those issued by the
+// upstream CA even grant access to the upstream cluster.
+func (t *CertificateSigningRequestTransformer) Forward(obj runtime.Object, tenantID string) (runtime.Object, error) {
+	csr, ok := obj.(*certificatesinternal.CertificateSigningRequest)
+	if !ok {
+		return nil, errors.Errorf("fail to assert the runtime object to the internal version of certificatesigningrequest")
+	}
+
+	if len(csr.Spec.SignerName) == 0 {
+		csr.Spec.SignerName = util.TenantClientSignerName
+	}
+	if csr.Spec.SignerName != util.TenantClientSignerName {
+		return nil, apierrors.NewInvalid(certificatesinternal.Kind("CertificateSigningRequest"), csr.Name, field.ErrorList{
+			field.NotSupported(field.NewPath("spec", "signerName"), csr.Spec.SignerName, []string{util.TenantClientSignerName}),
+		})
+	}
+
+	csr.Spec.Username = util.AddTenantIDToUserName(tenantID, csr.Spec.Username)
+	csr.Spec.Groups = util.AddTenantIDToGroups(tenantID, csr.Spec.Groups)
+	return csr, nil
+}
+
+// Backward transforms the upstream csr to the tenant csr.
+func (t *CertificateSigningRequestTransformer) Backward(obj runtime.Object, tenantID string) (runtime.Object, error) {
+	csr, ok := obj.(*certificatesinternal.CertificateSigningRequest)
+	if !ok {
+		return nil, errors.Errorf("fail to assert the runtime object to the internal version of certificatesigningrequest")
+	}
+
+	if len(csr.Spec.Username) == 0 {
+		return csr, nil
+	}
+	u := util.TrimTenantIDFromUserInfo(tenantID, &user.DefaultInfo{
+		Name:   csr.Spec.Username,
+		Groups: csr.Spec.Groups,
+	})
+	csr.Spec.Username = u.GetName()
+	if len(csr.Spec.Groups) != 0 {
+		csr.Spec.Groups = u.GetGroups()
+	}
+	return csr, nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package convert
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	certificatesinternal "k8s.io/kubernetes/pkg/apis/certificates"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// TestCertificateSigningRequestTransformerForward tests the forward method
+// of the CertificateSigningRequestTransformer.
+func TestCertificateSigningRequestTransformerForward(t *testing.T) {
+	cases := []struct {
+		name    string
+		tenant  string
+		in      certificatesinternal.CertificateSigningRequest
+		want    certificatesinternal.CertificateSigningRequest
+		wantErr bool
+	}{
+		{
+			name:   "test forward csr without signer",
+			tenant: "111111",
+			in: certificatesinternal.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "111111-carol"},
+				Spec: certificatesinternal.CertificateSigningRequestSpec{
+					Username: "admin",
+					Groups:   []string{"system:authenticated"},
+				},
+			},
+			want: certificatesinternal.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "111111-carol"},
+				Spec: certificatesinternal.CertificateSigningRequestSpec{
+					SignerName: util.TenantClientSignerName,
+					Username:   "111111-admin",
+					Groups:     []string{"system:authenticated"},
+				},
+			},
+		},
+		{
+			name:   "test forward csr of service account",
+			tenant: "111111",
+			in: certificatesinternal.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "111111-carol"},
+				Spec: certificatesinternal.CertificateSigningRequestSpec{
+					SignerName: util.TenantClientSignerName,
+					Username:   "system:serviceaccount:default:robot",
+					Groups:     []string{"system:serviceaccounts", "system:serviceaccounts:default"},
+				},
+			},
+			want: certificatesinternal.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "111111-carol"},
+				Spec: certificatesinternal.CertificateSigningRequestSpec{
+					SignerName: util.TenantClientSignerName,
+					Username:   "system:serviceaccount:111111-default:robot",
+					Groups:     []string{"system:serviceaccounts", "system:serviceaccounts:111111-default"},
+				},
+			},
+		},
+		{
+			name:   "test forward csr of other signer",
+			tenant: "111111",
+			in: certificatesinternal.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "111111-carol"},
+				Spec: certificatesinternal.CertificateSigningRequestSpec{
+					SignerName: "kubernetes.io/kube-apiserver-client",
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := NewCertificateSigningRequestTransformer()
+			_, err := e.Forward(&c.in, c.tenant)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expect error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to forward csr, err: %+v", err)
+			}
+			if !reflect.DeepEqual(c.in, c.want) {
+				t.Errorf("got %+v, want %+v", c.in, c.want)
+			}
+		})
+	}
+}
+
+// TestCertificateSigningRequestTransformerBackward tests the backward method
+// of the CertificateSigningRequestTransformer.
+func TestCertificateSigningRequestTransformerBackward(t *testing.T) {
+	cases := []struct {
+		name   string
+		tenant string
+		in     certificatesinternal.CertificateSigningRequest
+		want   certificatesinternal.CertificateSigningRequest
+	}{
+		{
+			name:   "test backward csr",
+			tenant: "111111",
+			in: certificatesinternal.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "111111-carol"},
+				Spec: certificatesinternal.CertificateSigningRequestSpec{
+					SignerName: util.TenantClientSignerName,
+					Username:   "111111-admin",
+				},
+			},
+			want: certificatesinternal.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "111111-carol"},
+				Spec: certificatesinternal.CertificateSigningRequestSpec{
+					SignerName: util.TenantClientSignerName,
+					Username:   "admin",
+				},
+			},
+		},
+		{
+			name:   "test backward csr of service account",
+			tenant: "111111",
+			in: certificatesinternal.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "111111-carol"},
+				Spec: certificatesinternal.CertificateSigningRequestSpec{
+					SignerName: util.TenantClientSignerName,
+					Username:   "system:serviceaccount:111111-default:robot",
+					Groups:     []string{"system:serviceaccounts", "system:serviceaccounts:111111-default"},
+				},
+			},
+			want: certificatesinternal.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "111111-carol"},
+				Spec: certificatesinternal.CertificateSigningRequestSpec{
+					SignerName: util.TenantClientSignerName,
+					Username:   "system:serviceaccount:default:robot",
+					Groups:     []string{"system:serviceaccounts", "system:serviceaccounts:default"},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := NewCertificateSigningRequestTransformer()
+			if _, err := e.Backward(&c.in, c.tenant); err != nil {
+				t.Fatalf("failed to backward csr, err: %+v", err)
+			}
+			if !reflect.DeepEqual(c.in, c.want) {
+				t.Errorf("got %+v, want %+v", c.in, c.want)
+			}
+		})
+	}
+}
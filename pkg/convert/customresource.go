@@ -46,6 +46,15 @@ func (t *CustomResourceTransformer) Forward(obj runtime.Object, tenantID string)
 	}
 	groupVersion := u.GetAPIVersion()
 	u.SetAPIVersion(util.AddTenantIDPrefix(tenantID, groupVersion))
+	// the managed fields are computed by the upstream cluster, whose
+	// api versions are prefixed as well
+	managedFields := u.GetManagedFields()
+	for i := range managedFields {
+		if managedFields[i].APIVersion != "" {
+			managedFields[i].APIVersion = util.AddTenantIDPrefix(tenantID, managedFields[i].APIVersion)
+		}
+	}
+	u.SetManagedFields(managedFields)
 	return u, nil
 }
 
@@ -60,5 +69,10 @@ func (t *CustomResourceTransformer) Backward(obj runtime.Object, tenantID string
 		return nil, errors.Errorf("invalid apiVersion %s in cr %s, tenant id is %s", groupVersion, u.GetName(), tenantID)
 	}
 	u.SetAPIVersion(util.TrimTenantIDPrefix(tenantID, groupVersion))
+	managedFields := u.GetManagedFields()
+	for i := range managedFields {
+		managedFields[i].APIVersion = util.TrimTenantIDPrefix(tenantID, managedFields[i].APIVersion)
+	}
+	u.SetManagedFields(managedFields)
 	return u, nil
 }
@@ -19,6 +19,7 @@ package convert
 import (
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/kubewharf/kubezoo/pkg/util"
@@ -35,6 +36,7 @@ func TestCustomResourceTransformerForward(t *testing.T) {
 	un.SetKind("Mykind")
 	un.SetNamespace("mynamespace")
 	un.SetName("myname")
+	un.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl", APIVersion: apiVersion}})
 
 	c := NewCustomResourceTransformer()
 	obj, err := c.Forward(&un, tenant)
@@ -48,6 +50,9 @@ func TestCustomResourceTransformerForward(t *testing.T) {
 	if newUn.GetAPIVersion() != tenant+util.TenantIDSeparator+apiVersion {
 		t.Errorf("Unexpected api version.")
 	}
+	if newUn.GetManagedFields()[0].APIVersion != tenant+util.TenantIDSeparator+apiVersion {
+		t.Errorf("Unexpected api version of managed fields.")
+	}
 }
 
 // TestCustomResourceTransformerBackward tests the backward method of the
@@ -61,6 +66,7 @@ func TestCustomResourceTransformerBackward(t *testing.T) {
 	un.SetKind("Mykind")
 	un.SetNamespace("mynamespace")
 	un.SetName("myname")
+	un.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl", APIVersion: apiVersion}})
 
 	c := NewCustomResourceTransformer()
 	obj, err := c.Backward(&un, tenant)
@@ -74,4 +80,7 @@ func TestCustomResourceTransformerBackward(t *testing.T) {
 	if tenant+util.TenantIDSeparator+newUn.GetAPIVersion() != apiVersion {
 		t.Errorf("Unexpected api version.")
 	}
+	if tenant+util.TenantIDSeparator+newUn.GetManagedFields()[0].APIVersion != apiVersion {
+		t.Errorf("Unexpected api version of managed fields.")
+	}
 }
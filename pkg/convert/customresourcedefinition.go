@@ -17,29 +17,53 @@ limitations under the License.
 package convert
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/pkg/errors"
 	crdinternal "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
 	"github.com/kubewharf/kubezoo/pkg/common"
 	"github.com/kubewharf/kubezoo/pkg/util"
 )
 
+// reservedGroupSuffixes are the api groups owned by the kubernetes
+// community, tenants are not allowed to define crds in them.
+var reservedGroupSuffixes = []string{"k8s.io", "kubernetes.io"}
+
+// CheckSystemCRDGroupFunc returns whether the group requested by the
+// tenant is served by a crd shared by all tenants, i.e. a crd created
+// in the upstream cluster without tenant id prefix.
+type CheckSystemCRDGroupFunc func(group, tenantID string) (bool, error)
+
+// GetTenantFunc gets the tenant by tenant id.
+type GetTenantFunc func(tenantID string) (*tenantv1alpha1.Tenant, error)
+
 // CRDConvertor implements the transformation between client and
 // upstream server for CustomResourceDefinition resource.
 type CRDConvertor struct {
 	ownerRefTransformer OwnerReferenceTransformer
+	isSystemCRDGroup    CheckSystemCRDGroupFunc
+	listTenantCRDs      ListTenantCRDsFunc
+	getTenant           GetTenantFunc
 }
 
 var _ common.ObjectConvertor = &CRDConvertor{}
 
 // NewCRDConvertor initiates a CRDConvertor which implements the
 // ObjectConvertor interfaces.
-func NewCRDConvertor(ort OwnerReferenceTransformer) common.ObjectConvertor {
+func NewCRDConvertor(ort OwnerReferenceTransformer, isSystemCRDGroup CheckSystemCRDGroupFunc, listTenantCRDs ListTenantCRDsFunc, getTenant GetTenantFunc) common.ObjectConvertor {
 	return &CRDConvertor{
 		ownerRefTransformer: ort,
+		isSystemCRDGroup:    isSystemCRDGroup,
+		listTenantCRDs:      listTenantCRDs,
+		getTenant:           getTenant,
 	}
 }
 
@@ -51,12 +75,14 @@ func (t *CRDConvertor) ConvertTenantObjectToUpstreamObject(obj runtime.Object, t
 		return errors.Errorf("fail to assert the runtime object to the internal version of crd")
 	}
 
-	if crd.Name != crd.Spec.Names.Plural+"."+crd.Spec.Group {
-		return errors.Errorf("The CustomResourceDefinition \"%s\" is invalid: metadata.name: Invalid value: \"%s\": must be spec.names.plural+\".\"+spec.group",
-			crd.Name, crd.Name)
+	if err := t.validateTenantCRD(crd, tenantID); err != nil {
+		return err
 	}
 	crd.Spec.Group = util.AddTenantIDPrefix(tenantID, crd.Spec.Group)
 	crd.Name = crd.Spec.Names.Plural + "." + crd.Spec.Group
+	if err := t.checkCRDLimits(crd, tenantID); err != nil {
+		return err
+	}
 	for i := range crd.OwnerReferences {
 		target, err := t.ownerRefTransformer.Forward(&crd.OwnerReferences[i], tenantID)
 		if err != nil {
@@ -89,3 +115,107 @@ func (t *CRDConvertor) ConvertUpstreamObjectToTenantObject(obj runtime.Object, t
 	}
 	return nil
 }
+
+// validateTenantCRD validates the tenant crd before it is prefixed with the
+// tenant id, so that the tenant gets errors with its own field values instead
+// of the upstream ones.
+func (t *CRDConvertor) validateTenantCRD(crd *crdinternal.CustomResourceDefinition, tenantID string) error {
+	var allErrs field.ErrorList
+	groupPath := field.NewPath("spec", "group")
+	prefixLen := len(util.AddTenantIDPrefix(tenantID, ""))
+
+	if crd.Name != crd.Spec.Names.Plural+"."+crd.Spec.Group {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), crd.Name, "must be spec.names.plural+\".\"+spec.group"))
+	}
+
+	maxGroupLen := utilvalidation.DNS1123SubdomainMaxLength - prefixLen
+	if len(crd.Spec.Group) > maxGroupLen {
+		allErrs = append(allErrs, field.TooLong(groupPath, crd.Spec.Group, maxGroupLen))
+	} else {
+		for _, msg := range utilvalidation.IsDNS1123Subdomain(util.AddTenantIDPrefix(tenantID, crd.Spec.Group)) {
+			allErrs = append(allErrs, field.Invalid(groupPath, crd.Spec.Group, msg))
+		}
+		// the upstream crd name is plural + "." + prefixed group
+		maxPluralLen := maxGroupLen - len(crd.Spec.Group) - 1
+		if len(crd.Spec.Names.Plural) > maxPluralLen {
+			allErrs = append(allErrs, field.TooLong(field.NewPath("spec", "names", "plural"), crd.Spec.Names.Plural, maxPluralLen))
+		}
+	}
+
+	for _, suffix := range reservedGroupSuffixes {
+		if crd.Spec.Group == suffix || strings.HasSuffix(crd.Spec.Group, "."+suffix) {
+			allErrs = append(allErrs, field.Forbidden(groupPath, fmt.Sprintf("groups under %q are reserved", suffix)))
+			break
+		}
+	}
+
+	if t.isSystemCRDGroup != nil {
+		isSystem, err := t.isSystemCRDGroup(crd.Spec.Group, tenantID)
+		if err != nil {
+			return err
+		}
+		if isSystem {
+			allErrs = append(allErrs, field.Forbidden(groupPath, fmt.Sprintf("group %q is already served by a shared crd", crd.Spec.Group)))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(crdinternal.Kind("CustomResourceDefinition"), crd.Name, allErrs)
+	}
+	return nil
+}
+
+// checkCRDLimits checks whether creating or updating the upstream crd
+// exceeds the crd limits of the tenant. An update is only rejected if
+// it increases the usage of an exceeded limit, so that tenants are
+// still able to shrink their crds after the limits are lowered.
+func (t *CRDConvertor) checkCRDLimits(crd *crdinternal.CustomResourceDefinition, tenantID string) error {
+	if t.getTenant == nil || t.listTenantCRDs == nil {
+		return nil
+	}
+	tenant, err := t.getTenant(tenantID)
+	if err != nil {
+		return err
+	}
+	limits := tenant.Spec.CRDLimits
+	if limits == nil {
+		return nil
+	}
+
+	crdList, err := t.listTenantCRDs(tenantID)
+	if err != nil {
+		return err
+	}
+	var existing *apiextensionsv1.CustomResourceDefinition
+	others := make([]*apiextensionsv1.CustomResourceDefinition, 0, len(crdList))
+	for _, c := range crdList {
+		if c.Name == crd.Name {
+			existing = c
+			continue
+		}
+		others = append(others, c)
+	}
+
+	target := &apiextensionsv1.CustomResourceDefinition{}
+	if err := apiextensionsv1.Convert_apiextensions_CustomResourceDefinition_To_v1_CustomResourceDefinition(crd, target, nil); err != nil {
+		return err
+	}
+	oldUsage := util.GetCRDUsage(append(others, existing)...)
+	newUsage := util.GetCRDUsage(append(others, target)...)
+
+	var exceeded []string
+	for _, limit := range util.ExceededCRDLimits(newUsage, limits) {
+		switch {
+		case limit == "maxCRDs" && newUsage.CRDs > oldUsage.CRDs,
+			limit == "maxServedVersions" && newUsage.ServedVersions > oldUsage.ServedVersions,
+			limit == "maxSchemaBytes" && newUsage.SchemaBytes > oldUsage.SchemaBytes:
+			exceeded = append(exceeded, limit)
+		}
+	}
+	if len(exceeded) > 0 {
+		return apierrors.NewForbidden(crdinternal.Resource("customresourcedefinitions"),
+			crd.Spec.Names.Plural+"."+util.TrimTenantIDPrefix(tenantID, crd.Spec.Group),
+			fmt.Errorf("exceeded crd limits of tenant %s: %s", tenantID, strings.Join(exceeded, ", ")))
+	}
+	return nil
+}
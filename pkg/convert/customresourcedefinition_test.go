@@ -17,11 +17,15 @@ limitations under the License.
 package convert
 
 import (
+	"strings"
 	"testing"
 
 	crdinternal "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
 	"github.com/kubewharf/kubezoo/pkg/util"
 )
 
@@ -34,12 +38,57 @@ func TestCRDConvertorConvertTenantObjectToUpstreamObject(t *testing.T) {
 	CRDGroup := "a.com"
 	CRDVersion := "v1"
 	FullCRDName := CRDPlural + "." + CRDGroup
-	c := NewCRDConvertor(NewOwnerReferenceTransformer(checkGroupKind))
+	c := NewCRDConvertor(NewOwnerReferenceTransformer(checkGroupKind), fakeCheckSystemCRDGroup, FakeListEmptyTenantCRDsFunc, FakeGetUnlimitedTenantFunc)
+
+	newCRD := func(plural, group string) crdinternal.CustomResourceDefinition {
+		return crdinternal.CustomResourceDefinition{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "CustomResourceDefinition",
+				APIVersion: "apiextensions/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: plural + "." + group,
+			},
+			Spec: crdinternal.CustomResourceDefinitionSpec{
+				Group:   group,
+				Version: CRDVersion,
+				Scope:   crdinternal.NamespaceScoped,
+				Names: crdinternal.CustomResourceDefinitionNames{
+					Plural: plural,
+					Kind:   "Foo",
+				},
+			},
+		}
+	}
 
 	testCases := map[string]struct {
 		crd       crdinternal.CustomResourceDefinition
 		expectErr bool
 	}{
+		"This is a crd in reserved group": {
+			crd:       newCRD(CRDPlural, "apps.k8s.io"),
+			expectErr: true,
+		},
+		"This is a crd in reserved root group": {
+			crd:       newCRD(CRDPlural, "kubernetes.io"),
+			expectErr: true,
+		},
+		"This is a crd in system crd group": {
+			crd:       newCRD(CRDPlural, "system.com"),
+			expectErr: true,
+		},
+		"This is a crd with invalid group": {
+			crd:       newCRD(CRDPlural, "A_B.com"),
+			expectErr: true,
+		},
+		"This is a crd with too long group": {
+			crd:       newCRD(CRDPlural, strings.Repeat("a", 247)+".com"),
+			expectErr: true,
+		},
+		"This is a crd with too long plural": {
+			crd:       newCRD(strings.Repeat("a", 63), strings.Repeat("b", 63)+"."+strings.Repeat("c", 63)+"."+strings.Repeat("d", 60)+".com"),
+			expectErr: true,
+		},
 		"This is a normal crd": {
 			crd: crdinternal.CustomResourceDefinition{
 				TypeMeta: metav1.TypeMeta{
@@ -90,6 +139,8 @@ func TestCRDConvertorConvertTenantObjectToUpstreamObject(t *testing.T) {
 			if testCase.expectErr {
 				if err == nil {
 					t.Errorf("Expect error")
+				} else if !apierrors.IsInvalid(err) {
+					t.Errorf("Expect invalid error, got %v", err)
 				} else {
 					return
 				}
@@ -115,7 +166,7 @@ func TestCRDConvertorConvertUpstreamObjectToTenantObject(t *testing.T) {
 	CRDGroup := tenant + util.TenantIDSeparator + "a.com"
 	CRDVersion := "v1"
 	FullCRDName := CRDPlural + "." + CRDGroup
-	c := NewCRDConvertor(NewOwnerReferenceTransformer(checkGroupKind))
+	c := NewCRDConvertor(NewOwnerReferenceTransformer(checkGroupKind), fakeCheckSystemCRDGroup, FakeListEmptyTenantCRDsFunc, FakeGetUnlimitedTenantFunc)
 
 	testCases := map[string]struct {
 		crd       crdinternal.CustomResourceDefinition
@@ -187,3 +238,131 @@ func TestCRDConvertorConvertUpstreamObjectToTenantObject(t *testing.T) {
 		})
 	}
 }
+
+// TestCRDConvertorCheckCRDLimits tests the crd limits of the tenant are
+// enforced by the ConvertTenantObjectToUpstreamObject methods of CRDConvertor.
+func TestCRDConvertorCheckCRDLimits(t *testing.T) {
+	tenant := "111111"
+	schema := &apiextensionsv1.CustomResourceValidation{
+		OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{Type: "object"},
+	}
+	schemaBytes := int64(len(`{"type":"object"}`))
+	existing := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "bars." + util.AddTenantIDPrefix(tenant, "a.com"),
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: util.AddTenantIDPrefix(tenant, "a.com"),
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "bars", Kind: "Bar"},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true, Storage: true, Schema: schema},
+			},
+		},
+	}
+	listTenantCRDs := func(tenantID string) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+		return []*apiextensionsv1.CustomResourceDefinition{existing}, nil
+	}
+	newCRD := func(plural string, versions ...string) *crdinternal.CustomResourceDefinition {
+		crd := &crdinternal.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: plural + ".a.com",
+			},
+			Spec: crdinternal.CustomResourceDefinitionSpec{
+				Group: "a.com",
+				Scope: crdinternal.NamespaceScoped,
+				Names: crdinternal.CustomResourceDefinitionNames{
+					Plural: plural,
+					Kind:   "Foo",
+				},
+			},
+		}
+		for i, v := range versions {
+			crd.Spec.Versions = append(crd.Spec.Versions, crdinternal.CustomResourceDefinitionVersion{
+				Name:    v,
+				Served:  true,
+				Storage: i == 0,
+				Schema: &crdinternal.CustomResourceValidation{
+					OpenAPIV3Schema: &crdinternal.JSONSchemaProps{Type: "object"},
+				},
+			})
+		}
+		return crd
+	}
+	int32Ptr := func(i int32) *int32 { return &i }
+	int64Ptr := func(i int64) *int64 { return &i }
+
+	testCases := map[string]struct {
+		limits    *tenantv1alpha1.TenantCRDLimits
+		crd       *crdinternal.CustomResourceDefinition
+		expectErr bool
+	}{
+		"no limits": {
+			crd: newCRD("foos", "v1", "v2"),
+		},
+		"create within limits": {
+			limits: &tenantv1alpha1.TenantCRDLimits{
+				MaxCRDs:           int32Ptr(2),
+				MaxServedVersions: int32Ptr(2),
+				MaxSchemaBytes:    int64Ptr(2 * schemaBytes),
+			},
+			crd: newCRD("foos", "v1"),
+		},
+		"create exceeds max crds": {
+			limits:    &tenantv1alpha1.TenantCRDLimits{MaxCRDs: int32Ptr(1)},
+			crd:       newCRD("foos", "v1"),
+			expectErr: true,
+		},
+		"create exceeds max served versions": {
+			limits:    &tenantv1alpha1.TenantCRDLimits{MaxServedVersions: int32Ptr(2)},
+			crd:       newCRD("foos", "v1", "v2"),
+			expectErr: true,
+		},
+		"create exceeds max schema bytes": {
+			limits:    &tenantv1alpha1.TenantCRDLimits{MaxSchemaBytes: int64Ptr(schemaBytes)},
+			crd:       newCRD("foos", "v1"),
+			expectErr: true,
+		},
+		"update at max crds": {
+			limits: &tenantv1alpha1.TenantCRDLimits{MaxCRDs: int32Ptr(1)},
+			crd:    newCRD("bars", "v1"),
+		},
+		"update exceeds max served versions": {
+			limits:    &tenantv1alpha1.TenantCRDLimits{MaxServedVersions: int32Ptr(1)},
+			crd:       newCRD("bars", "v1", "v2"),
+			expectErr: true,
+		},
+		"update shrinks the usage of an exceeded limit": {
+			limits: &tenantv1alpha1.TenantCRDLimits{MaxServedVersions: int32Ptr(0)},
+			crd:    newCRD("bars"),
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			getTenant := func(tenantID string) (*tenantv1alpha1.Tenant, error) {
+				return &tenantv1alpha1.Tenant{
+					ObjectMeta: metav1.ObjectMeta{Name: tenantID},
+					Spec:       tenantv1alpha1.TenantSpec{CRDLimits: testCase.limits},
+				}, nil
+			}
+			c := NewCRDConvertor(NewOwnerReferenceTransformer(checkGroupKind), fakeCheckSystemCRDGroup, listTenantCRDs, getTenant)
+			err := c.ConvertTenantObjectToUpstreamObject(testCase.crd, tenant, false)
+			if testCase.expectErr {
+				if !apierrors.IsForbidden(err) {
+					t.Errorf("Expect forbidden error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Failed ConvertTenantObjectToUpstreamObject with err %s", err)
+			}
+		})
+	}
+}
+
+// fakeCheckSystemCRDGroup treats system.com as the only group served by
+// system crds.
+func fakeCheckSystemCRDGroup(group, tenantID string) (bool, error) {
+	return group == "system.com", nil
+}
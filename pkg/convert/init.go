@@ -24,11 +24,12 @@ import (
 )
 
 // InitConvertors initialize native convertor and custom convertor
-func InitConvertors(checkGroupKind util.CheckGroupKindFunc, listTenantCRDs ListTenantCRDsFunc) (nativeConvertor, customConvertor common.ObjectConvertor) {
+func InitConvertors(checkGroupKind util.CheckGroupKindFunc, listTenantCRDs ListTenantCRDsFunc, isSystemCRDGroup CheckSystemCRDGroupFunc, getTenant GetTenantFunc, getClusterQuota GetClusterResourceQuotaFunc, listTenantQuotas ListTenantQuotasFunc) (nativeConvertor, customConvertor common.ObjectConvertor) {
 	ownerReferenceTransformer := NewOwnerReferenceTransformer(checkGroupKind)
 	objectReferenceTransformer := NewObjectReferenceTransformer(checkGroupKind)
 	defaultConvertor := NewDefaultConvertor(ownerReferenceTransformer)
 	nopeConvertor := NewNopeConvertor()
+	subjectAccessReviewConvertor := NewCrossReferenceConverter(defaultConvertor, NewSubjectAccessReviewTransformer(listTenantCRDs))
 
 	nativeKindToConvertors := map[schema.GroupKind]common.ObjectConvertor{
 		{
@@ -50,11 +51,19 @@ func InitConvertors(checkGroupKind util.CheckGroupKindFunc, listTenantCRDs ListT
 		{
 			Group: "apiextensions.k8s.io",
 			Kind:  "CustomResourceDefinition",
-		}: NewCRDConvertor(ownerReferenceTransformer),
+		}: NewCRDConvertor(ownerReferenceTransformer, isSystemCRDGroup, listTenantCRDs, getTenant),
 		{
 			Group: "",
 			Kind:  "PersistentVolumeClaim",
 		}: defaultConvertor,
+		{
+			Group: "",
+			Kind:  "ResourceQuota",
+		}: NewCrossReferenceConverter(defaultConvertor, NewResourceQuotaTransformer(getClusterQuota, listTenantQuotas)),
+		{
+			Group: "",
+			Kind:  "LimitRange",
+		}: NewCrossReferenceConverter(defaultConvertor, NewLimitRangeTransformer()),
 		{
 			Group: "",
 			Kind:  "PersistentVolume",
@@ -83,6 +92,26 @@ func InitConvertors(checkGroupKind util.CheckGroupKindFunc, listTenantCRDs ListT
 			Group: "authentication.k8s.io",
 			Kind:  "TokenReview",
 		}: NewCrossReferenceConverter(defaultConvertor, NewTokenReviewTransformer()),
+		{
+			Group: "certificates.k8s.io",
+			Kind:  "CertificateSigningRequest",
+		}: NewCrossReferenceConverter(defaultConvertor, NewCertificateSigningRequestTransformer()),
+		{
+			Group: "authorization.k8s.io",
+			Kind:  "SubjectAccessReview",
+		}: subjectAccessReviewConvertor,
+		{
+			Group: "authorization.k8s.io",
+			Kind:  "SelfSubjectAccessReview",
+		}: subjectAccessReviewConvertor,
+		{
+			Group: "authorization.k8s.io",
+			Kind:  "LocalSubjectAccessReview",
+		}: subjectAccessReviewConvertor,
+		{
+			Group: "authorization.k8s.io",
+			Kind:  "SelfSubjectRulesReview",
+		}: subjectAccessReviewConvertor,
 
 		// resources with nope convertor:
 		{
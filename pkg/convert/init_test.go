@@ -23,6 +23,7 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
 	"github.com/kubewharf/kubezoo/pkg/util"
 )
 
@@ -44,7 +45,7 @@ func TestNativeObjectConvertorConvertTenantObjectToUpstreamObject(t *testing.T)
 		},
 	}
 
-	c, _ := InitConvertors(checkGroupKind, FakeListEmptyTenantCRDsFunc)
+	c, _ := InitConvertors(checkGroupKind, FakeListEmptyTenantCRDsFunc, FakeCheckNoSystemCRDGroupFunc, FakeGetUnlimitedTenantFunc, nil, nil)
 	err := c.ConvertTenantObjectToUpstreamObject(&pod, tenant, true)
 	if err != nil {
 		t.Errorf("Failed to convert tenant object to upstream object")
@@ -72,7 +73,7 @@ func TestNativeObjectConvertorConvertUpstreamObjectToTenantObject(t *testing.T)
 		},
 	}
 
-	c, _ := InitConvertors(checkGroupKind, FakeListEmptyTenantCRDsFunc)
+	c, _ := InitConvertors(checkGroupKind, FakeListEmptyTenantCRDsFunc, FakeCheckNoSystemCRDGroupFunc, FakeGetUnlimitedTenantFunc, nil, nil)
 	err := c.ConvertUpstreamObjectToTenantObject(&pod, tenant, true)
 	if err != nil {
 		t.Errorf("Failed to convert tenant object to upstream object")
@@ -85,3 +86,11 @@ func TestNativeObjectConvertorConvertUpstreamObjectToTenantObject(t *testing.T)
 func FakeListEmptyTenantCRDsFunc(tenantID string) ([]*apiextensionsv1.CustomResourceDefinition, error) {
 	return []*apiextensionsv1.CustomResourceDefinition{}, nil
 }
+
+func FakeCheckNoSystemCRDGroupFunc(group, tenantID string) (bool, error) {
+	return false, nil
+}
+
+func FakeGetUnlimitedTenantFunc(tenantID string) (*tenantv1alpha1.Tenant, error) {
+	return &tenantv1alpha1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: tenantID}}, nil
+}
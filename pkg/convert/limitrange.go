@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package convert
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	internal "k8s.io/kubernetes/pkg/apis/core"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// LimitRangeTransformer implements the transformation between client and
+// upstream server for LimitRange resource. The default limit range synced
+// from the spec of the tenant is read-only, so that the tenant can only
+// tighten it with more limit ranges, all of which are enforced upstream.
+type LimitRangeTransformer struct{}
+
+var _ ObjectTransformer = &LimitRangeTransformer{}
+
+// NewLimitRangeTransformer initiates a LimitRangeTransformer which
+// implements the ObjectTransformer interfaces.
+func NewLimitRangeTransformer() ObjectTransformer {
+	return &LimitRangeTransformer{}
+}
+
+// Forward rejects the default limit range of the tenant, whose patches and
+// deletions are also rejected by name in the proxy.
+func (t *LimitRangeTransformer) Forward(obj runtime.Object, tenantID string) (runtime.Object, error) {
+	limitRange, ok := obj.(*internal.LimitRange)
+	if !ok {
+		return nil, errors.Errorf("fail to assert the runtime object to the internal version of limitrange")
+	}
+	if limitRange.Name == util.TenantLimitRangeName {
+		return nil, apierrors.NewForbidden(internal.Resource("limitranges"), limitRange.Name,
+			fmt.Errorf("the default limit range of tenant %s is read-only", tenantID))
+	}
+	return limitRange, nil
+}
+
+// Backward keeps the limit range as is.
+func (t *LimitRangeTransformer) Backward(obj runtime.Object, tenantID string) (runtime.Object, error) {
+	return obj, nil
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package convert
+
+import (
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	internal "k8s.io/kubernetes/pkg/apis/core"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// TestLimitRangeTransformerForward tests the default limit range of the
+// tenant is read-only, while the other limit ranges are forwarded.
+func TestLimitRangeTransformerForward(t *testing.T) {
+	transformer := NewLimitRangeTransformer()
+
+	limitRange := &internal.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: "limits"}}
+	if _, err := transformer.Forward(limitRange, "111111"); err != nil {
+		t.Errorf("failed to forward limit range: %v", err)
+	}
+
+	synced := &internal.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: util.TenantLimitRangeName}}
+	if _, err := transformer.Forward(synced, "111111"); !apierrors.IsForbidden(err) {
+		t.Errorf("expect the default limit range is forbidden, got %v", err)
+	}
+}
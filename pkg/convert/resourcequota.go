@@ -0,0 +1,167 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package convert
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog"
+	internal "k8s.io/kubernetes/pkg/apis/core"
+	internalv1 "k8s.io/kubernetes/pkg/apis/core/v1"
+
+	quotav1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/common"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// GetClusterResourceQuotaFunc gets the cluster resource quota of the
+// upstream cluster by name.
+type GetClusterResourceQuotaFunc func(name string) (*quotav1alpha1.ClusterResourceQuota, error)
+
+// ListTenantQuotasFunc lists the cluster resource quotas of the tenant and
+// the resource quotas in the namespaces of the tenant.
+type ListTenantQuotasFunc func(tenantID string) ([]*quotav1alpha1.ClusterResourceQuota, []*corev1.ResourceQuota, error)
+
+// ResourceQuotaTransformer implements the transformation between client
+// and upstream server for ResourceQuota resource. The resource quotas
+// generated by the cluster resource quota of the tenant are read-only, and
+// show the hard limits and the usage across the namespaces of the tenant,
+// i.e. the status of the cluster resource quota, instead of the usage in
+// their own namespaces. The resource quotas created by the tenant allocate
+// the quota of the tenant to the namespaces, whose hard limits summed over
+// the namespaces can not exceed the one of the tenant.
+type ResourceQuotaTransformer struct {
+	getClusterQuota  GetClusterResourceQuotaFunc
+	listTenantQuotas ListTenantQuotasFunc
+}
+
+var _ ObjectTransformer = &ResourceQuotaTransformer{}
+
+// NewResourceQuotaTransformer initiates a ResourceQuotaTransformer which
+// implements the ObjectTransformer interfaces. The status of the generated
+// resource quotas is kept if getClusterQuota is nil, and the allocations
+// are not checked if listTenantQuotas is nil.
+func NewResourceQuotaTransformer(getClusterQuota GetClusterResourceQuotaFunc, listTenantQuotas ListTenantQuotasFunc) ObjectTransformer {
+	return &ResourceQuotaTransformer{getClusterQuota: getClusterQuota, listTenantQuotas: listTenantQuotas}
+}
+
+// Forward rejects the generated resource quotas and the allocations
+// exceeding the quota of the tenant, and converts the object count quotas
+// of the custom resources of the tenant.
+func (t *ResourceQuotaTransformer) Forward(obj runtime.Object, tenantID string) (runtime.Object, error) {
+	quota, ok := obj.(*internal.ResourceQuota)
+	if !ok {
+		return nil, errors.Errorf("fail to assert the runtime object to the internal version of resourcequota")
+	}
+	// the generated resource quotas are also guarded by name in the proxy,
+	// against the patches and the deletions
+	_, generated := quota.Labels[quotav1alpha1.ClusterResourceQuotaCreatedby]
+	if generated || isTenantQuotaName(quota.Name) || isTenantQuotaName(quota.GenerateName) {
+		return nil, apierrors.NewForbidden(internal.Resource("resourcequotas"), quota.Name,
+			fmt.Errorf("the quota of tenant %s is read-only", tenantID))
+	}
+	quota.Spec.Hard = convertResourceList(quota.Spec.Hard, func(name corev1.ResourceName) corev1.ResourceName {
+		return util.ConvertTenantResourceNameToUpstream(tenantID, name)
+	})
+	// the partial documents of the patches, which have no namespace, are
+	// left to the quota webhook, which checks the patched object
+	if t.listTenantQuotas != nil && quota.Namespace != "" {
+		if err := t.checkAllocation(quota, tenantID); err != nil {
+			return nil, err
+		}
+	}
+	return quota, nil
+}
+
+// isTenantQuotaName returns true if the name is reserved for the resource
+// quotas generated by the cluster resource quotas of the tenant.
+func isTenantQuotaName(name string) bool {
+	return strings.HasPrefix(name, common.TenantQuotaNamePrefix+"-")
+}
+
+// checkAllocation checks the allocation of the upstream resource quota
+// against the cluster resource quotas of the tenant.
+func (t *ResourceQuotaTransformer) checkAllocation(quota *internal.ResourceQuota, tenantID string) error {
+	clusterquotas, quotas, err := t.listTenantQuotas(tenantID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list quotas of tenant %s", tenantID)
+	}
+	var allocation corev1.ResourceQuota
+	if err := internalv1.Convert_core_ResourceQuota_To_v1_ResourceQuota(quota, &allocation, nil); err != nil {
+		return err
+	}
+	if err := util.CheckQuotaAllocation(&allocation, quotas, clusterquotas); err != nil {
+		return apierrors.NewForbidden(internal.Resource("resourcequotas"), quota.Name, err)
+	}
+	return nil
+}
+
+// Backward replaces the status of the generated resource quotas with the
+// one of their cluster resource quota, and converts the object count quotas
+// of the upstream custom resources of the tenant.
+func (t *ResourceQuotaTransformer) Backward(obj runtime.Object, tenantID string) (runtime.Object, error) {
+	quota, ok := obj.(*internal.ResourceQuota)
+	if !ok {
+		return nil, errors.Errorf("fail to assert the runtime object to the internal version of resourcequota")
+	}
+	if name := quota.Labels[quotav1alpha1.ClusterResourceQuotaCreatedby]; name != "" && t.getClusterQuota != nil {
+		clusterquota, err := t.getClusterQuota(name)
+		if err == nil {
+			quota.Status.Hard = toInternalResourceList(clusterquota.Status.Hard)
+			quota.Status.Used = toInternalResourceList(clusterquota.Status.Used)
+		} else if !apierrors.IsNotFound(err) {
+			// the usage in the namespace is shown instead
+			klog.Warningf("failed to get cluster resource quota %s of tenant %s: %v", name, tenantID, err)
+		}
+	}
+	toTenant := func(name corev1.ResourceName) corev1.ResourceName {
+		return util.ConvertUpstreamResourceNameToTenant(tenantID, name)
+	}
+	quota.Spec.Hard = convertResourceList(quota.Spec.Hard, toTenant)
+	quota.Status.Hard = convertResourceList(quota.Status.Hard, toTenant)
+	quota.Status.Used = convertResourceList(quota.Status.Used, toTenant)
+	return quota, nil
+}
+
+// convertResourceList converts the names of the resources by convertName.
+func convertResourceList(in internal.ResourceList, convertName func(corev1.ResourceName) corev1.ResourceName) internal.ResourceList {
+	if in == nil {
+		return nil
+	}
+	out := make(internal.ResourceList, len(in))
+	for name, quantity := range in {
+		out[internal.ResourceName(convertName(corev1.ResourceName(name)))] = quantity
+	}
+	return out
+}
+
+// toInternalResourceList converts the resource list to the internal version.
+func toInternalResourceList(in corev1.ResourceList) internal.ResourceList {
+	if in == nil {
+		return nil
+	}
+	out := make(internal.ResourceList, len(in))
+	for name, quantity := range in {
+		out[internal.ResourceName(name)] = quantity.DeepCopy()
+	}
+	return out
+}
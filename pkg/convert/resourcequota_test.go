@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package convert
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	internal "k8s.io/kubernetes/pkg/apis/core"
+
+	quotav1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1"
+)
+
+// TestResourceQuotaTransformerForward tests the generated resource quotas
+// are read-only and the object count quotas of the custom resources are
+// converted.
+func TestResourceQuotaTransformerForward(t *testing.T) {
+	transformer := NewResourceQuotaTransformer(nil, nil)
+
+	quota := &internal.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "quota"},
+		Spec: internal.ResourceQuotaSpec{
+			Hard: internal.ResourceList{"count/foos.a.com": resource.MustParse("1")},
+		},
+	}
+	if _, err := transformer.Forward(quota, "111111"); err != nil {
+		t.Fatalf("failed to forward resource quota: %v", err)
+	}
+	if _, ok := quota.Spec.Hard["count/foos.111111-a.com"]; !ok {
+		t.Errorf("expect the object count quota of the upstream crd, got %v", quota.Spec.Hard)
+	}
+
+	generated := &internal.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "kubezoo-tenant-quota-111111-abcde",
+			Labels: map[string]string{quotav1alpha1.ClusterResourceQuotaCreatedby: "kubezoo-tenant-quota-111111"},
+		},
+	}
+	if _, err := transformer.Forward(generated, "111111"); !apierrors.IsForbidden(err) {
+		t.Errorf("expect the generated resource quota is forbidden, got %v", err)
+	}
+
+	unlabeled := &internal.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "kubezoo-tenant-quota-111111-"},
+	}
+	if _, err := transformer.Forward(unlabeled, "111111"); !apierrors.IsForbidden(err) {
+		t.Errorf("expect the reserved name of the generated resource quotas is forbidden, got %v", err)
+	}
+}
+
+// TestResourceQuotaTransformerForwardAllocation tests the resource quotas
+// created by the tenant can not allocate more than the quota of the tenant.
+func TestResourceQuotaTransformerForwardAllocation(t *testing.T) {
+	clusterquota := &quotav1alpha1.ClusterResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubezoo-tenant-quota-111111"},
+	}
+	clusterquota.Spec.Hard = corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("10")}
+	others := []*corev1.ResourceQuota{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "111111-team-a", Name: "budget"},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("6")},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "111111-team-b", Name: "budget"},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("3")},
+			},
+		},
+	}
+	listTenantQuotas := func(tenantID string) ([]*quotav1alpha1.ClusterResourceQuota, []*corev1.ResourceQuota, error) {
+		return []*quotav1alpha1.ClusterResourceQuota{clusterquota}, others, nil
+	}
+	transformer := NewResourceQuotaTransformer(nil, listTenantQuotas)
+
+	tests := []struct {
+		name      string
+		namespace string
+		cpu       string
+		forbidden bool
+	}{
+		{name: "allocate within the quota", namespace: "111111-team-c", cpu: "1"},
+		{name: "allocate over the quota", namespace: "111111-team-c", cpu: "2", forbidden: true},
+		{name: "increase allocation within the quota", namespace: "111111-team-b", cpu: "4"},
+		{name: "increase allocation over the quota", namespace: "111111-team-b", cpu: "5", forbidden: true},
+		{name: "partial patch of allocation", cpu: "5"},
+	}
+	for _, test := range tests {
+		quota := &internal.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Namespace: test.namespace, Name: "budget"},
+			Spec: internal.ResourceQuotaSpec{
+				Hard: internal.ResourceList{internal.ResourceRequestsCPU: resource.MustParse(test.cpu)},
+			},
+		}
+		_, err := transformer.Forward(quota, "111111")
+		if test.forbidden != apierrors.IsForbidden(err) {
+			t.Errorf("%s: expect forbidden %v, got %v", test.name, test.forbidden, err)
+		}
+	}
+}
+
+// TestResourceQuotaTransformerBackward tests the generated resource quotas
+// show the status of the cluster resource quota.
+func TestResourceQuotaTransformerBackward(t *testing.T) {
+	clusterquota := &quotav1alpha1.ClusterResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubezoo-tenant-quota-111111"},
+	}
+	clusterquota.Status.Hard = corev1.ResourceList{
+		corev1.ResourcePods:       resource.MustParse("10"),
+		"count/foos.111111-a.com": resource.MustParse("5"),
+	}
+	clusterquota.Status.Used = corev1.ResourceList{
+		corev1.ResourcePods:       resource.MustParse("7"),
+		"count/foos.111111-a.com": resource.MustParse("2"),
+	}
+	getClusterQuota := func(name string) (*quotav1alpha1.ClusterResourceQuota, error) {
+		if name != clusterquota.Name {
+			return nil, apierrors.NewNotFound(quotav1alpha1.Resource("clusterresourcequotas"), name)
+		}
+		return clusterquota, nil
+	}
+	transformer := NewResourceQuotaTransformer(getClusterQuota, nil)
+
+	tests := []struct {
+		name       string
+		createdBy  string
+		expectPods string
+	}{
+		{name: "generated quota", createdBy: clusterquota.Name, expectPods: "7"},
+		{name: "quota of the tenant", expectPods: "1"},
+		{name: "quota of removed cluster resource quota", createdBy: "kubezoo-tenant-quota-111111-removed", expectPods: "1"},
+	}
+	for _, test := range tests {
+		quota := &internal.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota"},
+			Spec: internal.ResourceQuotaSpec{
+				Hard: internal.ResourceList{internal.ResourcePods: resource.MustParse("10")},
+			},
+			Status: internal.ResourceQuotaStatus{
+				Hard: internal.ResourceList{internal.ResourcePods: resource.MustParse("10")},
+				Used: internal.ResourceList{internal.ResourcePods: resource.MustParse("1")},
+			},
+		}
+		if test.createdBy != "" {
+			quota.Labels = map[string]string{quotav1alpha1.ClusterResourceQuotaCreatedby: test.createdBy}
+		}
+		if _, err := transformer.Backward(quota, "111111"); err != nil {
+			t.Fatalf("%s: failed to backward resource quota: %v", test.name, err)
+		}
+		used := quota.Status.Used[internal.ResourcePods]
+		if used.String() != test.expectPods {
+			t.Errorf("%s: expect %s pods used, got %s", test.name, test.expectPods, used.String())
+		}
+		if test.createdBy == clusterquota.Name {
+			if _, ok := quota.Status.Used["count/foos.a.com"]; !ok {
+				t.Errorf("%s: expect the object count quota of the tenant crd, got %v", test.name, quota.Status.Used)
+			}
+		}
+	}
+}
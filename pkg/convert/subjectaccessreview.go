@@ -0,0 +1,201 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package convert
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	authzinternal "k8s.io/kubernetes/pkg/apis/authorization"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// SubjectAccessReviewTransformer implements the transformation between
+// client and upstream server for SubjectAccessReview,
+// SelfSubjectAccessReview, LocalSubjectAccessReview and
+// SelfSubjectRulesReview resources.
+type SubjectAccessReviewTransformer struct {
+	listTenantCRDs ListTenantCRDsFunc
+}
+
+var _ ObjectTransformer = &SubjectAccessReviewTransformer{}
+
+// NewSubjectAccessReviewTransformer initiates a
+// SubjectAccessReviewTransformer which implements the ObjectTransformer
+// interfaces.
+func NewSubjectAccessReviewTransformer(listTenantCRDs ListTenantCRDsFunc) ObjectTransformer {
+	return &SubjectAccessReviewTransformer{
+		listTenantCRDs: listTenantCRDs,
+	}
+}
+
+// Forward transforms the tenant review to the upstream review, i.e. the
+// namespaces, the custom resource groups, the names of the cluster scoped
+// objects and the users under review are prefixed with the tenant ID.
+func (t *SubjectAccessReviewTransformer) Forward(obj runtime.Object, tenantID string) (runtime.Object, error) {
+	switch review := obj.(type) {
+	case *authzinternal.SubjectAccessReview:
+		review.Spec.User = util.AddTenantIDToUserName(tenantID, review.Spec.User)
+		review.Spec.Groups = util.AddTenantIDToGroups(tenantID, review.Spec.Groups)
+		if err := t.forwardResourceAttributes(review.Spec.ResourceAttributes, tenantID); err != nil {
+			return nil, err
+		}
+	case *authzinternal.LocalSubjectAccessReview:
+		review.Spec.User = util.AddTenantIDToUserName(tenantID, review.Spec.User)
+		review.Spec.Groups = util.AddTenantIDToGroups(tenantID, review.Spec.Groups)
+		if err := t.forwardResourceAttributes(review.Spec.ResourceAttributes, tenantID); err != nil {
+			return nil, err
+		}
+	case *authzinternal.SelfSubjectAccessReview:
+		if err := t.forwardResourceAttributes(review.Spec.ResourceAttributes, tenantID); err != nil {
+			return nil, err
+		}
+	case *authzinternal.SelfSubjectRulesReview:
+		if len(review.Spec.Namespace) != 0 {
+			review.Spec.Namespace = util.AddTenantIDPrefix(tenantID, review.Spec.Namespace)
+		}
+	default:
+		return nil, errors.Errorf("fail to assert the runtime object to the internal version of subjectaccessreview")
+	}
+	return obj, nil
+}
+
+// Backward transforms the upstream review to the tenant review, the rules
+// of SelfSubjectRulesReview are transformed to those seen inside the tenant.
+func (t *SubjectAccessReviewTransformer) Backward(obj runtime.Object, tenantID string) (runtime.Object, error) {
+	switch review := obj.(type) {
+	case *authzinternal.SubjectAccessReview:
+		review.Spec.User, review.Spec.Groups = trimTenantIDFromUser(tenantID, review.Spec.User, review.Spec.Groups)
+		backwardResourceAttributes(review.Spec.ResourceAttributes, tenantID)
+		backwardSubjectAccessReviewStatus(&review.Status, tenantID)
+	case *authzinternal.LocalSubjectAccessReview:
+		review.Spec.User, review.Spec.Groups = trimTenantIDFromUser(tenantID, review.Spec.User, review.Spec.Groups)
+		backwardResourceAttributes(review.Spec.ResourceAttributes, tenantID)
+		backwardSubjectAccessReviewStatus(&review.Status, tenantID)
+	case *authzinternal.SelfSubjectAccessReview:
+		backwardResourceAttributes(review.Spec.ResourceAttributes, tenantID)
+		backwardSubjectAccessReviewStatus(&review.Status, tenantID)
+	case *authzinternal.SelfSubjectRulesReview:
+		review.Spec.Namespace = util.TrimTenantIDPrefix(tenantID, review.Spec.Namespace)
+		for i := range review.Status.ResourceRules {
+			backwardResourceRule(&review.Status.ResourceRules[i], tenantID)
+		}
+		review.Status.EvaluationError = strings.ReplaceAll(review.Status.EvaluationError, tenantID+"-", "")
+	default:
+		return nil, errors.Errorf("fail to assert the runtime object to the internal version of subjectaccessreview")
+	}
+	return obj, nil
+}
+
+// forwardResourceAttributes transforms the resource attributes under review
+// to those of the upstream cluster.
+func (t *SubjectAccessReviewTransformer) forwardResourceAttributes(attrs *authzinternal.ResourceAttributes, tenantID string) error {
+	if attrs == nil {
+		return nil
+	}
+	crdList, err := t.listTenantCRDs(tenantID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list crds of tenant")
+	}
+	grm := util.NewCustomGroupResourcesMap(crdList)
+
+	if len(attrs.Namespace) != 0 {
+		attrs.Namespace = util.AddTenantIDPrefix(tenantID, attrs.Namespace)
+	}
+	if group := util.AddTenantIDPrefix(tenantID, attrs.Group); grm.HasGroup(group) {
+		attrs.Group = group
+		if crd := grm.GetCRD(group, attrs.Resource); len(attrs.Name) != 0 && crd != nil && crd.Spec.Scope == v1.ClusterScoped {
+			attrs.Name = util.AddTenantIDPrefix(tenantID, attrs.Name)
+		}
+		return nil
+	}
+	if len(attrs.Name) == 0 || attrs.Group == "" && attrs.Resource == "nodes" {
+		return nil
+	}
+	if namespaced, err := util.IsGroupResourceNamespaced(schema.GroupResource{Group: attrs.Group, Resource: attrs.Resource}); err != nil || namespaced {
+		return nil
+	}
+	if attrs.Group == v1.GroupName && attrs.Resource == "customresourcedefinitions" {
+		attrs.Name = util.ConvertCRDNameToUpstream(attrs.Name, tenantID)
+	} else {
+		attrs.Name = util.AddTenantIDPrefix(tenantID, attrs.Name)
+	}
+	return nil
+}
+
+// backwardResourceAttributes transforms the resource attributes under review
+// back to those seen inside the tenant.
+func backwardResourceAttributes(attrs *authzinternal.ResourceAttributes, tenantID string) {
+	if attrs == nil {
+		return
+	}
+	attrs.Namespace = util.TrimTenantIDPrefix(tenantID, attrs.Namespace)
+	attrs.Group = util.TrimTenantIDPrefix(tenantID, attrs.Group)
+	if attrs.Group == v1.GroupName && attrs.Resource == "customresourcedefinitions" {
+		attrs.Name = strings.Replace(attrs.Name, "."+tenantID+"-", ".", 1)
+	} else {
+		attrs.Name = util.TrimTenantIDPrefix(tenantID, attrs.Name)
+	}
+}
+
+// backwardSubjectAccessReviewStatus trims the tenant ID from the reasons,
+// which may name the upstream roles and bindings.
+func backwardSubjectAccessReviewStatus(status *authzinternal.SubjectAccessReviewStatus, tenantID string) {
+	status.Reason = strings.ReplaceAll(status.Reason, tenantID+"-", "")
+	status.EvaluationError = strings.ReplaceAll(status.EvaluationError, tenantID+"-", "")
+}
+
+// backwardResourceRule transforms the rule of the upstream cluster to the
+// rule seen inside the tenant.
+func backwardResourceRule(rule *authzinternal.ResourceRule, tenantID string) {
+	for i := range rule.APIGroups {
+		rule.APIGroups[i] = util.TrimTenantIDPrefix(tenantID, rule.APIGroups[i])
+	}
+	if len(rule.ResourceNames) == 0 {
+		return
+	}
+	// the rules of the tenant roles name both the tenant and the upstream
+	// objects, see transformRuleToUpstream
+	resourceNames := make([]string, 0, len(rule.ResourceNames))
+	seen := make(map[string]bool, len(rule.ResourceNames))
+	for _, resourceName := range rule.ResourceNames {
+		resourceName = util.TrimTenantIDPrefix(tenantID, resourceName)
+		if !seen[resourceName] {
+			seen[resourceName] = true
+			resourceNames = append(resourceNames, resourceName)
+		}
+	}
+	rule.ResourceNames = resourceNames
+}
+
+// trimTenantIDFromUser returns the name and the groups of the upstream user
+// as seen inside the tenant.
+func trimTenantIDFromUser(tenantID, userName string, groups []string) (string, []string) {
+	if len(userName) == 0 && len(groups) == 0 {
+		return userName, groups
+	}
+	info := util.TrimTenantIDFromUserInfo(tenantID, &user.DefaultInfo{Name: userName, Groups: groups})
+	if groups == nil {
+		return info.GetName(), nil
+	}
+	return info.GetName(), info.GetGroups()
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package convert
+
+import (
+	"reflect"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	authzinternal "k8s.io/kubernetes/pkg/apis/authorization"
+)
+
+func fakeListClusterScopedTenantCRDsFunc(tenantID string) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	return []*apiextensionsv1.CustomResourceDefinition{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "foos." + tenantID + "-kubezoo.io"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: tenantID + "-kubezoo.io",
+				Scope: apiextensionsv1.ClusterScoped,
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "foos", Kind: "Foo"},
+			},
+		},
+	}, nil
+}
+
+// TestSubjectAccessReviewTransformerForward tests the forward method of the
+// SubjectAccessReviewTransformer.
+func TestSubjectAccessReviewTransformerForward(t *testing.T) {
+	cases := []struct {
+		name   string
+		tenant string
+		in     runtime.Object
+		want   runtime.Object
+	}{
+		{
+			name:   "test forward subjectaccessreview of namespaced resource",
+			tenant: "111111",
+			in: &authzinternal.SubjectAccessReview{
+				Spec: authzinternal.SubjectAccessReviewSpec{
+					ResourceAttributes: &authzinternal.ResourceAttributes{Namespace: "default", Verb: "get", Resource: "pods", Name: "my-pod"},
+					User:               "system:serviceaccount:default:my-sa",
+					Groups:             []string{"system:serviceaccounts", "system:serviceaccounts:default"},
+				},
+			},
+			want: &authzinternal.SubjectAccessReview{
+				Spec: authzinternal.SubjectAccessReviewSpec{
+					ResourceAttributes: &authzinternal.ResourceAttributes{Namespace: "111111-default", Verb: "get", Resource: "pods", Name: "my-pod"},
+					User:               "system:serviceaccount:111111-default:my-sa",
+					Groups:             []string{"system:serviceaccounts", "system:serviceaccounts:111111-default"},
+				},
+			},
+		},
+		{
+			name:   "test forward selfsubjectaccessreview of custom resource",
+			tenant: "111111",
+			in: &authzinternal.SelfSubjectAccessReview{
+				Spec: authzinternal.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authzinternal.ResourceAttributes{Verb: "create", Group: "kubezoo.io", Resource: "foos", Name: "my-foo"},
+				},
+			},
+			want: &authzinternal.SelfSubjectAccessReview{
+				Spec: authzinternal.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authzinternal.ResourceAttributes{Verb: "create", Group: "111111-kubezoo.io", Resource: "foos", Name: "111111-my-foo"},
+				},
+			},
+		},
+		{
+			name:   "test forward selfsubjectaccessreview of cluster scoped resource",
+			tenant: "111111",
+			in: &authzinternal.SelfSubjectAccessReview{
+				Spec: authzinternal.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authzinternal.ResourceAttributes{Verb: "delete", Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Name: "my-role"},
+				},
+			},
+			want: &authzinternal.SelfSubjectAccessReview{
+				Spec: authzinternal.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authzinternal.ResourceAttributes{Verb: "delete", Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Name: "111111-my-role"},
+				},
+			},
+		},
+		{
+			name:   "test forward selfsubjectaccessreview of node",
+			tenant: "111111",
+			in: &authzinternal.SelfSubjectAccessReview{
+				Spec: authzinternal.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authzinternal.ResourceAttributes{Verb: "get", Resource: "nodes", Name: "my-node"},
+				},
+			},
+			want: &authzinternal.SelfSubjectAccessReview{
+				Spec: authzinternal.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authzinternal.ResourceAttributes{Verb: "get", Resource: "nodes", Name: "my-node"},
+				},
+			},
+		},
+		{
+			name:   "test forward localsubjectaccessreview",
+			tenant: "111111",
+			in: &authzinternal.LocalSubjectAccessReview{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "111111-default"},
+				Spec: authzinternal.SubjectAccessReviewSpec{
+					ResourceAttributes: &authzinternal.ResourceAttributes{Namespace: "default", Verb: "list", Resource: "secrets"},
+					User:               "bob",
+				},
+			},
+			want: &authzinternal.LocalSubjectAccessReview{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "111111-default"},
+				Spec: authzinternal.SubjectAccessReviewSpec{
+					ResourceAttributes: &authzinternal.ResourceAttributes{Namespace: "111111-default", Verb: "list", Resource: "secrets"},
+					User:               "111111-bob",
+				},
+			},
+		},
+		{
+			name:   "test forward selfsubjectrulesreview",
+			tenant: "111111",
+			in: &authzinternal.SelfSubjectRulesReview{
+				Spec: authzinternal.SelfSubjectRulesReviewSpec{Namespace: "default"},
+			},
+			want: &authzinternal.SelfSubjectRulesReview{
+				Spec: authzinternal.SelfSubjectRulesReviewSpec{Namespace: "111111-default"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := NewSubjectAccessReviewTransformer(fakeListClusterScopedTenantCRDsFunc)
+			if _, err := e.Forward(c.in, c.tenant); err != nil {
+				t.Fatalf("failed to forward subjectaccessreview, err: %+v", err)
+			}
+			if !reflect.DeepEqual(c.in, c.want) {
+				t.Errorf("out: %+v, want: %+v", c.in, c.want)
+			}
+		})
+	}
+}
+
+// TestSubjectAccessReviewTransformerBackward tests the backward method of
+// the SubjectAccessReviewTransformer.
+func TestSubjectAccessReviewTransformerBackward(t *testing.T) {
+	cases := []struct {
+		name   string
+		tenant string
+		in     runtime.Object
+		want   runtime.Object
+	}{
+		{
+			name:   "test backward subjectaccessreview",
+			tenant: "111111",
+			in: &authzinternal.SubjectAccessReview{
+				Spec: authzinternal.SubjectAccessReviewSpec{
+					ResourceAttributes: &authzinternal.ResourceAttributes{Namespace: "111111-default", Verb: "get", Group: "111111-kubezoo.io", Resource: "foos", Name: "111111-my-foo"},
+					User:               "system:serviceaccount:111111-default:my-sa",
+					Groups:             []string{"system:serviceaccounts:111111-default"},
+				},
+				Status: authzinternal.SubjectAccessReviewStatus{
+					Allowed: true,
+					Reason:  `RBAC: allowed by RoleBinding "my-binding/111111-default" of ClusterRole "111111-my-role"`,
+				},
+			},
+			want: &authzinternal.SubjectAccessReview{
+				Spec: authzinternal.SubjectAccessReviewSpec{
+					ResourceAttributes: &authzinternal.ResourceAttributes{Namespace: "default", Verb: "get", Group: "kubezoo.io", Resource: "foos", Name: "my-foo"},
+					User:               "system:serviceaccount:default:my-sa",
+					Groups:             []string{"system:serviceaccounts:default"},
+				},
+				Status: authzinternal.SubjectAccessReviewStatus{
+					Allowed: true,
+					Reason:  `RBAC: allowed by RoleBinding "my-binding/default" of ClusterRole "my-role"`,
+				},
+			},
+		},
+		{
+			name:   "test backward selfsubjectaccessreview of crd",
+			tenant: "111111",
+			in: &authzinternal.SelfSubjectAccessReview{
+				Spec: authzinternal.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authzinternal.ResourceAttributes{Verb: "get", Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions", Name: "foos.111111-kubezoo.io"},
+				},
+			},
+			want: &authzinternal.SelfSubjectAccessReview{
+				Spec: authzinternal.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authzinternal.ResourceAttributes{Verb: "get", Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions", Name: "foos.kubezoo.io"},
+				},
+			},
+		},
+		{
+			name:   "test backward selfsubjectrulesreview",
+			tenant: "111111",
+			in: &authzinternal.SelfSubjectRulesReview{
+				Spec: authzinternal.SelfSubjectRulesReviewSpec{Namespace: "111111-default"},
+				Status: authzinternal.SubjectRulesReviewStatus{
+					ResourceRules: []authzinternal.ResourceRule{
+						{Verbs: []string{"get"}, APIGroups: []string{"111111-kubezoo.io"}, Resources: []string{"foos"}},
+						{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"my-cm", "111111-my-cm"}},
+					},
+					NonResourceRules: []authzinternal.NonResourceRule{
+						{Verbs: []string{"get"}, NonResourceURLs: []string{"/healthz"}},
+					},
+				},
+			},
+			want: &authzinternal.SelfSubjectRulesReview{
+				Spec: authzinternal.SelfSubjectRulesReviewSpec{Namespace: "default"},
+				Status: authzinternal.SubjectRulesReviewStatus{
+					ResourceRules: []authzinternal.ResourceRule{
+						{Verbs: []string{"get"}, APIGroups: []string{"kubezoo.io"}, Resources: []string{"foos"}},
+						{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"my-cm"}},
+					},
+					NonResourceRules: []authzinternal.NonResourceRule{
+						{Verbs: []string{"get"}, NonResourceURLs: []string{"/healthz"}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := NewSubjectAccessReviewTransformer(fakeListClusterScopedTenantCRDsFunc)
+			if _, err := e.Backward(c.in, c.tenant); err != nil {
+				t.Fatalf("failed to backward subjectaccessreview, err: %+v", err)
+			}
+			if !reflect.DeepEqual(c.in, c.want) {
+				t.Errorf("out: %+v, want: %+v", c.in, c.want)
+			}
+		})
+	}
+}
@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"net/http"
 
-	authenv1 "k8s.io/api/authentication/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -31,6 +30,8 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/client-go/rest"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
 )
 
 type dynamicClient struct {
@@ -98,8 +99,11 @@ func setImpersonateHeaders(req *rest.Request, ctx context.Context) {
 	if !exist {
 		return
 	}
-	req.SetHeader(authenv1.ImpersonateUserHeader, ui.GetName())
-	req.SetHeader(authenv1.ImpersonateGroupHeader, ui.GetGroups()...)
+	header := http.Header{}
+	util.SetImpersonateHeaders(header, ui)
+	for key, values := range header {
+		req.SetHeader(key, values...)
+	}
 }
 
 func (c *dynamicResourceClient) Create(ctx context.Context, obj *unstructured.Unstructured, opts metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
@@ -336,12 +340,13 @@ func (c *dynamicResourceClient) Patch(ctx context.Context, name string, pt types
 	if len(name) == 0 {
 		return nil, false, fmt.Errorf("name is required")
 	}
-	result := c.client.client.
+	req := c.client.client.
 		Patch(pt).
 		AbsPath(append(c.makeURLSegments(name), subresources...)...).
 		Body(data).
-		SpecificallyVersionedParams(&opts, dynamicParameterCodec, versionV1).
-		Do(ctx)
+		SpecificallyVersionedParams(&opts, dynamicParameterCodec, versionV1)
+	setImpersonateHeaders(req, ctx)
+	result := req.Do(ctx)
 	if err := result.Error(); err != nil {
 		return nil, false, err
 	}
@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filters
+
+import (
+	"io"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	genericapifilters "k8s.io/apiserver/pkg/endpoints/filters"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// TenantAuditLogPath is the path the tenant admin downloads the audit log
+// of the tenant from.
+const TenantAuditLogPath = "/kubezoo/audit/events"
+
+// TenantAuditLogs opens the audit logs of the tenants.
+type TenantAuditLogs interface {
+	OpenTenantLog(tenantID string) (io.ReadCloser, error)
+}
+
+// WithTenantAuditLog creates an http handler that serves the audit log of
+// the tenant to the tenant admin, one JSON event per line.
+func WithTenantAuditLog(handler http.Handler, logs TenantAuditLogs, s runtime.NegotiatedSerializer) http.Handler {
+	if logs == nil {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		tenantID, ok := util.TenantFrom(ctx)
+		if !ok || req.URL.Path != TenantAuditLogPath {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		attributes, err := genericapifilters.GetAuthorizerAttributes(ctx)
+		if err != nil {
+			responsewriters.InternalError(w, req, err)
+			return
+		}
+		if req.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if u, _ := request.UserFrom(ctx); u.GetName() != util.AddTenantIDPrefix(tenantID, util.TenantAdminUserName) {
+			responsewriters.Forbidden(ctx, attributes, w, req, "only the tenant admin can download the audit log", s)
+			return
+		}
+
+		log, err := logs.OpenTenantLog(tenantID)
+		if err != nil {
+			responsewriters.InternalError(w, req, err)
+			return
+		}
+		defer log.Close()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.Copy(w, log); err != nil {
+			klog.Errorf("failed to send the audit log of tenant %s: %v", tenantID, err)
+		}
+	})
+}
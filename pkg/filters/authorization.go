@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filters
+
+import (
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapifilters "k8s.io/apiserver/pkg/endpoints/filters"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/klog"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// WithTenantAuthorization creates an http handler that authorizes the tenant
+// requests before they are forwarded to the upstream cluster. The requests
+// denied by the authorizer are rejected, while the others are still subject
+// to the authorization of the upstream cluster with the impersonated user.
+func WithTenantAuthorization(handler http.Handler, a authorizer.Authorizer, s runtime.NegotiatedSerializer) http.Handler {
+	if a == nil {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		if _, ok := util.TenantFrom(ctx); !ok {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		attributes, err := genericapifilters.GetAuthorizerAttributes(ctx)
+		if err != nil {
+			responsewriters.InternalError(w, req, err)
+			return
+		}
+		decision, reason, err := a.Authorize(ctx, attributes)
+		if decision == authorizer.DecisionDeny {
+			klog.V(4).Infof("tenant request %s %s of user %s is denied: %s", attributes.GetVerb(), req.URL.Path, attributes.GetUser().GetName(), reason)
+			responsewriters.Forbidden(ctx, attributes, w, req, reason, s)
+			return
+		}
+		if err != nil {
+			responsewriters.InternalError(w, req, err)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filters
+
+import (
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapifilters "k8s.io/apiserver/pkg/endpoints/filters"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// WithTenantImpersonation creates an http handler that lets the tenant users
+// impersonate the identities of their own tenants, e.g. by kubectl --as.
+// The impersonation is authorized by the authorizer with the identities seen
+// inside the tenant, and the impersonated user is then converted to the
+// upstream user of the tenant, which is impersonated in the upstream cluster
+// in place of the requesting user.
+func WithTenantImpersonation(handler http.Handler, a authorizer.Authorizer, s runtime.NegotiatedSerializer) http.Handler {
+	if a == nil {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !hasImpersonateHeaders(req.Header) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		ctx := req.Context()
+		requester, _ := request.UserFrom(ctx)
+		tenantID, ok := util.TenantFrom(ctx)
+		if !ok {
+			attributes, err := genericapifilters.GetAuthorizerAttributes(ctx)
+			if err != nil {
+				responsewriters.InternalError(w, req, err)
+				return
+			}
+			responsewriters.Forbidden(ctx, attributes, w, req, "impersonation is only supported inside the tenants", s)
+			return
+		}
+
+		impersonated := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ctx := req.Context()
+			tenantUser, ok := request.UserFrom(ctx)
+			if !ok {
+				handler.ServeHTTP(w, req)
+				return
+			}
+			upstreamUser := toUpstreamUser(tenantID, tenantUser, requester)
+			audit.LogImpersonatedUser(audit.AuditEventFrom(ctx), upstreamUser)
+			handler.ServeHTTP(w, req.WithContext(request.WithUser(ctx, upstreamUser)))
+		})
+		genericapifilters.WithImpersonation(impersonated, a, s).ServeHTTP(w, req)
+	})
+}
+
+// hasImpersonateHeaders returns whether the request impersonates any user.
+func hasImpersonateHeaders(header http.Header) bool {
+	for key := range header {
+		if key == authenticationv1.ImpersonateUserHeader || key == authenticationv1.ImpersonateGroupHeader ||
+			key == authenticationv1.ImpersonateUIDHeader || strings.HasPrefix(key, authenticationv1.ImpersonateUserExtraHeaderPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// toUpstreamUser returns the upstream user of the tenant user, which keeps
+// the tenant UID and generation the requester is authenticated against.
+func toUpstreamUser(tenantID string, u, requester user.Info) user.Info {
+	groups := append([]string(nil), u.GetGroups()...)
+	extra := map[string][]string{}
+	for k, v := range u.GetExtra() {
+		extra[k] = v
+	}
+	extra[util.TenantIDKey] = []string{tenantID}
+	if requester != nil {
+		for _, k := range []string{util.TenantUIDKey, util.TenantGenerationKey} {
+			if v, ok := requester.GetExtra()[k]; ok {
+				extra[k] = v
+			}
+		}
+	}
+	return &user.DefaultInfo{
+		Name:   util.AddTenantIDToUserName(tenantID, u.GetName()),
+		UID:    u.GetUID(),
+		Groups: util.AddTenantIDToGroups(tenantID, groups),
+		Extra:  extra,
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filters
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	metainternalversionscheme "k8s.io/apimachinery/pkg/apis/meta/internalversion/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// WithPatchRequest creates an http handler that records the raw patch of
+// the patch requests in the context, so that the proxy storage is able to
+// pass it through to the upstream cluster. The request body is restored for
+// the following handlers, which are still responsible for reporting invalid
+// patches.
+func WithPatchRequest(handler http.Handler, maxRequestBodyBytes int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requestInfo, ok := request.RequestInfoFrom(req.Context())
+		if !ok || !requestInfo.IsResourceRequest || requestInfo.Verb != "patch" || req.Body == nil {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		var body io.Reader = req.Body
+		if maxRequestBodyBytes > 0 {
+			body = io.LimitReader(req.Body, maxRequestBodyBytes+1)
+		}
+		data, err := ioutil.ReadAll(body)
+		req.Body.Close()
+		if err != nil {
+			klog.Warningf("failed to read the body of patch request %s: %v", req.URL.Path, err)
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(data))
+		if err != nil || (maxRequestBodyBytes > 0 && int64(len(data)) > maxRequestBodyBytes) {
+			// leave the error to the patch handler
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		contentType := req.Header.Get("Content-Type")
+		// Remove "; charset=" if included in header.
+		if idx := strings.Index(contentType, ";"); idx > 0 {
+			contentType = contentType[:idx]
+		}
+		patch := &util.PatchRequest{
+			Type: types.PatchType(contentType),
+			Data: data,
+		}
+		if err := metainternalversionscheme.ParameterCodec.DecodeParameters(req.URL.Query(), metav1.SchemeGroupVersion, &patch.Options); err != nil {
+			// leave the error to the patch handler
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		req = req.WithContext(util.WithPatchRequest(req.Context(), patch))
+		handler.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filters
+
+import (
+	"net/http"
+
+	"github.com/kubewharf/kubezoo/pkg/quota"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// WithQuotaReview creates an http handler that serves the quota reviews of
+// the tenants, which evaluate the posted manifests against the quotas of the
+// tenant without creating anything. It is installed after the tenant
+// authorization, so that the reviews are subject to it as the other
+// non-resource requests.
+func WithQuotaReview(handler http.Handler, reviewHandler http.Handler) http.Handler {
+	if reviewHandler == nil {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != quota.ReviewPath {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		if _, ok := util.TenantFrom(req.Context()); !ok {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		reviewHandler.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filters
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WithServiceAccountIssuerDiscovery creates an http handler that serves the
+// OpenID discovery documents of the tenant service account issuers under the
+// path prefix. It is installed before the authentication, as the documents
+// are fetched anonymously by the relying parties.
+func WithServiceAccountIssuerDiscovery(handler http.Handler, discoveryHandler http.Handler, pathPrefix string) http.Handler {
+	if discoveryHandler == nil || len(pathPrefix) == 0 {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, pathPrefix) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		discoveryHandler.ServeHTTP(w, r)
+	})
+}
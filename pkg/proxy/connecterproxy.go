@@ -24,7 +24,6 @@ import (
 	"net/url"
 	"strings"
 
-	authenticationv1 "k8s.io/api/authentication/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/httpstream"
 	"k8s.io/apimachinery/pkg/util/proxy"
@@ -120,8 +119,7 @@ func (cp *ConnecterProxy) connect(req *http.Request, w http.ResponseWriter) {
 	if req.Header == nil {
 		req.Header = make(map[string][]string)
 	}
-	req.Header[authenticationv1.ImpersonateUserHeader] = []string{userInfo.GetName()}
-	req.Header[authenticationv1.ImpersonateGroupHeader] = userInfo.GetGroups()
+	util.SetImpersonateHeaders(req.Header, userInfo)
 
 	// decorate response writer to enable metrics
 	delegate := &ResponseWriterDelegator{ResponseWriter: w}
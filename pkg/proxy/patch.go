@@ -0,0 +1,549 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// apply passes the server-side apply patch through to the upstream cluster,
+// so that the managed fields are computed by the upstream cluster and the
+// field ownership is shared between the tenant and upstream managers. The
+// fields injected by the convertor, e.g. the tenant label of the namespaces,
+// are applied along with the fields of the tenant.
+func (tp *tenantProxy) apply(ctx context.Context, name string, patch *util.PatchRequest) (runtime.Object, bool, error) {
+	tenantID, ok := util.TenantFrom(ctx)
+	if !ok {
+		return nil, false, fmt.Errorf("missing tenantID in context")
+	}
+
+	data, err := yaml.YAMLToJSON(patch.Data)
+	if err != nil {
+		return nil, false, errors.NewBadRequest(fmt.Sprintf("error decoding YAML: %v", err))
+	}
+	data, err = tp.translatePatchDocument(data, tenantID, true)
+	if err != nil {
+		klog.V(4).Infof("failed to translate apply patch of %s %s: %v", tp.resource, name, err)
+		return nil, false, errors.NewBadRequest(fmt.Sprintf("failed to translate the apply patch: %v", err))
+	}
+	return tp.patch(ctx, name, patch.Type, data, patch)
+}
+
+// patch sends the translated patch to the upstream cluster, and then
+// converts the response to tenant object.
+func (tp *tenantProxy) patch(ctx context.Context, name string, pt types.PatchType, data []byte, patch *util.PatchRequest) (runtime.Object, bool, error) {
+	tenantID, ok := util.TenantFrom(ctx)
+	if !ok {
+		return nil, false, fmt.Errorf("missing tenantID in context")
+	}
+	client, err := tp.getClient(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if !tp.namespaceScoped && tp.kind.Kind != "Node" {
+		name = util.ConvertTenantObjectNameToUpstream(name, tenantID, tp.kind)
+	}
+
+	var (
+		got     *unstructured.Unstructured
+		created bool
+	)
+	if subresource := tp.subresource; subresource == "" {
+		got, created, err = client.Patch(ctx, name, pt, data, patch.Options)
+	} else {
+		got, created, err = client.Patch(ctx, name, pt, data, patch.Options, subresource)
+	}
+	if err != nil {
+		return nil, false, util.TrimTenantIDFromError(err, tenantID)
+	}
+
+	output, err := tp.convertPatchedObject(got, tenantID)
+	if err != nil {
+		return nil, false, err
+	}
+	// re-check the fields protected by the convertor against the patched
+	// object, and restore them if the patch has removed any
+	restore, err := tp.injectedFieldsOf(output, tenantID)
+	if err != nil {
+		return nil, false, errors.NewInternalError(fmt.Errorf("failed to check the patched %s %s: %v", tp.resource, name, err))
+	}
+	if restore == nil {
+		return output, created, nil
+	}
+	data, err = json.Marshal(restore)
+	if err != nil {
+		return nil, false, err
+	}
+	klog.Warningf("restore the fields of %s %s removed by the %s patch of tenant %s: %s", tp.resource, name, pt, tenantID, data)
+	options := metav1.PatchOptions{DryRun: patch.Options.DryRun, FieldManager: patch.Options.FieldManager}
+	if subresource := tp.subresource; subresource == "" {
+		got, _, err = client.Patch(ctx, name, types.MergePatchType, data, options)
+	} else {
+		got, _, err = client.Patch(ctx, name, types.MergePatchType, data, options, subresource)
+	}
+	if err != nil {
+		return nil, false, util.TrimTenantIDFromError(err, tenantID)
+	}
+	output, err = tp.convertPatchedObject(got, tenantID)
+	if err != nil {
+		return nil, false, err
+	}
+	return output, created, nil
+}
+
+// convertPatchedObject converts the patched upstream object to the tenant
+// object.
+func (tp *tenantProxy) convertPatchedObject(got *unstructured.Unstructured, tenantID string) (runtime.Object, error) {
+	output := tp.New()
+	if err := tp.convertUnstructuredToOutput(got, output); err != nil {
+		return nil, err
+	}
+	if err := tp.convertUpstreamObjectToTenantObject(output, tenantID); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// injectedFieldsOf returns the fields the convertor injects into the tenant
+// object which the upstream object lacks or holds different values of, as
+// a merge patch restoring them, nil if there is none.
+func (tp *tenantProxy) injectedFieldsOf(obj runtime.Object, tenantID string) (map[string]interface{}, error) {
+	utd, err := tp.convertInternalObjectToUnstructuredObject(obj.DeepCopyObject())
+	if err != nil {
+		return nil, err
+	}
+	_, injected, err := tp.translatePartialObject(utd.Object, tenantID)
+	return injected, err
+}
+
+// translatePatch translates the json, merge or strategic merge patch from
+// the tenant view to the upstream view. An error is returned if the patch
+// can't be translated safely, in which case the patch should be applied to
+// the tenant object locally instead. This includes the patches which may
+// remove the fields the convertor protects, i.e. the removals, the nulls,
+// the replacements of whole objects and the strategic merge patch
+// directives, and the patches of the resources whose convertor injects
+// fields, e.g. the tenant label of the namespaces.
+func (tp *tenantProxy) translatePatch(ctx context.Context, patch *util.PatchRequest) ([]byte, error) {
+	tenantID, ok := util.TenantFrom(ctx)
+	if !ok {
+		return nil, fmt.Errorf("missing tenantID in context")
+	}
+	switch patch.Type {
+	case types.JSONPatchType:
+		return tp.translateJSONPatch(patch.Data, tenantID)
+	case types.MergePatchType, types.StrategicMergePatchType:
+		return tp.translatePatchDocument(patch.Data, tenantID, false)
+	default:
+		return nil, fmt.Errorf("unsupported patch type %q", patch.Type)
+	}
+}
+
+// translateJSONPatch translates the values of the json patch operations.
+// Each value is placed at its path in an otherwise empty object, so that it
+// is translated as a partial object. The move and copy operations are not
+// supported, as the values moved or copied in the upstream object are in
+// the upstream view already, nor are the remove operations and the objects
+// added or replaced other than as array elements, which may remove the
+// fields the convertor protects.
+func (tp *tenantProxy) translateJSONPatch(data []byte, tenantID string) ([]byte, error) {
+	var operations []map[string]interface{}
+	if err := utiljson.Unmarshal(data, &operations); err != nil {
+		return nil, err
+	}
+	for _, operation := range operations {
+		op, _ := operation["op"].(string)
+		switch op {
+		case "add", "replace", "test":
+			value, ok := operation["value"]
+			if !ok {
+				// leave the error to the upstream cluster
+				continue
+			}
+			path, _ := operation["path"].(string)
+			if op != "test" {
+				if err := checkJSONPatchValue(op, path, value); err != nil {
+					return nil, err
+				}
+			}
+			translated, err := tp.translatePatchValue(path, value, tenantID)
+			if err != nil {
+				return nil, err
+			}
+			operation["value"] = translated
+		default:
+			return nil, fmt.Errorf("unsupported json patch operation %q", op)
+		}
+	}
+	return json.Marshal(operations)
+}
+
+// translatePatchValue translates the value of a json patch operation at path.
+func (tp *tenantProxy) translatePatchValue(path string, value interface{}, tenantID string) (interface{}, error) {
+	tokens, err := parseJSONPointer(path)
+	if err != nil {
+		return nil, err
+	}
+	partial := value
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if isJSONArrayIndex(tokens[i]) {
+			partial = []interface{}{partial}
+		} else {
+			partial = map[string]interface{}{tokens[i]: partial}
+		}
+	}
+	object, ok := partial.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("value at path %q is not an object", path)
+	}
+	translated, injected, err := tp.translatePartialObject(object, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if injected != nil {
+		return nil, fmt.Errorf("the convertor of %s injects fields", tp.resource)
+	}
+
+	var out interface{} = translated
+	for _, token := range tokens {
+		switch o := out.(type) {
+		case map[string]interface{}:
+			out = o[token]
+		case []interface{}:
+			out = o[0]
+		}
+	}
+	return out, nil
+}
+
+// parseJSONPointer splits the json pointer defined by RFC 6901 into tokens.
+func parseJSONPointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("invalid json pointer %q", path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(tokens[i], "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// isJSONArrayIndex returns whether the json pointer token refers to an array
+// element. Map keys made of digits are treated as indexes as well, in which
+// case the conversion of typed objects fails and the patch is applied locally.
+func isJSONArrayIndex(token string) bool {
+	if token == "-" {
+		return true
+	}
+	if token == "" {
+		return false
+	}
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// translatePatchDocument translates the apply, merge or strategic merge patch
+// document, which is a partial object, from the tenant view to the upstream
+// view. The fields injected by the convertor are merged into the apply
+// patches, whereas the other patches are rejected along with the removals.
+func (tp *tenantProxy) translatePatchDocument(data []byte, tenantID string, apply bool) ([]byte, error) {
+	var document map[string]interface{}
+	if err := utiljson.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	if document == nil {
+		return nil, fmt.Errorf("patch document is not an object")
+	}
+	if !apply {
+		if err := checkPatchRemovals(document, ""); err != nil {
+			return nil, err
+		}
+	}
+	translated, injected, err := tp.translatePartialObject(document, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if injected != nil {
+		if !apply {
+			return nil, fmt.Errorf("the convertor of %s injects fields", tp.resource)
+		}
+		translated = mergePartialObject(translated, injected).(map[string]interface{})
+	}
+	return json.Marshal(translated)
+}
+
+// mergePartialObject merges the fields of patch into the partial object.
+func mergePartialObject(partial, patch interface{}) interface{} {
+	p, ok := partial.(map[string]interface{})
+	q, ok2 := patch.(map[string]interface{})
+	if !ok || !ok2 {
+		return patch
+	}
+	out := make(map[string]interface{}, len(p)+len(q))
+	for k, v := range p {
+		out[k] = v
+	}
+	for k, v := range q {
+		out[k] = mergePartialObject(p[k], v)
+	}
+	return out
+}
+
+// patchDirectives are the strategic merge patch directives, or the prefixes
+// of them, which remove or replace the fields.
+var patchDirectives = []string{"$patch", "$retainKeys", "$setElementOrder/", "$deleteFromPrimitiveList/"}
+
+// checkPatchRemovals returns an error if the merge or strategic merge patch
+// document removes fields, i.e. it has a null value or a directive.
+func checkPatchRemovals(value interface{}, path string) error {
+	switch v := value.(type) {
+	case nil:
+		return fmt.Errorf("null value at %q", path)
+	case map[string]interface{}:
+		for key, child := range v {
+			for _, directive := range patchDirectives {
+				if strings.HasPrefix(key, directive) {
+					return fmt.Errorf("directive %q at %q", key, path)
+				}
+			}
+			if err := checkPatchRemovals(child, path+"/"+key); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			if err := checkPatchRemovals(child, fmt.Sprintf("%s/%d", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkJSONPatchValue returns an error if the add or replace operation of
+// the json patch may remove fields, i.e. the value is null or has a null,
+// or the value is an object which replaces the object at path as a whole,
+// unless it is added as an array element.
+func checkJSONPatchValue(op, path string, value interface{}) error {
+	if err := checkPatchRemovals(value, path); err != nil {
+		return err
+	}
+	if _, ok := value.(map[string]interface{}); !ok {
+		return nil
+	}
+	tokens, err := parseJSONPointer(path)
+	if err != nil {
+		return err
+	}
+	if op == "add" && len(tokens) > 0 && isJSONArrayIndex(tokens[len(tokens)-1]) {
+		return nil
+	}
+	return fmt.Errorf("json patch operation %q of object at %q", op, path)
+}
+
+// translatePartialObject translates a partial object with the convertor of
+// the resource. The partial object is converted as a whole object, and only
+// the fields present in the partial object are picked from the result, so
+// that the zero values of the absent fields are not introduced. The fields
+// the convertor injects beyond the partial object, e.g. the tenant label of
+// the namespaces, are returned as another partial object, nil if none.
+func (tp *tenantProxy) translatePartialObject(partial map[string]interface{}, tenantID string) (translated, injected map[string]interface{}, err error) {
+	if err := checkPartialReferences(partial); err != nil {
+		return nil, nil, err
+	}
+	obj := tp.New()
+	if obj == nil {
+		return nil, nil, fmt.Errorf("newFunc is nil")
+	}
+	utd := &unstructured.Unstructured{Object: runtime.DeepCopyJSON(partial)}
+	if err := tp.convertUnstructuredToOutput(utd, obj); err != nil {
+		return nil, nil, err
+	}
+	if _, ok := obj.(*unstructured.Unstructured); !ok {
+		obj.GetObjectKind().SetGroupVersionKind(tp.kind)
+	}
+	// the object converted without the convertor, by which the fields the
+	// convertor injects are told from the zero values of the conversion
+	unconverted, err := tp.convertInternalObjectToUnstructuredObject(obj.DeepCopyObject())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := tp.convertTenantObjectToUpstreamObject(obj, tenantID); err != nil {
+		return nil, nil, err
+	}
+	converted, err := tp.convertInternalObjectToUnstructuredObject(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+	translated = projectPartialObject(partial, converted.Object).(map[string]interface{})
+	if fields, ok := injectedFields(partial, unconverted.Object, converted.Object).(map[string]interface{}); ok {
+		injected = fields
+	}
+	return translated, injected, nil
+}
+
+// injectedFields returns the fields of converted absent from partial whose
+// values differ from the ones of unconverted, i.e. the fields injected by
+// the convertor, nil if none.
+func injectedFields(partial, unconverted, converted interface{}) interface{} {
+	c, ok := converted.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	p, _ := partial.(map[string]interface{})
+	u, _ := unconverted.(map[string]interface{})
+	var out map[string]interface{}
+	for k, cv := range c {
+		var fields interface{}
+		if pv, ok := p[k]; ok {
+			fields = injectedFields(pv, u[k], cv)
+		} else if !apiequality.Semantic.DeepEqual(u[k], cv) {
+			fields = injectedFields(nil, u[k], cv)
+			if fields == nil {
+				fields = cv
+			}
+		}
+		if fields == nil {
+			continue
+		}
+		if out == nil {
+			out = map[string]interface{}{}
+		}
+		out[k] = fields
+	}
+	if out == nil {
+		return nil
+	}
+	return out
+}
+
+// partialReference describes the references whose translation depends on
+// their sibling fields, e.g. the name of a subject is prefixed only if its
+// kind is User, which can't be translated if the fields are partial.
+type partialReference struct {
+	// path is the path of the reference in the object.
+	path []string
+	// list is true if the path holds a list of the references.
+	list bool
+	// fields are the fields the translation of the reference depends on.
+	fields []string
+}
+
+// partialReferences are the references which must be complete in the partial
+// objects.
+var partialReferences = []partialReference{
+	{path: []string{"subjects"}, list: true, fields: []string{"kind", "name"}},
+	{path: []string{"roleRef"}, fields: []string{"kind", "name"}},
+	{path: []string{"metadata", "ownerReferences"}, list: true, fields: []string{"apiVersion", "kind", "name"}},
+}
+
+// checkPartialReferences returns an error if a reference in the partial
+// object lacks any of the fields its translation depends on, e.g. the json
+// patch replacing the name of a subject only, or the strategic merge patch
+// of an owner reference merged by its uid, in which case the patch has to
+// be applied to the tenant object locally.
+func checkPartialReferences(partial map[string]interface{}) error {
+	for _, ref := range partialReferences {
+		value, found, err := unstructured.NestedFieldNoCopy(partial, ref.path...)
+		if err != nil || !found || value == nil {
+			continue
+		}
+		refs := []interface{}{value}
+		if ref.list {
+			items, ok := value.([]interface{})
+			if !ok {
+				return fmt.Errorf("%s is not a list", strings.Join(ref.path, "."))
+			}
+			refs = items
+		}
+		for _, r := range refs {
+			fields, ok := r.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s is not an object", strings.Join(ref.path, "."))
+			}
+			for _, field := range ref.fields {
+				if _, ok := fields[field]; !ok {
+					return fmt.Errorf("%s without %s can not be translated", strings.Join(ref.path, "."), field)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// projectPartialObject returns the values of translated at the fields present
+// in partial. Fields absent from translated, e.g. patch directives or fields
+// dropped by the conversion, keep the values of partial.
+func projectPartialObject(partial, translated interface{}) interface{} {
+	switch p := partial.(type) {
+	case map[string]interface{}:
+		t, ok := translated.(map[string]interface{})
+		if !ok {
+			return partial
+		}
+		out := make(map[string]interface{}, len(p))
+		for k, v := range p {
+			if tv, ok := t[k]; ok {
+				out[k] = projectPartialObject(v, tv)
+			} else {
+				out[k] = v
+			}
+		}
+		return out
+	case []interface{}:
+		t, ok := translated.([]interface{})
+		if !ok || len(t) != len(p) {
+			return partial
+		}
+		out := make([]interface{}, len(p))
+		for i := range p {
+			out[i] = projectPartialObject(p[i], t[i])
+		}
+		return out
+	case nil:
+		// null removes the field in merge patches
+		return nil
+	default:
+		switch translated.(type) {
+		case map[string]interface{}, []interface{}, nil:
+			return partial
+		}
+		return translated
+	}
+}
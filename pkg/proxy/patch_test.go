@@ -0,0 +1,680 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsapiv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacapiv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/kubernetes/pkg/apis/apps"
+	"k8s.io/kubernetes/pkg/apis/core"
+	"k8s.io/kubernetes/pkg/apis/rbac"
+
+	"github.com/kubewharf/kubezoo/pkg/common"
+	"github.com/kubewharf/kubezoo/pkg/convert"
+	"github.com/kubewharf/kubezoo/pkg/dynamic"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// TestTenantProxyApply tests the server-side apply patches are passed through
+// to the upstream cluster.
+func TestTenantProxyApply(t *testing.T) {
+	tenantID := "test01"
+	tenantNamespace := "default"
+	upstreamNamespace := util.AddTenantIDPrefix(tenantID, tenantNamespace)
+	deploymentName := "foo"
+	replicas := int32(3)
+	upstreamDeployment := appsapiv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: upstreamNamespace,
+			Name:      deploymentName,
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply, APIVersion: "apps/v1"},
+			},
+		},
+		Spec: appsapiv1.DeploymentSpec{Replicas: &replicas},
+	}
+
+	fakeUpstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != fmt.Sprintf("/apis/apps/v1/namespaces/%s/deployments/%s", upstreamNamespace, deploymentName) {
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+			return
+		}
+		assert.Equal(t, string(types.ApplyPatchType), r.Header.Get("Content-Type"))
+		assert.Equal(t, "kubectl", r.URL.Query().Get("fieldManager"))
+		assert.Equal(t, "true", r.URL.Query().Get("force"))
+
+		data, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(data, &body))
+		expected := map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      deploymentName,
+				"namespace": upstreamNamespace,
+			},
+			"spec": map[string]interface{}{
+				"replicas": float64(replicas),
+			},
+		}
+		assert.Equal(t, expected, body)
+
+		deployment, err := json.Marshal(upstreamDeployment)
+		assert.NoError(t, err)
+		w.Write(deployment)
+	}))
+	defer fakeUpstream.Close()
+	client := dynamic.NewForConfigOrDie(&restclient.Config{Host: fakeUpstream.URL})
+	config := common.StorageConfig{
+		Kind:            appsapiv1.SchemeGroupVersion.WithKind("Deployment"),
+		Resource:        "deployments",
+		NamespaceScoped: true,
+		NewFunc:         func() runtime.Object { return &apps.Deployment{} },
+		NewListFunc:     func() runtime.Object { return &apps.DeploymentList{} },
+		DynamicClient:   client,
+		Convertor:       &fakeConvertor{},
+	}
+	proxy, err := NewTenantProxy(config)
+	assert.NoError(t, err)
+	updater, ok := proxy.(rest.Updater)
+	if !ok {
+		t.Errorf("tenant proxy should implement rest.Updater")
+	}
+
+	force := true
+	ctx := tenantContext(tenantID, &request.RequestInfo{
+		Verb:      "patch",
+		Namespace: tenantNamespace,
+	})
+	ctx = util.WithPatchRequest(ctx, &util.PatchRequest{
+		Type: types.ApplyPatchType,
+		Data: []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  namespace: default
+spec:
+  replicas: 3
+`),
+		Options: metav1.PatchOptions{FieldManager: "kubectl", Force: &force},
+	})
+	failObjectInfo := rest.DefaultUpdatedObjectInfo(nil, func(ctx context.Context, newObj, oldObj runtime.Object) (runtime.Object, error) {
+		t.Errorf("apply patch should not be applied locally")
+		return newObj, nil
+	})
+
+	obj, created, err := updater.Update(ctx, deploymentName, failObjectInfo, nil, nil, true, &metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, false, created)
+	accessor, err := meta.Accessor(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, tenantNamespace, accessor.GetNamespace())
+	assert.Equal(t, 1, len(accessor.GetManagedFields()))
+}
+
+// TestTenantProxyApplyNamespace tests the fields injected by the convertor,
+// i.e. the tenant label, are applied along with the applied namespace.
+func TestTenantProxyApplyNamespace(t *testing.T) {
+	tenantID := "test01"
+	namespaceName := "foo"
+	upstreamName := util.AddTenantIDPrefix(tenantID, namespaceName)
+
+	cases := map[string]struct {
+		patch          string
+		expectedLabels map[string]interface{}
+	}{
+		"without labels": {
+			patch: `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"foo"}}`,
+			expectedLabels: map[string]interface{}{
+				common.TenantNamespaceLabelKey: tenantID,
+			},
+		},
+		"with labels": {
+			patch: `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"foo","labels":{"app":"foo"}}}`,
+			expectedLabels: map[string]interface{}{
+				"app":                          "foo",
+				common.TenantNamespaceLabelKey: tenantID,
+			},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			fakeUpstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPatch || r.URL.Path != fmt.Sprintf("/api/v1/namespaces/%s", upstreamName) {
+					t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+					return
+				}
+				assert.Equal(t, string(types.ApplyPatchType), r.Header.Get("Content-Type"))
+				data, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				var body map[string]interface{}
+				assert.NoError(t, json.Unmarshal(data, &body))
+				metadata, _ := body["metadata"].(map[string]interface{})
+				assert.Equal(t, upstreamName, metadata["name"])
+				assert.Equal(t, c.expectedLabels, metadata["labels"])
+
+				upstreamNamespace := corev1.Namespace{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: "v1",
+						Kind:       "Namespace",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   upstreamName,
+						Labels: map[string]string{},
+					},
+				}
+				for k, v := range c.expectedLabels {
+					upstreamNamespace.Labels[k] = v.(string)
+				}
+				namespace, err := json.Marshal(upstreamNamespace)
+				assert.NoError(t, err)
+				w.Write(namespace)
+			}))
+			defer fakeUpstream.Close()
+			client := dynamic.NewForConfigOrDie(&restclient.Config{Host: fakeUpstream.URL})
+			config := common.StorageConfig{
+				Kind:          corev1.SchemeGroupVersion.WithKind("Namespace"),
+				Resource:      "namespaces",
+				NewFunc:       func() runtime.Object { return &core.Namespace{} },
+				NewListFunc:   func() runtime.Object { return &core.NamespaceList{} },
+				DynamicClient: client,
+				Convertor:     newNamespaceConvertor(),
+			}
+			proxy, err := NewTenantProxy(config)
+			assert.NoError(t, err)
+			updater, ok := proxy.(rest.Updater)
+			if !ok {
+				t.Errorf("tenant proxy should implement rest.Updater")
+			}
+
+			ctx := tenantContext(tenantID, &request.RequestInfo{Verb: "patch", Name: namespaceName})
+			ctx = util.WithPatchRequest(ctx, &util.PatchRequest{
+				Type:    types.ApplyPatchType,
+				Data:    []byte(c.patch),
+				Options: metav1.PatchOptions{FieldManager: "kubectl"},
+			})
+			failObjectInfo := rest.DefaultUpdatedObjectInfo(nil, func(ctx context.Context, newObj, oldObj runtime.Object) (runtime.Object, error) {
+				t.Errorf("apply patch should not be applied locally")
+				return newObj, nil
+			})
+
+			obj, _, err := updater.Update(ctx, namespaceName, failObjectInfo, nil, nil, true, &metav1.UpdateOptions{})
+			assert.NoError(t, err)
+			accessor, err := meta.Accessor(obj)
+			assert.NoError(t, err)
+			assert.Equal(t, namespaceName, accessor.GetName())
+			assert.Equal(t, tenantID, accessor.GetLabels()[common.TenantNamespaceLabelKey])
+		})
+	}
+}
+
+// TestProjectPartialObject tests only the fields present in the partial
+// object are picked from the translated object.
+func TestProjectPartialObject(t *testing.T) {
+	partial := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"namespace": "default",
+			"labels":    nil,
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "a", "$patch": "delete"},
+			},
+		},
+	}
+	translated := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"namespace":         "test01-default",
+			"creationTimestamp": nil,
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "a", "image": ""},
+			},
+			"restartPolicy": "",
+		},
+	}
+	expected := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"namespace": "test01-default",
+			"labels":    nil,
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "a", "$patch": "delete"},
+			},
+		},
+	}
+	assert.Equal(t, expected, projectPartialObject(partial, translated))
+}
+
+// TestTenantProxyPatch tests the merge patches are passed through to the
+// upstream cluster as a single patch request.
+func TestTenantProxyPatch(t *testing.T) {
+	tenantID := "test01"
+	tenantNamespace := "default"
+	upstreamNamespace := util.AddTenantIDPrefix(tenantID, tenantNamespace)
+	deploymentName := "foo"
+	upstreamDeployment := appsapiv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: upstreamNamespace,
+			Name:      deploymentName,
+			Labels:    map[string]string{"app": "foo"},
+		},
+	}
+
+	fakeUpstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != fmt.Sprintf("/apis/apps/v1/namespaces/%s/deployments/%s", upstreamNamespace, deploymentName) {
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+			return
+		}
+		assert.Equal(t, string(types.MergePatchType), r.Header.Get("Content-Type"))
+		data, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, fmt.Sprintf(`{"metadata":{"namespace":%q,"labels":{"app":"foo"}}}`, upstreamNamespace), string(data))
+
+		deployment, err := json.Marshal(upstreamDeployment)
+		assert.NoError(t, err)
+		w.Write(deployment)
+	}))
+	defer fakeUpstream.Close()
+	client := dynamic.NewForConfigOrDie(&restclient.Config{Host: fakeUpstream.URL})
+	config := common.StorageConfig{
+		Kind:            appsapiv1.SchemeGroupVersion.WithKind("Deployment"),
+		Resource:        "deployments",
+		NamespaceScoped: true,
+		NewFunc:         func() runtime.Object { return &apps.Deployment{} },
+		NewListFunc:     func() runtime.Object { return &apps.DeploymentList{} },
+		DynamicClient:   client,
+		Convertor:       &fakeConvertor{},
+	}
+	proxy, err := NewTenantProxy(config)
+	assert.NoError(t, err)
+	updater, ok := proxy.(rest.Updater)
+	if !ok {
+		t.Errorf("tenant proxy should implement rest.Updater")
+	}
+
+	ctx := tenantContext(tenantID, &request.RequestInfo{
+		Verb:      "patch",
+		Namespace: tenantNamespace,
+	})
+	ctx = util.WithPatchRequest(ctx, &util.PatchRequest{
+		Type: types.MergePatchType,
+		Data: []byte(`{"metadata":{"namespace":"default","labels":{"app":"foo"}}}`),
+	})
+	failObjectInfo := rest.DefaultUpdatedObjectInfo(nil, func(ctx context.Context, newObj, oldObj runtime.Object) (runtime.Object, error) {
+		t.Errorf("merge patch should not be applied locally")
+		return newObj, nil
+	})
+
+	obj, _, err := updater.Update(ctx, deploymentName, failObjectInfo, nil, nil, false, &metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	accessor, err := meta.Accessor(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, tenantNamespace, accessor.GetNamespace())
+	assert.Equal(t, map[string]string{"app": "foo"}, accessor.GetLabels())
+}
+
+// TestTenantProxyTranslatePatch tests the translation of the patches, and
+// the patches which can't be translated are reported as errors.
+func TestTenantProxyTranslatePatch(t *testing.T) {
+	tp := &tenantProxy{
+		kind:            appsapiv1.SchemeGroupVersion.WithKind("Deployment"),
+		resource:        "deployments",
+		namespaceScoped: true,
+		newFunc:         func() runtime.Object { return &apps.Deployment{} },
+		convertor:       &fakeConvertor{},
+	}
+	ctx := tenantContext("test01", &request.RequestInfo{Verb: "patch", Namespace: "default"})
+
+	cases := map[string]struct {
+		patchType types.PatchType
+		patch     string
+		expected  string
+		expectErr bool
+	}{
+		"merge patch": {
+			patchType: types.MergePatchType,
+			patch:     `{"metadata":{"namespace":"default","labels":{"app":"foo"}},"spec":{"replicas":2}}`,
+			expected:  `{"metadata":{"namespace":"test01-default","labels":{"app":"foo"}},"spec":{"replicas":2}}`,
+		},
+		"merge patch with null": {
+			patchType: types.MergePatchType,
+			patch:     `{"metadata":{"namespace":"default","labels":{"app":null}}}`,
+			expectErr: true,
+		},
+		"merge patch with null labels": {
+			patchType: types.MergePatchType,
+			patch:     `{"metadata":{"labels":null}}`,
+			expectErr: true,
+		},
+		"strategic merge patch": {
+			patchType: types.StrategicMergePatchType,
+			patch:     `{"metadata":{"namespace":"default"},"spec":{"template":{"spec":{"containers":[{"name":"a","image":"nginx"}]}}}}`,
+			expected:  `{"metadata":{"namespace":"test01-default"},"spec":{"template":{"spec":{"containers":[{"name":"a","image":"nginx"}]}}}}`,
+		},
+		"strategic merge patch with patch directive": {
+			patchType: types.StrategicMergePatchType,
+			patch:     `{"metadata":{"labels":{"$patch":"replace"}}}`,
+			expectErr: true,
+		},
+		"strategic merge patch with retain keys directive": {
+			patchType: types.StrategicMergePatchType,
+			patch:     `{"metadata":{"$retainKeys":["name"]}}`,
+			expectErr: true,
+		},
+		"strategic merge patch with set element order directive": {
+			patchType: types.StrategicMergePatchType,
+			patch:     `{"spec":{"template":{"spec":{"$setElementOrder/containers":[{"name":"b"},{"name":"a"}]}}}}`,
+			expectErr: true,
+		},
+		"strategic merge patch with delete from primitive list directive": {
+			patchType: types.StrategicMergePatchType,
+			patch:     `{"metadata":{"$deleteFromPrimitiveList/finalizers":["foo"]}}`,
+			expectErr: true,
+		},
+		"json patch": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"test","path":"/metadata/namespace","value":"default"},{"op":"replace","path":"/spec/replicas","value":2},{"op":"add","path":"/metadata/labels/app","value":"foo"}]`,
+			expected:  `[{"op":"test","path":"/metadata/namespace","value":"test01-default"},{"op":"replace","path":"/spec/replicas","value":2},{"op":"add","path":"/metadata/labels/app","value":"foo"}]`,
+		},
+		"json patch with remove operation": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"remove","path":"/metadata/labels/kubezoo.io~1tenant"}]`,
+			expectErr: true,
+		},
+		"json patch replacing map": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"replace","path":"/metadata/labels","value":{}}]`,
+			expectErr: true,
+		},
+		"json patch adding parent": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"add","path":"/metadata","value":{"namespace":"default"}}]`,
+			expectErr: true,
+		},
+		"json patch with null value": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"replace","path":"/metadata/labels/app","value":null}]`,
+			expectErr: true,
+		},
+		"json patch of array element": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"add","path":"/spec/template/spec/containers/-","value":{"name":"a","image":"nginx"}}]`,
+			expected:  `[{"op":"add","path":"/spec/template/spec/containers/-","value":{"name":"a","image":"nginx"}}]`,
+		},
+		"json patch with move operation": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"move","from":"/metadata/namespace","path":"/metadata/labels/ns"}]`,
+			expectErr: true,
+		},
+		"json patch with digit map key": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"add","path":"/metadata/labels/0","value":"foo"}]`,
+			expectErr: true,
+		},
+		"invalid json pointer": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"add","path":"metadata","value":{}}]`,
+			expectErr: true,
+		},
+		"null merge patch": {
+			patchType: types.MergePatchType,
+			patch:     `null`,
+			expectErr: true,
+		},
+		"apply patch": {
+			patchType: types.ApplyPatchType,
+			patch:     `{}`,
+			expectErr: true,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, err := tp.translatePatch(ctx, &util.PatchRequest{Type: c.patchType, Data: []byte(c.patch)})
+			if c.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.JSONEq(t, c.expected, string(data))
+		})
+	}
+}
+
+// TestTenantProxyTranslatePatchReferences tests the patches of the partial
+// references, whose translation depends on their sibling fields, are not
+// translated, so that they are applied to the tenant object locally.
+func TestTenantProxyTranslatePatchReferences(t *testing.T) {
+	tp := &tenantProxy{
+		kind:            rbacapiv1.SchemeGroupVersion.WithKind("RoleBinding"),
+		resource:        "rolebindings",
+		namespaceScoped: true,
+		newFunc:         func() runtime.Object { return &rbac.RoleBinding{} },
+		convertor:       &fakeConvertor{},
+	}
+	ctx := tenantContext("test01", &request.RequestInfo{Verb: "patch", Namespace: "default"})
+
+	cases := map[string]struct {
+		patchType types.PatchType
+		patch     string
+		expectErr bool
+	}{
+		"json patch of subject name": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"replace","path":"/subjects/0/name","value":"admin"}]`,
+			expectErr: true,
+		},
+		"json patch of subject namespace": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"replace","path":"/subjects/0/namespace","value":"kube-system"}]`,
+			expectErr: true,
+		},
+		"json patch of subject kind": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"replace","path":"/subjects/0/kind","value":"User"}]`,
+			expectErr: true,
+		},
+		"json patch of role ref name": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"replace","path":"/roleRef/name","value":"cluster-admin"}]`,
+			expectErr: true,
+		},
+		"json patch of owner reference name": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"replace","path":"/metadata/ownerReferences/0/name","value":"foo"}]`,
+			expectErr: true,
+		},
+		"strategic merge patch of owner reference by uid": {
+			patchType: types.StrategicMergePatchType,
+			patch:     `{"metadata":{"ownerReferences":[{"uid":"123","name":"foo"}]}}`,
+			expectErr: true,
+		},
+		"merge patch of role ref name": {
+			patchType: types.MergePatchType,
+			patch:     `{"roleRef":{"name":"cluster-admin"}}`,
+			expectErr: true,
+		},
+		"json patch of whole subject": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"add","path":"/subjects/-","value":{"kind":"User","name":"admin"}}]`,
+		},
+		"json patch removing subject": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"remove","path":"/subjects/0"}]`,
+			expectErr: true,
+		},
+		"merge patch of whole subjects": {
+			patchType: types.MergePatchType,
+			patch:     `{"subjects":[{"kind":"User","name":"admin"}]}`,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tp.translatePatch(ctx, &util.PatchRequest{Type: c.patchType, Data: []byte(c.patch)})
+			if c.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// newNamespaceConvertor returns the convertor of the namespaces, which
+// injects the tenant label.
+func newNamespaceConvertor() common.ObjectConvertor {
+	checkGroupKind := func(group, kind, tenantID string, isTenantObject bool) (bool, bool, error) {
+		return true, false, nil
+	}
+	return convert.NewCrossReferenceConverter(convert.NewDefaultConvertor(convert.NewOwnerReferenceTransformer(checkGroupKind)), convert.NewNamespaceTransformer())
+}
+
+// TestTenantProxyTranslateNamespacePatch tests the patches of the namespaces,
+// whose convertor injects the tenant label, are not translated, so that the
+// tenant label can't be removed by the patches.
+func TestTenantProxyTranslateNamespacePatch(t *testing.T) {
+	tp := &tenantProxy{
+		kind:      corev1.SchemeGroupVersion.WithKind("Namespace"),
+		resource:  "namespaces",
+		newFunc:   func() runtime.Object { return &core.Namespace{} },
+		convertor: newNamespaceConvertor(),
+	}
+	ctx := tenantContext("test01", &request.RequestInfo{Verb: "patch", Name: "foo"})
+
+	cases := map[string]struct {
+		patchType types.PatchType
+		patch     string
+	}{
+		"json patch removing tenant label": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"remove","path":"/metadata/labels/kubezoo.io~1tenant"}]`,
+		},
+		"json patch replacing labels": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"replace","path":"/metadata/labels","value":{}}]`,
+		},
+		"json patch of label": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op":"add","path":"/metadata/labels/app","value":"foo"}]`,
+		},
+		"merge patch of null labels": {
+			patchType: types.MergePatchType,
+			patch:     `{"metadata":{"labels":null}}`,
+		},
+		"merge patch of label": {
+			patchType: types.MergePatchType,
+			patch:     `{"metadata":{"labels":{"app":"foo"}}}`,
+		},
+		"strategic merge patch replacing labels": {
+			patchType: types.StrategicMergePatchType,
+			patch:     `{"metadata":{"labels":{"$patch":"replace","app":"foo"}}}`,
+		},
+		"strategic merge patch retaining keys": {
+			patchType: types.StrategicMergePatchType,
+			patch:     `{"$retainKeys":["spec"],"spec":{}}`,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tp.translatePatch(ctx, &util.PatchRequest{Type: c.patchType, Data: []byte(c.patch)})
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestTenantProxyPatchRestoresInjectedFields tests the fields injected by the
+// convertor are restored if the patched upstream object lacks them.
+func TestTenantProxyPatchRestoresInjectedFields(t *testing.T) {
+	tenantID := "test01"
+	namespaceName := "foo"
+	upstreamName := util.AddTenantIDPrefix(tenantID, namespaceName)
+	upstreamNamespace := corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Namespace",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   upstreamName,
+			Labels: map[string]string{"app": "foo"},
+		},
+	}
+
+	var patches []string
+	fakeUpstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != fmt.Sprintf("/api/v1/namespaces/%s", upstreamName) {
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+			return
+		}
+		data, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		patches = append(patches, string(data))
+		if len(patches) > 1 {
+			assert.Equal(t, string(types.MergePatchType), r.Header.Get("Content-Type"))
+			upstreamNamespace.Labels[common.TenantNamespaceLabelKey] = tenantID
+		}
+
+		namespace, err := json.Marshal(upstreamNamespace)
+		assert.NoError(t, err)
+		w.Write(namespace)
+	}))
+	defer fakeUpstream.Close()
+	tp := &tenantProxy{
+		kind:          corev1.SchemeGroupVersion.WithKind("Namespace"),
+		resource:      "namespaces",
+		newFunc:       func() runtime.Object { return &core.Namespace{} },
+		dynamicClient: dynamic.NewForConfigOrDie(&restclient.Config{Host: fakeUpstream.URL}),
+		convertor:     newNamespaceConvertor(),
+	}
+	ctx := tenantContext(tenantID, &request.RequestInfo{Verb: "patch", Name: namespaceName})
+
+	obj, _, err := tp.patch(ctx, namespaceName, types.MergePatchType, []byte(`{"metadata":{"labels":{"app":"foo"}}}`), &util.PatchRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(patches))
+	assert.JSONEq(t, fmt.Sprintf(`{"metadata":{"labels":{%q:%q}}}`, common.TenantNamespaceLabelKey, tenantID), patches[1])
+	accessor, err := meta.Accessor(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, namespaceName, accessor.GetName())
+	assert.Equal(t, tenantID, accessor.GetLabels()[common.TenantNamespaceLabelKey])
+}
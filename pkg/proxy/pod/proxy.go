@@ -3,7 +3,6 @@ package pod
 import (
 	"context"
 	"github.com/kubewharf/kubezoo/pkg/util"
-	authenticationv1 "k8s.io/api/authentication/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/proxy"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
@@ -104,8 +103,7 @@ func (r *ProxyREST) Connect(ctx context.Context, id string, opts runtime.Object,
 		if req.Header == nil {
 			req.Header = make(map[string][]string)
 		}
-		req.Header[authenticationv1.ImpersonateUserHeader] = []string{userInfo.GetName()}
-		req.Header[authenticationv1.ImpersonateGroupHeader] = userInfo.GetGroups()
+		util.SetImpersonateHeaders(req.Header, userInfo)
 
 		//proxyOpts, ok := opts.(*api.PodProxyOptions)
 		//if !ok {
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/kubewharf/kubezoo/pkg/proxy/pod"
+	"github.com/kubewharf/kubezoo/pkg/proxy/serviceaccount"
 	"strings"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -31,11 +32,13 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/apiserver/pkg/endpoints/request"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
 	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/klog"
 	"k8s.io/kubernetes/pkg/printers"
 	printersinternal "k8s.io/kubernetes/pkg/printers/internalversion"
 	printerstorage "k8s.io/kubernetes/pkg/printers/storage"
@@ -114,6 +117,9 @@ func NewTenantProxy(config common.StorageConfig) (rest.Storage, error) {
 	if (config.Resource == "pods" || config.Resource == "services" || config.Resource == "nodes") && config.Subresource == "proxy" {
 		return pod.NewProxyREST(config.ProxyTransport, config.UpstreamMaster)
 	}
+	if config.Resource == "serviceaccounts" && config.Subresource == "token" && config.ServiceAccountTokenIssuer != nil {
+		return serviceaccount.NewTokenREST(config.ServiceAccountTokenIssuer, config.DynamicClient)
+	}
 
 	if config.NewFunc == nil && config.NewListFunc == nil {
 		return nil, fmt.Errorf("both NewFunc and NewListFunc is nil")
@@ -294,11 +300,31 @@ func (tp *tenantProxy) Update(ctx context.Context, name string, objInfo rest.Upd
 		return nil, false, fmt.Errorf("newFunc is nil")
 	}
 
+	tenantID, ok := util.TenantFrom(ctx)
+	if !ok {
+		return nil, false, fmt.Errorf("tanentID doesn't exist in context")
+	}
+	if err := tp.checkReadOnly(name, tenantID); err != nil {
+		return nil, false, err
+	}
+
 	requestInfo, ok := request.RequestInfoFrom(ctx)
 	if !ok {
 		return nil, false, fmt.Errorf("missing requestInfo")
 	}
 	if requestInfo.Verb == "patch" {
+		if patch, ok := util.PatchRequestFrom(ctx); ok {
+			if patch.Type == types.ApplyPatchType {
+				return tp.apply(ctx, name, patch)
+			}
+			// pass the patch through to the upstream cluster if possible, which
+			// avoids the conflicts of read-modify-write under contention
+			data, err := tp.translatePatch(ctx, patch)
+			if err == nil {
+				return tp.patch(ctx, name, patch.Type, data, patch)
+			}
+			klog.V(4).Infof("failed to translate %s patch of %s %s, fall back to read-modify-write: %v", patch.Type, tp.resource, name, err)
+		}
 		return tp.guaranteedUpdate(ctx, name, objInfo, options)
 	}
 
@@ -451,6 +477,9 @@ func (tp *tenantProxy) Delete(ctx context.Context, name string, _ rest.ValidateO
 	if !ok {
 		return nil, false, fmt.Errorf("tanentID doesn't exist in context")
 	}
+	if err := tp.checkReadOnly(name, tenantID); err != nil {
+		return nil, false, err
+	}
 
 	if !tp.namespaceScoped {
 		name = util.ConvertTenantObjectNameToUpstream(name, tenantID, tp.kind)
@@ -574,6 +603,14 @@ func (tp *tenantProxy) DeleteCollection(ctx context.Context, _ rest.ValidateObje
 		return nil, util.TrimTenantIDFromError(err, tenantID)
 	}
 	utdList = util.FilterUnstructuredList(utdList, tenantID, tp.namespaceScoped)
+	// the read-only objects are kept, and left out of the deleted ones
+	items := utdList.Items[:0]
+	for i := range utdList.Items {
+		if tp.checkReadOnly(utdList.Items[i].GetName(), tenantID) == nil {
+			items = append(items, utdList.Items[i])
+		}
+	}
+	utdList.Items = items
 	for i := range utdList.Items {
 		name := utdList.Items[i].GetName()
 		_, _, err = client.Delete(ctx, name, *options)
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubewharf/kubezoo/pkg/common"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// readOnlyObjects matches the names of the objects kubezoo manages in the
+// namespaces of the tenants, keyed by their kinds. The objects are checked
+// by name, since neither the patches nor the deletions carry the labels of
+// the objects.
+var readOnlyObjects = map[schema.GroupKind]func(name string) bool{
+	// the resource quotas generated by the cluster resource quotas of the
+	// tenant, i.e. kubezoo-tenant-quota-<tenant>[-<scope>]-<suffix>
+	{Kind: "ResourceQuota"}: func(name string) bool {
+		return strings.HasPrefix(name, common.TenantQuotaNamePrefix+"-")
+	},
+	// the default limit ranges synced from the tenant spec
+	{Kind: "LimitRange"}: func(name string) bool {
+		return name == util.TenantLimitRangeName
+	},
+}
+
+// checkReadOnly rejects the writes of the tenant to the objects managed by
+// kubezoo, including the updates, the patches and the deletions.
+func (tp *tenantProxy) checkReadOnly(name, tenantID string) error {
+	isReadOnly, ok := readOnlyObjects[tp.kind.GroupKind()]
+	if !ok || !isReadOnly(name) {
+		return nil
+	}
+	return errors.NewForbidden(schema.GroupResource{Group: tp.kind.Group, Resource: tp.resource}, name,
+		fmt.Errorf("the %s is managed for tenant %s and is read-only", strings.ToLower(tp.kind.Kind), tenantID))
+}
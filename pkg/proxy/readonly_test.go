@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/kubernetes/pkg/apis/core"
+
+	"github.com/kubewharf/kubezoo/pkg/common"
+	"github.com/kubewharf/kubezoo/pkg/dynamic"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// TestTenantProxyReadOnly tests the resource quotas generated for the
+// tenant can neither be updated, patched nor deleted by the tenant, whereas
+// the others are deleted by the collection.
+func TestTenantProxyReadOnly(t *testing.T) {
+	tenantID := "test01"
+	upstreamNamespace := util.AddTenantIDPrefix(tenantID, "default")
+	generatedName := "kubezoo-tenant-quota-test01-abcde"
+	quotaList := corev1.ResourceQuotaList{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ResourceQuotaList"},
+		Items: []corev1.ResourceQuota{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: upstreamNamespace, Name: generatedName}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: upstreamNamespace, Name: "budget"}},
+		},
+	}
+
+	var deleted []string
+	fakeUpstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		collection := fmt.Sprintf("/api/v1/namespaces/%s/resourcequotas", upstreamNamespace)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == collection:
+			data, err := json.Marshal(quotaList)
+			assert.NoError(t, err)
+			w.Write(data)
+		case r.Method == http.MethodDelete && r.URL.Path == collection+"/budget":
+			deleted = append(deleted, "budget")
+			data, err := json.Marshal(metav1.Status{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Status"},
+				Status:   metav1.StatusSuccess,
+			})
+			assert.NoError(t, err)
+			w.Write(data)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer fakeUpstream.Close()
+	client := dynamic.NewForConfigOrDie(&restclient.Config{Host: fakeUpstream.URL})
+	config := common.StorageConfig{
+		Kind:            corev1.SchemeGroupVersion.WithKind("ResourceQuota"),
+		Resource:        "resourcequotas",
+		NamespaceScoped: true,
+		NewFunc:         func() runtime.Object { return &core.ResourceQuota{} },
+		NewListFunc:     func() runtime.Object { return &core.ResourceQuotaList{} },
+		DynamicClient:   client,
+		Convertor:       &fakeConvertor{},
+	}
+	proxy, err := NewTenantProxy(config)
+	assert.NoError(t, err)
+
+	for _, verb := range []string{"update", "patch"} {
+		ctx := tenantContext(tenantID, &request.RequestInfo{Verb: verb, Namespace: "default"})
+		_, _, err = proxy.(rest.Updater).Update(ctx, generatedName, nil, nil, nil, false, &metav1.UpdateOptions{})
+		assert.True(t, errors.IsForbidden(err), "%s: expect forbidden, got %v", verb, err)
+	}
+
+	ctx := tenantContext(tenantID, &request.RequestInfo{Verb: "delete", Namespace: "default"})
+	_, _, err = proxy.(rest.GracefulDeleter).Delete(ctx, generatedName, nil, &metav1.DeleteOptions{})
+	assert.True(t, errors.IsForbidden(err), "delete: expect forbidden, got %v", err)
+
+	ctx = tenantContext(tenantID, &request.RequestInfo{Verb: "deletecollection", Namespace: "default"})
+	_, err = proxy.(rest.CollectionDeleter).DeleteCollection(ctx, nil, &metav1.DeleteOptions{}, &metainternalversion.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"budget"}, deleted)
+}
+
+// TestCheckReadOnly tests the objects managed for the tenants are matched
+// by their kinds and names.
+func TestCheckReadOnly(t *testing.T) {
+	cases := []struct {
+		kind     string
+		name     string
+		readOnly bool
+	}{
+		{kind: "ResourceQuota", name: "kubezoo-tenant-quota-111111-abcde", readOnly: true},
+		{kind: "ResourceQuota", name: "budget"},
+		{kind: "LimitRange", name: util.TenantLimitRangeName, readOnly: true},
+		{kind: "LimitRange", name: "limits"},
+		{kind: "ConfigMap", name: util.TenantLimitRangeName},
+	}
+	for _, c := range cases {
+		tp := &tenantProxy{kind: corev1.SchemeGroupVersion.WithKind(c.kind)}
+		err := tp.checkReadOnly(c.name, "111111")
+		assert.Equal(t, c.readOnly, errors.IsForbidden(err), "%s %s: unexpected error %v", c.kind, c.name, err)
+	}
+}
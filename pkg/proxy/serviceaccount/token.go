@@ -0,0 +1,262 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/warning"
+	authenticationapi "k8s.io/kubernetes/pkg/apis/authentication"
+	authenticationvalidation "k8s.io/kubernetes/pkg/apis/authentication/validation"
+	api "k8s.io/kubernetes/pkg/apis/core"
+	apiv1 "k8s.io/kubernetes/pkg/apis/core/v1"
+
+	"github.com/kubewharf/kubezoo/pkg/common"
+	"github.com/kubewharf/kubezoo/pkg/dynamic"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+var gvk = authenticationv1.SchemeGroupVersion.WithKind("TokenRequest")
+
+var (
+	serviceAccountsResource        = corev1.SchemeGroupVersion.WithResource("serviceaccounts")
+	podsResource                   = corev1.SchemeGroupVersion.WithResource("pods")
+	secretsResource                = corev1.SchemeGroupVersion.WithResource("secrets")
+	subjectAccessReviewsResource   = authorizationv1.SchemeGroupVersion.WithResource("subjectaccessreviews")
+	serviceAccountTokenResource    = "serviceaccounts"
+	serviceAccountTokenSubresource = "token"
+)
+
+// TokenREST implements serviceaccounts/token, the tokens are issued by
+// kubezoo with the issuer of the tenant instead of the upstream cluster,
+// so that the claims are those seen inside the tenant.
+type TokenREST struct {
+	issuer        common.ServiceAccountTokenIssuer
+	dynamicClient dynamic.Interface
+}
+
+var _ = rest.NamedCreater(&TokenREST{})
+var _ = rest.GroupVersionKindProvider(&TokenREST{})
+
+// NewTokenREST returns the storage of serviceaccounts/token. The dynamic
+// client is used to authorize the requests and to get the service accounts,
+// the pods and the secrets from the upstream cluster.
+func NewTokenREST(issuer common.ServiceAccountTokenIssuer, dynamicClient dynamic.Interface) (rest.Storage, error) {
+	if issuer == nil || dynamicClient == nil {
+		return nil, fmt.Errorf("both issuer and dynamic client are required")
+	}
+	return &TokenREST{issuer: issuer, dynamicClient: dynamicClient}, nil
+}
+
+// New returns an empty TokenRequest object.
+func (r *TokenREST) New() runtime.Object {
+	return &authenticationapi.TokenRequest{}
+}
+
+// GroupVersionKind implements rest.GroupVersionKindProvider.
+func (r *TokenREST) GroupVersionKind(schema.GroupVersion) schema.GroupVersionKind {
+	return gvk
+}
+
+// Create issues the token of the service account in the tenant.
+func (r *TokenREST) Create(ctx context.Context, name string, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	req := obj.(*authenticationapi.TokenRequest)
+
+	tenantID, ok := util.TenantFrom(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tanentID doesn't exist in context")
+	}
+	namespace, ok := apirequest.NamespaceFrom(ctx)
+	if !ok || len(namespace) == 0 {
+		return nil, errors.NewBadRequest("namespace is required")
+	}
+	if len(req.Name) > 0 && req.Name != name {
+		errs := field.ErrorList{field.Invalid(field.NewPath("metadata").Child("name"), req.Name, "must match the service account name if specified")}
+		return nil, errors.NewInvalid(gvk.GroupKind(), name, errs)
+	}
+	if len(req.Namespace) > 0 && req.Namespace != namespace {
+		errs := field.ErrorList{field.Invalid(field.NewPath("metadata").Child("namespace"), req.Namespace, "must match the service account namespace if specified")}
+		return nil, errors.NewInvalid(gvk.GroupKind(), name, errs)
+	}
+
+	// the token is not issued by the upstream cluster, hence authorize
+	// the request against it explicitly
+	if err := r.authorize(ctx, tenantID, namespace, name); err != nil {
+		return nil, err
+	}
+
+	upstreamNamespace := util.AddTenantIDPrefix(tenantID, namespace)
+	svcacct := &api.ServiceAccount{}
+	if err := r.get(ctx, serviceAccountsResource, upstreamNamespace, name, &corev1.ServiceAccount{}, svcacct, tenantID); err != nil {
+		return nil, err
+	}
+	svcacct.Namespace = namespace
+
+	if len(req.Spec.Audiences) == 0 {
+		req.Spec.Audiences = []string{r.issuer.TenantIssuerURL(tenantID)}
+	}
+	if len(req.Name) == 0 {
+		req.Name = svcacct.Name
+	}
+	if len(req.Namespace) == 0 {
+		req.Namespace = namespace
+	}
+	nowTime := time.Now()
+	req.CreationTimestamp = metav1.NewTime(nowTime)
+	req.Status = authenticationapi.TokenRequestStatus{}
+
+	if errs := authenticationvalidation.ValidateTokenRequest(req); len(errs) != 0 {
+		return nil, errors.NewInvalid(gvk.GroupKind(), "", errs)
+	}
+	if createValidation != nil {
+		if err := createValidation(ctx, obj.DeepCopyObject()); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		pod    *api.Pod
+		secret *api.Secret
+	)
+	if ref := req.Spec.BoundObjectRef; ref != nil {
+		var uid types.UID
+		refGVK := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind)
+		switch {
+		case refGVK.Group == "" && refGVK.Kind == "Pod":
+			pod = &api.Pod{}
+			if err := r.get(ctx, podsResource, upstreamNamespace, ref.Name, &corev1.Pod{}, pod, tenantID); err != nil {
+				return nil, err
+			}
+			if name != pod.Spec.ServiceAccountName {
+				return nil, errors.NewBadRequest(fmt.Sprintf("cannot bind token for serviceaccount %q to pod running with different serviceaccount name.", name))
+			}
+			pod.Namespace = namespace
+			uid = pod.UID
+		case refGVK.Group == "" && refGVK.Kind == "Secret":
+			secret = &api.Secret{}
+			if err := r.get(ctx, secretsResource, upstreamNamespace, ref.Name, &corev1.Secret{}, secret, tenantID); err != nil {
+				return nil, err
+			}
+			secret.Namespace = namespace
+			uid = secret.UID
+		default:
+			return nil, errors.NewBadRequest(fmt.Sprintf("cannot bind token to object of type %s", refGVK.String()))
+		}
+		if ref.UID != "" && uid != ref.UID {
+			return nil, errors.NewConflict(schema.GroupResource{Group: refGVK.Group, Resource: refGVK.Kind}, ref.Name, fmt.Errorf("the UID in the bound object reference (%s) does not match the UID in record. The object might have been deleted and then recreated", ref.UID))
+		}
+	}
+
+	if maxExpirationSeconds := int64(r.issuer.MaxExpiration() / time.Second); maxExpirationSeconds > 0 && req.Spec.ExpirationSeconds > maxExpirationSeconds {
+		warning.AddWarning(ctx, "", fmt.Sprintf("requested expiration of %d seconds shortened to %d seconds", req.Spec.ExpirationSeconds, maxExpirationSeconds))
+		req.Spec.ExpirationSeconds = maxExpirationSeconds
+	}
+
+	token, _, err := r.issuer.GenerateToken(tenantID, *svcacct, pod, secret, req.Spec.ExpirationSeconds, req.Spec.Audiences)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %v", err)
+	}
+	out := req.DeepCopy()
+	out.Status = authenticationapi.TokenRequestStatus{
+		Token:               token,
+		ExpirationTimestamp: metav1.Time{Time: nowTime.Add(time.Duration(out.Spec.ExpirationSeconds) * time.Second)},
+	}
+	return out, nil
+}
+
+// authorize checks whether the user is allowed to create the token of the
+// service account by the upstream cluster.
+func (r *TokenREST) authorize(ctx context.Context, tenantID, namespace, name string) error {
+	userInfo, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return errors.NewInternalError(fmt.Errorf("no User found in context"))
+	}
+	sar := &authorizationv1.SubjectAccessReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: authorizationv1.SchemeGroupVersion.String(), Kind: "SubjectAccessReview"},
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   userInfo.GetName(),
+			Groups: userInfo.GetGroups(),
+			UID:    userInfo.GetUID(),
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   util.AddTenantIDPrefix(tenantID, namespace),
+				Verb:        "create",
+				Resource:    serviceAccountTokenResource,
+				Subresource: serviceAccountTokenSubresource,
+				Name:        name,
+			},
+		},
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(sar)
+	if err != nil {
+		return err
+	}
+	got, err := r.dynamicClient.Resource(subjectAccessReviewsResource).Create(upstreamContext(ctx), &unstructured.Unstructured{Object: content}, metav1.CreateOptions{})
+	if err != nil {
+		return util.TrimTenantIDFromError(err, tenantID)
+	}
+	result := &authorizationv1.SubjectAccessReview{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(got.Object, result); err != nil {
+		return err
+	}
+	if !result.Status.Allowed {
+		return errors.NewForbidden(schema.GroupResource{Resource: serviceAccountTokenResource}, name,
+			fmt.Errorf("User %q cannot create resource \"serviceaccounts/token\" in the namespace %q", userInfo.GetName(), namespace))
+	}
+	return nil
+}
+
+// get gets the object from the upstream cluster and converts it to the
+// internal version.
+func (r *TokenREST) get(ctx context.Context, resource schema.GroupVersionResource, namespace, name string,
+	versioned, internal runtime.Object, tenantID string) error {
+	got, err := r.dynamicClient.Resource(resource).Namespace(namespace).Get(upstreamContext(ctx), name, metav1.GetOptions{})
+	if err != nil {
+		return util.TrimTenantIDFromError(err, tenantID)
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(got.Object, versioned); err != nil {
+		return err
+	}
+	switch v := versioned.(type) {
+	case *corev1.ServiceAccount:
+		return apiv1.Convert_v1_ServiceAccount_To_core_ServiceAccount(v, internal.(*api.ServiceAccount), nil)
+	case *corev1.Pod:
+		return apiv1.Convert_v1_Pod_To_core_Pod(v, internal.(*api.Pod), nil)
+	case *corev1.Secret:
+		return apiv1.Convert_v1_Secret_To_core_Secret(v, internal.(*api.Secret), nil)
+	}
+	return fmt.Errorf("unsupported object %T", versioned)
+}
+
+// upstreamContext drops the user from the context, so that kubezoo accesses
+// the upstream cluster as itself instead of impersonating the user.
+func upstreamContext(ctx context.Context) context.Context {
+	return apirequest.WithUser(ctx, nil)
+}
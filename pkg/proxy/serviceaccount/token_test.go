@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gopkg.in/square/go-jose.v2/jwt"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	restclient "k8s.io/client-go/rest"
+	authenticationapi "k8s.io/kubernetes/pkg/apis/authentication"
+	api "k8s.io/kubernetes/pkg/apis/core"
+
+	"github.com/kubewharf/kubezoo/pkg/dynamic"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+// fakeIssuer records the objects the token is generated for.
+type fakeIssuer struct {
+	serviceAccount *api.ServiceAccount
+	pod            *api.Pod
+	expiration     int64
+	audiences      []string
+}
+
+func (f *fakeIssuer) TenantIssuerURL(tenantID string) string {
+	return "https://kubezoo.example.com/tenants/" + tenantID
+}
+
+func (f *fakeIssuer) MaxExpiration() time.Duration {
+	return time.Hour
+}
+
+func (f *fakeIssuer) GenerateToken(tenantID string, serviceAccount api.ServiceAccount, pod *api.Pod, secret *api.Secret,
+	expirationSeconds int64, audiences []string) (string, *jwt.Claims, error) {
+	f.serviceAccount, f.pod, f.expiration, f.audiences = &serviceAccount, pod, expirationSeconds, audiences
+	return "token", &jwt.Claims{}, nil
+}
+
+// newFakeUpstream returns the upstream cluster which allows the tenant admin
+// to create the tokens, and serves the service account default and the pods.
+func newFakeUpstream(t *testing.T) *httptest.Server {
+	objects := map[string]interface{}{
+		"/api/v1/namespaces/foofoo-ns/serviceaccounts/default": &corev1.ServiceAccount{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+			ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "foofoo-ns", UID: "sa-uid"},
+		},
+		"/api/v1/namespaces/foofoo-ns/pods/web": &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "foofoo-ns", UID: "pod-uid"},
+			Spec:       corev1.PodSpec{ServiceAccountName: "default"},
+		},
+		"/api/v1/namespaces/foofoo-ns/pods/other": &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "foofoo-ns", UID: "other-uid"},
+			Spec:       corev1.PodSpec{ServiceAccountName: "builder"},
+		},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(authenticationv1.ImpersonateUserHeader) != "" {
+			t.Errorf("unexpected impersonation of %s", r.Header.Get(authenticationv1.ImpersonateUserHeader))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost && r.URL.Path == "/apis/authorization.k8s.io/v1/subjectaccessreviews" {
+			sar := &authorizationv1.SubjectAccessReview{}
+			if err := json.NewDecoder(r.Body).Decode(sar); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			attrs := sar.Spec.ResourceAttributes
+			sar.Status.Allowed = sar.Spec.User == "foofoo-admin" && attrs.Namespace == "foofoo-ns" &&
+				attrs.Resource == "serviceaccounts" && attrs.Subresource == "token"
+			json.NewEncoder(w).Encode(sar)
+			return
+		}
+		obj, ok := objects[r.URL.Path]
+		if !ok || r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(&apierrors.NewNotFound(corev1.Resource("serviceaccounts"), "foofoo-ns").ErrStatus)
+			return
+		}
+		json.NewEncoder(w).Encode(obj)
+	}))
+}
+
+func TestTokenRESTCreate(t *testing.T) {
+	server := newFakeUpstream(t)
+	defer server.Close()
+	client, err := dynamic.NewForConfig(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		user           string
+		serviceAccount string
+		boundPod       string
+		expiration     int64
+		expectErr      func(error) bool
+		expectPod      bool
+	}{
+		{
+			name:           "token with default audience",
+			user:           "foofoo-admin",
+			serviceAccount: "default",
+			expiration:     600,
+		},
+		{
+			name:           "expiration shortened",
+			user:           "foofoo-admin",
+			serviceAccount: "default",
+			expiration:     7200,
+		},
+		{
+			name:           "token bound to pod",
+			user:           "foofoo-admin",
+			serviceAccount: "default",
+			boundPod:       "web",
+			expiration:     600,
+			expectPod:      true,
+		},
+		{
+			name:           "pod of other service account",
+			user:           "foofoo-admin",
+			serviceAccount: "default",
+			boundPod:       "other",
+			expiration:     600,
+			expectErr:      apierrors.IsBadRequest,
+		},
+		{
+			name:           "forbidden user",
+			user:           "foofoo-alice",
+			serviceAccount: "default",
+			expiration:     600,
+			expectErr:      apierrors.IsForbidden,
+		},
+		{
+			name:           "unknown service account",
+			user:           "foofoo-admin",
+			serviceAccount: "unknown",
+			expiration:     600,
+			expectErr:      apierrors.IsNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issuer := &fakeIssuer{}
+			storage, err := NewTokenREST(issuer, client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := apirequest.WithNamespace(context.TODO(), "ns")
+			ctx = apirequest.WithUser(ctx, &user.DefaultInfo{
+				Name:  test.user,
+				Extra: map[string][]string{util.TenantIDKey: {"foofoo"}},
+			})
+			req := &authenticationapi.TokenRequest{
+				Spec: authenticationapi.TokenRequestSpec{ExpirationSeconds: test.expiration},
+			}
+			if len(test.boundPod) != 0 {
+				req.Spec.BoundObjectRef = &authenticationapi.BoundObjectReference{APIVersion: "v1", Kind: "Pod", Name: test.boundPod}
+			}
+
+			got, err := storage.(*TokenREST).Create(ctx, test.serviceAccount, req, nil, &metav1.CreateOptions{})
+			if test.expectErr != nil {
+				if !test.expectErr(err) {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			out := got.(*authenticationapi.TokenRequest)
+			if out.Status.Token != "token" || out.Namespace != "ns" {
+				t.Errorf("unexpected token request %+v", out)
+			}
+			if sa := issuer.serviceAccount; sa.Namespace != "ns" || sa.Name != "default" || sa.UID != "sa-uid" {
+				t.Errorf("expect tenant side service account, got %s/%s %s", sa.Namespace, sa.Name, sa.UID)
+			}
+			if len(issuer.audiences) != 1 || issuer.audiences[0] != issuer.TenantIssuerURL("foofoo") {
+				t.Errorf("expect tenant issuer audience, got %v", issuer.audiences)
+			}
+			if expect := int64(3600); test.expiration > expect && issuer.expiration != expect {
+				t.Errorf("expect expiration shortened to %d, got %d", expect, issuer.expiration)
+			}
+			if test.expectPod && (issuer.pod == nil || issuer.pod.Namespace != "ns" || issuer.pod.UID != "pod-uid") {
+				t.Errorf("expect tenant side pod, got %+v", issuer.pod)
+			}
+		})
+	}
+}
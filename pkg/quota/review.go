@@ -0,0 +1,505 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/admission/plugin/resourcequota"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	quotautil "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/apiserver/pkg/quota/v1/generic"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	quotainstall "k8s.io/kubernetes/pkg/quota/v1/install"
+
+	quotav1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+const (
+	// ReviewPath is the path the tenants post the manifests to, which are
+	// evaluated against the quotas of the tenant without being created.
+	ReviewPath = "/kubezoo/quotareview"
+
+	// maxReviewBytes limits the size of the manifests.
+	maxReviewBytes = 3 * 1024 * 1024
+
+	objectCountQuotaPrefix = "count/"
+)
+
+// QuotaReview is the result of evaluating the objects of a manifest against
+// the quotas of the tenant, as if they were created in order.
+type QuotaReview struct {
+	metav1.TypeMeta `json:",inline"`
+	// Allowed is true if all the objects fit the quotas.
+	Allowed bool `json:"allowed"`
+	// Quotas are the quotas of the tenant with the resulting usage.
+	Quotas []QuotaReviewQuota `json:"quotas,omitempty"`
+	// Objects are the results of the objects in the order of the manifest.
+	Objects []QuotaReviewObject `json:"objects,omitempty"`
+}
+
+// QuotaReviewQuota is the usage of a quota of the tenant before and after
+// the allowed objects of the manifest are created.
+type QuotaReviewQuota struct {
+	// Name is the name of the quota, which is also the prefix of the
+	// resource quotas in the namespaces of the tenant.
+	Name string `json:"name"`
+	// Hard is the hard limits of the quota.
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+	// Used is the current usage of the quota.
+	Used corev1.ResourceList `json:"used,omitempty"`
+	// Usage is the usage of the quota after the allowed objects are created.
+	Usage corev1.ResourceList `json:"usage,omitempty"`
+	// Exceeded is the resources whose hard limits would be exceeded by the
+	// denied objects.
+	Exceeded []corev1.ResourceName `json:"exceeded,omitempty"`
+}
+
+// QuotaReviewObject is the result of an object of the manifest.
+type QuotaReviewObject struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name,omitempty"`
+	// Allowed is true if the object fits the quotas.
+	Allowed bool `json:"allowed"`
+	// Reason is why the object is denied.
+	Reason string `json:"reason,omitempty"`
+	// Warning is the limit of the evaluation of the object, e.g. the pods
+	// of the daemonsets, which depend on the nodes, are not evaluated.
+	Warning string `json:"warning,omitempty"`
+}
+
+// ListClusterQuotasFunc lists the cluster resource quotas of the tenant.
+type ListClusterQuotasFunc func(tenantID string) ([]*quotav1alpha1.ClusterResourceQuota, error)
+
+// Reviewer evaluates the manifests of the tenants against their cluster
+// resource quotas with the evaluator of the resource quota admission, whose
+// quota accessor keeps the usage in memory, so that nothing is created or
+// reserved. The objects are evaluated in the view of the tenant, i.e. the
+// tenant namespaces and the groups of the custom resources of the tenant,
+// against the quotas converted to the view of the tenant.
+type Reviewer struct {
+	listClusterQuotas ListClusterQuotasFunc
+	mapper            meta.RESTMapper
+	scheme            *runtime.Scheme
+	registry          quotautil.Registry
+	ignoredResources  map[schema.GroupResource]struct{}
+}
+
+// NewReviewer returns the reviewer of the quotas listed by
+// listClusterQuotas, the kinds of the objects are mapped to the resources
+// by the mapper of the upstream cluster.
+func NewReviewer(listClusterQuotas ListClusterQuotasFunc, mapper meta.RESTMapper) *Reviewer {
+	config := quotainstall.NewQuotaConfigurationForAdmission()
+	return &Reviewer{
+		listClusterQuotas: listClusterQuotas,
+		mapper:            mapper,
+		scheme:            clientgoscheme.Scheme,
+		registry:          generic.NewRegistry(config.Evaluators()),
+		ignoredResources:  config.IgnoredResources(),
+	}
+}
+
+// ServeHTTP evaluates the manifest posted by the tenant, which is a list or
+// a stream of JSON or YAML objects.
+func (r *Reviewer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	tenantID, ok := util.TenantFrom(ctx)
+	if !ok {
+		responsewriters.ErrorNegotiated(apierrors.NewForbidden(schema.GroupResource{}, "", errors.New("quota review is only available to the tenants")),
+			clientgoscheme.Codecs, schema.GroupVersion{}, w, req)
+		return
+	}
+	if req.Method != http.MethodPost {
+		responsewriters.ErrorNegotiated(apierrors.NewMethodNotSupported(schema.GroupResource{}, req.Method),
+			clientgoscheme.Codecs, schema.GroupVersion{}, w, req)
+		return
+	}
+	// one more byte is read to tell the manifests over the limit, which are
+	// rejected instead of being truncated
+	data, err := io.ReadAll(io.LimitReader(req.Body, maxReviewBytes+1))
+	if err != nil {
+		responsewriters.ErrorNegotiated(apierrors.NewBadRequest(err.Error()), clientgoscheme.Codecs, schema.GroupVersion{}, w, req)
+		return
+	}
+	if len(data) > maxReviewBytes {
+		responsewriters.ErrorNegotiated(apierrors.NewRequestEntityTooLargeError(fmt.Sprintf("the manifest exceeds %d bytes", maxReviewBytes)),
+			clientgoscheme.Codecs, schema.GroupVersion{}, w, req)
+		return
+	}
+	objs, err := decodeManifest(bytes.NewReader(data))
+	if err != nil {
+		responsewriters.ErrorNegotiated(apierrors.NewBadRequest(err.Error()), clientgoscheme.Codecs, schema.GroupVersion{}, w, req)
+		return
+	}
+	u, _ := request.UserFrom(ctx)
+	review, err := r.Review(tenantID, u, objs)
+	if err != nil {
+		responsewriters.ErrorNegotiated(apierrors.NewInternalError(err), clientgoscheme.Codecs, schema.GroupVersion{}, w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		utilruntime.HandleError(err)
+	}
+}
+
+// Review evaluates the objects in order against the cluster resource quotas
+// of the tenant, the usage of the allowed objects is accumulated for the
+// later ones. The objects without namespace are created in the default
+// namespace if they are namespaced. The cluster scoped objects are only
+// counted by the object count quotas of the quotas without scopes. The
+// workloads are evaluated along with the pods of their replicas.
+func (r *Reviewer) Review(tenantID string, u user.Info, objs []*unstructured.Unstructured) (*QuotaReview, error) {
+	clusterquotas, err := r.listClusterQuotas(tenantID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list quotas of tenant %s", tenantID)
+	}
+	accessor := newReviewQuotaAccessor(tenantID, clusterquotas)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	evaluator := resourcequota.NewQuotaEvaluator(accessor, r.ignoredResources, r.registry, nil, nil, 1, stopCh)
+
+	review := &QuotaReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: quotav1alpha1.GroupVersion.String(), Kind: "QuotaReview"},
+		Allowed:  true,
+	}
+	exceeded := map[string]sets.String{}
+	for _, obj := range objs {
+		result := QuotaReviewObject{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+		}
+		if result.Name == "" {
+			result.Name = obj.GetGenerateName()
+		}
+		if err := r.evaluate(tenantID, u, evaluator, accessor, obj, &result, exceeded); err != nil {
+			result.Reason = err.Error()
+			review.Allowed = false
+		} else {
+			result.Allowed = true
+		}
+		review.Objects = append(review.Objects, result)
+	}
+
+	for _, clusterquota := range accessor.original {
+		current := accessor.get(clusterquota.Name)
+		q := QuotaReviewQuota{
+			Name:  clusterquota.Name,
+			Hard:  clusterquota.Status.Hard,
+			Used:  clusterquota.Status.Used,
+			Usage: current.Status.Used,
+		}
+		for _, name := range exceeded[clusterquota.Name].List() {
+			q.Exceeded = append(q.Exceeded, corev1.ResourceName(name))
+		}
+		review.Quotas = append(review.Quotas, q)
+	}
+	return review, nil
+}
+
+// evaluate evaluates the object with the evaluator of the resource quota
+// admission, or against the object count quotas if it is cluster scoped,
+// and records the resources exceeded if it is denied. The usage of the
+// workload is rolled back if any of its pods is denied.
+func (r *Reviewer) evaluate(tenantID string, u user.Info, evaluator resourcequota.Evaluator, accessor *reviewQuotaAccessor,
+	obj *unstructured.Unstructured, result *QuotaReviewObject, exceeded map[string]sets.String) error {
+	gvk := obj.GroupVersionKind()
+	gvr, namespaced, err := r.resourceFor(tenantID, gvk)
+	if err != nil {
+		return err
+	}
+	if !namespaced {
+		return accessor.admitClusterObject(gvr.GroupResource(), result.Name, exceeded)
+	}
+	if result.Namespace == "" {
+		result.Namespace = metav1.NamespaceDefault
+	}
+	typed, err := r.toTyped(obj, gvk)
+	if err != nil {
+		return err
+	}
+	attributes := admission.NewAttributesRecord(typed, nil, gvk, result.Namespace, obj.GetName(), gvr, "",
+		admission.Create, &metav1.CreateOptions{}, false, u)
+	usage := accessor.snapshot()
+	if err := evaluator.Evaluate(attributes); err != nil {
+		r.recordExceeded(accessor, gvr.GroupResource(), typed, exceeded)
+		return err
+	}
+	if err := r.evaluatePods(u, evaluator, accessor, typed, result, exceeded); err != nil {
+		accessor.restore(usage)
+		return err
+	}
+	return nil
+}
+
+// recordExceeded records the resources of the quotas matching the object,
+// whose hard limits are exceeded by the usage of the object.
+func (r *Reviewer) recordExceeded(accessor *reviewQuotaAccessor, gr schema.GroupResource, obj runtime.Object, exceeded map[string]sets.String) {
+	evaluator := r.registry.Get(gr)
+	if evaluator == nil {
+		evaluator = generic.NewObjectCountEvaluator(gr, nil, "")
+	}
+	usage, err := evaluator.Usage(obj)
+	if err != nil {
+		return
+	}
+	quotas, _ := accessor.GetQuotas("")
+	for i := range quotas {
+		q := &quotas[i]
+		if matches, err := evaluator.Matches(q, obj); err != nil || !matches {
+			continue
+		}
+		newUsage := quotautil.Add(q.Status.Used, usage)
+		for name := range usage {
+			hard, ok := q.Status.Hard[name]
+			if !ok {
+				continue
+			}
+			if used := newUsage[name]; used.Cmp(hard) > 0 {
+				insertExceeded(exceeded, q.Name, name)
+			}
+		}
+	}
+}
+
+func insertExceeded(exceeded map[string]sets.String, quotaName string, name corev1.ResourceName) {
+	if exceeded[quotaName] == nil {
+		exceeded[quotaName] = sets.NewString()
+	}
+	exceeded[quotaName].Insert(string(name))
+}
+
+// resourceFor maps the kind of the object to the resource, the groups of the
+// custom resources of the tenant are prefixed with the tenant ID upstream.
+func (r *Reviewer) resourceFor(tenantID string, gvk schema.GroupVersionKind) (schema.GroupVersionResource, bool, error) {
+	if gvk.Kind == "" || gvk.Version == "" {
+		return schema.GroupVersionResource{}, false, errors.New("apiVersion and kind of the object must be set")
+	}
+	mapping, err := r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) && gvk.Group != "" {
+		mapping, err = r.mapper.RESTMapping(schema.GroupKind{Group: util.AddTenantIDPrefix(tenantID, gvk.Group), Kind: gvk.Kind}, gvk.Version)
+	}
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+	gvr := gvk.GroupVersion().WithResource(mapping.Resource.Resource)
+	return gvr, mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+// toTyped converts the object to the typed one if the kind is known, e.g.
+// the pods, whose usage is calculated from the spec. The others, e.g. the
+// custom resources, are only counted by the object count quota.
+func (r *Reviewer) toTyped(obj *unstructured.Unstructured, gvk schema.GroupVersionKind) (runtime.Object, error) {
+	if !r.scheme.Recognizes(gvk) {
+		return obj, nil
+	}
+	typed, err := r.scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+		return nil, err
+	}
+	return typed, nil
+}
+
+// decodeManifest decodes the objects of the manifest, the items of the
+// lists are flattened.
+func decodeManifest(r io.Reader) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	var objs []*unstructured.Unstructured
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, err
+			}
+			for i := range list.Items {
+				objs = append(objs, &list.Items[i])
+			}
+			continue
+		}
+		objs = append(objs, obj)
+	}
+	if len(objs) == 0 {
+		return nil, errors.New("no object in the manifest")
+	}
+	return objs, nil
+}
+
+// reviewQuotaAccessor serves the cluster resource quotas of the tenant in
+// the form of the resource quotas to the evaluator, whose usage is updated
+// in memory only. All the quotas of the tenant apply to every namespace of
+// the tenant, including those to be created by the manifest.
+type reviewQuotaAccessor struct {
+	lock     sync.Mutex
+	original []corev1.ResourceQuota
+	quotas   map[string]*corev1.ResourceQuota
+}
+
+func newReviewQuotaAccessor(tenantID string, clusterquotas []*quotav1alpha1.ClusterResourceQuota) *reviewQuotaAccessor {
+	a := &reviewQuotaAccessor{quotas: map[string]*corev1.ResourceQuota{}}
+	toTenant := func(in corev1.ResourceList) corev1.ResourceList {
+		out := corev1.ResourceList{}
+		for name, quantity := range in {
+			out[util.ConvertUpstreamResourceNameToTenant(tenantID, name)] = quantity
+		}
+		return out
+	}
+	for _, clusterquota := range clusterquotas {
+		q := corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            clusterquota.Name,
+				ResourceVersion: clusterquota.ResourceVersion,
+			},
+			Spec: *clusterquota.Spec.ResourceQuotaSpec.DeepCopy(),
+			Status: corev1.ResourceQuotaStatus{
+				Hard: toTenant(clusterquota.Spec.Hard),
+				Used: toTenant(clusterquota.Status.Used),
+			},
+		}
+		q.Spec.Hard = q.Status.Hard
+		// the resources not observed yet are not used at all
+		for name := range q.Status.Hard {
+			if _, ok := q.Status.Used[name]; !ok {
+				q.Status.Used[name] = *resource.NewQuantity(0, resource.DecimalSI)
+			}
+		}
+		a.original = append(a.original, *q.DeepCopy())
+		a.quotas[q.Name] = &q
+	}
+	return a
+}
+
+// GetQuotas returns the quotas with the usage accumulated so far.
+func (a *reviewQuotaAccessor) GetQuotas(namespace string) ([]corev1.ResourceQuota, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	quotas := make([]corev1.ResourceQuota, 0, len(a.original))
+	for _, original := range a.original {
+		quotas = append(quotas, *a.quotas[original.Name].DeepCopy())
+	}
+	return quotas, nil
+}
+
+// UpdateQuotaStatus accumulates the usage of the allowed objects in memory.
+func (a *reviewQuotaAccessor) UpdateQuotaStatus(newQuota *corev1.ResourceQuota) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	q, ok := a.quotas[newQuota.Name]
+	if !ok {
+		return fmt.Errorf("unknown quota %s", newQuota.Name)
+	}
+	q.Status.Used = quotautil.Add(corev1.ResourceList{}, newQuota.Status.Used)
+	return nil
+}
+
+// snapshot returns the usage of the quotas accumulated so far.
+func (a *reviewQuotaAccessor) snapshot() map[string]corev1.ResourceList {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	usage := make(map[string]corev1.ResourceList, len(a.quotas))
+	for name, q := range a.quotas {
+		usage[name] = quotautil.Add(corev1.ResourceList{}, q.Status.Used)
+	}
+	return usage
+}
+
+// restore restores the usage of the quotas from the snapshot.
+func (a *reviewQuotaAccessor) restore(usage map[string]corev1.ResourceList) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for name, used := range usage {
+		a.quotas[name].Status.Used = used
+	}
+}
+
+func (a *reviewQuotaAccessor) get(name string) corev1.ResourceQuota {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return *a.quotas[name].DeepCopy()
+}
+
+// admitClusterObject counts the cluster scoped object by the object count
+// quota of the quotas without scopes, as the quota webhook does.
+func (a *reviewQuotaAccessor) admitClusterObject(gr schema.GroupResource, name string, exceeded map[string]sets.String) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	resourceName := corev1.ResourceName(objectCountQuotaPrefix + gr.String())
+	one := resource.MustParse("1")
+	var errs []string
+	var admitted []*corev1.ResourceQuota
+	for _, original := range a.original {
+		q := a.quotas[original.Name]
+		hard, ok := q.Status.Hard[resourceName]
+		if !ok || len(q.Spec.Scopes) > 0 || q.Spec.ScopeSelector != nil {
+			continue
+		}
+		used := q.Status.Used[resourceName]
+		newUsed := used.DeepCopy()
+		newUsed.Add(one)
+		if newUsed.Cmp(hard) > 0 {
+			insertExceeded(exceeded, q.Name, resourceName)
+			errs = append(errs, fmt.Sprintf("exceeded quota: %s, requested: %s=%s, used: %s=%s, limited: %s=%s",
+				q.Name, resourceName, one.String(), resourceName, used.String(), resourceName, hard.String()))
+			continue
+		}
+		admitted = append(admitted, q)
+	}
+	if len(errs) > 0 {
+		return apierrors.NewForbidden(gr, name, errors.New(errs[0]))
+	}
+	for _, q := range admitted {
+		q.Status.Used = quotautil.Add(q.Status.Used, corev1.ResourceList{resourceName: one})
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	quotautil "k8s.io/apiserver/pkg/quota/v1"
+
+	quotav1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/util"
+)
+
+const testManifest = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod-1
+spec:
+  containers:
+  - name: c
+    image: nginx
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Pod
+  metadata:
+    name: pod-2
+    namespace: test
+  spec:
+    containers:
+    - name: c
+      image: nginx
+- apiVersion: a.com/v1
+  kind: Foo
+  metadata:
+    name: foo-1
+- apiVersion: a.com/v1
+  kind: Foo
+  metadata:
+    name: foo-2
+---
+apiVersion: v1
+kind: PersistentVolume
+metadata:
+  name: pv-1
+---
+apiVersion: v1
+kind: PersistentVolume
+metadata:
+  name: pv-2
+`
+
+// TestReview tests the objects of the manifest are evaluated in order
+// against the quotas of the tenant, and the usage is only accumulated in the
+// review.
+func TestReview(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Pod"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("PersistentVolume"), meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "111111-a.com", Version: "v1", Kind: "Foo"}, meta.RESTScopeNamespace)
+
+	clusterquota := &quotav1alpha1.ClusterResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubezoo-tenant-quota-111111"},
+		Spec: quotav1alpha1.ClusterResourceQuotaSpec{
+			ResourceQuotaSpec: corev1.ResourceQuotaSpec{
+				Hard: corev1.ResourceList{
+					corev1.ResourcePods:            resource.MustParse("2"),
+					"count/foos.111111-a.com":      resource.MustParse("1"),
+					"count/persistentvolumes":      resource.MustParse("1"),
+					corev1.ResourceRequestsStorage: resource.MustParse("10Gi"),
+				},
+			},
+		},
+		Status: quotav1alpha1.ClusterResourceQuotaStatus{
+			ResourceQuotaStatus: corev1.ResourceQuotaStatus{
+				Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")},
+			},
+		},
+	}
+	reviewer := NewReviewer(func(tenantID string) ([]*quotav1alpha1.ClusterResourceQuota, error) {
+		if tenantID != "111111" {
+			t.Errorf("unexpected tenant %s", tenantID)
+		}
+		return []*quotav1alpha1.ClusterResourceQuota{clusterquota.DeepCopy()}, nil
+	}, mapper)
+
+	objs, err := decodeManifest(strings.NewReader(testManifest))
+	if err != nil {
+		t.Fatalf("unable to decode manifest: %v", err)
+	}
+	review, err := reviewer.Review("111111", &user.DefaultInfo{Name: "admin"}, objs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if review.Allowed {
+		t.Errorf("expect the manifest to be denied")
+	}
+	var allowed []string
+	for _, obj := range review.Objects {
+		if obj.Allowed {
+			allowed = append(allowed, obj.Name)
+		} else if obj.Reason == "" {
+			t.Errorf("expect the reason of the denied object %s", obj.Name)
+		}
+	}
+	if expect := []string{"pod-1", "foo-1", "pv-1"}; !reflect.DeepEqual(allowed, expect) {
+		t.Errorf("expect allowed objects %v, got %v", expect, allowed)
+	}
+	if review.Objects[0].Namespace != metav1.NamespaceDefault {
+		t.Errorf("expect the object without namespace in the default namespace, got %q", review.Objects[0].Namespace)
+	}
+
+	if len(review.Quotas) != 1 {
+		t.Fatalf("expect 1 quota, got %d", len(review.Quotas))
+	}
+	q := review.Quotas[0]
+	expectUsage := corev1.ResourceList{
+		corev1.ResourcePods:            resource.MustParse("2"),
+		"count/foos.a.com":             resource.MustParse("1"),
+		"count/persistentvolumes":      resource.MustParse("1"),
+		corev1.ResourceRequestsStorage: resource.MustParse("0"),
+	}
+	if !quotautil.Equals(q.Usage, expectUsage) {
+		t.Errorf("expect usage %v, got %v", expectUsage, q.Usage)
+	}
+	if used := q.Used[corev1.ResourcePods]; used.Cmp(resource.MustParse("1")) != 0 {
+		t.Errorf("expect the current usage to be kept, got %v", q.Used)
+	}
+	expectExceeded := []corev1.ResourceName{"count/foos.a.com", "count/persistentvolumes", corev1.ResourcePods}
+	if !reflect.DeepEqual(q.Exceeded, expectExceeded) {
+		t.Errorf("expect exceeded %v, got %v", expectExceeded, q.Exceeded)
+	}
+
+	// the usage is not kept across the reviews
+	review, err = reviewer.Review("111111", &user.DefaultInfo{Name: "admin"}, objs[:1])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !review.Allowed {
+		t.Errorf("expect the first pod to be allowed again, got %v", review.Objects)
+	}
+}
+
+// TestDecodeManifest tests the invalid manifests are rejected.
+func TestDecodeManifest(t *testing.T) {
+	for _, manifest := range []string{"", "---\n", "{"} {
+		if _, err := decodeManifest(strings.NewReader(manifest)); err == nil {
+			t.Errorf("expect error for manifest %q", manifest)
+		}
+	}
+}
+
+const testWorkloadManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: c
+        image: nginx
+        resources:
+          requests:
+            cpu: 200m
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: api
+  template:
+    metadata:
+      labels:
+        app: api
+    spec:
+      containers:
+      - name: c
+        image: nginx
+        resources:
+          requests:
+            cpu: 200m
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+  selector:
+    matchLabels:
+      app: agent
+  template:
+    metadata:
+      labels:
+        app: agent
+    spec:
+      containers:
+      - name: c
+        image: nginx
+`
+
+// TestReviewWorkloads tests the pods of the replicas of the workloads are
+// evaluated along with them, and the usage of the denied workload is rolled
+// back.
+func TestReviewWorkloads(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("DaemonSet"), meta.RESTScopeNamespace)
+
+	clusterquota := &quotav1alpha1.ClusterResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubezoo-tenant-quota-111111"},
+		Spec: quotav1alpha1.ClusterResourceQuotaSpec{
+			ResourceQuotaSpec: corev1.ResourceQuotaSpec{
+				Hard: corev1.ResourceList{
+					corev1.ResourcePods:        resource.MustParse("3"),
+					corev1.ResourceRequestsCPU: resource.MustParse("1"),
+				},
+			},
+		},
+	}
+	reviewer := NewReviewer(func(tenantID string) ([]*quotav1alpha1.ClusterResourceQuota, error) {
+		return []*quotav1alpha1.ClusterResourceQuota{clusterquota.DeepCopy()}, nil
+	}, mapper)
+
+	objs, err := decodeManifest(strings.NewReader(testWorkloadManifest))
+	if err != nil {
+		t.Fatalf("unable to decode manifest: %v", err)
+	}
+	review, err := reviewer.Review("111111", &user.DefaultInfo{Name: "admin"}, objs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if review.Allowed || len(review.Objects) != 3 {
+		t.Fatalf("expect the manifest to be denied, got %v", review.Objects)
+	}
+	if web := review.Objects[0]; !web.Allowed {
+		t.Errorf("expect deployment web to be allowed, got %v", web)
+	}
+	if api := review.Objects[1]; api.Allowed || !strings.Contains(api.Reason, "pod 2 of 2") {
+		t.Errorf("expect the second pod of deployment api to be denied, got %v", api)
+	}
+	if agent := review.Objects[2]; !agent.Allowed || agent.Warning == "" {
+		t.Errorf("expect daemonset agent to be allowed with a warning, got %v", agent)
+	}
+	expectUsage := corev1.ResourceList{
+		corev1.ResourcePods:        resource.MustParse("2"),
+		corev1.ResourceRequestsCPU: resource.MustParse("400m"),
+	}
+	if usage := review.Quotas[0].Usage; !quotautil.Equals(usage, expectUsage) {
+		t.Errorf("expect usage %v, got %v", expectUsage, usage)
+	}
+}
+
+// TestServeHTTPTooLarge tests the manifests over the limit are rejected
+// instead of being truncated.
+func TestServeHTTPTooLarge(t *testing.T) {
+	reviewer := NewReviewer(func(tenantID string) ([]*quotav1alpha1.ClusterResourceQuota, error) {
+		return nil, nil
+	}, meta.NewDefaultRESTMapper(nil))
+	body := strings.NewReader(testManifest + strings.Repeat("#", maxReviewBytes))
+	req := httptest.NewRequest(http.MethodPost, ReviewPath, body)
+	userInfo := util.AddTenantIDToUserInfo("111111", &user.DefaultInfo{Name: "admin"})
+	req = req.WithContext(request.WithUser(req.Context(), userInfo))
+	w := httptest.NewRecorder()
+	reviewer.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expect status %d, got %d: %s", http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+	}
+}
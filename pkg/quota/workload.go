@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/admission/plugin/resourcequota"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+// maxReviewReplicas bounds the pods of a workload evaluated by the review.
+const maxReviewReplicas = 1000
+
+var (
+	podKind     = corev1.SchemeGroupVersion.WithKind("Pod")
+	podResource = corev1.SchemeGroupVersion.WithResource("pods")
+)
+
+// podsOf returns the pod template of the workload and the number of the
+// pods running at the same time, or false if the object has no pods or
+// their number is not known ahead, e.g. the daemonsets.
+func podsOf(obj runtime.Object) (*corev1.PodTemplateSpec, int32, bool) {
+	replicasOf := func(replicas *int32) int32 {
+		if replicas == nil {
+			return 1
+		}
+		return *replicas
+	}
+	jobPods := func(spec *batchv1.JobSpec) (*corev1.PodTemplateSpec, int32, bool) {
+		parallelism := replicasOf(spec.Parallelism)
+		if spec.Completions != nil && *spec.Completions < parallelism {
+			parallelism = *spec.Completions
+		}
+		return &spec.Template, parallelism, true
+	}
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return &o.Spec.Template, replicasOf(o.Spec.Replicas), true
+	case *appsv1.ReplicaSet:
+		return &o.Spec.Template, replicasOf(o.Spec.Replicas), true
+	case *appsv1.StatefulSet:
+		return &o.Spec.Template, replicasOf(o.Spec.Replicas), true
+	case *corev1.ReplicationController:
+		if o.Spec.Template == nil {
+			return nil, 0, false
+		}
+		return o.Spec.Template, replicasOf(o.Spec.Replicas), true
+	case *batchv1.Job:
+		return jobPods(&o.Spec)
+	case *batchv1.CronJob:
+		// the pods of one job at a time
+		return jobPods(&o.Spec.JobTemplate.Spec)
+	}
+	return nil, 0, false
+}
+
+// evaluatePods evaluates the pods of the replicas of the workload as if
+// they were created along with it, up to maxReviewReplicas of them. The
+// pods of the daemonsets are left out with a warning.
+func (r *Reviewer) evaluatePods(u user.Info, evaluator resourcequota.Evaluator, accessor *reviewQuotaAccessor,
+	obj runtime.Object, result *QuotaReviewObject, exceeded map[string]sets.String) error {
+	if _, ok := obj.(*appsv1.DaemonSet); ok {
+		result.Warning = "the pods of the daemonset are not evaluated, which depend on the nodes"
+		return nil
+	}
+	template, replicas, ok := podsOf(obj)
+	if !ok {
+		return nil
+	}
+	if replicas > maxReviewReplicas {
+		result.Warning = fmt.Sprintf("only %d of the %d pods are evaluated", maxReviewReplicas, replicas)
+		replicas = maxReviewReplicas
+	}
+	for i := int32(0); i < replicas; i++ {
+		pod := &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: podKind.GroupVersion().String(), Kind: podKind.Kind},
+			ObjectMeta: *template.ObjectMeta.DeepCopy(),
+			Spec:       *template.Spec.DeepCopy(),
+		}
+		pod.Namespace = result.Namespace
+		pod.Name = fmt.Sprintf("%s-%d", result.Name, i)
+		attributes := admission.NewAttributesRecord(pod, nil, podKind, pod.Namespace, pod.Name, podResource, "",
+			admission.Create, &metav1.CreateOptions{}, false, u)
+		if err := evaluator.Evaluate(attributes); err != nil {
+			r.recordExceeded(accessor, podResource.GroupResource(), pod, exceeded)
+			return errors.Wrapf(err, "pod %d of %d", i+1, replicas)
+		}
+	}
+	return nil
+}
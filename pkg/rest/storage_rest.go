@@ -49,8 +49,9 @@ func (p RESTStorageProvider) NewRESTStorage(apiResourceConfigSource serverstorag
 func (p RESTStorageProvider) v1alpha1Storage(apiResourceConfigSource serverstorage.APIResourceConfigSource, restOptionsGetter generic.RESTOptionsGetter) map[string]rest.Storage {
 	storage := map[string]rest.Storage{}
 
-	tenantStorage, _ := NewREST(legacyscheme.Scheme, restOptionsGetter)
+	tenantStorage, tenantStatusStorage, _ := NewREST(legacyscheme.Scheme, restOptionsGetter)
 	storage["tenants"] = tenantStorage
+	storage["tenants/status"] = tenantStatusStorage
 	return storage
 
 }
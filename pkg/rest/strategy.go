@@ -30,6 +30,7 @@ import (
 	"k8s.io/apiserver/pkg/storage/names"
 
 	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	"github.com/kubewharf/kubezoo/pkg/audit"
 	"github.com/kubewharf/kubezoo/pkg/util"
 )
 
@@ -97,7 +98,7 @@ func (tenantStrategy) Validate(ctx context.Context, obj runtime.Object) field.Er
 		}}
 	}
 
-	return nil
+	return validateTenantSpec(tenant)
 }
 
 // WarningsOnCreate returns warnings for the creation of the given object.
@@ -115,7 +116,38 @@ func (tenantStrategy) Canonicalize(obj runtime.Object) {
 }
 
 func (tenantStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
-	return field.ErrorList{}
+	tenant := obj.(*tenantv1alpha1.Tenant)
+	return validateTenantSpec(tenant)
+}
+
+// tenantStatusStrategy is the strategy of the status subresource, which
+// updates the status of the tenant only.
+type tenantStatusStrategy struct {
+	tenantStrategy
+}
+
+// NewStatusStrategy creates and returns a tenantStatusStrategy instance.
+func NewStatusStrategy(strategy tenantStrategy) tenantStatusStrategy {
+	return tenantStatusStrategy{strategy}
+}
+
+func (tenantStatusStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	newTenant := obj.(*tenantv1alpha1.Tenant)
+	oldTenant := old.(*tenantv1alpha1.Tenant)
+	newTenant.Spec = oldTenant.Spec
+}
+
+func (tenantStatusStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	return nil
+}
+
+// validateTenantSpec validates the users, the quota, the limit range and the
+// audit policy of the tenant.
+func validateTenantSpec(tenant *tenantv1alpha1.Tenant) field.ErrorList {
+	allErrs := util.ValidateTenantUsers(tenant.Spec.Users, field.NewPath("spec", "users"))
+	allErrs = append(allErrs, util.ValidateTenantQuota(&tenant.Spec.Quota, field.NewPath("spec", "quota"))...)
+	allErrs = append(allErrs, util.ValidateTenantLimitRange(tenant.Spec.LimitRange, field.NewPath("spec", "limitRange"))...)
+	return append(allErrs, audit.ValidateTenantAudit(tenant.Spec.Audit, field.NewPath("spec", "audit"))...)
 }
 
 // WarningsOnUpdate returns warnings for the given update.
@@ -18,6 +18,8 @@ package util
 
 import (
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	cryptorand "crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
@@ -28,20 +30,40 @@ import (
 	"math"
 	"math/big"
 	"net"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/keyutil"
 )
 
 const (
 	AnnotationTenantKubeConfigBase64 = "kubezoo.io/tenant.kubeconfig.base64"
 	KubeZooClusterName               = "kube-zoo"
 
+	// TenantCertificateURIPrefix prefixes the tenant UID in the URI SAN of
+	// the tenant certificates, which binds the certificates to the tenant
+	// they are issued for instead of any tenant recreated with the same ID.
+	TenantCertificateURIPrefix = "kubezoo:tenant:"
+
 	RsaKeySize = 2048
 	// CertificateValidity defines the validity, i.e., 10 Years, for all the signed certificates.
 	CertificateValidity = time.Hour * 24 * 365 * 10
 )
 
+// KeyAlgorithm is the algorithm of the private keys generated for the
+// certificates.
+type KeyAlgorithm string
+
+const (
+	// KeyAlgorithmRSA generates the RSA keys of RsaKeySize bits.
+	KeyAlgorithmRSA KeyAlgorithm = "RSA"
+	// KeyAlgorithmECDSA generates the ECDSA keys on the P-256 curve.
+	KeyAlgorithmECDSA KeyAlgorithm = "ECDSA"
+)
+
 // Config contains the basic fields required for creating a certificate
 type Config struct {
 	CommonName         string
@@ -49,6 +71,12 @@ type Config struct {
 	OrganizationalUnit []string
 	AltNames           AltNames
 	Usages             []x509.ExtKeyUsage
+	// Validity is the validity of the certificate, CertificateValidity
+	// is used if zero.
+	Validity time.Duration
+	// KeyAlgorithm is the algorithm of the generated private key,
+	// KeyAlgorithmRSA is used if empty.
+	KeyAlgorithm KeyAlgorithm
 }
 
 // AltNames contains the domain names and IP addresses that will be added
@@ -57,6 +85,7 @@ type Config struct {
 type AltNames struct {
 	DNSNames []string
 	IPs      []net.IP
+	URIs     []*url.URL
 }
 
 // EncodeCertPEM returns PEM-endcoded certificate data.
@@ -68,19 +97,57 @@ func EncodeCertPEM(cert *x509.Certificate) []byte {
 	return pem.EncodeToMemory(&block)
 }
 
-// EncodePrivateKeyPEM returns PEM-encoded private key data.
-func EncodePrivateKeyPEM(key *rsa.PrivateKey) []byte {
-	block := pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(key),
+// EncodePrivateKeyPEM returns PEM-encoded private key data, i.e. the
+// "RSA PRIVATE KEY" or "EC PRIVATE KEY" block.
+func EncodePrivateKeyPEM(key crypto.Signer) ([]byte, error) {
+	return keyutil.MarshalPrivateKeyToPEM(key)
+}
+
+// NewTenantCertAndKey creates new certificate and key for the denoted tenant.
+func NewTenantCertAndKey(caFile, caKeyFile, tenantID string) (*x509.Certificate, crypto.Signer, error) {
+	return NewTenantUserCertAndKey(caFile, caKeyFile, tenantID, TenantAdminUserName)
+}
+
+// NewTenantUserCertAndKey creates new certificate and key for the named user
+// of the denoted tenant, whose common name is <tenant>-<user>.
+func NewTenantUserCertAndKey(caFile, caKeyFile, tenantID, userName string) (*x509.Certificate, crypto.Signer, error) {
+	return NewCertAndKey(NewFileCASigner(caFile, caKeyFile), NewTenantUserCertConfig(tenantID, "", userName))
+}
+
+// NewTenantUserCertConfig returns the certificate config for the named user
+// of the denoted tenant, whose common name is <tenant>-<user>. The tenant
+// UID, if any, is recorded in the URI SAN of the certificate.
+func NewTenantUserCertConfig(tenantID string, tenantUID types.UID, userName string) *Config {
+	config := &Config{
+		OrganizationalUnit: []string{tenantID},
+		CommonName:         AddTenantIDPrefix(tenantID, userName),
+		Usages:             []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if len(tenantUID) != 0 {
+		config.AltNames.URIs = []*url.URL{TenantCertificateURI(tenantUID)}
 	}
+	return config
+}
 
-	return pem.EncodeToMemory(&block)
+// TenantCertificateURI returns the URI SAN of the certificates issued for
+// the tenant with the UID.
+func TenantCertificateURI(tenantUID types.UID) *url.URL {
+	return &url.URL{Scheme: "kubezoo", Opaque: "tenant:" + string(tenantUID)}
 }
 
-// NewTenantCertAndKey creates new certificate and key for the denoted tenant.
-func NewTenantCertAndKey(caFile, caKeyFile, tenantID string) (*x509.Certificate, *rsa.PrivateKey, error) {
-	// load ca, ca-key from files
+// TenantUIDFromCertificate returns the UID of the tenant the certificate is
+// issued for, which is false if the certificate predates the UID binding.
+func TenantUIDFromCertificate(cert *x509.Certificate) (types.UID, bool) {
+	for _, uri := range cert.URIs {
+		if s := uri.String(); strings.HasPrefix(s, TenantCertificateURIPrefix) {
+			return types.UID(strings.TrimPrefix(s, TenantCertificateURIPrefix)), true
+		}
+	}
+	return "", false
+}
+
+// LoadCertAndKey loads the certificate authority certificate and key from files.
+func LoadCertAndKey(caFile, caKeyFile string) (*x509.Certificate, crypto.Signer, error) {
 	tlsCert, err := tls.LoadX509KeyPair(caFile, caKeyFile)
 	if err != nil {
 		return nil, nil, err
@@ -94,19 +161,18 @@ func NewTenantCertAndKey(caFile, caKeyFile, tenantID string) (*x509.Certificate,
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to parse cert: %v", err)
 	}
-	// generate the certificate config
-	config := &Config{
-		OrganizationalUnit: []string{tenantID},
-		CommonName:         tenantID + "-admin",
-		Usages:             []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
-	}
-
-	return NewCertAndKey(cert, key, config)
+	return cert, key, nil
 }
 
-// NewCertAndKey creates new certificate and key by passing the certificate authority certificate and key.
-func NewCertAndKey(caCert *x509.Certificate, caKey crypto.Signer, config *Config) (*x509.Certificate, *rsa.PrivateKey, error) {
-	key, err := NewPrivateKey()
+// NewCertAndKey creates new certificate and key, the certificate is signed
+// by the certificate authority of the signer.
+func NewCertAndKey(signer CASigner, config *Config) (*x509.Certificate, crypto.Signer, error) {
+	caCert, caKey, err := signer.CA()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unable to load certificate authority")
+	}
+
+	key, err := NewPrivateKeyWithAlgorithm(config.KeyAlgorithm)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "unable to create private key")
 	}
@@ -124,8 +190,28 @@ func NewPrivateKey() (*rsa.PrivateKey, error) {
 	return rsa.GenerateKey(cryptorand.Reader, RsaKeySize)
 }
 
+// NewPrivateKeyWithAlgorithm creates a private key of the algorithm, an RSA
+// key is created if the algorithm is empty.
+func NewPrivateKeyWithAlgorithm(algorithm KeyAlgorithm) (crypto.Signer, error) {
+	switch algorithm {
+	case "", KeyAlgorithmRSA:
+		return NewPrivateKey()
+	case KeyAlgorithmECDSA:
+		return ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	default:
+		return nil, errors.Errorf("unsupported key algorithm %s", algorithm)
+	}
+}
+
 // NewSignedCert creates a signed certificate using the given CA certificate and key.
 func NewSignedCert(cfg *Config, key crypto.Signer, caCert *x509.Certificate, caKey crypto.Signer) (*x509.Certificate, error) {
+	return NewSignedCertForPublicKey(cfg, key.Public(), caCert, caKey)
+}
+
+// NewSignedCertForPublicKey creates a signed certificate of the public key,
+// e.g. the one in a certificate signing request, using the given CA
+// certificate and key.
+func NewSignedCertForPublicKey(cfg *Config, pub crypto.PublicKey, caCert *x509.Certificate, caKey crypto.Signer) (*x509.Certificate, error) {
 	serial, err := cryptorand.Int(cryptorand.Reader, new(big.Int).SetInt64(math.MaxInt64))
 	if err != nil {
 		return nil, err
@@ -141,6 +227,16 @@ func NewSignedCert(cfg *Config, key crypto.Signer, caCert *x509.Certificate, caK
 		return nil, errors.New("must specify a OrganizationalUnit")
 	}
 
+	validity := cfg.Validity
+	if validity == 0 {
+		validity = CertificateValidity
+	}
+	// key encipherment is only meaningful for the RSA keys
+	keyUsage := x509.KeyUsageDigitalSignature
+	if _, ok := pub.(*rsa.PublicKey); ok {
+		keyUsage |= x509.KeyUsageKeyEncipherment
+	}
+
 	certTmpl := x509.Certificate{
 		Subject: pkix.Name{
 			CommonName:         cfg.CommonName,
@@ -149,13 +245,14 @@ func NewSignedCert(cfg *Config, key crypto.Signer, caCert *x509.Certificate, caK
 		},
 		DNSNames:     cfg.AltNames.DNSNames,
 		IPAddresses:  cfg.AltNames.IPs,
+		URIs:         cfg.AltNames.URIs,
 		SerialNumber: serial,
 		NotBefore:    caCert.NotBefore,
-		NotAfter:     time.Now().Add(CertificateValidity).UTC(),
-		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		NotAfter:     time.Now().Add(validity).UTC(),
+		KeyUsage:     keyUsage,
 		ExtKeyUsage:  cfg.Usages,
 	}
-	certDERBytes, err := x509.CreateCertificate(cryptorand.Reader, &certTmpl, caCert, key.Public(), caKey)
+	certDERBytes, err := x509.CreateCertificate(cryptorand.Reader, &certTmpl, caCert, pub, caKey)
 	if err != nil {
 		return nil, err
 	}
@@ -153,3 +153,58 @@ func TestNewTenantCertAndKey(t *testing.T) {
 		})
 	}
 }
+
+// TestNewTenantUserCertAndKey tests the certificate of a named user is
+// issued under the tenant OU.
+func TestNewTenantUserCertAndKey(t *testing.T) {
+	tenantId := "111111"
+
+	caf, err := ioutil.TempFile("", "")
+	if err != nil {
+		t.Fatalf("error creating tmpfile: %v", err)
+	}
+	defer os.Remove(caf.Name())
+	if err := ioutil.WriteFile(caf.Name(), []byte(CA), os.FileMode(0600)); err != nil {
+		t.Fatalf("error writing ca to tmpfile: %v", err)
+	}
+	keyf, err := ioutil.TempFile("", "")
+	if err != nil {
+		t.Fatalf("error creating tmpfile: %v", err)
+	}
+	defer os.Remove(keyf.Name())
+	if err := ioutil.WriteFile(keyf.Name(), []byte(Key), os.FileMode(0600)); err != nil {
+		t.Fatalf("error writing key to tmpfile: %v", err)
+	}
+
+	cert, _, err := NewTenantUserCertAndKey(caf.Name(), keyf.Name(), tenantId, "alice")
+	if err != nil {
+		t.Fatalf("expect nil error, got %s", err)
+	}
+	if len(cert.Subject.OrganizationalUnit) != 1 || cert.Subject.OrganizationalUnit[0] != tenantId {
+		t.Errorf("unexpect OU %v", cert.Subject.OrganizationalUnit)
+	}
+	if cert.Subject.CommonName != tenantId+"-alice" {
+		t.Errorf("unexpect CN %s", cert.Subject.CommonName)
+	}
+}
+
+// TestTenantUIDFromCertificate tests the tenant UID recorded in the tenant
+// certificate is extracted.
+func TestTenantUIDFromCertificate(t *testing.T) {
+	signer := NewFileCASigner(writeTestCAFiles(t))
+	cert, _, err := NewCertAndKey(signer, NewTenantUserCertConfig("111111", "uid-1", TenantAdminUserName))
+	if err != nil {
+		t.Fatalf("expect nil error, got %s", err)
+	}
+	if uid, ok := TenantUIDFromCertificate(cert); !ok || uid != "uid-1" {
+		t.Errorf("expect tenant uid uid-1, got %q", uid)
+	}
+
+	cert, _, err = NewCertAndKey(signer, NewTenantUserCertConfig("111111", "", TenantAdminUserName))
+	if err != nil {
+		t.Fatalf("expect nil error, got %s", err)
+	}
+	if uid, ok := TenantUIDFromCertificate(cert); ok {
+		t.Errorf("expect no tenant uid, got %q", uid)
+	}
+}
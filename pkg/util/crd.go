@@ -16,7 +16,13 @@ limitations under the License.
 
 package util
 
-import v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+import (
+	"encoding/json"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+)
 
 // CustomGroupResourcesMap records the existence of all custom api group and resources for a tenant
 // the first key is api group and the second key is resource name
@@ -88,3 +94,48 @@ func (grm CustomGroupResourcesMap) HasGroupVersionResource(apiGroup, version, re
 func (grm CustomGroupResourcesMap) GetCRD(apiGroup, resourceName string) *v1.CustomResourceDefinition {
 	return grm[apiGroup][resourceName]
 }
+
+// GetCRDUsage returns the number of crds, the number of served versions and
+// the size of the openapi schemas summed over the given crds.
+func GetCRDUsage(crds ...*v1.CustomResourceDefinition) tenantv1alpha1.TenantCRDUsage {
+	usage := tenantv1alpha1.TenantCRDUsage{}
+	for _, crd := range crds {
+		if crd == nil {
+			continue
+		}
+		usage.CRDs++
+		for i := range crd.Spec.Versions {
+			version := &crd.Spec.Versions[i]
+			if version.Served {
+				usage.ServedVersions++
+			}
+			if version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+				continue
+			}
+			// the schema is validated by the upstream apiserver, so it can always be marshaled
+			if data, err := json.Marshal(version.Schema.OpenAPIV3Schema); err == nil {
+				usage.SchemaBytes += int64(len(data))
+			}
+		}
+	}
+	return usage
+}
+
+// ExceededCRDLimits returns the names of the limits exceeded by the usage,
+// nil limits are never exceeded.
+func ExceededCRDLimits(usage tenantv1alpha1.TenantCRDUsage, limits *tenantv1alpha1.TenantCRDLimits) []string {
+	if limits == nil {
+		return nil
+	}
+	var exceeded []string
+	if limits.MaxCRDs != nil && usage.CRDs > *limits.MaxCRDs {
+		exceeded = append(exceeded, "maxCRDs")
+	}
+	if limits.MaxServedVersions != nil && usage.ServedVersions > *limits.MaxServedVersions {
+		exceeded = append(exceeded, "maxServedVersions")
+	}
+	if limits.MaxSchemaBytes != nil && usage.SchemaBytes > *limits.MaxSchemaBytes {
+		exceeded = append(exceeded, "maxSchemaBytes")
+	}
+	return exceeded
+}
@@ -21,6 +21,8 @@ import (
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
 )
 
 // TestCRD mainly tests the methods of CustomGroupResourcesMap.
@@ -84,3 +86,48 @@ func TestCRD(t *testing.T) {
 		t.Errorf("crd should not be nil.")
 	}
 }
+
+// TestGetCRDUsage tests the usage of crds is summed over all crds.
+func TestGetCRDUsage(t *testing.T) {
+	schema := &apiextensionsv1.CustomResourceValidation{
+		OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{Type: "object"},
+	}
+	crds := []*apiextensionsv1.CustomResourceDefinition{
+		{
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1", Served: true, Schema: schema},
+					{Name: "v2", Served: false, Schema: schema},
+				},
+			},
+		},
+		{
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1", Served: true},
+				},
+			},
+		},
+		nil,
+	}
+
+	usage := GetCRDUsage(crds...)
+	if usage.CRDs != 2 {
+		t.Errorf("expect 2 crds, got %d", usage.CRDs)
+	}
+	if usage.ServedVersions != 2 {
+		t.Errorf("expect 2 served versions, got %d", usage.ServedVersions)
+	}
+	if expected := int64(2 * len(`{"type":"object"}`)); usage.SchemaBytes != expected {
+		t.Errorf("expect %d schema bytes, got %d", expected, usage.SchemaBytes)
+	}
+
+	maxCRDs := int32(1)
+	exceeded := ExceededCRDLimits(usage, &tenantv1alpha1.TenantCRDLimits{MaxCRDs: &maxCRDs})
+	if len(exceeded) != 1 || exceeded[0] != "maxCRDs" {
+		t.Errorf("expect maxCRDs exceeded, got %v", exceeded)
+	}
+	if exceeded := ExceededCRDLimits(usage, nil); len(exceeded) != 0 {
+		t.Errorf("expect no limit exceeded, got %v", exceeded)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+)
+
+const (
+	// TenantClientSignerName is the signer name of the certificate signing
+	// requests of the tenants, which are signed by kubezoo with the client
+	// CA. It is also recorded as the second organizational unit of the
+	// issued certificates.
+	TenantClientSignerName = "kubezoo.io/tenant-client"
+)
+
+// ParseCSR decodes the PEM encoded certificate signing request and checks
+// its signature.
+func ParseCSR(pemBytes []byte) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, errors.New("PEM block type must be CERTIFICATE REQUEST")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, errors.Wrap(err, "invalid signature of certificate request")
+	}
+	return csr, nil
+}
+
+// NewTenantCSRCertConfig returns the config of the client certificate issued
+// for the certificate signing request of the tenant. The subject requested
+// is overridden, i.e. the common name is prefixed with the tenant ID, the
+// organizations are dropped as the groups are not isolated between tenants,
+// the organizational units are the tenant ID and the signer name, and the
+// tenant UID is recorded in the URI SAN.
+func NewTenantCSRCertConfig(tenant *tenantv1alpha1.Tenant, csr *x509.CertificateRequest, validity time.Duration) (*Config, error) {
+	if len(csr.Subject.CommonName) == 0 {
+		return nil, errors.New("certificate request must specify a common name")
+	}
+	userName := TrimTenantIDPrefix(tenant.Name, csr.Subject.CommonName)
+	if err := CheckTenantCSRUserName(tenant, userName); err != nil {
+		return nil, err
+	}
+	return &Config{
+		CommonName:         AddTenantIDPrefix(tenant.Name, userName),
+		OrganizationalUnit: []string{tenant.Name, TenantClientSignerName},
+		AltNames:           AltNames{URIs: []*url.URL{TenantCertificateURI(tenant.UID)}},
+		Usages:             []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		Validity:           validity,
+	}, nil
+}
+
+// IsTenantCSRCertificate returns true if the certificate is issued by the
+// tenant client signer.
+func IsTenantCSRCertificate(cert *x509.Certificate) bool {
+	ou := cert.Subject.OrganizationalUnit
+	return len(ou) == 2 && ou[1] == TenantClientSignerName
+}
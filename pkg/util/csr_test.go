@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+)
+
+func newTestCSR(t *testing.T, subject pkix.Name) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: subject}, key)
+	if err != nil {
+		t.Fatalf("failed to create csr: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+}
+
+// TestNewTenantCSRCertConfig tests the subject of the certificate signing
+// requests is forced into the tenant.
+func TestNewTenantCSRCertConfig(t *testing.T) {
+	tenant := &tenantv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "111111", UID: "uid-1"},
+		Spec: tenantv1alpha1.TenantSpec{
+			Users: []tenantv1alpha1.TenantUser{{Name: "alice"}},
+		},
+	}
+	tests := []struct {
+		name     string
+		subject  pkix.Name
+		expectCN string
+	}{
+		{
+			name:     "plain user name",
+			subject:  pkix.Name{CommonName: "carol", Organization: []string{"system:masters"}, OrganizationalUnit: []string{"222222"}},
+			expectCN: "111111-carol",
+		},
+		{
+			name:     "prefixed user name",
+			subject:  pkix.Name{CommonName: "111111-carol"},
+			expectCN: "111111-carol",
+		},
+		{
+			name:    "admin user",
+			subject: pkix.Name{CommonName: TenantAdminUserName},
+		},
+		{
+			name:    "named user",
+			subject: pkix.Name{CommonName: "alice"},
+		},
+		{
+			name:    "invalid user name",
+			subject: pkix.Name{CommonName: "system:carol"},
+		},
+		{
+			name: "no common name",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			csr, err := ParseCSR(newTestCSR(t, test.subject))
+			if err != nil {
+				t.Fatalf("failed to parse csr: %v", err)
+			}
+			config, err := NewTenantCSRCertConfig(tenant, csr, time.Hour)
+			if test.expectCN == "" {
+				if err == nil {
+					t.Errorf("expect error, got config %#v", config)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config.CommonName != test.expectCN {
+				t.Errorf("expect common name %s, got %s", test.expectCN, config.CommonName)
+			}
+			if len(config.Organization) != 0 {
+				t.Errorf("expect no organization, got %v", config.Organization)
+			}
+			if len(config.OrganizationalUnit) != 2 || config.OrganizationalUnit[0] != "111111" ||
+				config.OrganizationalUnit[1] != TenantClientSignerName {
+				t.Errorf("unexpected organizational unit %v", config.OrganizationalUnit)
+			}
+			if uris := config.AltNames.URIs; len(uris) != 1 || uris[0].String() != TenantCertificateURIPrefix+"uid-1" {
+				t.Errorf("expect tenant uid uid-1 in uri sans, got %v", uris)
+			}
+			if len(config.Usages) != 1 || config.Usages[0] != x509.ExtKeyUsageClientAuth {
+				t.Errorf("expect client auth usage only, got %v", config.Usages)
+			}
+			if config.Validity != time.Hour {
+				t.Errorf("expect validity %v, got %v", time.Hour, config.Validity)
+			}
+		})
+	}
+}
+
+// TestParseCSR tests the invalid certificate signing requests are rejected.
+func TestParseCSR(t *testing.T) {
+	if _, err := ParseCSR([]byte("invalid")); err == nil {
+		t.Errorf("expect error for invalid pem")
+	}
+	if _, err := ParseCSR(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("x")})); err == nil {
+		t.Errorf("expect error for wrong pem block type")
+	}
+	block, _ := pem.Decode(newTestCSR(t, pkix.Name{CommonName: "carol"}))
+	// corrupt the signature
+	block.Bytes[len(block.Bytes)-1] ^= 0xff
+	if _, err := ParseCSR(pem.EncodeToMemory(block)); err == nil {
+		t.Errorf("expect error for invalid signature")
+	}
+}
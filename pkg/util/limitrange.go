@@ -0,0 +1,56 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	internal "k8s.io/kubernetes/pkg/apis/core"
+	internalv1 "k8s.io/kubernetes/pkg/apis/core/v1"
+	"k8s.io/kubernetes/pkg/apis/core/validation"
+)
+
+// TenantLimitRangeName is the name of the limit range synced from the spec
+// of the tenant into each namespace of the tenant.
+const TenantLimitRangeName = "kubezoo-tenant-limits"
+
+// ValidateTenantLimitRange validates the default limit range of the tenant
+// as the upstream cluster validates the limit range synced from it, so that
+// the invalid one is rejected on the tenant rather than on every sync.
+func ValidateTenantLimitRange(spec *corev1.LimitRangeSpec, fldPath *field.Path) field.ErrorList {
+	if spec == nil {
+		return nil
+	}
+	limitRange := &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: TenantLimitRangeName, Namespace: metav1.NamespaceDefault},
+		Spec:       *spec,
+	}
+	var internalLimitRange internal.LimitRange
+	if err := internalv1.Convert_v1_LimitRange_To_core_LimitRange(limitRange, &internalLimitRange, nil); err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	allErrs := validation.ValidateLimitRange(&internalLimitRange)
+	// the errors are reported on the spec of the limit range
+	specPath := field.NewPath("spec").String()
+	for _, err := range allErrs {
+		err.Field = fldPath.String() + strings.TrimPrefix(err.Field, specPath)
+	}
+	return allErrs
+}
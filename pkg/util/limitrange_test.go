@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// TestValidateTenantLimitRange tests the default limit range of the tenant
+// is validated as a limit range, and the errors are reported on the tenant.
+func TestValidateTenantLimitRange(t *testing.T) {
+	fldPath := field.NewPath("spec", "limitRange")
+	if errs := ValidateTenantLimitRange(nil, fldPath); len(errs) != 0 {
+		t.Errorf("expect no error without limit range, got %v", errs)
+	}
+
+	valid := &corev1.LimitRangeSpec{
+		Limits: []corev1.LimitRangeItem{{
+			Type:           corev1.LimitTypeContainer,
+			Min:            corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+			Max:            corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			Default:        corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+			DefaultRequest: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+		}},
+	}
+	if errs := ValidateTenantLimitRange(valid, fldPath); len(errs) != 0 {
+		t.Errorf("expect valid limit range, got %v", errs)
+	}
+
+	invalid := valid.DeepCopy()
+	invalid.Limits[0].DefaultRequest[corev1.ResourceCPU] = resource.MustParse("1")
+	errs := ValidateTenantLimitRange(invalid, fldPath)
+	if len(errs) == 0 {
+		t.Fatalf("expect the default request above the default limit to be rejected")
+	}
+	for _, err := range errs {
+		if expect := "spec.limitRange.limits[0]"; err.Field[:len(expect)] != expect {
+			t.Errorf("expect the error on %s, got %s", expect, err.Field)
+		}
+	}
+}
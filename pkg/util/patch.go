@@ -0,0 +1,52 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type patchRequestKeyType int
+
+// patchRequestKey is the context key for the patch request.
+const patchRequestKey patchRequestKeyType = iota
+
+// PatchRequest records the raw patch sent by the tenant, so that the proxy
+// is able to pass it through to the upstream cluster instead of applying
+// it locally.
+type PatchRequest struct {
+	// Type is the patch type of the request.
+	Type types.PatchType
+	// Data is the raw patch document.
+	Data []byte
+	// Options is the patch options of the request.
+	Options metav1.PatchOptions
+}
+
+// WithPatchRequest returns a copy of parent in which the patch request value is set.
+func WithPatchRequest(parent context.Context, patch *PatchRequest) context.Context {
+	return context.WithValue(parent, patchRequestKey, patch)
+}
+
+// PatchRequestFrom returns the value of the patch request on the ctx.
+func PatchRequestFrom(ctx context.Context) (*PatchRequest, bool) {
+	patch, ok := ctx.Value(patchRequestKey).(*PatchRequest)
+	return patch, ok && patch != nil
+}
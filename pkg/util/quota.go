@@ -0,0 +1,217 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	quotautil "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	quotav1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1"
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+)
+
+// objectCountQuotaPrefix prefixes the object count quota of the resources,
+// i.e. count/<resource>.<group>.
+const objectCountQuotaPrefix = "count/"
+
+// standardResourceQuotaScopes are the scopes supported by the resource
+// quota of the upstream cluster.
+var standardResourceQuotaScopes = sets.NewString(
+	string(corev1.ResourceQuotaScopeTerminating),
+	string(corev1.ResourceQuotaScopeNotTerminating),
+	string(corev1.ResourceQuotaScopeBestEffort),
+	string(corev1.ResourceQuotaScopeNotBestEffort),
+	string(corev1.ResourceQuotaScopePriorityClass),
+	string(corev1.ResourceQuotaScopeCrossNamespacePodAffinity),
+)
+
+// ValidateTenantQuota validates the quota of the tenant. Every scoped quota
+// must be named by a unique DNS label and match the objects by at least
+// one of the standard scopes.
+func ValidateTenantQuota(quota *tenantv1alpha1.TenantQuota, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := make(map[string]bool, len(quota.Scoped))
+	for i, scoped := range quota.Scoped {
+		idxPath := fldPath.Child("scoped").Index(i)
+		namePath := idxPath.Child("name")
+		for _, msg := range validation.IsDNS1123Label(scoped.Name) {
+			allErrs = append(allErrs, field.Invalid(namePath, scoped.Name, msg))
+		}
+		if names[scoped.Name] {
+			allErrs = append(allErrs, field.Duplicate(namePath, scoped.Name))
+		}
+		names[scoped.Name] = true
+
+		if len(scoped.Scopes) == 0 && (scoped.ScopeSelector == nil || len(scoped.ScopeSelector.MatchExpressions) == 0) {
+			allErrs = append(allErrs, field.Required(idxPath.Child("scopes"), "either scopes or scopeSelector must be set"))
+		}
+		for j, scope := range scoped.Scopes {
+			if !standardResourceQuotaScopes.Has(string(scope)) {
+				allErrs = append(allErrs, field.NotSupported(idxPath.Child("scopes").Index(j), scope, standardResourceQuotaScopes.List()))
+			}
+		}
+		if scoped.ScopeSelector != nil {
+			for j, req := range scoped.ScopeSelector.MatchExpressions {
+				if !standardResourceQuotaScopes.Has(string(req.ScopeName)) {
+					allErrs = append(allErrs, field.NotSupported(idxPath.Child("scopeSelector", "matchExpressions").Index(j).Child("scopeName"),
+						req.ScopeName, standardResourceQuotaScopes.List()))
+				}
+			}
+		}
+	}
+	return allErrs
+}
+
+// ConvertTenantResourceListToUpstream converts the quota of the tenant to
+// the one of the upstream cluster. The object count quotas of the custom
+// resources, i.e. count/<resource>.<group>, are converted to the upstream
+// custom resources whose group is prefixed with the tenant ID, while those
+// of the built-in resources and the other quotas are kept.
+func ConvertTenantResourceListToUpstream(tenantID string, resources corev1.ResourceList) corev1.ResourceList {
+	if resources == nil {
+		return nil
+	}
+	upstream := make(corev1.ResourceList, len(resources))
+	for name, quantity := range resources {
+		upstream[ConvertTenantResourceNameToUpstream(tenantID, name)] = quantity
+	}
+	return upstream
+}
+
+// ConvertTenantResourceNameToUpstream converts the object count quota of the
+// custom resource of the tenant to the one of the upstream custom resource.
+func ConvertTenantResourceNameToUpstream(tenantID string, name corev1.ResourceName) corev1.ResourceName {
+	if !strings.HasPrefix(string(name), objectCountQuotaPrefix) {
+		return name
+	}
+	parts := strings.SplitN(strings.TrimPrefix(string(name), objectCountQuotaPrefix), ".", 2)
+	if len(parts) < 2 || scheme.Scheme.IsGroupRegistered(parts[1]) || parts[1] == apiextensionsv1.GroupName ||
+		strings.HasPrefix(parts[1], tenantID+TenantIDSeparator) {
+		return name
+	}
+	return corev1.ResourceName(objectCountQuotaPrefix + ConvertCRDNameToUpstream(strings.Join(parts, "."), tenantID))
+}
+
+// ConvertUpstreamResourceNameToTenant converts the object count quota of the
+// upstream custom resource of the tenant to the one seen inside the tenant,
+// it reverses ConvertTenantResourceNameToUpstream.
+func ConvertUpstreamResourceNameToTenant(tenantID string, name corev1.ResourceName) corev1.ResourceName {
+	if !strings.HasPrefix(string(name), objectCountQuotaPrefix) {
+		return name
+	}
+	parts := strings.SplitN(strings.TrimPrefix(string(name), objectCountQuotaPrefix), ".", 2)
+	if len(parts) < 2 || !strings.HasPrefix(parts[1], tenantID+TenantIDSeparator) {
+		return name
+	}
+	return corev1.ResourceName(objectCountQuotaPrefix + parts[0] + "." + TrimTenantIDPrefix(tenantID, parts[1]))
+}
+
+// IsQuotaAllocation returns true if the resource quota is created in the
+// namespace by the tenant, which allocates a part of the quota of the tenant
+// to the namespace, rather than generated by the cluster resource quota.
+func IsQuotaAllocation(quota *corev1.ResourceQuota) bool {
+	_, ok := quota.Labels[quotav1alpha1.ClusterResourceQuotaCreatedby]
+	return !ok
+}
+
+// QuotaScopesMatch returns true if the quotas track the same objects, i.e.
+// they have the same scopes and scope selector.
+func QuotaScopesMatch(a, b *corev1.ResourceQuotaSpec) bool {
+	scopes := func(spec *corev1.ResourceQuotaSpec) sets.String {
+		s := sets.NewString()
+		for _, scope := range spec.Scopes {
+			s.Insert(string(scope))
+		}
+		return s
+	}
+	matchExpressions := func(spec *corev1.ResourceQuotaSpec) []corev1.ScopedResourceSelectorRequirement {
+		if spec.ScopeSelector == nil || len(spec.ScopeSelector.MatchExpressions) == 0 {
+			return nil
+		}
+		return spec.ScopeSelector.MatchExpressions
+	}
+	return scopes(a).Equal(scopes(b)) && apiequality.Semantic.DeepEqual(matchExpressions(a), matchExpressions(b))
+}
+
+// CheckQuotaAllocation checks the hard limits of the resource quota, summed
+// with the other allocations of the same scopes, are no more than the hard
+// limits of each cluster resource quota of the same scopes. The others are
+// the resource quotas in the namespaces of the cluster resource quotas, which
+// may include the former version of the quota. Only the resources whose
+// allocation is increased are checked, so that the allocations exceeding a
+// lowered cluster resource quota can still be decreased.
+func CheckQuotaAllocation(quota *corev1.ResourceQuota, others []*corev1.ResourceQuota, clusterquotas []*quotav1alpha1.ClusterResourceQuota) error {
+	var former corev1.ResourceList
+	allocated := quotautil.Add(corev1.ResourceList{}, quota.Spec.Hard)
+	for _, other := range others {
+		if !IsQuotaAllocation(other) || !QuotaScopesMatch(&other.Spec, &quota.Spec) {
+			continue
+		}
+		if other.Namespace == quota.Namespace && other.Name == quota.Name {
+			former = other.Spec.Hard
+			continue
+		}
+		allocated = quotautil.Add(allocated, other.Spec.Hard)
+	}
+
+	for _, clusterquota := range clusterquotas {
+		if !QuotaScopesMatch(&clusterquota.Spec.ResourceQuotaSpec, &quota.Spec) {
+			continue
+		}
+		var exceeded []corev1.ResourceName
+		for name, hard := range clusterquota.Spec.Hard {
+			requested, ok := quota.Spec.Hard[name]
+			if !ok {
+				continue
+			}
+			if formerValue, ok := former[name]; ok && requested.Cmp(formerValue) <= 0 {
+				continue
+			}
+			if value := allocated[name]; value.Cmp(hard) > 0 {
+				exceeded = append(exceeded, name)
+			}
+		}
+		if len(exceeded) > 0 {
+			return fmt.Errorf("exceeded quota allocation: %s, requested: %s, allocated: %s, limited: %s", clusterquota.Name,
+				prettyPrintResourceList(quotautil.Mask(quota.Spec.Hard, exceeded)),
+				prettyPrintResourceList(quotautil.Mask(allocated, exceeded)),
+				prettyPrintResourceList(quotautil.Mask(clusterquota.Spec.Hard, exceeded)))
+		}
+	}
+	return nil
+}
+
+// prettyPrintResourceList formats the resource list as the resource quota
+// admission does, e.g. pods=2,requests.cpu=1.
+func prettyPrintResourceList(resources corev1.ResourceList) string {
+	var parts []string
+	for name, quantity := range resources {
+		parts = append(parts, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
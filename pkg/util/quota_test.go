@@ -0,0 +1,194 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	quotav1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/quota/v1alpha1"
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+)
+
+// TestConvertTenantResourceNameToUpstream tests only the object count quotas
+// of the custom resources are converted.
+func TestConvertTenantResourceNameToUpstream(t *testing.T) {
+	tests := []struct {
+		name   corev1.ResourceName
+		expect corev1.ResourceName
+	}{
+		{name: "count/foos.kubezoo.io", expect: "count/foos.111111-kubezoo.io"},
+		{name: "count/foos.111111-kubezoo.io", expect: "count/foos.111111-kubezoo.io"},
+		{name: "count/deployments.apps", expect: "count/deployments.apps"},
+		{name: "count/customresourcedefinitions.apiextensions.k8s.io", expect: "count/customresourcedefinitions.apiextensions.k8s.io"},
+		{name: "count/persistentvolumes", expect: "count/persistentvolumes"},
+		{name: "count/pods", expect: "count/pods"},
+		{name: "requests.storage", expect: "requests.storage"},
+		{name: "gold.storageclass.storage.k8s.io/requests.storage", expect: "gold.storageclass.storage.k8s.io/requests.storage"},
+	}
+	for _, test := range tests {
+		if got := ConvertTenantResourceNameToUpstream("111111", test.name); got != test.expect {
+			t.Errorf("expect %s for %s, got %s", test.expect, test.name, got)
+		}
+	}
+}
+
+// TestValidateTenantQuota tests the scoped quotas are uniquely named and
+// matched by the standard scopes.
+func TestValidateTenantQuota(t *testing.T) {
+	priorityClassSelector := &corev1.ScopeSelector{
+		MatchExpressions: []corev1.ScopedResourceSelectorRequirement{{
+			ScopeName: corev1.ResourceQuotaScopePriorityClass,
+			Operator:  corev1.ScopeSelectorOpIn,
+			Values:    []string{"high"},
+		}},
+	}
+	tests := []struct {
+		name      string
+		scoped    []tenantv1alpha1.TenantScopedQuota
+		expectErr bool
+	}{
+		{
+			name: "valid scoped quotas",
+			scoped: []tenantv1alpha1.TenantScopedQuota{
+				{Name: "best-effort", Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}},
+				{Name: "high", ScopeSelector: priorityClassSelector},
+			},
+		},
+		{
+			name: "duplicate names",
+			scoped: []tenantv1alpha1.TenantScopedQuota{
+				{Name: "high", ScopeSelector: priorityClassSelector},
+				{Name: "high", Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeTerminating}},
+			},
+			expectErr: true,
+		},
+		{
+			name:      "invalid name",
+			scoped:    []tenantv1alpha1.TenantScopedQuota{{Name: "High", ScopeSelector: priorityClassSelector}},
+			expectErr: true,
+		},
+		{
+			name:      "no scopes",
+			scoped:    []tenantv1alpha1.TenantScopedQuota{{Name: "none"}},
+			expectErr: true,
+		},
+		{
+			name:      "unknown scope",
+			scoped:    []tenantv1alpha1.TenantScopedQuota{{Name: "foo", Scopes: []corev1.ResourceQuotaScope{"Foo"}}},
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		quota := &tenantv1alpha1.TenantQuota{Scoped: test.scoped}
+		errs := ValidateTenantQuota(quota, field.NewPath("spec", "quota"))
+		if (len(errs) != 0) != test.expectErr {
+			t.Errorf("%s: expect error %v, got %v", test.name, test.expectErr, errs)
+		}
+	}
+}
+
+// TestConvertUpstreamResourceNameToTenant tests only the object count quotas
+// of the custom resources of the tenant are converted back.
+func TestConvertUpstreamResourceNameToTenant(t *testing.T) {
+	tests := []struct {
+		name   corev1.ResourceName
+		expect corev1.ResourceName
+	}{
+		{name: "count/foos.111111-kubezoo.io", expect: "count/foos.kubezoo.io"},
+		{name: "count/foos.222222-kubezoo.io", expect: "count/foos.222222-kubezoo.io"},
+		{name: "count/deployments.apps", expect: "count/deployments.apps"},
+		{name: "count/pods", expect: "count/pods"},
+		{name: "requests.storage", expect: "requests.storage"},
+	}
+	for _, test := range tests {
+		if got := ConvertUpstreamResourceNameToTenant("111111", test.name); got != test.expect {
+			t.Errorf("expect %s for %s, got %s", test.expect, test.name, got)
+		}
+	}
+}
+
+// TestCheckQuotaAllocation tests the allocations of the same scopes summed
+// over the namespaces can not exceed the cluster resource quota.
+func TestCheckQuotaAllocation(t *testing.T) {
+	newQuota := func(namespace, pods string, scopes ...corev1.ResourceQuotaScope) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "budget"},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard:   corev1.ResourceList{corev1.ResourcePods: resource.MustParse(pods)},
+				Scopes: scopes,
+			},
+		}
+	}
+	generated := newQuota("111111-b", "10")
+	generated.Name = "kubezoo-tenant-quota-111111-abcde"
+	generated.Labels = map[string]string{quotav1alpha1.ClusterResourceQuotaCreatedby: "kubezoo-tenant-quota-111111"}
+	others := []*corev1.ResourceQuota{
+		newQuota("111111-a", "6"),
+		newQuota("111111-b", "2"),
+		newQuota("111111-c", "5", corev1.ResourceQuotaScopeBestEffort),
+		generated,
+	}
+	clusterquotas := []*quotav1alpha1.ClusterResourceQuota{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubezoo-tenant-quota-111111"},
+			Spec: quotav1alpha1.ClusterResourceQuotaSpec{
+				ResourceQuotaSpec: corev1.ResourceQuotaSpec{
+					Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubezoo-tenant-quota-111111-besteffort"},
+			Spec: quotav1alpha1.ClusterResourceQuotaSpec{
+				ResourceQuotaSpec: corev1.ResourceQuotaSpec{
+					Hard:   corev1.ResourceList{corev1.ResourcePods: resource.MustParse("5")},
+					Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name   string
+		quota  *corev1.ResourceQuota
+		others []*corev1.ResourceQuota
+		expect bool
+	}{
+		{name: "allocate within quota", quota: newQuota("111111-d", "2"), others: others, expect: true},
+		{name: "allocate over quota", quota: newQuota("111111-d", "3"), others: others},
+		{name: "increase allocation within quota", quota: newQuota("111111-b", "4"), others: others, expect: true},
+		{name: "increase allocation over quota", quota: newQuota("111111-b", "5"), others: others},
+		{name: "allocate over scoped quota", quota: newQuota("111111-d", "1", corev1.ResourceQuotaScopeBestEffort), others: others},
+		{name: "allocate without matched quota", quota: newQuota("111111-d", "20", corev1.ResourceQuotaScopeTerminating), others: others, expect: true},
+		{
+			name:   "decrease allocation over lowered quota",
+			quota:  newQuota("111111-b", "7"),
+			others: []*corev1.ResourceQuota{newQuota("111111-a", "6"), newQuota("111111-b", "8")},
+			expect: true,
+		},
+	}
+	for _, test := range tests {
+		err := CheckQuotaAllocation(test.quota, test.others, clusterquotas)
+		if (err == nil) != test.expect {
+			t.Errorf("%s: expect allowed %v, got %v", test.name, test.expect, err)
+		}
+	}
+}
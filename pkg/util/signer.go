@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"time"
+
+	"github.com/pkg/errors"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+// CASigner provides the client CA which signs the certificates of the
+// tenant users.
+type CASigner interface {
+	// CA returns the certificate of the client CA and the signer of its
+	// key. The signer may send the digests to be signed out of the proxy
+	// host, so that the key of the client CA is never loaded by kubezoo.
+	CA() (*x509.Certificate, crypto.Signer, error)
+}
+
+// fileCASigner loads the client CA from the local files.
+type fileCASigner struct {
+	caFile    string
+	caKeyFile string
+}
+
+// NewFileCASigner returns a CASigner which loads the certificate and the key
+// of the client CA from the local files. The files are loaded on every call,
+// so that the rotated client CA is picked up without restart.
+func NewFileCASigner(caFile, caKeyFile string) CASigner {
+	return &fileCASigner{caFile: caFile, caKeyFile: caKeyFile}
+}
+
+// CA implements CASigner.
+func (s *fileCASigner) CA() (*x509.Certificate, crypto.Signer, error) {
+	return LoadCertAndKey(s.caFile, s.caKeyFile)
+}
+
+// SignRequest is sent to the external signer to sign the digest with the
+// key of the client CA.
+type SignRequest struct {
+	// Hash is the hash function of the digest, e.g. SHA-256.
+	Hash string `json:"hash"`
+	// Digest is the digest to be signed.
+	Digest []byte `json:"digest"`
+}
+
+// SignResponse is returned by the external signer.
+type SignResponse struct {
+	// Signature is the signature of the digest, i.e. PKCS #1 v1.5 for RSA
+	// keys and ASN.1 DER for ECDSA keys as with crypto.Signer.
+	Signature []byte `json:"signature"`
+}
+
+// signFunc signs the digest by the external signer.
+type signFunc func(ctx context.Context, req *SignRequest) (*SignResponse, error)
+
+// remoteCASigner loads the certificate of the client CA from the local file,
+// and signs the digests by the external signer holding the key.
+type remoteCASigner struct {
+	caFile  string
+	timeout time.Duration
+	sign    signFunc
+}
+
+// NewExecCASigner returns a CASigner which signs the digests by running the
+// command, e.g. a PKCS #11 helper backed by a HSM. The SignRequest is written
+// to the stdin of the command in JSON, and the SignResponse is expected from
+// its stdout.
+func NewExecCASigner(caFile, command string, args []string, timeout time.Duration) CASigner {
+	return &remoteCASigner{
+		caFile:  caFile,
+		timeout: timeout,
+		sign: func(ctx context.Context, req *SignRequest) (*SignResponse, error) {
+			in, err := json.Marshal(req)
+			if err != nil {
+				return nil, err
+			}
+			var stdout, stderr bytes.Buffer
+			cmd := exec.CommandContext(ctx, command, args...)
+			cmd.Stdin = bytes.NewReader(in)
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+			if err := cmd.Run(); err != nil {
+				return nil, errors.Wrapf(err, "signer command %s failed: %s", command, stderr.String())
+			}
+			resp := &SignResponse{}
+			if err := json.Unmarshal(stdout.Bytes(), resp); err != nil {
+				return nil, errors.Wrapf(err, "invalid response of signer command %s", command)
+			}
+			return resp, nil
+		},
+	}
+}
+
+// NewHTTPCASigner returns a CASigner which signs the digests by posting the
+// SignRequest to the url in JSON, and the SignResponse is expected in the
+// response body. The client is expected to authenticate kubezoo to the
+// signer, e.g. by the client certificate.
+func NewHTTPCASigner(caFile, url string, client *http.Client, timeout time.Duration) CASigner {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &remoteCASigner{
+		caFile:  caFile,
+		timeout: timeout,
+		sign: func(ctx context.Context, req *SignRequest) (*SignResponse, error) {
+			in, err := json.Marshal(req)
+			if err != nil {
+				return nil, err
+			}
+			httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(in))
+			if err != nil {
+				return nil, err
+			}
+			httpReq.Header.Set("Content-Type", "application/json")
+			httpResp, err := client.Do(httpReq)
+			if err != nil {
+				return nil, err
+			}
+			defer httpResp.Body.Close()
+			body, err := io.ReadAll(io.LimitReader(httpResp.Body, 1<<20))
+			if err != nil {
+				return nil, err
+			}
+			if httpResp.StatusCode != http.StatusOK {
+				return nil, errors.Errorf("signer %s returned %s: %s", url, httpResp.Status, string(body))
+			}
+			resp := &SignResponse{}
+			if err := json.Unmarshal(body, resp); err != nil {
+				return nil, errors.Wrapf(err, "invalid response of signer %s", url)
+			}
+			return resp, nil
+		},
+	}
+}
+
+// CA implements CASigner.
+func (s *remoteCASigner) CA() (*x509.Certificate, crypto.Signer, error) {
+	certs, err := certutil.CertsFromFile(s.caFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return certs[0], &remoteKey{public: certs[0].PublicKey, timeout: s.timeout, sign: s.sign}, nil
+}
+
+// remoteKey implements crypto.Signer by the external signer.
+type remoteKey struct {
+	public  crypto.PublicKey
+	timeout time.Duration
+	sign    signFunc
+}
+
+// Public implements crypto.Signer.
+func (k *remoteKey) Public() crypto.PublicKey {
+	return k.public
+}
+
+// Sign implements crypto.Signer.
+func (k *remoteKey) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if _, ok := opts.(*rsa.PSSOptions); ok {
+		return nil, errors.New("RSA-PSS signatures are not supported by the external signer")
+	}
+	if !opts.HashFunc().Available() {
+		return nil, fmt.Errorf("unsupported hash function %v", opts.HashFunc())
+	}
+
+	ctx := context.Background()
+	if k.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, k.timeout)
+		defer cancel()
+	}
+	resp, err := k.sign(ctx, &SignRequest{Hash: opts.HashFunc().String(), Digest: digest})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Signature) == 0 {
+		return nil, errors.New("empty signature returned by the external signer")
+	}
+	return resp.Signature, nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+)
+
+// writeTestCAFiles writes the test CA and its key into the temp dir.
+func writeTestCAFiles(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	caFile, caKeyFile := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+	if err := os.WriteFile(caFile, []byte(CA), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(caKeyFile, []byte(Key), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return caFile, caKeyFile
+}
+
+// signWithTestKey signs the request with the key of the test CA, as the
+// external signers do.
+func signWithTestKey(req *SignRequest) (*SignResponse, error) {
+	key, err := keyutil.ParsePrivateKeyPEM([]byte(Key))
+	if err != nil {
+		return nil, err
+	}
+	hashes := map[string]crypto.Hash{
+		crypto.SHA256.String(): crypto.SHA256,
+		crypto.SHA384.String(): crypto.SHA384,
+		crypto.SHA512.String(): crypto.SHA512,
+	}
+	signature, err := key.(crypto.Signer).Sign(rand.Reader, req.Digest, hashes[req.Hash])
+	if err != nil {
+		return nil, err
+	}
+	return &SignResponse{Signature: signature}, nil
+}
+
+// TestHelperSignerProcess is not a real test, but the external signer run
+// by TestCASigners.
+func TestHelperSignerProcess(t *testing.T) {
+	if os.Getenv("KUBEZOO_TEST_SIGNER_PROCESS") != "1" {
+		return
+	}
+	req := &SignRequest{}
+	if err := json.NewDecoder(os.Stdin).Decode(req); err != nil {
+		os.Exit(1)
+	}
+	resp, err := signWithTestKey(req)
+	if err != nil {
+		os.Exit(1)
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// verifyTestCert verifies the certificate is signed by the test CA.
+func verifyTestCert(t *testing.T, caFile string, cert *x509.Certificate) {
+	caCerts, err := certutil.CertsFromFile(caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cert.CheckSignatureFrom(caCerts[0]); err != nil {
+		t.Errorf("certificate is not signed by the CA: %v", err)
+	}
+}
+
+// TestCASigners tests the certificates signed by the signers are signed
+// by the client CA.
+func TestCASigners(t *testing.T) {
+	caFile, caKeyFile := writeTestCAFiles(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &SignRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp, err := signWithTestKey(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer failingServer.Close()
+
+	os.Setenv("KUBEZOO_TEST_SIGNER_PROCESS", "1")
+	defer os.Unsetenv("KUBEZOO_TEST_SIGNER_PROCESS")
+
+	tests := []struct {
+		name      string
+		signer    CASigner
+		algorithm KeyAlgorithm
+		expectErr bool
+	}{
+		{
+			name:   "file signer",
+			signer: NewFileCASigner(caFile, caKeyFile),
+		},
+		{
+			name:      "file signer with ecdsa key",
+			signer:    NewFileCASigner(caFile, caKeyFile),
+			algorithm: KeyAlgorithmECDSA,
+		},
+		{
+			name:      "http signer",
+			signer:    NewHTTPCASigner(caFile, server.URL, nil, time.Minute),
+			algorithm: KeyAlgorithmECDSA,
+		},
+		{
+			name:      "http signer returning error",
+			signer:    NewHTTPCASigner(caFile, failingServer.URL, nil, time.Minute),
+			expectErr: true,
+		},
+		{
+			name:   "exec signer",
+			signer: NewExecCASigner(caFile, os.Args[0], []string{"-test.run=TestHelperSignerProcess"}, time.Minute),
+		},
+		{
+			name:      "exec signer failing",
+			signer:    NewExecCASigner(caFile, "false", nil, time.Minute),
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := NewTenantUserCertConfig("111111", "uid-1", "alice")
+			config.KeyAlgorithm = test.algorithm
+			config.Validity = time.Hour
+			cert, key, err := NewCertAndKey(test.signer, config)
+			if test.expectErr {
+				if err == nil {
+					t.Errorf("expect error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			verifyTestCert(t, caFile, cert)
+
+			expectAlgorithm := x509.RSA
+			if test.algorithm == KeyAlgorithmECDSA {
+				expectAlgorithm = x509.ECDSA
+			}
+			if cert.PublicKeyAlgorithm != expectAlgorithm {
+				t.Errorf("expect public key algorithm %v, got %v", expectAlgorithm, cert.PublicKeyAlgorithm)
+			}
+			if _, err := EncodePrivateKeyPEM(key); err != nil {
+				t.Errorf("failed to encode key: %v", err)
+			}
+			if cert.Subject.CommonName != "111111-alice" {
+				t.Errorf("unexpect CN %s", cert.Subject.CommonName)
+			}
+			if remaining := time.Until(cert.NotAfter); remaining > time.Hour || remaining < 59*time.Minute {
+				t.Errorf("expect validity of an hour, got %v remaining", remaining)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authentication/user"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+)
+
+const (
+	// TenantAdminUserName is the name of the admin user of every tenant,
+	// whose certificate is issued on the creation of the tenant.
+	TenantAdminUserName = "admin"
+	// MaxTenantUserNameLength is the max length of the name of a tenant
+	// user, which keeps the kubeconfig annotation key a valid label.
+	MaxTenantUserNameLength = 32
+
+	tenantUserKubeConfigAnnotationPrefix = "kubezoo.io/tenant.user."
+	tenantUserKubeConfigAnnotationSuffix = ".kubeconfig.base64"
+)
+
+// TenantUserKubeConfigAnnotation returns the annotation key of the base64
+// encoded kubeconfig of the named tenant user.
+func TenantUserKubeConfigAnnotation(userName string) string {
+	return tenantUserKubeConfigAnnotationPrefix + userName + tenantUserKubeConfigAnnotationSuffix
+}
+
+// TenantUserFromKubeConfigAnnotation returns the name of the tenant user
+// whose kubeconfig is stored in the annotation key.
+func TenantUserFromKubeConfigAnnotation(key string) (string, bool) {
+	if !strings.HasPrefix(key, tenantUserKubeConfigAnnotationPrefix) ||
+		!strings.HasSuffix(key, tenantUserKubeConfigAnnotationSuffix) {
+		return "", false
+	}
+	userName := strings.TrimSuffix(strings.TrimPrefix(key, tenantUserKubeConfigAnnotationPrefix), tenantUserKubeConfigAnnotationSuffix)
+	return userName, len(userName) != 0
+}
+
+// ValidateTenantUsers validates the named users of the tenant.
+func ValidateTenantUsers(users []tenantv1alpha1.TenantUser, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := make(map[string]bool, len(users))
+	for i, u := range users {
+		namePath := fldPath.Index(i).Child("name")
+		for _, msg := range validation.IsDNS1123Label(u.Name) {
+			allErrs = append(allErrs, field.Invalid(namePath, u.Name, msg))
+		}
+		if len(u.Name) > MaxTenantUserNameLength {
+			allErrs = append(allErrs, field.TooLong(namePath, u.Name, MaxTenantUserNameLength))
+		}
+		if u.Name == TenantAdminUserName {
+			allErrs = append(allErrs, field.Invalid(namePath, u.Name, "the admin user is reserved"))
+		}
+		if names[u.Name] {
+			allErrs = append(allErrs, field.Duplicate(namePath, u.Name))
+		}
+		names[u.Name] = true
+	}
+	return allErrs
+}
+
+// CheckTenantUserCertificate checks whether the certificate with the serial
+// number is the one issued to the named user of the tenant, the certificates
+// of revoked or removed users and the superseded ones are rejected.
+func CheckTenantUserCertificate(tenant *tenantv1alpha1.Tenant, userName string, serialNumber *big.Int) error {
+	if serialNumber == nil {
+		return errors.Errorf("certificate of user %s has no serial number", userName)
+	}
+	var found bool
+	for _, u := range tenant.Spec.Users {
+		if u.Name != userName {
+			continue
+		}
+		if u.Revoked {
+			return errors.Errorf("user %s of tenant %s is revoked", userName, tenant.Name)
+		}
+		found = true
+		break
+	}
+	if !found {
+		return errors.Errorf("user %s is not found in tenant %s", userName, tenant.Name)
+	}
+	for _, s := range tenant.Status.Users {
+		if s.Name == userName {
+			if s.SerialNumber != serialNumber.String() {
+				return errors.Errorf("certificate %s of user %s in tenant %s is revoked", serialNumber, userName, tenant.Name)
+			}
+			return nil
+		}
+	}
+	return errors.Errorf("no certificate is issued to user %s in tenant %s", userName, tenant.Name)
+}
+
+// CheckTenantCSRUserName checks whether a certificate can be issued to the
+// user of the tenant by the tenant client signer. The admin and the named
+// users of the tenant are excluded, as their certificates are managed in
+// the tenant spec.
+func CheckTenantCSRUserName(tenant *tenantv1alpha1.Tenant, userName string) error {
+	if msgs := validation.IsDNS1123Label(userName); len(msgs) != 0 {
+		return errors.Errorf("invalid user name %s: %s", userName, strings.Join(msgs, ", "))
+	}
+	if len(userName) > MaxTenantUserNameLength {
+		return errors.Errorf("user name %s must be no more than %d characters", userName, MaxTenantUserNameLength)
+	}
+	if userName == TenantAdminUserName {
+		return errors.Errorf("user %s is reserved in tenant %s", userName, tenant.Name)
+	}
+	for _, u := range tenant.Spec.Users {
+		if u.Name == userName {
+			return errors.Errorf("user %s is declared in the spec of tenant %s", userName, tenant.Name)
+		}
+	}
+	return nil
+}
+
+// AddTenantIDToUserName returns the upstream name of the tenant user, the
+// namespaces of the service accounts are prefixed instead of the names.
+func AddTenantIDToUserName(tenantID, userName string) string {
+	if len(userName) == 0 {
+		return userName
+	}
+	if namespace, name, err := serviceaccount.SplitUsername(userName); err == nil {
+		return serviceaccount.MakeUsername(AddTenantIDPrefix(tenantID, namespace), name)
+	}
+	return AddTenantIDPrefix(tenantID, userName)
+}
+
+// AddTenantIDToGroups returns the upstream groups of the tenant user, i.e.
+// the namespaces of the service account groups are prefixed.
+func AddTenantIDToGroups(tenantID string, groups []string) []string {
+	for i, group := range groups {
+		if strings.HasPrefix(group, serviceaccount.ServiceAccountGroupPrefix) {
+			namespace := strings.TrimPrefix(group, serviceaccount.ServiceAccountGroupPrefix)
+			groups[i] = serviceaccount.MakeNamespaceGroupName(AddTenantIDPrefix(tenantID, namespace))
+		}
+	}
+	return groups
+}
+
+// TrimTenantIDFromUserInfo returns the user as seen inside the tenant, the
+// tenant ID prefix is trimmed from the user name and from the namespaces of
+// the service accounts.
+func TrimTenantIDFromUserInfo(tenantID string, info user.Info) user.Info {
+	name := info.GetName()
+	prefixedNamespace, saName, err := serviceaccount.SplitUsername(name)
+	if err == nil {
+		name = serviceaccount.MakeUsername(TrimTenantIDPrefix(tenantID, prefixedNamespace), saName)
+	} else {
+		name = TrimTenantIDPrefix(tenantID, name)
+	}
+
+	groups := make([]string, 0, len(info.GetGroups()))
+	for _, g := range info.GetGroups() {
+		if strings.HasPrefix(g, serviceaccount.ServiceAccountGroupPrefix) {
+			namespace := strings.TrimPrefix(g, serviceaccount.ServiceAccountGroupPrefix)
+			g = serviceaccount.MakeNamespaceGroupName(TrimTenantIDPrefix(tenantID, namespace))
+		}
+		groups = append(groups, g)
+	}
+	return &user.DefaultInfo{
+		Name:   name,
+		UID:    info.GetUID(),
+		Groups: groups,
+		Extra:  info.GetExtra(),
+	}
+}
+
+// SetImpersonateHeaders sets the headers impersonating the user in the
+// upstream cluster, including the UID and the extras of the user. The
+// impersonation headers already in the request are removed.
+func SetImpersonateHeaders(header http.Header, u user.Info) {
+	for key := range header {
+		if strings.HasPrefix(key, authenticationv1.ImpersonateUserExtraHeaderPrefix) {
+			header.Del(key)
+		}
+	}
+	header.Del(authenticationv1.ImpersonateGroupHeader)
+	header.Del(authenticationv1.ImpersonateUIDHeader)
+
+	header.Set(authenticationv1.ImpersonateUserHeader, u.GetName())
+	for _, group := range u.GetGroups() {
+		header.Add(authenticationv1.ImpersonateGroupHeader, group)
+	}
+	if uid := u.GetUID(); len(uid) != 0 {
+		header.Set(authenticationv1.ImpersonateUIDHeader, uid)
+	}
+	for key, values := range u.GetExtra() {
+		// the extra keys are unescaped by the upstream cluster
+		name := authenticationv1.ImpersonateUserExtraHeaderPrefix + url.PathEscape(key)
+		for _, value := range values {
+			header.Add(name, value)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The KubeZoo Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math/big"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/authentication/user"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+)
+
+// TestTenantUserKubeConfigAnnotation tests the user name is recovered from
+// the kubeconfig annotation key.
+func TestTenantUserKubeConfigAnnotation(t *testing.T) {
+	key := TenantUserKubeConfigAnnotation("alice")
+	if userName, ok := TenantUserFromKubeConfigAnnotation(key); !ok || userName != "alice" {
+		t.Errorf("expect user alice from annotation %s, got %q", key, userName)
+	}
+	for _, key := range []string{AnnotationTenantKubeConfigBase64, "kubezoo.io/tenant.user..kubeconfig.base64"} {
+		if userName, ok := TenantUserFromKubeConfigAnnotation(key); ok {
+			t.Errorf("expect no user from annotation %s, got %q", key, userName)
+		}
+	}
+}
+
+// TestValidateTenantUsers tests the validation of the named tenant users.
+func TestValidateTenantUsers(t *testing.T) {
+	tests := []struct {
+		name       string
+		users      []tenantv1alpha1.TenantUser
+		expectErrs int
+	}{
+		{
+			name:  "valid users",
+			users: []tenantv1alpha1.TenantUser{{Name: "alice"}, {Name: "ci-bot", Revoked: true}},
+		},
+		{
+			name:       "reserved admin user",
+			users:      []tenantv1alpha1.TenantUser{{Name: TenantAdminUserName}},
+			expectErrs: 1,
+		},
+		{
+			name:       "duplicate users",
+			users:      []tenantv1alpha1.TenantUser{{Name: "alice"}, {Name: "alice"}},
+			expectErrs: 1,
+		},
+		{
+			name:       "invalid user name",
+			users:      []tenantv1alpha1.TenantUser{{Name: "Alice"}},
+			expectErrs: 1,
+		},
+		{
+			name:       "too long user name",
+			users:      []tenantv1alpha1.TenantUser{{Name: strings.Repeat("a", MaxTenantUserNameLength+1)}},
+			expectErrs: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateTenantUsers(test.users, field.NewPath("spec", "users"))
+			if len(errs) != test.expectErrs {
+				t.Errorf("expect %d errors, got %v", test.expectErrs, errs)
+			}
+		})
+	}
+}
+
+// TestCheckTenantUserCertificate tests only the certificate recorded in
+// the tenant status is accepted for an active user.
+func TestCheckTenantUserCertificate(t *testing.T) {
+	tenant := &tenantv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "111111"},
+		Spec: tenantv1alpha1.TenantSpec{
+			Users: []tenantv1alpha1.TenantUser{
+				{Name: "alice"},
+				{Name: "bob", Revoked: true},
+				{Name: "carol"},
+			},
+		},
+		Status: tenantv1alpha1.TenantStatus{
+			Users: []tenantv1alpha1.TenantUserStatus{
+				{Name: "alice", SerialNumber: "1001"},
+				{Name: "bob", SerialNumber: "1002"},
+				{Name: "dave", SerialNumber: "1003"},
+			},
+		},
+	}
+	tests := []struct {
+		name         string
+		userName     string
+		serialNumber *big.Int
+		expectErr    bool
+	}{
+		{
+			name:         "issued certificate",
+			userName:     "alice",
+			serialNumber: big.NewInt(1001),
+		},
+		{
+			name:         "superseded certificate",
+			userName:     "alice",
+			serialNumber: big.NewInt(1000),
+			expectErr:    true,
+		},
+		{
+			name:         "revoked user",
+			userName:     "bob",
+			serialNumber: big.NewInt(1002),
+			expectErr:    true,
+		},
+		{
+			name:         "user without certificate",
+			userName:     "carol",
+			serialNumber: big.NewInt(1004),
+			expectErr:    true,
+		},
+		{
+			name:         "removed user",
+			userName:     "dave",
+			serialNumber: big.NewInt(1003),
+			expectErr:    true,
+		},
+		{
+			name:      "no serial number",
+			userName:  "alice",
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := CheckTenantUserCertificate(tenant, test.userName, test.serialNumber)
+			if test.expectErr != (err != nil) {
+				t.Errorf("expect error %v, got %v", test.expectErr, err)
+			}
+		})
+	}
+}
+
+// TestTrimTenantIDFromUserInfo tests the tenant ID prefix is trimmed from the
+// users and the service accounts.
+func TestTrimTenantIDFromUserInfo(t *testing.T) {
+	extra := map[string][]string{TenantIDKey: {"foofoo"}}
+	tests := []struct {
+		name   string
+		user   user.Info
+		expect user.Info
+	}{
+		{
+			name:   "tenant user",
+			user:   &user.DefaultInfo{Name: "foofoo-alice", UID: "1", Groups: []string{"dev"}, Extra: extra},
+			expect: &user.DefaultInfo{Name: "alice", UID: "1", Groups: []string{"dev"}, Extra: extra},
+		},
+		{
+			name: "service account",
+			user: &user.DefaultInfo{
+				Name:   "system:serviceaccount:foofoo-default:builder",
+				Groups: []string{"system:serviceaccounts", "system:serviceaccounts:foofoo-default", "system:authenticated"},
+				Extra:  extra,
+			},
+			expect: &user.DefaultInfo{
+				Name:   "system:serviceaccount:default:builder",
+				Groups: []string{"system:serviceaccounts", "system:serviceaccounts:default", "system:authenticated"},
+				Extra:  extra,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := TrimTenantIDFromUserInfo("foofoo", test.user); !reflect.DeepEqual(got, test.expect) {
+				t.Errorf("expect user %#v, got %#v", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestSetImpersonateHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Impersonate-Extra-Scopes", "admin")
+	header.Set("Impersonate-Group", "system:masters")
+	header.Set("Accept", "application/json")
+	SetImpersonateHeaders(header, &user.DefaultInfo{
+		Name:   AddTenantIDToUserName("foofoo", "system:serviceaccount:default:builder"),
+		UID:    "1",
+		Groups: AddTenantIDToGroups("foofoo", []string{"system:serviceaccounts", "system:serviceaccounts:default"}),
+		Extra:  map[string][]string{TenantIDKey: {"foofoo"}, "authentication.kubernetes.io/pod-name": {"builder-1"}},
+	})
+	expect := http.Header{
+		"Accept":                   {"application/json"},
+		"Impersonate-User":         {"system:serviceaccount:foofoo-default:builder"},
+		"Impersonate-Uid":          {"1"},
+		"Impersonate-Group":        {"system:serviceaccounts", "system:serviceaccounts:foofoo-default"},
+		"Impersonate-Extra-Tenant": {"foofoo"},
+		"Impersonate-Extra-Authentication.kubernetes.io%2fpod-Name": {"builder-1"},
+	}
+	if !reflect.DeepEqual(header, expect) {
+		t.Errorf("expect headers %v, got %v", expect, header)
+	}
+}
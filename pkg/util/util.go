@@ -39,6 +39,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog"
+
+	tenantlister "github.com/kubewharf/kubezoo/pkg/generated/listers/tenant/v1alpha1"
 )
 
 const (
@@ -46,6 +48,11 @@ const (
 	// TODO(renjingsi): move this to tenant apis and add some validations
 	TenantIDLength = 6
 	TenantIDKey    = "tenant"
+	// TenantUIDKey and TenantGenerationKey are the user extras recording the
+	// UID and the generation of the tenant the certificate user is
+	// authenticated against.
+	TenantUIDKey        = "tenant-uid"
+	TenantGenerationKey = "tenant-generation"
 )
 
 // AddTenantIDPrefix add tenantId as the prefix.
@@ -295,6 +302,26 @@ func IsGroupKindNamespaced(kind metav1.GroupKind) (bool, error) {
 	return namespaced, nil
 }
 
+// groupResourceNamespaced is groupKindNamespaced keyed by the plural
+// resource names of the kinds.
+var groupResourceNamespaced = func() map[schema.GroupResource]bool {
+	m := make(map[schema.GroupResource]bool, len(groupKindNamespaced))
+	for gk, namespaced := range groupKindNamespaced {
+		plural, _ := meta.UnsafeGuessKindToResource(schema.GroupVersionKind{Group: gk.Group, Kind: gk.Kind})
+		m[plural.GroupResource()] = namespaced
+	}
+	return m
+}()
+
+// IsGroupResourceNamespaced check the resource is namespace scoped or not.
+func IsGroupResourceNamespaced(resource schema.GroupResource) (bool, error) {
+	namespaced, ok := groupResourceNamespaced[resource]
+	if !ok {
+		return false, fmt.Errorf("unrecognized resource: %+v", resource)
+	}
+	return namespaced, nil
+}
+
 // TenantIDFrom returns tenantID from ctx.
 func TenantIDFrom(ctx context.Context) string {
 	tenantExtra := "tenant"
@@ -323,6 +350,33 @@ func ListCRDsForTenant(tenantID string, crdLister v1.CustomResourceDefinitionLis
 	return tenantCRDs, nil
 }
 
+// IsSystemCRDGroup returns whether the group requested by the tenant is
+// served by a system crd, i.e. a crd in the upstream cluster whose group is
+// not prefixed for a tenant. The crds of the tenant and of the other tenants
+// never match, so that the tenant can not tell the groups of the others.
+func IsSystemCRDGroup(group, tenantID string, crdLister v1.CustomResourceDefinitionLister, tenantLister tenantlister.TenantLister) (bool, error) {
+	if strings.HasPrefix(group, tenantID+TenantIDSeparator) {
+		return false, nil
+	}
+	if parts := strings.SplitN(group, TenantIDSeparator, 2); len(parts) == 2 && ValidateTenantName(parts[0]) == nil {
+		if _, err := tenantLister.Get(parts[0]); err == nil {
+			return false, nil
+		} else if !errors.IsNotFound(err) {
+			return false, err
+		}
+	}
+	crdList, err := crdLister.List(labels.Everything())
+	if err != nil {
+		return false, err
+	}
+	for _, crd := range crdList {
+		if crd.Spec.Group == group {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // CheckGroupKindFunc returns whether resource of the group/kind is namespaced and whether it is custom resource group for the tenant.
 type CheckGroupKindFunc func(group, kind, tenantID string, isTenantObject bool) (namespaced, customResourceGroup bool, err error)
 
@@ -27,6 +27,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+
+	tenantv1alpha1 "github.com/kubewharf/kubezoo/pkg/apis/tenant/v1alpha1"
+	tenantlister "github.com/kubewharf/kubezoo/pkg/generated/listers/tenant/v1alpha1"
 )
 
 // TestGetTenantIDFromNamespace tests the GetTenantIDFromNamespace function.
@@ -462,3 +466,92 @@ func TestNewCheckGroupKindFunc(t *testing.T) {
 		})
 	}
 }
+
+// TestIsGroupResourceNamespaced tests the scope of the native resources.
+func TestIsGroupResourceNamespaced(t *testing.T) {
+	tests := []struct {
+		resource         schema.GroupResource
+		expectNamespaced bool
+		expectErr        bool
+	}{
+		{resource: schema.GroupResource{Resource: "endpoints"}, expectNamespaced: true},
+		{resource: schema.GroupResource{Resource: "namespaces"}, expectNamespaced: false},
+		{resource: schema.GroupResource{Group: "networking.k8s.io", Resource: "networkpolicies"}, expectNamespaced: true},
+		{resource: schema.GroupResource{Group: "networking.k8s.io", Resource: "ingressclasses"}, expectNamespaced: false},
+		{resource: schema.GroupResource{Group: "kubezoo.io", Resource: "foos"}, expectErr: true},
+	}
+	for _, test := range tests {
+		namespaced, err := IsGroupResourceNamespaced(test.resource)
+		if (err != nil) != test.expectErr {
+			t.Errorf("unexpected error of %v: %v", test.resource, err)
+		}
+		if namespaced != test.expectNamespaced {
+			t.Errorf("unexpected namespaced of %v got %v, want %v", test.resource, namespaced, test.expectNamespaced)
+		}
+	}
+}
+
+// TestIsSystemCRDGroup tests the system crd group checking function.
+func TestIsSystemCRDGroup(t *testing.T) {
+	crdLister := FakeCRDLister{
+		[]*apiextensionsv1.CustomResourceDefinition{
+			{Spec: apiextensionsv1.CustomResourceDefinitionSpec{Group: "111111-kubezoo.io"}},
+			{Spec: apiextensionsv1.CustomResourceDefinitionSpec{Group: "222222-kubezoo.io"}},
+			{Spec: apiextensionsv1.CustomResourceDefinitionSpec{Group: "system.io"}},
+			{Spec: apiextensionsv1.CustomResourceDefinitionSpec{Group: "shared-system.io"}},
+		},
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	indexer.Add(&tenantv1alpha1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "111111"}})
+	indexer.Add(&tenantv1alpha1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "222222"}})
+	tenantLister := tenantlister.NewTenantLister(indexer)
+
+	tests := []struct {
+		name  string
+		group string
+		want  bool
+	}{
+		{
+			name:  "system crd group",
+			group: "system.io",
+			want:  true,
+		},
+		{
+			name:  "system crd group like a tenant prefix",
+			group: "shared-system.io",
+			want:  true,
+		},
+		{
+			name:  "unprefixed tenant crd group",
+			group: "kubezoo.io",
+			want:  false,
+		},
+		{
+			name:  "own prefixed crd group",
+			group: "111111-kubezoo.io",
+			want:  false,
+		},
+		{
+			name:  "crd group of another tenant",
+			group: "222222-kubezoo.io",
+			want:  false,
+		},
+		{
+			name:  "unknown group",
+			group: "unknown.io",
+			want:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := IsSystemCRDGroup(test.group, "111111", &crdLister, tenantLister)
+			if err != nil {
+				t.Errorf("unexpected err %s", err)
+			}
+			if got != test.want {
+				t.Errorf("unexpected result got %v, want %v", got, test.want)
+			}
+		})
+	}
+}